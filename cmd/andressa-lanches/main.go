@@ -15,17 +15,42 @@ package main
 // @name Authorization
 // @description Insira o token JWT no formato: Bearer {token}
 
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description Chave de API das integrações, no formato al_{prefixo}_{segredo}
+
 import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/fiscal"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
 	"andressa-lanches/internal/infrastructure/db"
+	"andressa-lanches/internal/infrastructure/events"
+	"andressa-lanches/internal/infrastructure/nfce"
+	"andressa-lanches/internal/infrastructure/payments"
+	"andressa-lanches/internal/infrastructure/printing"
+	"andressa-lanches/internal/infrastructure/qrcode"
 	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/infrastructure/scheduler"
+	"andressa-lanches/internal/infrastructure/spreadsheet"
 	"andressa-lanches/internal/interfaces/api"
+	"andressa-lanches/internal/interfaces/api/handlers"
 
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	// Embute a base de fusos horários para STORE_TIMEZONE funcionar mesmo em
+	// imagens sem tzdata.
+	_ "time/tzdata"
 )
 
 func main() {
@@ -38,17 +63,190 @@ func main() {
 	}
 	defer pool.Close()
 
+	serviceCharge := sale.ServiceChargePolicy{Percentage: cfg.ServiceChargePercentage}
+	for _, orderType := range cfg.ServiceChargeOrderTypes {
+		serviceCharge.OrderTypes = append(serviceCharge.OrderTypes, sale.OrderType(orderType))
+	}
+	if err := serviceCharge.Validate(); err != nil {
+		log.Fatalf("Configuração da taxa de serviço inválida: %v", err)
+	}
+
+	storeLocation, err := time.LoadLocation(cfg.StoreTimezone)
+	if err != nil {
+		log.Fatalf("Fuso horário da loja inválido: %v", err)
+	}
+	if storeLocation == time.Local {
+		// Os relatórios agrupam vendas no Postgres, que não conhece o fuso "Local".
+		log.Fatalf("STORE_TIMEZONE deve ser um fuso IANA, como America/Sao_Paulo")
+	}
+	calendar := sale.Calendar{Location: storeLocation, CutoffHour: cfg.BusinessDayCutoffHour}
+	if err := calendar.Validate(); err != nil {
+		log.Fatalf("Configuração do dia de operação inválida: %v", err)
+	}
+
 	categoryRepo := repository.NewCategoryRepository(pool)
 	productRepo := repository.NewProductRepository(pool)
 	additionRepo := repository.NewAdditionRepository(pool)
 	saleRepo := repository.NewSaleRepository(pool)
+	stationRepo := repository.NewStationRepository(pool)
+	paymentRepo := repository.NewPaymentRepository(pool)
+	fiscalRepo := repository.NewFiscalRepository(pool)
+	userRepo := repository.NewUserRepository(pool)
+	sessionRepo := repository.NewSessionRepository(pool)
+	terminalRepo := repository.NewTerminalRepository(pool)
+	apiKeyRepo := repository.NewAPIKeyRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	priceRepo := repository.NewPriceRepository(pool)
+	adjustmentRepo := repository.NewAdjustmentRepository(pool)
+	catalogRepo := repository.NewCatalogRepository(pool)
+	rateLimitStore := repository.NewInMemoryRateLimitStore()
+
+	kitchenBroker := events.NewKitchenBroker(cfg.KitchenFeedHistory)
+
+	auditService := services.NewAuditService(auditRepo, calendar)
+	menuService := services.NewMenuService(categoryRepo, productRepo, additionRepo)
+	categoryService := services.NewCategoryService(categoryRepo, stationRepo, menuService)
+	productService := services.NewProductService(productRepo, menuService)
+	additionService := services.NewAdditionService(additionRepo, menuService)
+	pricingService := services.NewPricingService(priceRepo, adjustmentRepo, productService, additionService, menuService)
+	catalogService := services.NewCatalogService(catalogRepo, categoryRepo, productRepo, additionRepo, stationRepo, menuService)
+	priceScheduler := scheduler.Every(cfg.PriceSchedulerInterval, "trocas de preço agendadas", pricingService.ApplyDuePriceChanges)
+	defer priceScheduler.Close()
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, userRepo, serviceCharge, calendar, kitchenBroker)
+	stationService := services.NewStationService(stationRepo)
+	userService := services.NewUserService(userRepo)
+	if err := userService.EnsureOwner(context.Background(), cfg.AuthUser, cfg.AuthPassword); err != nil {
+		log.Fatalf("Falha ao criar o proprietário: %v", err)
+	}
+	loginGuard := services.NewLoginGuard(rateLimitStore, auditRepo, services.LoginPolicy{
+		PerUser: ratelimit.LockoutPolicy{MaxAttempts: cfg.LoginMaxAttempts, BaseLockout: cfg.LoginLockout, MaxLockout: cfg.LoginMaxLockout},
+		PerIP:   ratelimit.LockoutPolicy{MaxAttempts: cfg.LoginMaxAttemptsPerIP, BaseLockout: cfg.LoginLockout, MaxLockout: cfg.LoginMaxLockout},
+	})
+	authService := services.NewAuthService(userRepo, sessionRepo, terminalRepo, apiKeyRepo, loginGuard, services.TokenPolicy{
+		Secret:             cfg.JWTSecret,
+		AccessTokenTTL:     cfg.AuthAccessTokenTTL,
+		RefreshTokenTTL:    cfg.AuthRefreshTokenTTL,
+		OperatorSessionTTL: cfg.AuthOperatorSessionTTL,
+	})
+	terminalService := services.NewTerminalService(terminalRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+
+	accents := printing.AccentMode(cfg.PrinterAccents)
+	if !accents.IsValid() {
+		log.Fatalf("Configuração da impressora inválida: %v", printing.ErrAccentModeInvalid)
+	}
+	renderer := printing.NewRenderer(printing.Layout{
+		Width:    cfg.PrinterWidth,
+		Header:   cfg.ReceiptHeader,
+		Footer:   cfg.ReceiptFooter,
+		Accents:  accents,
+		Location: storeLocation,
+	})
+
+	var printer receipt.Printer
+	switch {
+	case cfg.PrinterAddress != "":
+		printer = &printing.NetworkPrinter{Address: cfg.PrinterAddress}
+	case cfg.PrinterDevice != "":
+		printer = &printing.DevicePrinter{Path: cfg.PrinterDevice}
+	}
+	if printer != nil {
+		printQueue := printing.NewPrintQueue(printer, cfg.PrinterQueueSize)
+		defer printQueue.Close()
+		printer = printQueue
+	}
+	receiptService := services.NewReceiptService(saleRepo, renderer, printer)
 
-	categoryService := services.NewCategoryService(categoryRepo)
-	productService := services.NewProductService(productRepo)
-	additionService := services.NewAdditionService(additionRepo)
-	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo)
+	pixMerchant := pix.Merchant{
+		Key:  cfg.PixKey,
+		Name: cfg.PixMerchantName,
+		City: cfg.PixMerchantCity,
+	}
+	pixService := services.NewPixService(saleRepo, pixMerchant, qrcode.PNGEncoder{})
+
+	var gateway payment.Gateway
+	// Sem segredo, qualquer um poderia forjar a confirmação de um pagamento
+	// pelo webhook.
+	if cfg.PaymentWebhookSecret == "" {
+		log.Fatalf("PAYMENT_WEBHOOK_SECRET não configurado")
+	}
+	var fakeGateway handlers.FakeSettler
+	switch cfg.PaymentGateway {
+	case payments.FakeGatewayName:
+		fake := payments.NewFakeGateway(cfg.PaymentWebhookSecret, pixMerchant)
+		gateway, fakeGateway = fake, fake
+		log.Printf("Usando o gateway de pagamento simulado")
+	default:
+		log.Fatalf("Gateway de pagamento não suportado: %s", cfg.PaymentGateway)
+	}
+	paymentService := services.NewPaymentService(paymentRepo, saleRepo, gateway)
+
+	fiscalEnvironment, err := fiscal.ParseEnvironment(cfg.FiscalEnvironment)
+	if err != nil {
+		log.Fatalf("Configuração da NFC-e inválida: %v", err)
+	}
+	if cfg.FiscalSeries < 0 || cfg.FiscalSeries > 999 {
+		log.Fatalf("Configuração da NFC-e inválida: %v", fiscal.ErrSeriesInvalid)
+	}
+	issuer := fiscal.Issuer{
+		CNPJ:              cfg.FiscalCNPJ,
+		StateRegistration: cfg.FiscalStateRegistration,
+		Name:              cfg.FiscalCompanyName,
+		TradeName:         cfg.FiscalTradeName,
+		TaxRegime:         fiscal.TaxRegime(cfg.FiscalTaxRegime),
+		Address: fiscal.Address{
+			Street:   cfg.FiscalStreet,
+			Number:   cfg.FiscalStreetNumber,
+			District: cfg.FiscalDistrict,
+			CityCode: cfg.FiscalCityCode,
+			City:     cfg.FiscalCity,
+			State:    cfg.FiscalState,
+			ZipCode:  cfg.FiscalZipCode,
+		},
+		CSCID:      cfg.FiscalCSCID,
+		CSC:        cfg.FiscalCSC,
+		QRCodeURL:  cfg.FiscalQRCodeURL,
+		ConsultURL: cfg.FiscalConsultURL,
+	}
 
-	router := api.SetupRouter(productService, categoryService, additionService, saleService)
+	var fiscalEncoder fiscal.Encoder
+	switch {
+	case cfg.FiscalCertFile != "":
+		signer, err := nfce.LoadSigner(cfg.FiscalCertFile, cfg.FiscalKeyFile)
+		if err != nil {
+			log.Fatalf("Falha ao carregar o certificado da NFC-e: %v", err)
+		}
+		fiscalEncoder = nfce.NewEncoder(signer)
+	case fiscalEnvironment == fiscal.EnvironmentHomologation && issuer.Validate() == nil:
+		certPEM, keyPEM, err := nfce.GenerateTestCertificate(issuer.Name, issuer.CNPJ)
+		if err == nil {
+			var signer *nfce.Signer
+			if signer, err = nfce.ParseSigner(certPEM, keyPEM); err == nil {
+				fiscalEncoder = nfce.NewEncoder(signer)
+				log.Printf("Usando um certificado de teste para assinar a NFC-e")
+			}
+		}
+		if err != nil {
+			log.Fatalf("Falha ao gerar o certificado de teste da NFC-e: %v", err)
+		}
+	}
+
+	var fiscalTransmitter fiscal.Transmitter
+	switch cfg.FiscalTransmitter {
+	case nfce.FakeTransmitterName:
+		fiscalTransmitter = nfce.NewFakeTransmitter()
+	default:
+		log.Fatalf("Transmissor da NFC-e não suportado: %s", cfg.FiscalTransmitter)
+	}
+	fiscalService := services.NewFiscalService(fiscalRepo, saleRepo, productRepo, categoryRepo, issuer, fiscalEnvironment, cfg.FiscalSeries, fiscalEncoder, fiscalTransmitter)
+
+	exportService := services.NewExportService(repository.NewExportRepository(pool), spreadsheet.NewEncoder(), calendar)
+
+	router, err := api.SetupRouter(productService, categoryService, additionService, saleService, stationService, receiptService, pixService, paymentService, fakeGateway, fiscalService, kitchenBroker, userService, authService, terminalService, apiKeyService, auditService, pricingService,
+		catalogService, menuService, exportService, rateLimitStore, ratelimit.Limit{Requests: cfg.RateLimitRequests, Window: cfg.RateLimitWindow}, cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Configuração de TRUSTED_PROXIES inválida: %v", err)
+	}
 
 	go func() {
 		if err := router.Run(cfg.ServerAddress); err != nil {
@@ -0,0 +1,6 @@
+ALTER TABLE sales
+    DROP COLUMN IF EXISTS service_charge_waiver_reason,
+    DROP COLUMN IF EXISTS service_charge_waived,
+    DROP COLUMN IF EXISTS service_charge,
+    DROP COLUMN IF EXISTS employee,
+    DROP COLUMN IF EXISTS order_type;
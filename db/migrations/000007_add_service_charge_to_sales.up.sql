@@ -0,0 +1,6 @@
+ALTER TABLE sales
+    ADD COLUMN IF NOT EXISTS order_type VARCHAR(20) NOT NULL DEFAULT 'takeaway',
+    ADD COLUMN IF NOT EXISTS employee VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS service_charge NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS service_charge_waived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS service_charge_waiver_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE sale_items
+    DROP COLUMN IF EXISTS notes;
+
+ALTER TABLE sales
+    DROP COLUMN IF EXISTS status;
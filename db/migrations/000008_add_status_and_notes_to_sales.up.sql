@@ -0,0 +1,5 @@
+ALTER TABLE sales
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'received';
+
+ALTER TABLE sale_items
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
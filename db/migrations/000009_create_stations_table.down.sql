@@ -0,0 +1,8 @@
+ALTER TABLE sale_items
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS station_id;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS station_id;
+
+DROP TABLE IF EXISTS stations;
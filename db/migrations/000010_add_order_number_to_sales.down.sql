@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS sales_business_date_order_number_idx;
+
+ALTER TABLE sales
+    DROP COLUMN IF EXISTS order_number,
+    DROP COLUMN IF EXISTS business_date;
+
+DROP TABLE IF EXISTS order_number_sequences;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS order_number_sequences (
+    business_date DATE PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+ALTER TABLE sales
+    ADD COLUMN IF NOT EXISTS business_date DATE,
+    ADD COLUMN IF NOT EXISTS order_number INTEGER;
+
+UPDATE sales s
+SET business_date = numbered.business_date,
+    order_number = numbered.order_number
+FROM (
+    SELECT id,
+           date::date AS business_date,
+           ROW_NUMBER() OVER (PARTITION BY date::date ORDER BY date, id) AS order_number
+    FROM sales
+) numbered
+WHERE s.id = numbered.id;
+
+INSERT INTO order_number_sequences (business_date, last_number)
+SELECT business_date, MAX(order_number)
+FROM sales
+GROUP BY business_date
+ON CONFLICT (business_date) DO NOTHING;
+
+ALTER TABLE sales
+    ALTER COLUMN business_date SET NOT NULL,
+    ALTER COLUMN order_number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sales_business_date_order_number_idx
+    ON sales (business_date, order_number);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS sale_payments (
+    sale_id UUID NOT NULL,
+    payment_id SERIAL NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    PRIMARY KEY (sale_id, payment_id),
+    FOREIGN KEY (sale_id) REFERENCES sales(id)
+);
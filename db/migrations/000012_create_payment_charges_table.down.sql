@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS payment_charges;
+
+ALTER TABLE sale_payments
+    DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,26 @@
+ALTER TABLE sale_payments
+    ADD COLUMN IF NOT EXISTS reference VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS payment_charges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sale_id UUID NOT NULL REFERENCES sales(id),
+    provider VARCHAR(50) NOT NULL,
+    provider_charge_id VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    pix_payload TEXT NOT NULL DEFAULT '',
+    checkout_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_charge_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_charges_sale_id ON payment_charges (sale_id);
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS fiscal_documents;
+DROP TABLE IF EXISTS fiscal_number_sequences;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS ncm,
+    DROP COLUMN IF EXISTS cfop,
+    DROP COLUMN IF EXISTS cst;
@@ -0,0 +1,28 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS ncm VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cfop VARCHAR(4) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cst VARCHAR(3) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS fiscal_number_sequences (
+    series INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS fiscal_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sale_id UUID NOT NULL UNIQUE REFERENCES sales(id),
+    series INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    access_key CHAR(44) NOT NULL UNIQUE,
+    environment SMALLINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    xml TEXT NOT NULL,
+    protocol VARCHAR(20) NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    issued_at TIMESTAMP NOT NULL,
+    authorized_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (series, number)
+);
@@ -0,0 +1,18 @@
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS ncm,
+    DROP COLUMN IF EXISTS cest,
+    DROP COLUMN IF EXISTS cfop,
+    DROP COLUMN IF EXISTS cst,
+    DROP COLUMN IF EXISTS origin,
+    DROP COLUMN IF EXISTS unit,
+    DROP COLUMN IF EXISTS icms_rate,
+    DROP COLUMN IF EXISTS pis_rate,
+    DROP COLUMN IF EXISTS cofins_rate;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS cest,
+    DROP COLUMN IF EXISTS origin,
+    DROP COLUMN IF EXISTS unit,
+    DROP COLUMN IF EXISTS icms_rate,
+    DROP COLUMN IF EXISTS pis_rate,
+    DROP COLUMN IF EXISTS cofins_rate;
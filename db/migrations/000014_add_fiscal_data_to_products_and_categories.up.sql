@@ -0,0 +1,18 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS cest VARCHAR(7) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS origin SMALLINT,
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(6) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS icms_rate NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS pis_rate NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS cofins_rate NUMERIC(5, 2);
+
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS ncm VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cest VARCHAR(7) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cfop VARCHAR(4) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cst VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS origin SMALLINT,
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(6) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS icms_rate NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS pis_rate NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS cofins_rate NUMERIC(5, 2);
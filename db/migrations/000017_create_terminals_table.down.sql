@@ -0,0 +1,8 @@
+ALTER TABLE sales
+    DROP COLUMN IF EXISTS terminal_id,
+    DROP COLUMN IF EXISTS operator_id;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS pin_hash;
+
+DROP TABLE IF EXISTS terminals;
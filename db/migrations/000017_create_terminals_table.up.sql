@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS terminals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    last_seen_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS pin_hash VARCHAR(100) NOT NULL DEFAULT '';
+
+ALTER TABLE sales
+    ADD COLUMN IF NOT EXISTS operator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS terminal_id UUID REFERENCES terminals(id) ON DELETE SET NULL;
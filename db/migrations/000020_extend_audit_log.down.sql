@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP INDEX IF EXISTS idx_audit_log_request_id;
+DROP INDEX IF EXISTS idx_audit_log_actor_id;
+DROP INDEX IF EXISTS idx_audit_log_entity;
+
+ALTER TABLE audit_log
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS changes,
+    DROP COLUMN IF EXISTS entity_id,
+    DROP COLUMN IF EXISTS entity_type,
+    DROP COLUMN IF EXISTS api_key_id;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS scheduled_price_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_type VARCHAR(20) NOT NULL,
+    item_id UUID NOT NULL,
+    price NUMERIC(10, 2) NOT NULL,
+    effective_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    applied_at TIMESTAMPTZ,
+    canceled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_item ON scheduled_price_changes (item_type, item_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_due ON scheduled_price_changes (effective_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS price_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_type VARCHAR(20) NOT NULL,
+    item_id UUID NOT NULL,
+    old_price NUMERIC(10, 2),
+    new_price NUMERIC(10, 2) NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    scheduled_change_id UUID REFERENCES scheduled_price_changes(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_item ON price_history (item_type, item_id, changed_at);
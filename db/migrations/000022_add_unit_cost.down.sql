@@ -0,0 +1,9 @@
+ALTER TABLE sale_items
+    DROP COLUMN IF EXISTS unit_cost,
+    DROP COLUMN IF EXISTS total_cost;
+
+ALTER TABLE additions
+    DROP COLUMN IF EXISTS unit_cost;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS unit_cost;
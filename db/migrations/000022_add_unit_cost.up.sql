@@ -0,0 +1,9 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(10, 2);
+
+ALTER TABLE additions
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(10, 2);
+
+ALTER TABLE sale_items
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(10, 2),
+    ADD COLUMN IF NOT EXISTS total_cost NUMERIC(10, 2);
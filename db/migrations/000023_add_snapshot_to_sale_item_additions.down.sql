@@ -0,0 +1,3 @@
+ALTER TABLE sale_item_additions
+    DROP COLUMN IF EXISTS unit_price,
+    DROP COLUMN IF EXISTS unit_cost;
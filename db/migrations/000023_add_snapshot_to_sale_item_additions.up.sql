@@ -0,0 +1,8 @@
+ALTER TABLE sale_item_additions
+    ADD COLUMN IF NOT EXISTS unit_price NUMERIC(10, 2),
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(10, 2);
+
+UPDATE sale_item_additions sia
+SET unit_price = a.price
+FROM additions a
+WHERE a.id = sia.addition_id AND sia.unit_price IS NULL;
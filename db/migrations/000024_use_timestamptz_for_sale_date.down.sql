@@ -0,0 +1,3 @@
+-- O dia de operação recalculado em 000024 é mantido.
+ALTER TABLE sales
+    ALTER COLUMN date TYPE TIMESTAMP USING date AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
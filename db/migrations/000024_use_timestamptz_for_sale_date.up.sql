@@ -0,0 +1,41 @@
+-- sales.date guardava o horário local sem fuso, no fuso do servidor da
+-- aplicação que gravou a venda. Os valores existentes são interpretados no
+-- fuso de app.server_timezone, que deve ser o fuso desse servidor; sem ele,
+-- vale o fuso do servidor do banco. Antes de migrar:
+--   ALTER DATABASE <banco> SET app.server_timezone = 'America/Sao_Paulo';
+ALTER TABLE sales
+    ALTER COLUMN date TYPE TIMESTAMPTZ USING date AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+-- O dia de operação das vendas anteriores a 000010 foi preenchido com a data
+-- do calendário, sem o horário de corte. Ele é recalculado no fuso da loja
+-- (app.store_timezone, igual a STORE_TIMEZONE) com o corte de
+-- app.business_day_cutoff_hour (igual a BUSINESS_DAY_CUTOFF_HOUR, padrão 2).
+-- As vendas que mudam de dia recebem números depois do último do novo dia,
+-- para não repetir números de pedido.
+WITH settings AS (
+    SELECT COALESCE(NULLIF(current_setting('app.store_timezone', true), ''), current_setting('TimeZone')) AS store_timezone,
+           COALESCE(NULLIF(current_setting('app.business_day_cutoff_hour', true), '')::INTEGER, 2) AS cutoff_hour
+), recalculated AS (
+    SELECT s.id, s.date,
+           ((s.date AT TIME ZONE settings.store_timezone) - make_interval(hours => settings.cutoff_hour))::DATE AS business_date
+    FROM sales s, settings
+), moved AS (
+    SELECT r.id, r.business_date,
+           COALESCE((SELECT MAX(order_number) FROM sales WHERE business_date = r.business_date), 0)
+               + ROW_NUMBER() OVER (PARTITION BY r.business_date ORDER BY r.date, r.id) AS order_number
+    FROM recalculated r
+    JOIN sales s ON s.id = r.id
+    WHERE r.business_date <> s.business_date
+)
+UPDATE sales s
+SET business_date = moved.business_date,
+    order_number = moved.order_number
+FROM moved
+WHERE s.id = moved.id;
+
+INSERT INTO order_number_sequences (business_date, last_number)
+SELECT business_date, MAX(order_number)
+FROM sales
+GROUP BY business_date
+ON CONFLICT (business_date)
+DO UPDATE SET last_number = GREATEST(order_number_sequences.last_number, EXCLUDED.last_number);
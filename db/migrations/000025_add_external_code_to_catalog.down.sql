@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS additions_external_code_idx;
+DROP INDEX IF EXISTS products_external_code_idx;
+DROP INDEX IF EXISTS categories_external_code_idx;
+
+ALTER TABLE additions
+    DROP COLUMN IF EXISTS external_code;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS external_code;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS external_code;
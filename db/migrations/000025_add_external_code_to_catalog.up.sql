@@ -0,0 +1,12 @@
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS external_code TEXT;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS external_code TEXT;
+
+ALTER TABLE additions
+    ADD COLUMN IF NOT EXISTS external_code TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS categories_external_code_idx ON categories (external_code) WHERE external_code IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS products_external_code_idx ON products (external_code) WHERE external_code IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS additions_external_code_idx ON additions (external_code) WHERE external_code IS NOT NULL;
@@ -0,0 +1,5 @@
+DELETE FROM fiscal_documents WHERE access_key IS NULL;
+
+ALTER TABLE fiscal_documents
+    ALTER COLUMN access_key SET NOT NULL,
+    ALTER COLUMN xml DROP DEFAULT;
@@ -0,0 +1,5 @@
+-- A NFC-e é gravada como pendente, já com o número da série, antes de ser
+-- gerada e transmitida; a chave de acesso e o XML chegam depois.
+ALTER TABLE fiscal_documents
+    ALTER COLUMN access_key DROP NOT NULL,
+    ALTER COLUMN xml SET DEFAULT '';
@@ -0,0 +1,38 @@
+ALTER TABLE payment_charges
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE payment_webhook_events
+    ALTER COLUMN received_at TYPE TIMESTAMP USING received_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE fiscal_documents
+    ALTER COLUMN issued_at TYPE TIMESTAMP USING issued_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN authorized_at TYPE TIMESTAMP USING authorized_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE users
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN access_token_expires_at TYPE TIMESTAMP USING access_token_expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN used_at TYPE TIMESTAMP USING used_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN revoked_at TYPE TIMESTAMP USING revoked_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE revoked_access_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE terminals
+    ALTER COLUMN last_seen_at TYPE TIMESTAMP USING last_seen_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE audit_log
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE api_keys
+    ALTER COLUMN last_used_at TYPE TIMESTAMP USING last_used_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN revoked_at TYPE TIMESTAMP USING revoked_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
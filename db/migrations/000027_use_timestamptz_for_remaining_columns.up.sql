@@ -0,0 +1,43 @@
+-- As demais colunas de data e hora também passam a guardar o instante com
+-- fuso. Como em 000024, os valores existentes são interpretados no fuso de
+-- app.server_timezone, o do servidor da aplicação que os gravou, ou, sem
+-- ele, no fuso do servidor do banco.
+ALTER TABLE payment_charges
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+-- received_at vem do DEFAULT NOW(), gravado pelo banco no seu próprio fuso.
+ALTER TABLE payment_webhook_events
+    ALTER COLUMN received_at TYPE TIMESTAMPTZ USING received_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE fiscal_documents
+    ALTER COLUMN issued_at TYPE TIMESTAMPTZ USING issued_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN authorized_at TYPE TIMESTAMPTZ USING authorized_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE users
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN access_token_expires_at TYPE TIMESTAMPTZ USING access_token_expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN used_at TYPE TIMESTAMPTZ USING used_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN revoked_at TYPE TIMESTAMPTZ USING revoked_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE revoked_access_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE terminals
+    ALTER COLUMN last_seen_at TYPE TIMESTAMPTZ USING last_seen_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE audit_log
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
+
+ALTER TABLE api_keys
+    ALTER COLUMN last_used_at TYPE TIMESTAMPTZ USING last_used_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN revoked_at TYPE TIMESTAMPTZ USING revoked_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone')),
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE COALESCE(NULLIF(current_setting('app.server_timezone', true), ''), current_setting('TimeZone'));
@@ -2,6 +2,8 @@ package services
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"context"
 
 	"github.com/google/uuid"
@@ -17,11 +19,13 @@ type AdditionService interface {
 
 type additionService struct {
 	additionRepo addition.Repository
+	menu         MenuInvalidator
 }
 
-func NewAdditionService(additionRepo addition.Repository) AdditionService {
+func NewAdditionService(additionRepo addition.Repository, menu MenuInvalidator) AdditionService {
 	return &additionService{
 		additionRepo: additionRepo,
+		menu:         menu,
 	}
 }
 
@@ -30,7 +34,17 @@ func (s *additionService) CreateAddition(ctx context.Context, a *addition.Additi
 		return err
 	}
 
-	return s.additionRepo.Create(ctx, a)
+	a.ID = uuid.New()
+	entry, err := audit.NewEntry(ctx, audit.ActionCreate, audit.EntityAddition, a.ID, nil, a)
+	if err != nil {
+		return err
+	}
+	change := newPriceChange(ctx, pricing.ItemAddition, a.ID, nil, a.Price)
+	if err := s.additionRepo.Create(ctx, a, change, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *additionService) GetAdditionByID(ctx context.Context, id uuid.UUID) (*addition.Addition, error) {
@@ -66,7 +80,20 @@ func (s *additionService) UpdateAddition(ctx context.Context, a *addition.Additi
 		return addition.ErrAdditionNotFound
 	}
 
-	return s.additionRepo.Update(ctx, a)
+	var change *pricing.PriceChange
+	if existingAddition.Price != a.Price {
+		oldPrice := existingAddition.Price
+		change = newPriceChange(ctx, pricing.ItemAddition, a.ID, &oldPrice, a.Price)
+	}
+	entry, err := audit.NewEntry(ctx, audit.ActionUpdate, audit.EntityAddition, a.ID, existingAddition, a)
+	if err != nil {
+		return err
+	}
+	if err := s.additionRepo.Update(ctx, a, existingAddition.Price, change, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *additionService) DeleteAddition(ctx context.Context, id uuid.UUID) error {
@@ -82,7 +109,15 @@ func (s *additionService) DeleteAddition(ctx context.Context, id uuid.UUID) erro
 		return addition.ErrAdditionNotFound
 	}
 
-	return s.additionRepo.Delete(ctx, id)
+	entry, err := audit.NewEntry(ctx, audit.ActionDelete, audit.EntityAddition, id, existingAddition, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.additionRepo.Delete(ctx, id, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *additionService) ListAdditions(ctx context.Context) ([]*addition.Addition, error) {
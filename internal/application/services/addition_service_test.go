@@ -2,6 +2,8 @@ package services
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"context"
 	"testing"
 
@@ -14,8 +16,8 @@ type MockAdditionRepository struct {
 	mock.Mock
 }
 
-func (m *MockAdditionRepository) Create(ctx context.Context, a *addition.Addition) error {
-	args := m.Called(ctx, a)
+func (m *MockAdditionRepository) Create(ctx context.Context, a *addition.Addition, change *pricing.PriceChange, entry *audit.Entry) error {
+	args := m.Called(ctx, a, change, entry)
 	return args.Error(0)
 }
 
@@ -28,13 +30,13 @@ func (m *MockAdditionRepository) GetByID(ctx context.Context, id uuid.UUID) (*ad
 	return add.(*addition.Addition), args.Error(1)
 }
 
-func (m *MockAdditionRepository) Update(ctx context.Context, a *addition.Addition) error {
-	args := m.Called(ctx, a)
+func (m *MockAdditionRepository) Update(ctx context.Context, a *addition.Addition, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error {
+	args := m.Called(ctx, a, oldPrice, change, entry)
 	return args.Error(0)
 }
 
-func (m *MockAdditionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockAdditionRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
+	args := m.Called(ctx, id, entry)
 	return args.Error(0)
 }
 
@@ -46,14 +48,14 @@ func (m *MockAdditionRepository) List(ctx context.Context) ([]*addition.Addition
 func TestAdditionService_CreateAddition_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	testAddition := &addition.Addition{
 		Name:  "Bacon",
 		Price: 2.50,
 	}
 
-	mockRepo.On("Create", ctx, testAddition).Return(nil)
+	mockRepo.On("Create", ctx, testAddition, mock.AnythingOfType("*pricing.PriceChange"), mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.CreateAddition(ctx, testAddition)
 
@@ -64,7 +66,7 @@ func TestAdditionService_CreateAddition_Success(t *testing.T) {
 func TestAdditionService_CreateAddition_InvalidName(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	testAddition := &addition.Addition{
 		Name:  "",
@@ -81,7 +83,7 @@ func TestAdditionService_CreateAddition_InvalidName(t *testing.T) {
 func TestAdditionService_CreateAddition_InvalidPrice(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	testAddition := &addition.Addition{
 		Name:  "Bacon",
@@ -98,7 +100,7 @@ func TestAdditionService_CreateAddition_InvalidPrice(t *testing.T) {
 func TestAdditionService_GetAdditionByID_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	additionID := uuid.New()
 	expectedAddition := &addition.Addition{
@@ -119,7 +121,7 @@ func TestAdditionService_GetAdditionByID_Success(t *testing.T) {
 func TestAdditionService_UpdateAddition_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	additionID := uuid.New()
 	updatedAddition := &addition.Addition{
@@ -129,7 +131,7 @@ func TestAdditionService_UpdateAddition_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetByID", ctx, additionID).Return(updatedAddition, nil)
-	mockRepo.On("Update", ctx, updatedAddition).Return(nil)
+	mockRepo.On("Update", ctx, updatedAddition, updatedAddition.Price, (*pricing.PriceChange)(nil), (*audit.Entry)(nil)).Return(nil)
 
 	err := service.UpdateAddition(ctx, updatedAddition)
 
@@ -140,12 +142,12 @@ func TestAdditionService_UpdateAddition_Success(t *testing.T) {
 func TestAdditionService_DeleteAddition_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	additionID := uuid.New()
 
 	mockRepo.On("GetByID", ctx, additionID).Return(&addition.Addition{}, nil)
-	mockRepo.On("Delete", ctx, additionID).Return(nil)
+	mockRepo.On("Delete", ctx, additionID, mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.DeleteAddition(ctx, additionID)
 
@@ -156,7 +158,7 @@ func TestAdditionService_DeleteAddition_Success(t *testing.T) {
 func TestAdditionService_ListAdditions_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockAdditionRepository)
-	service := NewAdditionService(mockRepo)
+	service := NewAdditionService(mockRepo, nil)
 
 	expectedAdditions := []*addition.Addition{
 		{
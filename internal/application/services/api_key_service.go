@@ -0,0 +1,111 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/apikey"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, k *apikey.APIKey) (*apikey.Issued, error)
+	GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error)
+	RotateAPIKey(ctx context.Context, id uuid.UUID) (*apikey.Issued, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	ListAPIKeys(ctx context.Context) ([]*apikey.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo apikey.Repository
+}
+
+func NewAPIKeyService(apiKeyRepo apikey.Repository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateAPIKey cadastra a chave e devolve o valor completo, que não pode ser
+// consultado depois.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, k *apikey.APIKey) (*apikey.Issued, error) {
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+
+	key, err := s.newKey(k)
+	if err != nil {
+		return nil, err
+	}
+	k.CreatedAt = time.Now()
+	if err := s.apiKeyRepo.Create(ctx, k); err != nil {
+		return nil, err
+	}
+	return &apikey.Issued{APIKey: k, Key: key}, nil
+}
+
+func (s *apiKeyService) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	if id == uuid.Nil {
+		return nil, apikey.ErrAPIKeyIdInvalid
+	}
+
+	k, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return nil, apikey.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
+
+// RotateAPIKey troca o segredo e o prefixo mantendo o nome e os escopos; a
+// chave anterior deixa de valer imediatamente.
+func (s *apiKeyService) RotateAPIKey(ctx context.Context, id uuid.UUID) (*apikey.Issued, error) {
+	k, err := s.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if k.IsRevoked() {
+		return nil, apikey.ErrAPIKeyRevoked
+	}
+
+	key, err := s.newKey(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Update(ctx, k); err != nil {
+		return nil, err
+	}
+	return &apikey.Issued{APIKey: k, Key: key}, nil
+}
+
+// RevokeAPIKey desativa a chave de vez. O registro é mantido para consulta
+// de quando foi usada pela última vez.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	k, err := s.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if k.IsRevoked() {
+		return nil
+	}
+
+	now := time.Now()
+	k.RevokedAt = &now
+	return s.apiKeyRepo.Update(ctx, k)
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*apikey.APIKey, error) {
+	return s.apiKeyRepo.List(ctx)
+}
+
+func (s *apiKeyService) newKey(k *apikey.APIKey) (string, error) {
+	key, prefix, err := apikey.NewKey()
+	if err != nil {
+		return "", err
+	}
+	k.Prefix = prefix
+	k.KeyHash = apikey.HashKey(key)
+	return key, nil
+}
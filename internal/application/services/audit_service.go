@@ -0,0 +1,40 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+)
+
+// AuditService consulta a trilha de auditoria. Os registros são gravados
+// pelos repositórios, na mesma transação da alteração que descrevem.
+type AuditService interface {
+	ListEntries(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error)
+}
+
+type auditService struct {
+	auditRepo audit.Repository
+	calendar  sale.Calendar
+}
+
+func NewAuditService(auditRepo audit.Repository, calendar sale.Calendar) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		calendar:  calendar,
+	}
+}
+
+// ListEntries consulta a trilha; Start e End são datas do calendário da loja
+// e passam a valer a partir da meia-noite no fuso dela.
+func (s *auditService) ListEntries(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	if !filter.Start.IsZero() {
+		filter.Start = s.calendar.Date(filter.Start)
+	}
+	if !filter.End.IsZero() {
+		filter.End = s.calendar.Date(filter.End)
+	}
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.auditRepo.List(ctx, filter)
+}
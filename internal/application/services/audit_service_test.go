@@ -0,0 +1,46 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_ListEntries(t *testing.T) {
+	ctx := context.Background()
+	auditRepo := new(MockAuditRepository)
+	service := NewAuditService(auditRepo, sale.Calendar{})
+
+	auditRepo.On("List", ctx, audit.Filter{EntityType: audit.EntitySale, Limit: audit.DefaultLimit}).Return([]*audit.Entry{}, nil).Once()
+	_, err := service.ListEntries(ctx, audit.Filter{EntityType: audit.EntitySale})
+	require.NoError(t, err)
+
+	_, err = service.ListEntries(ctx, audit.Filter{Limit: -1})
+	assert.Equal(t, audit.ErrFilterInvalid, err)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestAuditService_ListEntries_UsesStoreCalendar(t *testing.T) {
+	ctx := context.Background()
+	auditRepo := new(MockAuditRepository)
+	store := time.FixedZone("BRT", -3*60*60)
+	service := NewAuditService(auditRepo, sale.Calendar{Location: store, CutoffHour: 2})
+
+	// As datas chegam sem fuso e valem de meia-noite a meia-noite na loja.
+	auditRepo.On("List", ctx, audit.Filter{
+		Start: time.Date(2024, 9, 1, 0, 0, 0, 0, store),
+		End:   time.Date(2024, 9, 2, 0, 0, 0, 0, store),
+		Limit: audit.DefaultLimit,
+	}).Return([]*audit.Entry{}, nil).Once()
+	_, err := service.ListEntries(ctx, audit.Filter{
+		Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	auditRepo.AssertExpectations(t)
+}
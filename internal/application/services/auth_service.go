@@ -0,0 +1,403 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, username, password, ip string) (*session.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*session.TokenPair, error)
+	OperatorLogin(ctx context.Context, deviceToken, username, pin, ip string) (*session.TokenPair, error)
+	ListOperators(ctx context.Context, deviceToken string) ([]*user.User, error)
+	Logout(ctx context.Context, claims *session.Claims) error
+	VerifyAccessToken(ctx context.Context, token string) (*session.Claims, error)
+	VerifyAPIKey(ctx context.Context, key string) (*session.Claims, error)
+}
+
+// TokenPolicy define a assinatura e a validade dos tokens. OperatorSessionTTL
+// é a validade das sessões abertas com PIN nos terminais.
+type TokenPolicy struct {
+	Secret             string
+	AccessTokenTTL     time.Duration
+	RefreshTokenTTL    time.Duration
+	OperatorSessionTTL time.Duration
+}
+
+type authService struct {
+	userRepo     user.Repository
+	sessionRepo  session.Repository
+	terminalRepo terminal.Repository
+	apiKeyRepo   apikey.Repository
+	guard        LoginGuard
+	policy       TokenPolicy
+}
+
+func NewAuthService(userRepo user.Repository, sessionRepo session.Repository, terminalRepo terminal.Repository, apiKeyRepo apikey.Repository, guard LoginGuard, policy TokenPolicy) AuthService {
+	return &authService{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		terminalRepo: terminalRepo,
+		apiKeyRepo:   apiKeyRepo,
+		guard:        guard,
+		policy:       policy,
+	}
+}
+
+// accessClaims é o conteúdo do JWT; sid identifica a sessão (a família de
+// refresh tokens) para que o logout revogue todos os tokens dela, e tid o
+// terminal das sessões abertas com PIN. tkey prende essas sessões ao token do
+// dispositivo usado no login, para que a troca do token as encerre.
+type accessClaims struct {
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	SessionID   string `json:"sid"`
+	TerminalID  string `json:"tid,omitempty"`
+	TerminalKey string `json:"tkey,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Login devolve o mesmo erro para usuário inexistente, inativo ou senha
+// errada, para não revelar quais contas existem. A tentativa é contada antes
+// de a senha ser conferida e, enquanto o IP ou o usuário estiverem
+// bloqueados por excesso de falhas, a senha nem é conferida.
+func (s *authService) Login(ctx context.Context, username, password, ip string) (*session.TokenPair, error) {
+	attempt := session.LoginAttempt{Method: session.LoginMethodPassword, IP: ip, Username: strings.ToLower(strings.TrimSpace(username))}
+	if err := s.guard.Reserve(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByUsername(ctx, attempt.Username)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || !u.Active || !u.CheckPassword(password) {
+		return nil, s.failLogin(ctx, attempt, u)
+	}
+	if err := s.guard.Succeed(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.sessionRepo.DeleteExpired(ctx, now); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, u, uuid.New(), now)
+}
+
+// Refresh troca o refresh token por um novo par. Um token já usado indica que
+// foi copiado, e toda a sessão é revogada.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*session.TokenPair, error) {
+	stored, err := s.sessionRepo.GetByHash(ctx, session.HashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored == nil || stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, session.ErrRefreshTokenInvalid
+	}
+	if stored.UsedAt != nil {
+		return nil, s.revokeReused(ctx, stored.FamilyID, now)
+	}
+	marked, err := s.sessionRepo.MarkUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, s.revokeReused(ctx, stored.FamilyID, now)
+	}
+
+	u, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || !u.Active {
+		if err := s.sessionRepo.RevokeFamily(ctx, stored.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, session.ErrRefreshTokenInvalid
+	}
+	return s.issue(ctx, u, stored.FamilyID, now)
+}
+
+// OperatorLogin abre uma sessão curta, sem refresh token, para o operador que
+// digitou o PIN em um terminal ativo. O token fica preso ao terminal. Como o
+// PIN é curto, as falhas bloqueiam o PIN do usuário como no login com senha.
+func (s *authService) OperatorLogin(ctx context.Context, deviceToken, username, pin, ip string) (*session.TokenPair, error) {
+	t, err := s.authenticateTerminal(ctx, deviceToken)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := session.LoginAttempt{Method: session.LoginMethodPIN, IP: ip, Username: strings.ToLower(strings.TrimSpace(username)), TerminalID: &t.ID}
+	if err := s.guard.Reserve(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByUsername(ctx, attempt.Username)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || !u.Active || !u.CheckPIN(pin) {
+		return nil, s.failLogin(ctx, attempt, u)
+	}
+	if err := s.guard.Succeed(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.terminalRepo.UpdateLastSeen(ctx, t.ID, now); err != nil {
+		return nil, err
+	}
+
+	tokenID := uuid.New()
+	expiresAt := now.Add(s.policy.OperatorSessionTTL)
+	accessToken, err := s.signAccessToken(u, tokenID, uuid.New(), t, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session.TokenPair{
+		AccessToken: accessToken,
+		TokenType:   session.TokenType,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// ListOperators devolve os usuários ativos com PIN, exibidos na tela de troca
+// de operador do terminal.
+func (s *authService) ListOperators(ctx context.Context, deviceToken string) ([]*user.User, error) {
+	if _, err := s.authenticateTerminal(ctx, deviceToken); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	operators := make([]*user.User, 0, len(users))
+	for _, u := range users {
+		if u.Active && u.HasPIN() {
+			operators = append(operators, u)
+		}
+	}
+	return operators, nil
+}
+
+// Logout encerra a sessão do token: os refresh tokens deixam de valer e os
+// access tokens emitidos nela entram na lista de revogação, incluindo o da
+// própria requisição, que nas sessões de operador não tem família. Chaves de
+// API não têm sessão e são revogadas pelo cadastro de chaves.
+func (s *authService) Logout(ctx context.Context, claims *session.Claims) error {
+	if claims.IsAPIKey() {
+		return apikey.ErrLogoutUnsupported
+	}
+	if err := s.sessionRepo.RevokeFamily(ctx, claims.SessionID, time.Now()); err != nil {
+		return err
+	}
+	return s.sessionRepo.RevokeAccessToken(ctx, claims.TokenID, claims.ExpiresAt)
+}
+
+func (s *authService) VerifyAccessToken(ctx context.Context, token string) (*session.Claims, error) {
+	var parsed accessClaims
+	_, err := jwt.ParseWithClaims(token, &parsed, func(token *jwt.Token) (interface{}, error) {
+		return []byte(s.policy.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, session.ErrAccessTokenInvalid
+	}
+
+	claims, err := parsed.claims()
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.sessionRepo.IsAccessTokenRevoked(ctx, claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, session.ErrAccessTokenRevoked
+	}
+
+	// Desativar ou remover o terminal, ou trocar o token do dispositivo,
+	// encerra as sessões abertas nele.
+	if claims.TerminalID != nil {
+		t, err := s.terminalRepo.GetByID(ctx, *claims.TerminalID)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil || !t.Active || parsed.TerminalKey != terminalKey(t) {
+			return nil, session.ErrAccessTokenRevoked
+		}
+	}
+	return claims, nil
+}
+
+// VerifyAPIKey autentica uma integração pela chave de API. O prefixo localiza
+// a chave e o hash confirma o segredo; o último uso é gravado no máximo uma
+// vez por minuto.
+func (s *authService) VerifyAPIKey(ctx context.Context, key string) (*session.Claims, error) {
+	prefix, ok := apikey.ParsePrefix(key)
+	if !ok {
+		return nil, apikey.ErrAPIKeyInvalid
+	}
+
+	k, err := s.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil || !k.Matches(key) {
+		return nil, apikey.ErrAPIKeyInvalid
+	}
+	if k.IsRevoked() {
+		return nil, apikey.ErrAPIKeyRevoked
+	}
+
+	if now := time.Now(); k.ShouldTouch(now) {
+		if err := s.apiKeyRepo.UpdateLastUsed(ctx, k.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return &session.Claims{
+		Username: k.Name,
+		APIKeyID: &k.ID,
+		Scopes:   k.Scopes,
+	}, nil
+}
+
+func (s *authService) issue(ctx context.Context, u *user.User, familyID uuid.UUID, now time.Time) (*session.TokenPair, error) {
+	tokenID := uuid.New()
+	accessExpiresAt := now.Add(s.policy.AccessTokenTTL)
+
+	accessToken, err := s.signAccessToken(u, tokenID, familyID, nil, now, accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := session.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessionRepo.Create(ctx, &session.RefreshToken{
+		FamilyID:             familyID,
+		UserID:               u.ID,
+		TokenHash:            session.HashToken(refreshToken),
+		AccessTokenID:        tokenID,
+		AccessTokenExpiresAt: accessExpiresAt,
+		ExpiresAt:            now.Add(s.policy.RefreshTokenTTL),
+		CreatedAt:            now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &session.TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    session.TokenType,
+		ExpiresAt:    accessExpiresAt,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authService) signAccessToken(u *user.User, tokenID, sessionID uuid.UUID, t *terminal.Terminal, now, expiresAt time.Time) (string, error) {
+	claims := accessClaims{
+		Username:  u.Username,
+		Role:      string(u.Role),
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID.String(),
+			Subject:   u.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	if t != nil {
+		claims.TerminalID = t.ID.String()
+		claims.TerminalKey = terminalKey(t)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.policy.Secret))
+}
+
+// failLogin conta a falha e devolve o erro de credenciais.
+func (s *authService) failLogin(ctx context.Context, attempt session.LoginAttempt, u *user.User) error {
+	if u != nil {
+		attempt.UserID = &u.ID
+	}
+	if err := s.guard.Fail(ctx, attempt); err != nil {
+		return err
+	}
+	return user.ErrInvalidCredentials
+}
+
+// authenticateTerminal identifica o terminal pelo token do dispositivo.
+func (s *authService) authenticateTerminal(ctx context.Context, deviceToken string) (*terminal.Terminal, error) {
+	if deviceToken == "" {
+		return nil, terminal.ErrDeviceTokenInvalid
+	}
+	t, err := s.terminalRepo.GetByTokenHash(ctx, terminal.HashDeviceToken(deviceToken))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || !t.Active {
+		return nil, terminal.ErrDeviceTokenInvalid
+	}
+	return t, nil
+}
+
+// terminalKey identifica o token do dispositivo sem expô-lo: é um trecho do
+// hash guardado no cadastro, que muda a cada troca do token.
+func terminalKey(t *terminal.Terminal) string {
+	if len(t.TokenHash) < 16 {
+		return t.TokenHash
+	}
+	return t.TokenHash[:16]
+}
+
+func (s *authService) revokeReused(ctx context.Context, familyID uuid.UUID, now time.Time) error {
+	if err := s.sessionRepo.RevokeFamily(ctx, familyID, now); err != nil {
+		return err
+	}
+	return session.ErrRefreshTokenReused
+}
+
+// claims converte o JWT; tokens sem jti ou sid (emitidos antes das sessões)
+// são recusados.
+func (c accessClaims) claims() (*session.Claims, error) {
+	tokenID, err := uuid.Parse(c.ID)
+	if err != nil {
+		return nil, session.ErrAccessTokenInvalid
+	}
+	sessionID, err := uuid.Parse(c.SessionID)
+	if err != nil {
+		return nil, session.ErrAccessTokenInvalid
+	}
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, session.ErrAccessTokenInvalid
+	}
+	claims := &session.Claims{
+		TokenID:   tokenID,
+		SessionID: sessionID,
+		UserID:    userID,
+		Username:  c.Username,
+		Role:      user.Role(c.Role),
+		ExpiresAt: c.ExpiresAt.Time,
+	}
+	if c.TerminalID != "" {
+		terminalID, err := uuid.Parse(c.TerminalID)
+		if err != nil {
+			return nil, session.ErrAccessTokenInvalid
+		}
+		claims.TerminalID = &terminalID
+	}
+	return claims, nil
+}
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, t *session.RefreshToken) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetByHash(ctx context.Context, tokenHash string) (*session.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	t := args.Get(0)
+	if t == nil {
+		return nil, args.Error(1)
+	}
+	return t.(*session.RefreshToken), args.Error(1)
+}
+
+func (m *MockSessionRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, familyID, at)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAccessToken(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error {
+	args := m.Called(ctx, tokenID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tokenID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, k *apikey.APIKey) error {
+	args := m.Called(ctx, k)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	args := m.Called(ctx, id)
+	k := args.Get(0)
+	if k == nil {
+		return nil, args.Error(1)
+	}
+	return k.(*apikey.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	k := args.Get(0)
+	if k == nil {
+		return nil, args.Error(1)
+	}
+	return k.(*apikey.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Update(ctx context.Context, k *apikey.APIKey) error {
+	args := m.Called(ctx, k)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*apikey.APIKey), args.Error(1)
+}
+
+type MockTerminalRepository struct {
+	mock.Mock
+}
+
+func (m *MockTerminalRepository) Create(ctx context.Context, t *terminal.Terminal) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTerminalRepository) GetByID(ctx context.Context, id uuid.UUID) (*terminal.Terminal, error) {
+	args := m.Called(ctx, id)
+	t := args.Get(0)
+	if t == nil {
+		return nil, args.Error(1)
+	}
+	return t.(*terminal.Terminal), args.Error(1)
+}
+
+func (m *MockTerminalRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*terminal.Terminal, error) {
+	args := m.Called(ctx, tokenHash)
+	t := args.Get(0)
+	if t == nil {
+		return nil, args.Error(1)
+	}
+	return t.(*terminal.Terminal), args.Error(1)
+}
+
+func (m *MockTerminalRepository) Update(ctx context.Context, t *terminal.Terminal) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTerminalRepository) UpdateLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockTerminalRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTerminalRepository) List(ctx context.Context) ([]*terminal.Terminal, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*terminal.Terminal), args.Error(1)
+}
+
+type MockLoginGuard struct {
+	mock.Mock
+}
+
+func (m *MockLoginGuard) Reserve(ctx context.Context, attempt session.LoginAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+func (m *MockLoginGuard) Fail(ctx context.Context, attempt session.LoginAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+func (m *MockLoginGuard) Succeed(ctx context.Context, attempt session.LoginAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+// newAllowingGuard libera todas as tentativas.
+func newAllowingGuard() *MockLoginGuard {
+	guard := new(MockLoginGuard)
+	guard.On("Reserve", mock.Anything, mock.Anything).Return(nil)
+	guard.On("Fail", mock.Anything, mock.Anything).Return(nil)
+	guard.On("Succeed", mock.Anything, mock.Anything).Return(nil)
+	return guard
+}
+
+var testTokenPolicy = TokenPolicy{Secret: "test_secret", AccessTokenTTL: 15 * time.Minute, RefreshTokenTTL: time.Hour, OperatorSessionTTL: 30 * time.Minute}
+
+func TestAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	service := NewAuthService(userRepo, sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	cashier := newTestUser(t, user.RoleCashier, "segredo123")
+	inactive := newTestUser(t, user.RoleKitchen, "segredo123")
+	inactive.Active = false
+	userRepo.On("GetByUsername", ctx, "cashier").Return(cashier, nil)
+	userRepo.On("GetByUsername", ctx, "kitchen").Return(inactive, nil)
+	userRepo.On("GetByUsername", ctx, "nobody").Return(nil, nil)
+	sessionRepo.On("DeleteExpired", ctx, mock.AnythingOfType("time.Time")).Return(nil)
+	sessionRepo.On("Create", ctx, mock.AnythingOfType("*session.RefreshToken")).Return(nil)
+
+	tokens, err := service.Login(ctx, " Cashier ", "segredo123", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, session.TokenType, tokens.TokenType)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	stored := sessionRepo.Calls[1].Arguments.Get(1).(*session.RefreshToken)
+	assert.Equal(t, session.HashToken(tokens.RefreshToken), stored.TokenHash)
+	assert.Equal(t, cashier.ID, stored.UserID)
+
+	sessionRepo.On("IsAccessTokenRevoked", ctx, stored.AccessTokenID).Return(false, nil)
+	claims, err := service.VerifyAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, cashier.ID, claims.UserID)
+	assert.Equal(t, user.RoleCashier, claims.Role)
+	assert.Equal(t, stored.FamilyID, claims.SessionID)
+
+	for _, username := range []string{"kitchen", "nobody"} {
+		_, err = service.Login(ctx, username, "segredo123", "10.0.0.1")
+		assert.Equal(t, user.ErrInvalidCredentials, err)
+	}
+	_, err = service.Login(ctx, "cashier", "errada123", "10.0.0.1")
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+}
+
+func TestAuthService_RefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	service := NewAuthService(userRepo, sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	cashier := newTestUser(t, user.RoleCashier, "segredo123")
+	current := &session.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), UserID: cashier.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	sessionRepo.On("GetByHash", ctx, session.HashToken("atual")).Return(current, nil)
+	sessionRepo.On("MarkUsed", ctx, current.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	userRepo.On("GetByID", ctx, cashier.ID).Return(cashier, nil)
+	sessionRepo.On("Create", ctx, mock.MatchedBy(func(t *session.RefreshToken) bool {
+		return t.FamilyID == current.FamilyID && t.TokenHash != session.HashToken("atual")
+	})).Return(nil)
+
+	tokens, err := service.Refresh(ctx, "atual")
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "atual", tokens.RefreshToken)
+	sessionRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_RefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	service := NewAuthService(new(MockUserRepository), sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	usedAt := time.Now().Add(-time.Minute)
+	used := &session.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}
+	sessionRepo.On("GetByHash", ctx, session.HashToken("usado")).Return(used, nil)
+	sessionRepo.On("RevokeFamily", ctx, used.FamilyID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	_, err := service.Refresh(ctx, "usado")
+	assert.Equal(t, session.ErrRefreshTokenReused, err)
+
+	// Dois pedidos simultâneos com o mesmo token: só um consegue marcá-lo.
+	raced := &session.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	sessionRepo.On("GetByHash", ctx, session.HashToken("concorrente")).Return(raced, nil)
+	sessionRepo.On("MarkUsed", ctx, raced.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+	sessionRepo.On("RevokeFamily", ctx, raced.FamilyID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	_, err = service.Refresh(ctx, "concorrente")
+	assert.Equal(t, session.ErrRefreshTokenReused, err)
+	sessionRepo.AssertExpectations(t)
+
+	expired := &session.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), ExpiresAt: time.Now().Add(-time.Second)}
+	sessionRepo.On("GetByHash", ctx, session.HashToken("expirado")).Return(expired, nil)
+	sessionRepo.On("GetByHash", ctx, session.HashToken("desconhecido")).Return(nil, nil)
+	for _, token := range []string{"expirado", "desconhecido"} {
+		_, err = service.Refresh(ctx, token)
+		assert.Equal(t, session.ErrRefreshTokenInvalid, err)
+	}
+}
+
+func TestAuthService_VerifyAccessToken_Revoked(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	service := NewAuthService(userRepo, sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	owner := newTestUser(t, user.RoleOwner, "segredo123")
+	userRepo.On("GetByUsername", ctx, "owner").Return(owner, nil)
+	sessionRepo.On("DeleteExpired", ctx, mock.Anything).Return(nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+	sessionRepo.On("IsAccessTokenRevoked", ctx, mock.Anything).Return(true, nil)
+
+	tokens, err := service.Login(ctx, "owner", "segredo123", "10.0.0.1")
+	require.NoError(t, err)
+
+	_, err = service.VerifyAccessToken(ctx, tokens.AccessToken)
+	assert.Equal(t, session.ErrAccessTokenRevoked, err)
+
+	other := NewAuthService(userRepo, sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), TokenPolicy{Secret: "outro_segredo", AccessTokenTTL: time.Minute})
+	_, err = other.VerifyAccessToken(ctx, tokens.AccessToken)
+	assert.Equal(t, session.ErrAccessTokenInvalid, err)
+}
+
+func TestAuthService_OperatorLogin(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	terminalRepo := new(MockTerminalRepository)
+	service := NewAuthService(userRepo, sessionRepo, terminalRepo, new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	counter := &terminal.Terminal{ID: uuid.New(), Name: "Balcão", Active: true}
+	terminalRepo.On("GetByTokenHash", ctx, terminal.HashDeviceToken("tablet")).Return(counter, nil)
+	terminalRepo.On("GetByTokenHash", ctx, terminal.HashDeviceToken("outro")).Return(nil, nil)
+	terminalRepo.On("UpdateLastSeen", ctx, counter.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	cashier := newTestUser(t, user.RoleCashier, "segredo123")
+	require.NoError(t, cashier.SetPIN("2468"))
+	userRepo.On("GetByUsername", ctx, "cashier").Return(cashier, nil)
+
+	_, err := service.OperatorLogin(ctx, "outro", "cashier", "2468", "10.0.0.1")
+	assert.Equal(t, terminal.ErrDeviceTokenInvalid, err)
+	_, err = service.OperatorLogin(ctx, "tablet", "cashier", "1357", "10.0.0.1")
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+
+	tokens, err := service.OperatorLogin(ctx, "tablet", "cashier", "2468", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, tokens.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), tokens.ExpiresAt, time.Minute)
+
+	sessionRepo.On("IsAccessTokenRevoked", ctx, mock.Anything).Return(false, nil)
+	terminalRepo.On("GetByID", ctx, counter.ID).Return(counter, nil).Once()
+	claims, err := service.VerifyAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, cashier.ID, claims.UserID)
+	assert.Equal(t, &counter.ID, claims.TerminalID)
+
+	// O terminal desativado derruba a sessão do operador.
+	terminalRepo.On("GetByID", ctx, counter.ID).Return(&terminal.Terminal{ID: counter.ID, Active: false}, nil).Once()
+	_, err = service.VerifyAccessToken(ctx, tokens.AccessToken)
+	assert.Equal(t, session.ErrAccessTokenRevoked, err)
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_LogoutRevokesCurrentToken(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	service := NewAuthService(new(MockUserRepository), sessionRepo, new(MockTerminalRepository), new(MockAPIKeyRepository), newAllowingGuard(), testTokenPolicy)
+
+	claims := &session.Claims{TokenID: uuid.New(), SessionID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}
+	sessionRepo.On("RevokeFamily", ctx, claims.SessionID, mock.AnythingOfType("time.Time")).Return(nil)
+	sessionRepo.On("RevokeAccessToken", ctx, claims.TokenID, claims.ExpiresAt).Return(nil)
+
+	assert.NoError(t, service.Logout(ctx, claims))
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_LoginGuard(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	guard := new(MockLoginGuard)
+	service := NewAuthService(userRepo, new(MockSessionRepository), new(MockTerminalRepository), new(MockAPIKeyRepository), guard, testTokenPolicy)
+
+	cashier := newTestUser(t, user.RoleCashier, "segredo123")
+	userRepo.On("GetByUsername", ctx, "cashier").Return(cashier, nil)
+
+	attempt := session.LoginAttempt{Method: session.LoginMethodPassword, IP: "10.0.0.1", Username: "cashier"}
+	guard.On("Reserve", ctx, attempt).Return(nil).Once()
+	failed := attempt
+	failed.UserID = &cashier.ID
+	guard.On("Fail", ctx, failed).Return(nil).Once()
+
+	_, err := service.Login(ctx, "Cashier", "errada123", "10.0.0.1")
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+
+	// Bloqueado, a senha nem é conferida.
+	locked := &ratelimit.LockedError{Until: time.Now().Add(time.Minute)}
+	guard.On("Reserve", ctx, attempt).Return(locked).Once()
+	_, err = service.Login(ctx, "cashier", "segredo123", "10.0.0.1")
+	assert.Equal(t, locked, err)
+
+	guard.AssertExpectations(t)
+	userRepo.AssertNumberOfCalls(t, "GetByUsername", 1)
+}
+
+func TestAuthService_VerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+	apiKeyRepo := new(MockAPIKeyRepository)
+	service := NewAuthService(new(MockUserRepository), new(MockSessionRepository), new(MockTerminalRepository), apiKeyRepo, newAllowingGuard(), testTokenPolicy)
+
+	key, prefix, err := apikey.NewKey()
+	require.NoError(t, err)
+	stored := &apikey.APIKey{ID: uuid.New(), Name: "ponte-delivery", Prefix: prefix, KeyHash: apikey.HashKey(key), Scopes: []user.Permission{user.PermissionSalesWrite}}
+	apiKeyRepo.On("GetByPrefix", ctx, prefix).Return(stored, nil)
+	apiKeyRepo.On("UpdateLastUsed", ctx, stored.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	claims, err := service.VerifyAPIKey(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, claims.IsAPIKey())
+	assert.Equal(t, "ponte-delivery", claims.Username)
+	assert.True(t, claims.Can(user.PermissionSalesWrite))
+	assert.False(t, claims.Can(user.PermissionSalesRead))
+
+	// Usada há menos de um minuto, a chave não gera nova escrita.
+	recently := time.Now()
+	stored.LastUsedAt = &recently
+	_, err = service.VerifyAPIKey(ctx, key)
+	require.NoError(t, err)
+	apiKeyRepo.AssertNumberOfCalls(t, "UpdateLastUsed", 1)
+
+	// O prefixo certo com outro segredo não passa.
+	_, err = service.VerifyAPIKey(ctx, apikey.KeyPrefix+"_"+prefix+"_outro")
+	assert.Equal(t, apikey.ErrAPIKeyInvalid, err)
+
+	_, err = service.VerifyAPIKey(ctx, "sem-formato")
+	assert.Equal(t, apikey.ErrAPIKeyInvalid, err)
+
+	revokedAt := time.Now()
+	stored.RevokedAt = &revokedAt
+	_, err = service.VerifyAPIKey(ctx, key)
+	assert.Equal(t, apikey.ErrAPIKeyRevoked, err)
+
+	assert.Equal(t, apikey.ErrLogoutUnsupported, service.Logout(ctx, claims))
+}
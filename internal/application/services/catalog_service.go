@@ -0,0 +1,559 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/catalog"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/station"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CatalogService interface {
+	// ExportCatalog grava em w as categorias, os produtos e os acréscimos em
+	// ordem de nome, no formato que ImportCatalog lê.
+	ExportCatalog(ctx context.Context, w io.Writer, format catalog.Format) error
+	// ImportCatalog cria ou atualiza os itens do arquivo, procurando cada um
+	// pelo código externo ou pelo nome. Com algum erro de linha, ou com
+	// dryRun, devolve o relatório sem gravar nada.
+	ImportCatalog(ctx context.Context, r io.Reader, format catalog.Format, dryRun bool) (*catalog.ImportResult, error)
+}
+
+type catalogService struct {
+	catalogRepo  catalog.Repository
+	categoryRepo category.Repository
+	productRepo  product.Repository
+	additionRepo addition.Repository
+	stationRepo  station.Repository
+	menu         MenuInvalidator
+}
+
+func NewCatalogService(catalogRepo catalog.Repository, categoryRepo category.Repository, productRepo product.Repository, additionRepo addition.Repository, stationRepo station.Repository, menu MenuInvalidator) CatalogService {
+	return &catalogService{
+		catalogRepo:  catalogRepo,
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		additionRepo: additionRepo,
+		stationRepo:  stationRepo,
+		menu:         menu,
+	}
+}
+
+func (s *catalogService) ExportCatalog(ctx context.Context, w io.Writer, format catalog.Format) error {
+	if !format.IsValid() {
+		return catalog.ErrFormatInvalid
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	products, err := s.productRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	additions, err := s.additionRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	stations, err := s.stationRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	stationNames := make(map[uuid.UUID]string, len(stations))
+	for _, st := range stations {
+		stationNames[st.ID] = st.Name
+	}
+	categoriesByID := make(map[uuid.UUID]*category.Category, len(categories))
+	for _, c := range categories {
+		categoriesByID[c.ID] = c
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return byName(categories[i].Name, categories[i].ID, categories[j].Name, categories[j].ID)
+	})
+	sort.Slice(products, func(i, j int) bool {
+		return byName(products[i].Name, products[i].ID, products[j].Name, products[j].ID)
+	})
+	sort.Slice(additions, func(i, j int) bool {
+		return byName(additions[i].Name, additions[i].ID, additions[j].Name, additions[j].ID)
+	})
+
+	doc := &catalog.Catalog{
+		Categories: make([]catalog.CategoryEntry, 0, len(categories)),
+		Products:   make([]catalog.ProductEntry, 0, len(products)),
+		Additions:  make([]catalog.AdditionEntry, 0, len(additions)),
+	}
+	for _, c := range categories {
+		entry := catalog.CategoryEntry{
+			ExternalCode: c.ExternalCode,
+			Name:         c.Name,
+			Description:  c.Description,
+			FiscalData:   c.FiscalData,
+		}
+		if c.StationID != nil {
+			entry.Station = stationNames[*c.StationID]
+		}
+		doc.Categories = append(doc.Categories, entry)
+	}
+	for _, p := range products {
+		entry := catalog.ProductEntry{
+			ExternalCode: p.ExternalCode,
+			Name:         p.Name,
+			Price:        p.Price,
+			UnitCost:     p.UnitCost,
+			Description:  p.Description,
+			FiscalData:   p.FiscalData,
+		}
+		if c, ok := categoriesByID[p.CategoryID]; ok {
+			entry.Category = c.Name
+			entry.CategoryCode = c.ExternalCode
+		}
+		doc.Products = append(doc.Products, entry)
+	}
+	for _, a := range additions {
+		doc.Additions = append(doc.Additions, catalog.AdditionEntry{
+			ExternalCode: a.ExternalCode,
+			Name:         a.Name,
+			Price:        a.Price,
+			UnitCost:     a.UnitCost,
+		})
+	}
+	return catalog.Write(w, format, doc)
+}
+
+// byName ordena pelo nome normalizado e, no empate, pelo ID, para que duas
+// exportações do mesmo cardápio sejam iguais.
+func byName(nameA string, idA uuid.UUID, nameB string, idB uuid.UUID) bool {
+	if a, b := catalog.Key(nameA), catalog.Key(nameB); a != b {
+		return a < b
+	}
+	return idA.String() < idB.String()
+}
+
+func (s *catalogService) ImportCatalog(ctx context.Context, r io.Reader, format catalog.Format, dryRun bool) (*catalog.ImportResult, error) {
+	if !format.IsValid() {
+		return nil, catalog.ErrFormatInvalid
+	}
+	doc, rowErrors, err := catalog.Read(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, err := s.newCatalogImport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imp.result.DryRun = dryRun
+	imp.result.Errors = append(imp.result.Errors, rowErrors...)
+	for i := range doc.Categories {
+		if err := imp.category(&doc.Categories[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Products {
+		if err := imp.product(&doc.Products[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Additions {
+		if err := imp.addition(&doc.Additions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	result := imp.result
+	if len(result.Errors) > 0 || dryRun || imp.plan.IsEmpty() {
+		for i := range result.Items {
+			if result.Items[i].Action == catalog.ActionCreate {
+				result.Items[i].ID = nil
+			}
+		}
+		return result, nil
+	}
+	if err := s.catalogRepo.Apply(ctx, imp.plan); err != nil {
+		return nil, err
+	}
+	invalidateMenu(s.menu)
+	result.Applied = true
+	return result, nil
+}
+
+// catalogImport confere as linhas do arquivo contra o cadastro e monta o
+// plano de gravação e o relatório. Os itens criados ou alterados entram nos
+// índices na hora, para que as linhas seguintes os encontrem.
+type catalogImport struct {
+	ctx        context.Context
+	categories map[uuid.UUID]*category.Category
+	products   map[uuid.UUID]*product.Product
+	additions  map[uuid.UUID]*addition.Addition
+	stations   map[string][]uuid.UUID
+
+	categoryIndex *catalogIndex
+	productIndex  *catalogIndex
+	additionIndex *catalogIndex
+
+	plan   *catalog.Plan
+	result *catalog.ImportResult
+}
+
+func (s *catalogService) newCatalogImport(ctx context.Context) (*catalogImport, error) {
+	imp := &catalogImport{
+		ctx:           ctx,
+		categories:    make(map[uuid.UUID]*category.Category),
+		products:      make(map[uuid.UUID]*product.Product),
+		additions:     make(map[uuid.UUID]*addition.Addition),
+		stations:      make(map[string][]uuid.UUID),
+		categoryIndex: newCatalogIndex(),
+		productIndex:  newCatalogIndex(),
+		additionIndex: newCatalogIndex(),
+		plan: &catalog.Plan{
+			PreviousCategories: make(map[uuid.UUID]*category.Category),
+			PreviousProducts:   make(map[uuid.UUID]*product.Product),
+			PreviousAdditions:  make(map[uuid.UUID]*addition.Addition),
+		},
+		result: &catalog.ImportResult{Items: []catalog.ImportItem{}, Errors: []catalog.RowError{}},
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range categories {
+		imp.categories[c.ID] = c
+		imp.categoryIndex.add(c.ID, c.ExternalCode, c.Name)
+	}
+	products, err := s.productRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		imp.products[p.ID] = p
+		imp.productIndex.add(p.ID, p.ExternalCode, p.Name)
+	}
+	additions, err := s.additionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range additions {
+		imp.additions[a.ID] = a
+		imp.additionIndex.add(a.ID, a.ExternalCode, a.Name)
+	}
+	stations, err := s.stationRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range stations {
+		key := catalog.Key(st.Name)
+		imp.stations[key] = append(imp.stations[key], st.ID)
+	}
+	return imp, nil
+}
+
+func (imp *catalogImport) category(e *catalog.CategoryEntry) error {
+	e.Name, e.ExternalCode = strings.TrimSpace(e.Name), strings.TrimSpace(e.ExternalCode)
+	id, ok := imp.match(imp.categoryIndex, catalog.EntryCategory, e.Row, e.ExternalCode, e.Name)
+	if !ok {
+		return nil
+	}
+	var existing *category.Category
+	updated := &category.Category{}
+	if id == uuid.Nil {
+		id = uuid.New()
+	} else {
+		existing = imp.categories[id]
+		copied := *existing
+		updated = &copied
+	}
+	updated.ID = id
+	updated.Name = e.Name
+	updated.Description = e.Description
+	updated.FiscalData = e.FiscalData
+	if e.ExternalCode != "" {
+		updated.ExternalCode = e.ExternalCode
+	}
+	updated.StationID = nil
+	if station := strings.TrimSpace(e.Station); station != "" {
+		ids := imp.stations[catalog.Key(station)]
+		switch len(ids) {
+		case 0:
+			imp.fail(catalog.EntryCategory, e.Row, e.Name, fmt.Sprintf("estação %q não encontrada", station))
+			return nil
+		case 1:
+			updated.StationID = &ids[0]
+		default:
+			imp.fail(catalog.EntryCategory, e.Row, e.Name, fmt.Sprintf("há %d estações com o nome %q", len(ids), station))
+			return nil
+		}
+	}
+	if err := updated.Validate(); err != nil {
+		imp.fail(catalog.EntryCategory, e.Row, e.Name, err.Error())
+		return nil
+	}
+
+	imp.categories[id] = updated
+	imp.categoryIndex.add(id, updated.ExternalCode, updated.Name)
+	imp.categoryIndex.rows[id] = e.Row
+	action, err := imp.record(catalog.EntryCategory, e.Row, id, updated.Name, audit.EntityCategory, existing == nil, existing, updated)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case catalog.ActionCreate:
+		imp.plan.NewCategories = append(imp.plan.NewCategories, updated)
+	case catalog.ActionUpdate:
+		imp.plan.UpdatedCategories = append(imp.plan.UpdatedCategories, updated)
+		previous := *existing
+		imp.plan.PreviousCategories[id] = &previous
+	}
+	return nil
+}
+
+func (imp *catalogImport) product(e *catalog.ProductEntry) error {
+	e.Name, e.ExternalCode = strings.TrimSpace(e.Name), strings.TrimSpace(e.ExternalCode)
+	id, ok := imp.match(imp.productIndex, catalog.EntryProduct, e.Row, e.ExternalCode, e.Name)
+	if !ok {
+		return nil
+	}
+	var existing *product.Product
+	updated := &product.Product{}
+	if id == uuid.Nil {
+		id = uuid.New()
+	} else {
+		existing = imp.products[id]
+		copied := *existing
+		updated = &copied
+	}
+	updated.ID = id
+	updated.Name = e.Name
+	updated.Price = e.Price
+	updated.UnitCost = e.UnitCost
+	updated.Description = e.Description
+	updated.FiscalData = e.FiscalData
+	if e.ExternalCode != "" {
+		updated.ExternalCode = e.ExternalCode
+	}
+	categoryID, err := imp.categoryIndex.resolve(strings.TrimSpace(e.CategoryCode), strings.TrimSpace(e.Category))
+	if err != nil {
+		imp.fail(catalog.EntryProduct, e.Row, e.Name, err.Error())
+		return nil
+	}
+	updated.CategoryID = categoryID
+	if err := updated.Validate(); err != nil {
+		imp.fail(catalog.EntryProduct, e.Row, e.Name, err.Error())
+		return nil
+	}
+
+	imp.products[id] = updated
+	imp.productIndex.add(id, updated.ExternalCode, updated.Name)
+	imp.productIndex.rows[id] = e.Row
+	action, err := imp.record(catalog.EntryProduct, e.Row, id, updated.Name, audit.EntityProduct, existing == nil, existing, updated)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case catalog.ActionCreate:
+		imp.plan.NewProducts = append(imp.plan.NewProducts, updated)
+		imp.plan.PriceChanges = append(imp.plan.PriceChanges, newPriceChange(imp.ctx, pricing.ItemProduct, id, nil, updated.Price))
+	case catalog.ActionUpdate:
+		imp.plan.UpdatedProducts = append(imp.plan.UpdatedProducts, updated)
+		previous := *existing
+		imp.plan.PreviousProducts[id] = &previous
+		if updated.Price != existing.Price {
+			oldPrice := existing.Price
+			imp.plan.PriceChanges = append(imp.plan.PriceChanges, newPriceChange(imp.ctx, pricing.ItemProduct, id, &oldPrice, updated.Price))
+		}
+	}
+	return nil
+}
+
+func (imp *catalogImport) addition(e *catalog.AdditionEntry) error {
+	e.Name, e.ExternalCode = strings.TrimSpace(e.Name), strings.TrimSpace(e.ExternalCode)
+	id, ok := imp.match(imp.additionIndex, catalog.EntryAddition, e.Row, e.ExternalCode, e.Name)
+	if !ok {
+		return nil
+	}
+	var existing *addition.Addition
+	updated := &addition.Addition{}
+	if id == uuid.Nil {
+		id = uuid.New()
+	} else {
+		existing = imp.additions[id]
+		copied := *existing
+		updated = &copied
+	}
+	updated.ID = id
+	updated.Name = e.Name
+	updated.Price = e.Price
+	updated.UnitCost = e.UnitCost
+	if e.ExternalCode != "" {
+		updated.ExternalCode = e.ExternalCode
+	}
+	if err := updated.Validate(); err != nil {
+		imp.fail(catalog.EntryAddition, e.Row, e.Name, err.Error())
+		return nil
+	}
+
+	imp.additions[id] = updated
+	imp.additionIndex.add(id, updated.ExternalCode, updated.Name)
+	imp.additionIndex.rows[id] = e.Row
+	action, err := imp.record(catalog.EntryAddition, e.Row, id, updated.Name, audit.EntityAddition, existing == nil, existing, updated)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case catalog.ActionCreate:
+		imp.plan.NewAdditions = append(imp.plan.NewAdditions, updated)
+		imp.plan.PriceChanges = append(imp.plan.PriceChanges, newPriceChange(imp.ctx, pricing.ItemAddition, id, nil, updated.Price))
+	case catalog.ActionUpdate:
+		imp.plan.UpdatedAdditions = append(imp.plan.UpdatedAdditions, updated)
+		previous := *existing
+		imp.plan.PreviousAdditions[id] = &previous
+		if updated.Price != existing.Price {
+			oldPrice := existing.Price
+			imp.plan.PriceChanges = append(imp.plan.PriceChanges, newPriceChange(imp.ctx, pricing.ItemAddition, id, &oldPrice, updated.Price))
+		}
+	}
+	return nil
+}
+
+// match procura o item da linha no índice e confere que a linha não repete
+// um item de outra linha. Devolve uuid.Nil para um item novo; ok é falso
+// quando a linha tem erro.
+func (imp *catalogImport) match(index *catalogIndex, entryType catalog.EntryType, row int, code, name string) (uuid.UUID, bool) {
+	id, err := index.match(code, name)
+	if err != nil {
+		imp.fail(entryType, row, name, err.Error())
+		return uuid.Nil, false
+	}
+	if id == uuid.Nil {
+		return uuid.Nil, true
+	}
+	if previous, repeated := index.rows[id]; repeated {
+		imp.fail(entryType, row, name, fmt.Sprintf("o item já aparece na linha %d", previous))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// record inclui a linha no relatório e prepara a auditoria.
+func (imp *catalogImport) record(entryType catalog.EntryType, row int, id uuid.UUID, name string, entityType audit.EntityType, created bool, before, after any) (catalog.Action, error) {
+	action, auditAction := catalog.ActionCreate, audit.ActionCreate
+	if created {
+		before = nil
+	} else {
+		action, auditAction = catalog.ActionUpdate, audit.ActionUpdate
+		if reflect.DeepEqual(before, after) {
+			action = catalog.ActionUnchanged
+		}
+	}
+
+	itemID := id
+	imp.result.Items = append(imp.result.Items, catalog.ImportItem{Type: entryType, Row: row, ID: &itemID, Name: name, Action: action})
+	switch action {
+	case catalog.ActionCreate:
+		imp.result.Created++
+	case catalog.ActionUpdate:
+		imp.result.Updated++
+	default:
+		imp.result.Unchanged++
+		return action, nil
+	}
+
+	entry, err := audit.NewEntry(imp.ctx, auditAction, entityType, id, before, after)
+	if err != nil {
+		return "", err
+	}
+	imp.plan.Entries = append(imp.plan.Entries, entry)
+	return action, nil
+}
+
+func (imp *catalogImport) fail(entryType catalog.EntryType, row int, name, message string) {
+	imp.result.Errors = append(imp.result.Errors, catalog.RowError{Type: entryType, Row: row, Name: name, Error: message})
+}
+
+// catalogIndex localiza os itens de um tipo pelo código externo e pelo nome
+// normalizado. rows guarda a linha do arquivo que já gravou cada item.
+type catalogIndex struct {
+	byCode map[string]uuid.UUID
+	byName map[string][]uuid.UUID
+	codes  map[uuid.UUID]string
+	rows   map[uuid.UUID]int
+}
+
+func newCatalogIndex() *catalogIndex {
+	return &catalogIndex{
+		byCode: make(map[string]uuid.UUID),
+		byName: make(map[string][]uuid.UUID),
+		codes:  make(map[uuid.UUID]string),
+		rows:   make(map[uuid.UUID]int),
+	}
+}
+
+func (ix *catalogIndex) add(id uuid.UUID, code, name string) {
+	if code != "" {
+		ix.byCode[code] = id
+		ix.codes[id] = code
+	}
+	key := catalog.Key(name)
+	for _, known := range ix.byName[key] {
+		if known == id {
+			return
+		}
+	}
+	ix.byName[key] = append(ix.byName[key], id)
+}
+
+// match procura primeiro pelo código e depois pelo nome. Um item encontrado
+// pelo nome que já tem outro código é um erro, e não uma atualização, para
+// que uma troca de código não junte dois itens diferentes.
+func (ix *catalogIndex) match(code, name string) (uuid.UUID, error) {
+	if code != "" {
+		if id, ok := ix.byCode[code]; ok {
+			return id, nil
+		}
+	}
+	ids := ix.byName[catalog.Key(name)]
+	switch {
+	case len(ids) == 0:
+		return uuid.Nil, nil
+	case len(ids) > 1:
+		return uuid.Nil, fmt.Errorf("há %d itens com o nome %q; informe o código externo", len(ids), name)
+	}
+	if known := ix.codes[ids[0]]; code != "" && known != "" && known != code {
+		return uuid.Nil, fmt.Errorf("o nome %q já pertence ao item de código %q", name, known)
+	}
+	return ids[0], nil
+}
+
+// resolve localiza o item referenciado por outra linha, como a categoria de
+// um produto.
+func (ix *catalogIndex) resolve(code, name string) (uuid.UUID, error) {
+	if code != "" {
+		if id, ok := ix.byCode[code]; ok {
+			return id, nil
+		}
+		return uuid.Nil, fmt.Errorf("categoria de código %q não encontrada", code)
+	}
+	if name == "" {
+		return uuid.Nil, product.ErrProductCategoryID
+	}
+	ids := ix.byName[catalog.Key(name)]
+	switch len(ids) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("categoria %q não encontrada", name)
+	case 1:
+		return ids[0], nil
+	}
+	return uuid.Nil, fmt.Errorf("há %d categorias com o nome %q; informe o código da categoria", len(ids), name)
+}
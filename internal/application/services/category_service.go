@@ -1,7 +1,9 @@
 package services
 
 import (
+	"andressa-lanches/internal/domain/audit"
 	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/station"
 	"context"
 
 	"github.com/google/uuid"
@@ -17,11 +19,15 @@ type CategoryService interface {
 
 type categoryService struct {
 	categoryRepo category.Repository
+	stationRepo  station.Repository
+	menu         MenuInvalidator
 }
 
-func NewCategoryService(categoryRepo category.Repository) CategoryService {
+func NewCategoryService(categoryRepo category.Repository, stationRepo station.Repository, menu MenuInvalidator) CategoryService {
 	return &categoryService{
 		categoryRepo: categoryRepo,
+		stationRepo:  stationRepo,
+		menu:         menu,
 	}
 }
 
@@ -29,8 +35,22 @@ func (s *categoryService) CreateCategory(ctx context.Context, c *category.Catego
 	if err := c.Validate(); err != nil {
 		return err
 	}
+	if err := s.validateStation(ctx, c); err != nil {
+		return err
+	}
 
-	return s.categoryRepo.Create(ctx, c)
+	// O ID é definido antes da gravação para entrar na auditoria gravada na
+	// mesma transação.
+	c.ID = uuid.New()
+	entry, err := audit.NewEntry(ctx, audit.ActionCreate, audit.EntityCategory, c.ID, nil, c)
+	if err != nil {
+		return err
+	}
+	if err := s.categoryRepo.Create(ctx, c, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *categoryService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*category.Category, error) {
@@ -56,6 +76,9 @@ func (s *categoryService) UpdateCategory(ctx context.Context, c *category.Catego
 	if err := c.Validate(); err != nil {
 		return err
 	}
+	if err := s.validateStation(ctx, c); err != nil {
+		return err
+	}
 
 	existingCategory, err := s.categoryRepo.GetByID(ctx, c.ID)
 	if err != nil {
@@ -65,7 +88,15 @@ func (s *categoryService) UpdateCategory(ctx context.Context, c *category.Catego
 		return category.ErrCategoryNotFound
 	}
 
-	return s.categoryRepo.Update(ctx, c)
+	entry, err := audit.NewEntry(ctx, audit.ActionUpdate, audit.EntityCategory, c.ID, existingCategory, c)
+	if err != nil {
+		return err
+	}
+	if err := s.categoryRepo.Update(ctx, c, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
@@ -81,7 +112,15 @@ func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) erro
 		return category.ErrCategoryNotFound
 	}
 
-	return s.categoryRepo.Delete(ctx, id)
+	entry, err := audit.NewEntry(ctx, audit.ActionDelete, audit.EntityCategory, id, existingCategory, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.categoryRepo.Delete(ctx, id, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *categoryService) ListCategories(ctx context.Context) ([]*category.Category, error) {
@@ -91,3 +130,19 @@ func (s *categoryService) ListCategories(ctx context.Context) ([]*category.Categ
 	}
 	return categories, nil
 }
+
+// validateStation confere que a estação de preparo informada existe, para
+// que um ID desconhecido seja recusado antes de chegar ao banco.
+func (s *categoryService) validateStation(ctx context.Context, c *category.Category) error {
+	if c.StationID == nil {
+		return nil
+	}
+	st, err := s.stationRepo.GetByID(ctx, *c.StationID)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		return category.ErrStationNotFound
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"andressa-lanches/internal/domain/audit"
 	"andressa-lanches/internal/domain/category"
 	"context"
 	"testing"
@@ -14,8 +15,8 @@ type MockCategoryRepository struct {
 	mock.Mock
 }
 
-func (m *MockCategoryRepository) Create(ctx context.Context, c *category.Category) error {
-	args := m.Called(ctx, c)
+func (m *MockCategoryRepository) Create(ctx context.Context, c *category.Category, entry *audit.Entry) error {
+	args := m.Called(ctx, c, entry)
 	return args.Error(0)
 }
 
@@ -28,13 +29,13 @@ func (m *MockCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*ca
 	return c.(*category.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category) error {
-	args := m.Called(ctx, c)
+func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category, entry *audit.Entry) error {
+	args := m.Called(ctx, c, entry)
 	return args.Error(0)
 }
 
-func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
+	args := m.Called(ctx, id, entry)
 	return args.Error(0)
 }
 
@@ -46,25 +47,33 @@ func (m *MockCategoryRepository) List(ctx context.Context) ([]*category.Category
 func TestCategoryService_CreateCategory_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	testCategory := &category.Category{
 		Name:        "Bebidas",
 		Description: "Bebidas geladas",
 	}
 
-	mockRepo.On("Create", ctx, testCategory).Return(nil)
+	var entry *audit.Entry
+	mockRepo.On("Create", ctx, testCategory, mock.AnythingOfType("*audit.Entry")).Run(func(args mock.Arguments) {
+		entry = args.Get(2).(*audit.Entry)
+	}).Return(nil)
 
 	err := service.CreateCategory(ctx, testCategory)
 
 	assert.NoError(t, err)
+	// A auditoria vai para o repositório junto com a categoria.
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, audit.ActionCreate, entry.Action)
+		assert.Equal(t, testCategory.ID, *entry.EntityID)
+	}
 	mockRepo.AssertExpectations(t)
 }
 
 func TestCategoryService_CreateCategory_InvalidName(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	testCategory := &category.Category{
 		Name: "",
@@ -80,7 +89,7 @@ func TestCategoryService_CreateCategory_InvalidName(t *testing.T) {
 func TestCategoryService_GetCategoryByID_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	categoryID := uuid.New()
 	expectedCategory := &category.Category{
@@ -100,7 +109,7 @@ func TestCategoryService_GetCategoryByID_Success(t *testing.T) {
 func TestCategoryService_UpdateCategory_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	categoryID := uuid.New()
 	updatedCategory := &category.Category{
@@ -109,7 +118,7 @@ func TestCategoryService_UpdateCategory_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetByID", ctx, categoryID).Return(updatedCategory, nil)
-	mockRepo.On("Update", ctx, updatedCategory).Return(nil)
+	mockRepo.On("Update", ctx, updatedCategory, mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.UpdateCategory(ctx, updatedCategory)
 
@@ -120,12 +129,12 @@ func TestCategoryService_UpdateCategory_Success(t *testing.T) {
 func TestCategoryService_DeleteCategory_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	categoryID := uuid.New()
 
 	mockRepo.On("GetByID", ctx, categoryID).Return(&category.Category{}, nil)
-	mockRepo.On("Delete", ctx, categoryID).Return(nil)
+	mockRepo.On("Delete", ctx, categoryID, mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.DeleteCategory(ctx, categoryID)
 
@@ -136,7 +145,7 @@ func TestCategoryService_DeleteCategory_Success(t *testing.T) {
 func TestCategoryService_ListCategories_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(MockStationRepository), nil)
 
 	expectedCategories := []*category.Category{
 		{
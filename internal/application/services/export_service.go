@@ -0,0 +1,70 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/export"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"io"
+	"time"
+)
+
+type ExportService interface {
+	// ExportSales grava em w as vendas dos dias de operação de start até o
+	// dia anterior a end, uma linha por vez, com os horários no fuso da loja.
+	ExportSales(ctx context.Context, w io.Writer, start, end time.Time, opts export.Options) error
+}
+
+type exportService struct {
+	exportRepo export.Repository
+	encoder    export.Encoder
+	calendar   sale.Calendar
+}
+
+func NewExportService(exportRepo export.Repository, encoder export.Encoder, calendar sale.Calendar) ExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+		encoder:    encoder,
+		calendar:   calendar,
+	}
+}
+
+func (s *exportService) ExportSales(ctx context.Context, w io.Writer, start, end time.Time, opts export.Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	writer, err := s.encoder.NewWriter(w, export.SheetName(opts.Dataset), opts)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(export.Header(opts.Dataset)); err != nil {
+		return err
+	}
+
+	from, to := s.calendar.Period(start, end)
+	switch opts.Dataset {
+	case export.DatasetItems:
+		err = s.exportRepo.StreamItems(ctx, from, to, func(sl *sale.Sale, item *sale.SaleItem) error {
+			return writer.WriteRow(export.ItemRow(s.local(sl), item))
+		})
+	case export.DatasetAdditions:
+		err = s.exportRepo.StreamAdditions(ctx, from, to, func(sl *sale.Sale, item *sale.SaleItem, a *addition.Addition) error {
+			return writer.WriteRow(export.AdditionRow(s.local(sl), item, a))
+		})
+	default:
+		err = s.exportRepo.StreamSales(ctx, from, to, func(sl *sale.Sale) error {
+			return writer.WriteRow(export.SaleRow(s.local(sl)))
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// local passa o horário da venda para o fuso da loja.
+func (s *exportService) local(sl *sale.Sale) *sale.Sale {
+	sl.Date = s.calendar.In(sl.Date)
+	return sl
+}
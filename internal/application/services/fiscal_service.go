@@ -0,0 +1,253 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/fiscal"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FiscalService interface {
+	IssueSaleDocument(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error)
+	GetSaleDocument(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error)
+	ListProductsMissingFiscalData(ctx context.Context) ([]fiscal.MissingFiscalData, error)
+}
+
+type fiscalService struct {
+	fiscalRepo   fiscal.Repository
+	saleRepo     sale.Repository
+	productRepo  product.Repository
+	categoryRepo category.Repository
+	issuer       fiscal.Issuer
+	environment  fiscal.Environment
+	series       int
+	encoder      fiscal.Encoder
+	transmitter  fiscal.Transmitter
+}
+
+func NewFiscalService(
+	fiscalRepo fiscal.Repository,
+	saleRepo sale.Repository,
+	productRepo product.Repository,
+	categoryRepo category.Repository,
+	issuer fiscal.Issuer,
+	environment fiscal.Environment,
+	series int,
+	encoder fiscal.Encoder,
+	transmitter fiscal.Transmitter,
+) FiscalService {
+	return &fiscalService{
+		fiscalRepo:   fiscalRepo,
+		saleRepo:     saleRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		issuer:       issuer,
+		environment:  environment,
+		series:       series,
+		encoder:      encoder,
+		transmitter:  transmitter,
+	}
+}
+
+// IssueSaleDocument gera, assina e transmite a NFC-e da venda. O documento
+// é reservado como pendente antes da transmissão e atualizado com a resposta
+// da SEFAZ. Uma nota rejeitada ou não transmitida é gerada de novo com o
+// mesmo número.
+func (s *fiscalService) IssueSaleDocument(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error) {
+	if s.encoder == nil || s.transmitter == nil || s.issuer.Validate() != nil {
+		return nil, fiscal.ErrNotConfigured
+	}
+	if saleID == uuid.Nil {
+		return nil, errors.New("ID da venda inválido")
+	}
+
+	current, err := s.saleRepo.GetByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sale.ErrSaleNotFound
+	}
+	if current.Status == sale.StatusCanceled {
+		return nil, sale.ErrSaleStatusInvalid
+	}
+
+	doc, err := s.fiscalRepo.GetBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if doc != nil && doc.Status == fiscal.StatusAuthorized {
+		return nil, fiscal.ErrDocumentAuthorized
+	}
+
+	products, err := s.saleProducts(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if doc == nil {
+		// Valida os dados antes de consumir um número da série.
+		if _, err := fiscal.NewNFCe(s.issuer, s.environment, s.series, 0, now, current, products); err != nil {
+			return nil, err
+		}
+		doc = &fiscal.Document{
+			SaleID:      saleID,
+			Series:      s.series,
+			Environment: s.environment,
+			Status:      fiscal.StatusPending,
+			IssuedAt:    now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.fiscalRepo.Reserve(ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	nfce, err := fiscal.NewNFCe(s.issuer, s.environment, doc.Series, doc.Number, now, current, products)
+	if err != nil {
+		return nil, err
+	}
+	xml, err := s.encoder.Encode(nfce)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.AccessKey = nfce.AccessKey.String()
+	doc.XML = xml
+	doc.IssuedAt = now
+	doc.Status = fiscal.StatusPending
+	doc.Protocol, doc.StatusCode, doc.Message = "", 0, ""
+	doc.UpdatedAt = now
+
+	// A nota é gravada antes de sair para a SEFAZ, para que uma nota
+	// autorizada nunca fique sem registro local.
+	if err := s.fiscalRepo.Update(ctx, doc); err != nil {
+		return nil, err
+	}
+
+	auth, transmitErr := s.transmitter.Transmit(ctx, doc.AccessKey, xml)
+	if transmitErr != nil {
+		doc.Message = transmitErr.Error()
+	} else {
+		doc.StatusCode = auth.StatusCode
+		doc.Message = auth.Message
+		doc.Status = fiscal.StatusRejected
+		if auth.Authorized() {
+			doc.Status = fiscal.StatusAuthorized
+			doc.Protocol = auth.Protocol
+			doc.AuthorizedAt = &auth.ReceivedAt
+		}
+	}
+	doc.UpdatedAt = time.Now()
+
+	if err := s.fiscalRepo.Update(ctx, doc); err != nil {
+		return nil, err
+	}
+	if transmitErr != nil {
+		return doc, fiscal.ErrTransmissionFailed
+	}
+	return doc, nil
+}
+
+func (s *fiscalService) GetSaleDocument(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error) {
+	if saleID == uuid.Nil {
+		return nil, errors.New("ID da venda inválido")
+	}
+	doc, err := s.fiscalRepo.GetBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fiscal.ErrDocumentNotFound
+	}
+	return doc, nil
+}
+
+// ListProductsMissingFiscalData lista os produtos que não podem ser
+// emitidos na NFC-e, já considerando os padrões das categorias.
+func (s *fiscalService) ListProductsMissingFiscalData(ctx context.Context) ([]fiscal.MissingFiscalData, error) {
+	products, err := s.productRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	missing := []fiscal.MissingFiscalData{}
+	for _, p := range products {
+		if fields := fiscalData(p, categories).Missing(); len(fields) > 0 {
+			missing = append(missing, fiscal.MissingFiscalData{
+				ProductID:  p.ID,
+				Name:       p.Name,
+				CategoryID: p.CategoryID,
+				Fields:     fields,
+			})
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Name < missing[j].Name })
+	return missing, nil
+}
+
+// saleProducts carrega os produtos da venda com os dados fiscais efetivos,
+// isto é, com os campos ausentes herdados da categoria.
+func (s *fiscalService) saleProducts(ctx context.Context, sl *sale.Sale) (map[uuid.UUID]*product.Product, error) {
+	products := make(map[uuid.UUID]*product.Product)
+	var categories map[uuid.UUID]*category.Category
+	for _, item := range sl.Items {
+		if _, loaded := products[item.ProductID]; loaded {
+			continue
+		}
+		p, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			if categories == nil {
+				if categories, err = s.categories(ctx); err != nil {
+					return nil, err
+				}
+			}
+			effective := *p
+			effective.FiscalData = fiscalData(p, categories)
+			p = &effective
+		}
+		products[item.ProductID] = p
+	}
+	return products, nil
+}
+
+// categories indexa o cadastro de categorias pelo ID, lido uma vez por
+// operação.
+func (s *fiscalService) categories(ctx context.Context) (map[uuid.UUID]*category.Category, error) {
+	list, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[uuid.UUID]*category.Category, len(list))
+	for _, c := range list {
+		categories[c.ID] = c
+	}
+	return categories, nil
+}
+
+// fiscalData completa os dados fiscais do produto com os padrões da sua
+// categoria, quando ela existe.
+func fiscalData(p *product.Product, categories map[uuid.UUID]*category.Category) taxation.FiscalData {
+	c := categories[p.CategoryID]
+	if c == nil {
+		return p.FiscalData
+	}
+	return p.FiscalData.WithDefaults(c.FiscalData)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/fiscal"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFiscalRepository struct {
+	mock.Mock
+}
+
+func (m *MockFiscalRepository) Reserve(ctx context.Context, doc *fiscal.Document) error {
+	args := m.Called(ctx, doc)
+	if number := args.Int(0); number > 0 {
+		doc.Number = number
+	}
+	return args.Error(1)
+}
+
+func (m *MockFiscalRepository) Update(ctx context.Context, doc *fiscal.Document) error {
+	args := m.Called(ctx, doc)
+	return args.Error(0)
+}
+
+func (m *MockFiscalRepository) GetBySale(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error) {
+	args := m.Called(ctx, saleID)
+	doc := args.Get(0)
+	if doc == nil {
+		return nil, args.Error(1)
+	}
+	return doc.(*fiscal.Document), args.Error(1)
+}
+
+type MockFiscalEncoder struct {
+	mock.Mock
+}
+
+func (m *MockFiscalEncoder) Encode(n *fiscal.NFCe) ([]byte, error) {
+	args := m.Called(n)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type MockTransmitter struct {
+	mock.Mock
+}
+
+func (m *MockTransmitter) Transmit(ctx context.Context, accessKey string, xml []byte) (*fiscal.Authorization, error) {
+	args := m.Called(ctx, accessKey, xml)
+	auth := args.Get(0)
+	if auth == nil {
+		return nil, args.Error(1)
+	}
+	return auth.(*fiscal.Authorization), args.Error(1)
+}
+
+var fiscalTestIssuer = fiscal.Issuer{
+	CNPJ:              "11222333000181",
+	StateRegistration: "123456789012",
+	Name:              "Andressa Lanches LTDA",
+	TaxRegime:         fiscal.TaxRegimeSimples,
+	Address:           fiscal.Address{CityCode: "3550308", City: "Sao Paulo", State: "SP"},
+	CSCID:             "1",
+	CSC:               "123456",
+	QRCodeURL:         "https://nfce.example.com/qrcode",
+	ConsultURL:        "https://nfce.example.com/consulta",
+}
+
+type fiscalTestDeps struct {
+	fiscalRepo   *MockFiscalRepository
+	saleRepo     *MockSaleRepository
+	productRepo  *MockProductRepository
+	categoryRepo *MockCategoryRepository
+	encoder      *MockFiscalEncoder
+	transmitter  *MockTransmitter
+	service      FiscalService
+}
+
+func newFiscalTestDeps() fiscalTestDeps {
+	d := fiscalTestDeps{
+		fiscalRepo:   new(MockFiscalRepository),
+		saleRepo:     new(MockSaleRepository),
+		productRepo:  new(MockProductRepository),
+		categoryRepo: new(MockCategoryRepository),
+		encoder:      new(MockFiscalEncoder),
+		transmitter:  new(MockTransmitter),
+	}
+	d.service = NewFiscalService(d.fiscalRepo, d.saleRepo, d.productRepo, d.categoryRepo, fiscalTestIssuer, fiscal.EnvironmentHomologation, 1, d.encoder, d.transmitter)
+	return d
+}
+
+func (d fiscalTestDeps) paidSale(ctx context.Context) *sale.Sale {
+	// CFOP e CST vêm da categoria.
+	c := &category.Category{ID: uuid.New(), Name: "Lanches", FiscalData: taxation.FiscalData{CFOP: "5102", CST: "102"}}
+	p := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 20, CategoryID: c.ID, FiscalData: taxation.FiscalData{NCM: "21069090"}}
+	s := &sale.Sale{
+		ID:          uuid.New(),
+		OrderNumber: 1,
+		TotalAmount: 20,
+		Items:       []sale.SaleItem{{ProductID: p.ID, Quantity: 1, UnitPrice: 20, TotalPrice: 20}},
+		Payments:    []sale.Payment{{Method: sale.PaymentMethodPix, Amount: 20}},
+	}
+	d.saleRepo.On("GetByID", ctx, s.ID).Return(s, nil)
+	d.productRepo.On("GetByID", ctx, p.ID).Return(p, nil)
+	d.categoryRepo.On("List", ctx).Return([]*category.Category{c}, nil)
+	return s
+}
+
+func TestFiscalService_IssueSaleDocument_Authorized(t *testing.T) {
+	ctx := context.Background()
+	d := newFiscalTestDeps()
+	s := d.paidSale(ctx)
+
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(nil, nil)
+	d.fiscalRepo.On("Reserve", ctx, mock.MatchedBy(func(doc *fiscal.Document) bool {
+		return doc.Status == fiscal.StatusPending && doc.SaleID == s.ID
+	})).Return(15, nil)
+	d.encoder.On("Encode", mock.MatchedBy(func(n *fiscal.NFCe) bool {
+		return n.AccessKey.Number == 15 && n.Items[0].CFOP == "5102" && n.Items[0].CST == "102"
+	})).Return([]byte("<NFe/>"), nil)
+	d.transmitter.On("Transmit", ctx, mock.AnythingOfType("string"), []byte("<NFe/>")).
+		Return(&fiscal.Authorization{StatusCode: 100, Message: "Autorizado o uso da NF-e", Protocol: "135240000000001", ReceivedAt: time.Now()}, nil)
+	d.fiscalRepo.On("Update", ctx, mock.AnythingOfType("*fiscal.Document")).Return(nil)
+
+	doc, err := d.service.IssueSaleDocument(ctx, s.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiscal.StatusAuthorized, doc.Status)
+	assert.Equal(t, 15, doc.Number)
+	assert.Len(t, doc.AccessKey, 44)
+	assert.Equal(t, "135240000000001", doc.Protocol)
+	assert.NotNil(t, doc.AuthorizedAt)
+	// A nota é gravada antes da transmissão e de novo com a resposta.
+	d.fiscalRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestFiscalService_IssueSaleDocument_RetriesRejectedWithSameNumber(t *testing.T) {
+	ctx := context.Background()
+	d := newFiscalTestDeps()
+	s := d.paidSale(ctx)
+
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(&fiscal.Document{ID: uuid.New(), SaleID: s.ID, Series: 1, Number: 7, Status: fiscal.StatusRejected}, nil)
+	d.encoder.On("Encode", mock.Anything).Return([]byte("<NFe/>"), nil)
+	d.transmitter.On("Transmit", ctx, mock.AnythingOfType("string"), mock.Anything).
+		Return(&fiscal.Authorization{StatusCode: 100, Protocol: "1", ReceivedAt: time.Now()}, nil)
+	d.fiscalRepo.On("Update", ctx, mock.AnythingOfType("*fiscal.Document")).Return(nil)
+
+	doc, err := d.service.IssueSaleDocument(ctx, s.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, doc.Number)
+	assert.Equal(t, fiscal.StatusAuthorized, doc.Status)
+	d.fiscalRepo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestFiscalService_IssueSaleDocument_TransmissionFailureKeepsPending(t *testing.T) {
+	ctx := context.Background()
+	d := newFiscalTestDeps()
+	s := d.paidSale(ctx)
+
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(nil, nil)
+	d.fiscalRepo.On("Reserve", ctx, mock.AnythingOfType("*fiscal.Document")).Return(1, nil)
+	d.encoder.On("Encode", mock.Anything).Return([]byte("<NFe/>"), nil)
+	d.transmitter.On("Transmit", ctx, mock.AnythingOfType("string"), mock.Anything).Return(nil, errors.New("timeout"))
+	d.fiscalRepo.On("Update", ctx, mock.MatchedBy(func(doc *fiscal.Document) bool {
+		return doc.Status == fiscal.StatusPending
+	})).Return(nil)
+
+	doc, err := d.service.IssueSaleDocument(ctx, s.ID)
+
+	assert.Equal(t, fiscal.ErrTransmissionFailed, err)
+	assert.Equal(t, fiscal.StatusPending, doc.Status)
+	assert.Equal(t, "timeout", doc.Message)
+}
+
+func TestFiscalService_IssueSaleDocument_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	d := newFiscalTestDeps()
+	s := d.paidSale(ctx)
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(&fiscal.Document{Status: fiscal.StatusAuthorized}, nil)
+	_, err := d.service.IssueSaleDocument(ctx, s.ID)
+	assert.Equal(t, fiscal.ErrDocumentAuthorized, err)
+
+	d = newFiscalTestDeps()
+	unpaid := &sale.Sale{ID: uuid.New(), TotalAmount: 10}
+	d.saleRepo.On("GetByID", ctx, unpaid.ID).Return(unpaid, nil)
+	d.fiscalRepo.On("GetBySale", ctx, unpaid.ID).Return(nil, nil)
+	_, err = d.service.IssueSaleDocument(ctx, unpaid.ID)
+	assert.Equal(t, fiscal.ErrSaleNotPaid, err)
+	d.fiscalRepo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+
+	// CST 00 sem alíquota do ICMS no regime normal é recusado sem consumir
+	// um número.
+	d = newFiscalTestDeps()
+	normal := fiscalTestIssuer
+	normal.TaxRegime = fiscal.TaxRegimeNormal
+	d.service = NewFiscalService(d.fiscalRepo, d.saleRepo, d.productRepo, d.categoryRepo, normal, fiscal.EnvironmentHomologation, 1, d.encoder, d.transmitter)
+	s = d.paidSale(ctx)
+	p, _ := d.productRepo.GetByID(ctx, s.Items[0].ProductID)
+	p.CST = "00"
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(nil, nil)
+	_, err = d.service.IssueSaleDocument(ctx, s.ID)
+	assert.Equal(t, taxation.ErrICMSRequired, err)
+	d.fiscalRepo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+
+	// Outra emissão da mesma venda em andamento: nada é transmitido.
+	d = newFiscalTestDeps()
+	s = d.paidSale(ctx)
+	d.fiscalRepo.On("GetBySale", ctx, s.ID).Return(nil, nil)
+	d.fiscalRepo.On("Reserve", ctx, mock.AnythingOfType("*fiscal.Document")).Return(0, fiscal.ErrIssueInProgress)
+	_, err = d.service.IssueSaleDocument(ctx, s.ID)
+	assert.Equal(t, fiscal.ErrIssueInProgress, err)
+	d.transmitter.AssertNotCalled(t, "Transmit", mock.Anything, mock.Anything, mock.Anything)
+
+	unconfigured := NewFiscalService(d.fiscalRepo, d.saleRepo, d.productRepo, d.categoryRepo, fiscal.Issuer{}, fiscal.EnvironmentHomologation, 1, d.encoder, d.transmitter)
+	_, err = unconfigured.IssueSaleDocument(ctx, unpaid.ID)
+	assert.Equal(t, fiscal.ErrNotConfigured, err)
+}
+
+func TestFiscalService_ListProductsMissingFiscalData(t *testing.T) {
+	ctx := context.Background()
+	d := newFiscalTestDeps()
+
+	withDefaults := &category.Category{ID: uuid.New(), Name: "Lanches", FiscalData: taxation.FiscalData{NCM: "21069090", CFOP: "5102", CST: "102"}}
+	withoutDefaults := &category.Category{ID: uuid.New(), Name: "Bebidas"}
+	products := []*product.Product{
+		{ID: uuid.New(), Name: "X-Salada", CategoryID: withDefaults.ID},
+		{ID: uuid.New(), Name: "Suco", CategoryID: withoutDefaults.ID, FiscalData: taxation.FiscalData{CFOP: "5102"}},
+		{ID: uuid.New(), Name: "Água", CategoryID: withoutDefaults.ID, FiscalData: taxation.FiscalData{NCM: "22011000", CFOP: "5405", CST: "500"}},
+	}
+	d.productRepo.On("List", ctx).Return(products, nil)
+	d.categoryRepo.On("List", ctx).Return([]*category.Category{withDefaults, withoutDefaults}, nil).Once()
+
+	missing, err := d.service.ListProductsMissingFiscalData(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+	assert.Equal(t, products[1].ID, missing[0].ProductID)
+	assert.Equal(t, []string{"ncm", "cst"}, missing[0].Fields)
+	d.categoryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/session"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// loginFailureWindow é por quanto tempo, desde a primeira falha, as falhas de
+// uma chave são lembradas.
+const loginFailureWindow = 24 * time.Hour
+
+// LoginGuard protege o login contra força bruta, contando as falhas por IP e
+// por usuário, e registra as falhas na auditoria. Cada tentativa é contada
+// como falha antes de a senha ser conferida, para que tentativas simultâneas
+// não passem todas pela mesma verificação; o sucesso desfaz a contagem.
+type LoginGuard interface {
+	// Reserve conta a tentativa e a recusa com *ratelimit.LockedError
+	// enquanto o IP ou o usuário estiverem bloqueados.
+	Reserve(ctx context.Context, attempt session.LoginAttempt) error
+	// Fail registra na auditoria a tentativa já contada por Reserve.
+	Fail(ctx context.Context, attempt session.LoginAttempt) error
+	// Succeed zera as falhas do usuário e devolve a tentativa contada no IP;
+	// as falhas anteriores do IP continuam valendo.
+	Succeed(ctx context.Context, attempt session.LoginAttempt) error
+}
+
+// LoginPolicy define os bloqueios por usuário e por IP. O limite por IP deve
+// ser mais folgado, já que os terminais da loja costumam sair pelo mesmo IP.
+type LoginPolicy struct {
+	PerUser ratelimit.LockoutPolicy
+	PerIP   ratelimit.LockoutPolicy
+}
+
+type loginGuard struct {
+	store     ratelimit.Store
+	auditRepo audit.Repository
+	policy    LoginPolicy
+}
+
+func NewLoginGuard(store ratelimit.Store, auditRepo audit.Repository, policy LoginPolicy) LoginGuard {
+	return &loginGuard{
+		store:     store,
+		auditRepo: auditRepo,
+		policy:    policy,
+	}
+}
+
+func (g *loginGuard) Reserve(ctx context.Context, attempt session.LoginAttempt) error {
+	now := time.Now()
+	var until time.Time
+	keys := g.keys(attempt)
+	for key, policy := range keys {
+		previous, err := g.store.Get(ctx, key, now)
+		if err != nil {
+			return err
+		}
+		c, err := g.store.Increment(ctx, key, loginFailureWindow, now)
+		if err != nil {
+			return err
+		}
+		// As tentativas contadas entre a leitura e o incremento ainda estão
+		// em andamento e valem como falhas de agora.
+		if c.Count-1 > previous.Count {
+			previous = ratelimit.Counter{Count: c.Count - 1, LastHit: now}
+		}
+		if lockedUntil := policy.LockedUntil(previous); lockedUntil.After(until) {
+			until = lockedUntil
+		}
+	}
+	if !until.After(now) {
+		return nil
+	}
+
+	// A tentativa recusada não conta como falha.
+	for key := range keys {
+		if err := g.store.Decrement(ctx, key, now); err != nil {
+			return err
+		}
+	}
+	return &ratelimit.LockedError{Until: until}
+}
+
+func (g *loginGuard) Fail(ctx context.Context, attempt session.LoginAttempt) error {
+	now := time.Now()
+	var until time.Time
+	for key, policy := range g.keys(attempt) {
+		c, err := g.store.Get(ctx, key, now)
+		if err != nil {
+			return err
+		}
+		if lockedUntil := policy.LockedUntil(c); lockedUntil.After(until) {
+			until = lockedUntil
+		}
+	}
+
+	details := loginFailureDetails{Method: attempt.Method, TerminalID: attempt.TerminalID}
+	if !until.IsZero() {
+		details.LockedUntil = &until
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	action := audit.ActionLoginFailed
+	if attempt.Method == session.LoginMethodPIN {
+		action = audit.ActionPINLoginFailed
+	}
+	return g.auditRepo.Append(ctx, &audit.Entry{
+		Action:    action,
+		ActorID:   attempt.UserID,
+		Actor:     audit.TruncateActor(attempt.Username),
+		IP:        attempt.IP,
+		Details:   raw,
+		RequestID: audit.RequestIDFrom(ctx),
+		CreatedAt: now,
+	})
+}
+
+func (g *loginGuard) Succeed(ctx context.Context, attempt session.LoginAttempt) error {
+	if err := g.store.Reset(ctx, userKey(attempt)); err != nil {
+		return err
+	}
+	return g.store.Decrement(ctx, ipKey(attempt), time.Now())
+}
+
+// keys separa as falhas de senha e de PIN do mesmo usuário: errar o PIN no
+// balcão não bloqueia o login com senha.
+func (g *loginGuard) keys(attempt session.LoginAttempt) map[string]ratelimit.LockoutPolicy {
+	return map[string]ratelimit.LockoutPolicy{
+		ipKey(attempt):   g.policy.PerIP,
+		userKey(attempt): g.policy.PerUser,
+	}
+}
+
+func ipKey(attempt session.LoginAttempt) string {
+	return "login:ip:" + attempt.IP
+}
+
+func userKey(attempt session.LoginAttempt) string {
+	return "login:" + string(attempt.Method) + ":" + attempt.Username
+}
+
+type loginFailureDetails struct {
+	Method      session.LoginMethod `json:"method"`
+	TerminalID  *uuid.UUID          `json:"terminal_id,omitempty"`
+	LockedUntil *time.Time          `json:"locked_until,omitempty"`
+}
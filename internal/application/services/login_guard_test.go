@@ -0,0 +1,160 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/session"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (ratelimit.Counter, error) {
+	args := m.Called(ctx, key, window, now)
+	return args.Get(0).(ratelimit.Counter), args.Error(1)
+}
+
+func (m *MockRateLimitStore) Get(ctx context.Context, key string, now time.Time) (ratelimit.Counter, error) {
+	args := m.Called(ctx, key, now)
+	return args.Get(0).(ratelimit.Counter), args.Error(1)
+}
+
+func (m *MockRateLimitStore) Decrement(ctx context.Context, key string, now time.Time) error {
+	args := m.Called(ctx, key, now)
+	return args.Error(0)
+}
+
+func (m *MockRateLimitStore) Reset(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*audit.Entry), args.Error(1)
+}
+
+var testLoginPolicy = LoginPolicy{
+	PerUser: ratelimit.LockoutPolicy{MaxAttempts: 3, BaseLockout: time.Minute, MaxLockout: time.Hour},
+	PerIP:   ratelimit.LockoutPolicy{MaxAttempts: 10, BaseLockout: time.Minute, MaxLockout: time.Hour},
+}
+
+func TestLoginGuard_Reserve(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockRateLimitStore)
+	guard := NewLoginGuard(store, new(MockAuditRepository), testLoginPolicy)
+	attempt := session.LoginAttempt{Method: session.LoginMethodPassword, IP: "10.0.0.1", Username: "maria"}
+
+	store.On("Get", ctx, "login:ip:10.0.0.1", mock.Anything).Return(ratelimit.Counter{Count: 4, LastHit: time.Now()}, nil)
+	store.On("Increment", ctx, "login:ip:10.0.0.1", loginFailureWindow, mock.Anything).Return(ratelimit.Counter{Count: 5, LastHit: time.Now()}, nil)
+	store.On("Get", ctx, "login:password:maria", mock.Anything).Return(ratelimit.Counter{Count: 2, LastHit: time.Now()}, nil).Once()
+	store.On("Increment", ctx, "login:password:maria", loginFailureWindow, mock.Anything).Return(ratelimit.Counter{Count: 3, LastHit: time.Now()}, nil).Once()
+	assert.NoError(t, guard.Reserve(ctx, attempt))
+	store.AssertNotCalled(t, "Decrement", mock.Anything, mock.Anything, mock.Anything)
+
+	// A quarta falha dobra o bloqueio de um minuto, e a tentativa recusada é
+	// devolvida.
+	store.On("Get", ctx, "login:password:maria", mock.Anything).Return(ratelimit.Counter{Count: 4, LastHit: time.Now()}, nil).Once()
+	store.On("Increment", ctx, "login:password:maria", loginFailureWindow, mock.Anything).Return(ratelimit.Counter{Count: 5, LastHit: time.Now()}, nil).Once()
+	store.On("Decrement", ctx, "login:ip:10.0.0.1", mock.Anything).Return(nil).Once()
+	store.On("Decrement", ctx, "login:password:maria", mock.Anything).Return(nil).Once()
+	err := guard.Reserve(ctx, attempt)
+	var locked *ratelimit.LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), locked.Until, 5*time.Second)
+	store.AssertNumberOfCalls(t, "Decrement", 2)
+
+	// O bloqueio já terminou.
+	store.On("Get", ctx, "login:password:maria", mock.Anything).Return(ratelimit.Counter{Count: 4, LastHit: time.Now().Add(-3 * time.Minute)}, nil).Once()
+	store.On("Increment", ctx, "login:password:maria", loginFailureWindow, mock.Anything).Return(ratelimit.Counter{Count: 5, LastHit: time.Now()}, nil).Once()
+	assert.NoError(t, guard.Reserve(ctx, attempt))
+
+	// Duas tentativas simultâneas leram dois erros; a que chegou depois ao
+	// incremento já passa do limite.
+	store.On("Get", ctx, "login:password:maria", mock.Anything).Return(ratelimit.Counter{Count: 2, LastHit: time.Now()}, nil).Once()
+	store.On("Increment", ctx, "login:password:maria", loginFailureWindow, mock.Anything).Return(ratelimit.Counter{Count: 4, LastHit: time.Now()}, nil).Once()
+	store.On("Decrement", ctx, "login:ip:10.0.0.1", mock.Anything).Return(nil).Once()
+	store.On("Decrement", ctx, "login:password:maria", mock.Anything).Return(nil).Once()
+	require.ErrorAs(t, guard.Reserve(ctx, attempt), &locked)
+}
+
+func TestLoginGuard_FailAuditsAttempt(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockRateLimitStore)
+	auditRepo := new(MockAuditRepository)
+	guard := NewLoginGuard(store, auditRepo, testLoginPolicy)
+	attempt := session.LoginAttempt{Method: session.LoginMethodPIN, IP: "10.0.0.1", Username: "maria"}
+
+	store.On("Get", ctx, "login:ip:10.0.0.1", mock.Anything).Return(ratelimit.Counter{Count: 1, LastHit: time.Now()}, nil)
+	store.On("Get", ctx, "login:pin:maria", mock.Anything).Return(ratelimit.Counter{Count: 3, LastHit: time.Now()}, nil)
+
+	var entry *audit.Entry
+	auditRepo.On("Append", ctx, mock.AnythingOfType("*audit.Entry")).Run(func(args mock.Arguments) {
+		entry = args.Get(1).(*audit.Entry)
+	}).Return(nil)
+
+	require.NoError(t, guard.Fail(ctx, attempt))
+	require.NotNil(t, entry)
+	assert.Equal(t, audit.ActionPINLoginFailed, entry.Action)
+	assert.Equal(t, "maria", entry.Actor)
+	assert.Equal(t, "10.0.0.1", entry.IP)
+
+	var details map[string]any
+	require.NoError(t, json.Unmarshal(entry.Details, &details))
+	assert.Equal(t, "pin", details["method"])
+	assert.NotEmpty(t, details["locked_until"])
+}
+
+func TestLoginGuard_FailTruncatesLongUsername(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockRateLimitStore)
+	auditRepo := new(MockAuditRepository)
+	guard := NewLoginGuard(store, auditRepo, testLoginPolicy)
+	username := strings.Repeat("á", audit.MaxActorLength+20)
+	attempt := session.LoginAttempt{Method: session.LoginMethodPassword, IP: "10.0.0.1", Username: username}
+
+	store.On("Get", ctx, mock.Anything, mock.Anything).Return(ratelimit.Counter{Count: 1, LastHit: time.Now()}, nil)
+
+	var entry *audit.Entry
+	auditRepo.On("Append", ctx, mock.AnythingOfType("*audit.Entry")).Run(func(args mock.Arguments) {
+		entry = args.Get(1).(*audit.Entry)
+	}).Return(nil)
+
+	// O nome digitado no login não pode estourar a coluna da auditoria.
+	require.NoError(t, guard.Fail(ctx, attempt))
+	require.NotNil(t, entry)
+	assert.Equal(t, strings.Repeat("á", audit.MaxActorLength), entry.Actor)
+	store.AssertCalled(t, "Get", ctx, "login:password:"+username, mock.Anything)
+}
+
+func TestLoginGuard_SucceedReleasesIP(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockRateLimitStore)
+	guard := NewLoginGuard(store, new(MockAuditRepository), testLoginPolicy)
+
+	store.On("Reset", ctx, "login:password:maria").Return(nil)
+	store.On("Decrement", ctx, "login:ip:10.0.0.1", mock.Anything).Return(nil)
+
+	assert.NoError(t, guard.Succeed(ctx, session.LoginAttempt{Method: session.LoginMethodPassword, IP: "10.0.0.1", Username: "maria"}))
+	store.AssertNotCalled(t, "Reset", ctx, "login:ip:10.0.0.1")
+	store.AssertCalled(t, "Decrement", ctx, "login:ip:10.0.0.1", mock.Anything)
+}
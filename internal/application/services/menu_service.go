@@ -0,0 +1,105 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/menu"
+	"andressa-lanches/internal/domain/product"
+	"context"
+	"sync"
+	"time"
+)
+
+// MenuInvalidator é avisado quando o cardápio muda, para descartar o
+// cardápio público em cache. Os serviços do cardápio aceitam um
+// MenuInvalidator nulo, como nos testes.
+type MenuInvalidator interface {
+	InvalidateMenu()
+}
+
+// invalidateMenu avisa da alteração quando há cardápio público configurado.
+func invalidateMenu(invalidator MenuInvalidator) {
+	if invalidator != nil {
+		invalidator.InvalidateMenu()
+	}
+}
+
+type MenuService interface {
+	MenuInvalidator
+	// PublicMenu devolve o cardápio público, montado uma vez e guardado até
+	// a próxima alteração do cardápio.
+	PublicMenu(ctx context.Context) (*menu.Snapshot, error)
+}
+
+type menuService struct {
+	categoryRepo category.Repository
+	productRepo  product.Repository
+	additionRepo addition.Repository
+
+	mu       sync.Mutex
+	snapshot *menu.Snapshot
+	modified time.Time
+	// version muda a cada alteração, para que um cardápio montado antes
+	// dela não seja guardado.
+	version uint64
+}
+
+// NewMenuService considera o cardápio alterado na criação do serviço, já que
+// o cadastro não guarda a data da última alteração.
+func NewMenuService(categoryRepo category.Repository, productRepo product.Repository, additionRepo addition.Repository) MenuService {
+	return &menuService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		additionRepo: additionRepo,
+		modified:     time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func (s *menuService) PublicMenu(ctx context.Context) (*menu.Snapshot, error) {
+	s.mu.Lock()
+	snapshot, version, modified := s.snapshot, s.version, s.modified
+	s.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.productRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	additions, err := s.additionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err = menu.NewSnapshot(menu.New(categories, products, additions), modified)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.version == version {
+		s.snapshot = snapshot
+	}
+	s.mu.Unlock()
+	return snapshot, nil
+}
+
+// InvalidateMenu descarta o cardápio guardado. O Last-Modified tem precisão
+// de segundos, como no cabeçalho HTTP, então duas alterações no mesmo
+// segundo avançam a data um segundo para que o If-Modified-Since de quem
+// leu entre elas não devolva 304.
+func (s *menuService) InvalidateMenu() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	s.snapshot = nil
+	modified := time.Now().UTC().Truncate(time.Second)
+	if !modified.After(s.modified) {
+		modified = s.modified.Add(time.Second)
+	}
+	s.modified = modified
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentService interface {
+	CreateCharge(ctx context.Context, saleID uuid.UUID, method sale.PaymentMethod) (*payment.Charge, error)
+	GetCharge(ctx context.Context, id uuid.UUID) (*payment.Charge, error)
+	ListSaleCharges(ctx context.Context, saleID uuid.UUID) ([]*payment.Charge, error)
+	RefundCharge(ctx context.Context, id uuid.UUID) (*payment.Charge, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type paymentService struct {
+	paymentRepo payment.Repository
+	saleRepo    sale.Repository
+	gateway     payment.Gateway
+}
+
+func NewPaymentService(paymentRepo payment.Repository, saleRepo sale.Repository, gateway payment.Gateway) PaymentService {
+	return &paymentService{
+		paymentRepo: paymentRepo,
+		saleRepo:    saleRepo,
+		gateway:     gateway,
+	}
+}
+
+func (s *paymentService) CreateCharge(ctx context.Context, saleID uuid.UUID, method sale.PaymentMethod) (*payment.Charge, error) {
+	if method == "" {
+		method = sale.PaymentMethodPix
+	}
+	if !method.IsValid() {
+		return nil, sale.ErrPaymentMethodInvalid
+	}
+	if method == sale.PaymentMethodCash {
+		return nil, payment.ErrChargeMethodInvalid
+	}
+
+	current, err := s.findSale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == sale.StatusCanceled {
+		return nil, sale.ErrSaleStatusInvalid
+	}
+
+	// Os pagamentos da venda já incluem as cobranças confirmadas; as
+	// pendentes ainda podem ser pagas e também saem do valor a cobrar.
+	charges, err := s.paymentRepo.ListBySale(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	var pending float64
+	for _, charge := range charges {
+		if charge.Status == payment.StatusPending {
+			pending += charge.Amount
+		}
+	}
+
+	amount := math.Round((current.TotalAmount-current.AmountPaid()-pending)*100) / 100
+	if amount <= 0 {
+		return nil, payment.ErrNothingToCharge
+	}
+
+	gatewayCharge, err := s.gateway.CreateCharge(ctx, payment.ChargeRequest{
+		SaleID:      current.ID,
+		Reference:   pix.TxIDForOrder(current.BusinessDate, current.OrderNumber),
+		Method:      method,
+		Amount:      amount,
+		Description: "Pedido " + current.ID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	charge := &payment.Charge{
+		SaleID:           current.ID,
+		Provider:         s.gateway.Name(),
+		ProviderChargeID: gatewayCharge.ID,
+		Method:           method,
+		Amount:           amount,
+		Status:           payment.StatusPending,
+		PixPayload:       gatewayCharge.PixPayload,
+		CheckoutURL:      gatewayCharge.CheckoutURL,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.paymentRepo.Create(ctx, charge); err != nil {
+		return nil, err
+	}
+
+	// Alguns provedores confirmam na hora (por exemplo, cartão aprovado).
+	if err := s.applyStatus(ctx, charge, gatewayCharge.Status); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+// GetCharge devolve a cobrança consultando o gateway enquanto ela estiver
+// pendente, para o caso de uma notificação ter se perdido.
+func (s *paymentService) GetCharge(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	charge, err := s.findCharge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if charge.Status == payment.StatusPending {
+		gatewayCharge, err := s.gateway.GetCharge(ctx, charge.ProviderChargeID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyStatus(ctx, charge, gatewayCharge.Status); err != nil {
+			return nil, err
+		}
+	}
+	return charge, nil
+}
+
+func (s *paymentService) ListSaleCharges(ctx context.Context, saleID uuid.UUID) ([]*payment.Charge, error) {
+	if _, err := s.findSale(ctx, saleID); err != nil {
+		return nil, err
+	}
+	return s.paymentRepo.ListBySale(ctx, saleID)
+}
+
+func (s *paymentService) RefundCharge(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	charge, err := s.findCharge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if charge.Status != payment.StatusConfirmed {
+		return nil, payment.ErrChargeNotRefundable
+	}
+
+	gatewayCharge, err := s.gateway.Refund(ctx, charge.ProviderChargeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyStatus(ctx, charge, gatewayCharge.Status); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+// HandleWebhook processa uma notificação do gateway. O processamento é
+// idempotente: notificações repetidas ou fora de ordem não mudam uma cobrança
+// já resolvida nem registram o pagamento na venda mais de uma vez.
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	charge, err := s.paymentRepo.GetByProviderChargeID(ctx, s.gateway.Name(), event.ChargeID)
+	if err != nil {
+		return err
+	}
+	if charge == nil {
+		return payment.ErrChargeNotFound
+	}
+
+	isNew, err := s.paymentRepo.RegisterWebhookEvent(ctx, s.gateway.Name(), event.ID)
+	if err != nil {
+		return err
+	}
+	if !isNew {
+		return nil
+	}
+
+	// Uma falha libera o evento para que o gateway possa reenviá-lo.
+	if err := s.applyStatus(ctx, charge, event.Status); err != nil {
+		if releaseErr := s.paymentRepo.ReleaseWebhookEvent(ctx, s.gateway.Name(), event.ID); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// applyStatus muda o status da cobrança e, na mesma transação, reflete a
+// mudança nos pagamentos da venda e a registra na auditoria. A troca
+// condicional no repositório garante que apenas uma das chamadas
+// concorrentes efetive a transição.
+func (s *paymentService) applyStatus(ctx context.Context, charge *payment.Charge, status payment.Status) error {
+	if !charge.Status.CanTransitionTo(status) {
+		return nil
+	}
+
+	after := *charge
+	after.Status = status
+	entry, err := audit.NewEntry(ctx, audit.ActionUpdate, audit.EntityCharge, charge.ID, charge, &after)
+	if err != nil {
+		return err
+	}
+	changed, err := s.paymentRepo.UpdateStatus(ctx, charge, status, entry)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		current, err := s.findCharge(ctx, charge.ID)
+		if err != nil {
+			return err
+		}
+		*charge = *current
+		return nil
+	}
+	charge.Status = status
+	charge.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *paymentService) findSale(ctx context.Context, id uuid.UUID) (*sale.Sale, error) {
+	if id == uuid.Nil {
+		return nil, errors.New("ID da venda inválido")
+	}
+
+	current, err := s.saleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sale.ErrSaleNotFound
+	}
+	return current, nil
+}
+
+func (s *paymentService) findCharge(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	charge, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if charge == nil {
+		return nil, payment.ErrChargeNotFound
+	}
+	return charge, nil
+}
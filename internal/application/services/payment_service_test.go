@@ -0,0 +1,289 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPaymentRepository struct {
+	mock.Mock
+	// entries guarda os registros de auditoria recebidos em UpdateStatus.
+	entries []*audit.Entry
+}
+
+func (m *MockPaymentRepository) Create(ctx context.Context, c *payment.Charge) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	args := m.Called(ctx, id)
+	c := args.Get(0)
+	if c == nil {
+		return nil, args.Error(1)
+	}
+	return c.(*payment.Charge), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetByProviderChargeID(ctx context.Context, provider, providerChargeID string) (*payment.Charge, error) {
+	args := m.Called(ctx, provider, providerChargeID)
+	c := args.Get(0)
+	if c == nil {
+		return nil, args.Error(1)
+	}
+	return c.(*payment.Charge), args.Error(1)
+}
+
+func (m *MockPaymentRepository) ListBySale(ctx context.Context, saleID uuid.UUID) ([]*payment.Charge, error) {
+	args := m.Called(ctx, saleID)
+	return args.Get(0).([]*payment.Charge), args.Error(1)
+}
+
+func (m *MockPaymentRepository) UpdateStatus(ctx context.Context, c *payment.Charge, to payment.Status, entry *audit.Entry) (bool, error) {
+	m.entries = append(m.entries, entry)
+	args := m.Called(ctx, c.ID, c.Status, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPaymentRepository) RegisterWebhookEvent(ctx context.Context, provider, eventID string) (bool, error) {
+	args := m.Called(ctx, provider, eventID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPaymentRepository) ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error {
+	args := m.Called(ctx, provider, eventID)
+	return args.Error(0)
+}
+
+type MockGateway struct {
+	mock.Mock
+}
+
+func (m *MockGateway) Name() string {
+	return "mock"
+}
+
+func (m *MockGateway) CreateCharge(ctx context.Context, req payment.ChargeRequest) (*payment.GatewayCharge, error) {
+	args := m.Called(ctx, req)
+	c := args.Get(0)
+	if c == nil {
+		return nil, args.Error(1)
+	}
+	return c.(*payment.GatewayCharge), args.Error(1)
+}
+
+func (m *MockGateway) GetCharge(ctx context.Context, id string) (*payment.GatewayCharge, error) {
+	args := m.Called(ctx, id)
+	c := args.Get(0)
+	if c == nil {
+		return nil, args.Error(1)
+	}
+	return c.(*payment.GatewayCharge), args.Error(1)
+}
+
+func (m *MockGateway) Refund(ctx context.Context, id string) (*payment.GatewayCharge, error) {
+	args := m.Called(ctx, id)
+	c := args.Get(0)
+	if c == nil {
+		return nil, args.Error(1)
+	}
+	return c.(*payment.GatewayCharge), args.Error(1)
+}
+
+func (m *MockGateway) ParseWebhook(payload []byte, signature string) (*payment.WebhookEvent, error) {
+	args := m.Called(payload, signature)
+	e := args.Get(0)
+	if e == nil {
+		return nil, args.Error(1)
+	}
+	return e.(*payment.WebhookEvent), args.Error(1)
+}
+
+func TestPaymentService_CreateCharge_PendingAmount(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockSaleRepo := new(MockSaleRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, mockSaleRepo, mockGateway)
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{
+		ID:          saleID,
+		OrderNumber: 3,
+		TotalAmount: 50,
+		Payments:    []sale.Payment{{Method: sale.PaymentMethodCash, Amount: 20}},
+	}, nil)
+	mockGateway.On("CreateCharge", ctx, mock.MatchedBy(func(req payment.ChargeRequest) bool {
+		return req.Amount == 20 && req.Method == sale.PaymentMethodPix
+	})).Return(&payment.GatewayCharge{ID: "ch_1", Status: payment.StatusPending, PixPayload: "000201"}, nil)
+	mockPaymentRepo.On("ListBySale", ctx, saleID).Return([]*payment.Charge{
+		{SaleID: saleID, Amount: 10, Status: payment.StatusPending},
+		{SaleID: saleID, Amount: 20, Status: payment.StatusFailed},
+	}, nil)
+	mockPaymentRepo.On("Create", ctx, mock.AnythingOfType("*payment.Charge")).Return(nil)
+
+	charge, err := service.CreateCharge(ctx, saleID, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, charge.Amount)
+	assert.Equal(t, payment.StatusPending, charge.Status)
+	assert.Equal(t, "ch_1", charge.ProviderChargeID)
+	assert.Equal(t, "000201", charge.PixPayload)
+	mockPaymentRepo.AssertNotCalled(t, "UpdateStatus")
+}
+
+func TestPaymentService_CreateCharge_NothingToCharge(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockSaleRepo := new(MockSaleRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, mockSaleRepo, mockGateway)
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{
+		ID:          saleID,
+		TotalAmount: 20,
+		Payments:    []sale.Payment{{Method: sale.PaymentMethodPix, Amount: 20}},
+	}, nil)
+	mockPaymentRepo.On("ListBySale", ctx, saleID).Return([]*payment.Charge{}, nil)
+
+	_, err := service.CreateCharge(ctx, saleID, sale.PaymentMethodPix)
+
+	assert.Equal(t, payment.ErrNothingToCharge, err)
+	mockGateway.AssertNotCalled(t, "CreateCharge")
+}
+
+func TestPaymentService_CreateCharge_CashNotSupported(t *testing.T) {
+	ctx := context.Background()
+	service := NewPaymentService(new(MockPaymentRepository), new(MockSaleRepository), new(MockGateway))
+
+	_, err := service.CreateCharge(ctx, uuid.New(), sale.PaymentMethodCash)
+
+	assert.Equal(t, payment.ErrChargeMethodInvalid, err)
+}
+
+func TestPaymentService_HandleWebhook_ConfirmsOnce(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockSaleRepo := new(MockSaleRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, mockSaleRepo, mockGateway)
+
+	chargeID := uuid.New()
+	saleID := uuid.New()
+	payload := []byte(`{}`)
+	event := &payment.WebhookEvent{ID: "evt_1", ChargeID: "ch_1", Status: payment.StatusConfirmed}
+
+	mockGateway.On("ParseWebhook", payload, "sig").Return(event, nil)
+	mockPaymentRepo.On("GetByProviderChargeID", ctx, "mock", "ch_1").Return(&payment.Charge{
+		ID: chargeID, SaleID: saleID, Provider: "mock", ProviderChargeID: "ch_1",
+		Method: sale.PaymentMethodPix, Amount: 30, Status: payment.StatusPending,
+	}, nil).Once()
+	mockPaymentRepo.On("RegisterWebhookEvent", ctx, "mock", "evt_1").Return(true, nil).Once()
+	mockPaymentRepo.On("UpdateStatus", ctx, chargeID, payment.StatusPending, payment.StatusConfirmed).Return(true, nil).Once()
+
+	err := service.HandleWebhook(ctx, payload, "sig")
+	assert.NoError(t, err)
+	require.Len(t, mockPaymentRepo.entries, 1)
+	entry := mockPaymentRepo.entries[0]
+	assert.Equal(t, audit.EntityCharge, entry.EntityType)
+	assert.Equal(t, chargeID, *entry.EntityID)
+	assert.Contains(t, string(entry.Changes), `"status"`)
+
+	// Reenvio da mesma notificação: ignorado antes de tocar na cobrança
+	mockPaymentRepo.On("GetByProviderChargeID", ctx, "mock", "ch_1").Return(&payment.Charge{
+		ID: chargeID, SaleID: saleID, Provider: "mock", ProviderChargeID: "ch_1",
+		Method: sale.PaymentMethodPix, Amount: 30, Status: payment.StatusPending,
+	}, nil).Once()
+	mockPaymentRepo.On("RegisterWebhookEvent", ctx, "mock", "evt_1").Return(false, nil).Once()
+
+	err = service.HandleWebhook(ctx, payload, "sig")
+	assert.NoError(t, err)
+
+	mockPaymentRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
+
+func TestPaymentService_HandleWebhook_ReleasesEventOnFailure(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, new(MockSaleRepository), mockGateway)
+
+	chargeID := uuid.New()
+	payload := []byte(`{}`)
+	event := &payment.WebhookEvent{ID: "evt_1", ChargeID: "ch_1", Status: payment.StatusConfirmed}
+	failure := errors.New("conexão perdida")
+
+	mockGateway.On("ParseWebhook", payload, "sig").Return(event, nil)
+	mockPaymentRepo.On("GetByProviderChargeID", ctx, "mock", "ch_1").Return(&payment.Charge{
+		ID: chargeID, Provider: "mock", ProviderChargeID: "ch_1", Status: payment.StatusPending,
+	}, nil)
+	mockPaymentRepo.On("RegisterWebhookEvent", ctx, "mock", "evt_1").Return(true, nil)
+	mockPaymentRepo.On("UpdateStatus", ctx, chargeID, payment.StatusPending, payment.StatusConfirmed).Return(false, failure)
+	mockPaymentRepo.On("ReleaseWebhookEvent", ctx, "mock", "evt_1").Return(nil)
+
+	err := service.HandleWebhook(ctx, payload, "sig")
+
+	assert.Equal(t, failure, err)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_HandleWebhook_InvalidSignature(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, new(MockSaleRepository), mockGateway)
+
+	mockGateway.On("ParseWebhook", []byte(`{}`), "bad").Return(nil, payment.ErrWebhookSignatureInvalid)
+
+	err := service.HandleWebhook(ctx, []byte(`{}`), "bad")
+
+	assert.Equal(t, payment.ErrWebhookSignatureInvalid, err)
+	mockPaymentRepo.AssertNotCalled(t, "GetByProviderChargeID")
+}
+
+func TestPaymentService_RefundCharge_RemovesSalePayment(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockSaleRepo := new(MockSaleRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, mockSaleRepo, mockGateway)
+
+	chargeID := uuid.New()
+	saleID := uuid.New()
+	mockPaymentRepo.On("GetByID", ctx, chargeID).Return(&payment.Charge{
+		ID: chargeID, SaleID: saleID, Provider: "mock", ProviderChargeID: "ch_1", Status: payment.StatusConfirmed,
+	}, nil)
+	mockGateway.On("Refund", ctx, "ch_1").Return(&payment.GatewayCharge{ID: "ch_1", Status: payment.StatusRefunded}, nil)
+	mockPaymentRepo.On("UpdateStatus", ctx, chargeID, payment.StatusConfirmed, payment.StatusRefunded).Return(true, nil)
+
+	charge, err := service.RefundCharge(ctx, chargeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, payment.StatusRefunded, charge.Status)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_RefundCharge_NotConfirmed(t *testing.T) {
+	ctx := context.Background()
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockGateway := new(MockGateway)
+	service := NewPaymentService(mockPaymentRepo, new(MockSaleRepository), mockGateway)
+
+	chargeID := uuid.New()
+	mockPaymentRepo.On("GetByID", ctx, chargeID).Return(&payment.Charge{ID: chargeID, Status: payment.StatusPending}, nil)
+
+	_, err := service.RefundCharge(ctx, chargeID)
+
+	assert.Equal(t, payment.ErrChargeNotRefundable, err)
+	mockGateway.AssertNotCalled(t, "Refund")
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type PixService interface {
+	GetSaleCharge(ctx context.Context, saleID uuid.UUID) (*pix.Charge, error)
+	GetSaleQRCode(ctx context.Context, saleID uuid.UUID, size int) ([]byte, error)
+}
+
+type pixService struct {
+	saleRepo sale.Repository
+	merchant pix.Merchant
+	qrCodes  pix.QRCodeEncoder
+}
+
+func NewPixService(saleRepo sale.Repository, merchant pix.Merchant, qrCodes pix.QRCodeEncoder) PixService {
+	return &pixService{
+		saleRepo: saleRepo,
+		merchant: merchant,
+		qrCodes:  qrCodes,
+	}
+}
+
+func (s *pixService) GetSaleCharge(ctx context.Context, saleID uuid.UUID) (*pix.Charge, error) {
+	if s.merchant.Validate() != nil {
+		return nil, pix.ErrNotConfigured
+	}
+	if saleID == uuid.Nil {
+		return nil, errors.New("ID da venda inválido")
+	}
+
+	current, err := s.saleRepo.GetByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sale.ErrSaleNotFound
+	}
+	if current.TotalAmount <= 0 {
+		return nil, pix.ErrAmountInvalid
+	}
+
+	charge := &pix.Charge{
+		SaleID:      current.ID,
+		OrderNumber: current.OrderNumber,
+		TxID:        pix.TxIDForOrder(current.BusinessDate, current.OrderNumber),
+		Amount:      current.TotalAmount,
+	}
+	charge.Payload, err = pix.Payload{
+		Merchant: s.merchant,
+		Amount:   charge.Amount,
+		TxID:     charge.TxID,
+	}.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+func (s *pixService) GetSaleQRCode(ctx context.Context, saleID uuid.UUID, size int) ([]byte, error) {
+	charge, err := s.GetSaleCharge(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.qrCodes.EncodePNG(charge.Payload, size)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockQRCodeEncoder struct {
+	mock.Mock
+}
+
+func (m *MockQRCodeEncoder) EncodePNG(content string, size int) ([]byte, error) {
+	args := m.Called(content, size)
+	data := args.Get(0)
+	if data == nil {
+		return nil, args.Error(1)
+	}
+	return data.([]byte), args.Error(1)
+}
+
+var testPixMerchant = pix.Merchant{
+	Key:  "123e4567-e12b-12d1-a456-426655440000",
+	Name: "Andressa Lanches",
+	City: "Sao Paulo",
+}
+
+func TestPixService_GetSaleCharge_Success(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	service := NewPixService(mockSaleRepo, testPixMerchant, new(MockQRCodeEncoder))
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{
+		ID:           saleID,
+		OrderNumber:  7,
+		BusinessDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		TotalAmount:  25.9,
+	}, nil)
+
+	charge, err := service.GetSaleCharge(ctx, saleID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PED202409010007", charge.TxID)
+	assert.Equal(t, 25.9, charge.Amount)
+	assert.Equal(t,
+		"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000520400005303986540525.905802BR5916Andressa Lanches6009Sao Paulo62190515PED202409010007630457F2",
+		charge.Payload)
+}
+
+func TestPixService_GetSaleCharge_NotConfigured(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	service := NewPixService(mockSaleRepo, pix.Merchant{Name: "Andressa Lanches"}, new(MockQRCodeEncoder))
+
+	_, err := service.GetSaleCharge(ctx, uuid.New())
+
+	assert.Equal(t, pix.ErrNotConfigured, err)
+	mockSaleRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestPixService_GetSaleCharge_SaleNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	service := NewPixService(mockSaleRepo, testPixMerchant, new(MockQRCodeEncoder))
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(nil, nil)
+
+	_, err := service.GetSaleCharge(ctx, saleID)
+
+	assert.Equal(t, sale.ErrSaleNotFound, err)
+}
+
+func TestPixService_GetSaleCharge_ZeroAmount(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	service := NewPixService(mockSaleRepo, testPixMerchant, new(MockQRCodeEncoder))
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{ID: saleID}, nil)
+
+	_, err := service.GetSaleCharge(ctx, saleID)
+
+	assert.Equal(t, pix.ErrAmountInvalid, err)
+}
+
+func TestPixService_GetSaleQRCode(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockEncoder := new(MockQRCodeEncoder)
+	service := NewPixService(mockSaleRepo, testPixMerchant, mockEncoder)
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{ID: saleID, OrderNumber: 1, TotalAmount: 10}, nil)
+	mockEncoder.On("EncodePNG", mock.AnythingOfType("string"), 300).Return([]byte("png"), nil)
+
+	image, err := service.GetSaleQRCode(ctx, saleID, 300)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("png"), image)
+	mockEncoder.AssertExpectations(t)
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newPriceChange monta a troca de preço com o autor da requisição e, quando
+// a troca vem do agendador, o agendamento aplicado.
+func newPriceChange(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID, oldPrice *float64, newPrice float64) *pricing.PriceChange {
+	change := &pricing.PriceChange{
+		ItemType:  itemType,
+		ItemID:    itemID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		ChangedAt: time.Now(),
+	}
+	if actor, ok := audit.ActorFrom(ctx); ok {
+		change.ChangedBy = actor.UserID
+		change.Actor = actor.Name
+	}
+	if scheduleID, ok := pricing.ScheduledChangeFrom(ctx); ok {
+		change.ScheduledChangeID = &scheduleID
+	}
+	return change
+}
+
+type PricingService interface {
+	ListPriceHistory(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.PriceChange, error)
+	SchedulePriceChange(ctx context.Context, s *pricing.ScheduledChange) error
+	ListScheduledPriceChanges(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.ScheduledChange, error)
+	CancelScheduledPriceChange(ctx context.Context, itemType pricing.ItemType, itemID, id uuid.UUID) error
+	ApplyDuePriceChanges(ctx context.Context) error
+	AdjustPrices(ctx context.Context, adjustment *pricing.Adjustment) (*pricing.AdjustmentResult, error)
+}
+
+type pricingService struct {
+	priceRepo       pricing.Repository
+	adjustmentRepo  pricing.AdjustmentRepository
+	productService  ProductService
+	additionService AdditionService
+	menu            MenuInvalidator
+}
+
+// NewPricingService lê os itens pelos serviços do cardápio, para que os
+// agendamentos e reajustes passem pelas mesmas validações de uma alteração
+// manual, e grava as trocas pelo adjustmentRepo, com o histórico e a
+// auditoria na mesma transação.
+func NewPricingService(priceRepo pricing.Repository, adjustmentRepo pricing.AdjustmentRepository, productService ProductService, additionService AdditionService, menu MenuInvalidator) PricingService {
+	return &pricingService{
+		priceRepo:       priceRepo,
+		adjustmentRepo:  adjustmentRepo,
+		productService:  productService,
+		additionService: additionService,
+		menu:            menu,
+	}
+}
+
+func (s *pricingService) ListPriceHistory(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.PriceChange, error) {
+	if err := s.checkPrice(ctx, itemType, itemID, nil); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.ListChanges(ctx, itemType, itemID)
+}
+
+// SchedulePriceChange confere o item e o preço com as regras do cadastro já
+// no agendamento, para que ele não falhe na hora de ser aplicado.
+func (s *pricingService) SchedulePriceChange(ctx context.Context, sc *pricing.ScheduledChange) error {
+	now := time.Now()
+	if err := sc.Validate(now); err != nil {
+		return err
+	}
+	if err := s.checkPrice(ctx, sc.ItemType, sc.ItemID, &sc.Price); err != nil {
+		return err
+	}
+
+	sc.Status = pricing.ScheduleStatusPending
+	sc.CreatedAt = now
+	sc.CreatedBy = nil
+	if actor, ok := audit.ActorFrom(ctx); ok {
+		sc.CreatedBy = actor.UserID
+	}
+	sc.AppliedAt, sc.CanceledAt = nil, nil
+	return s.priceRepo.CreateSchedule(ctx, sc)
+}
+
+func (s *pricingService) ListScheduledPriceChanges(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.ScheduledChange, error) {
+	if err := s.checkPrice(ctx, itemType, itemID, nil); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.ListSchedules(ctx, itemType, itemID)
+}
+
+func (s *pricingService) CancelScheduledPriceChange(ctx context.Context, itemType pricing.ItemType, itemID, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return pricing.ErrScheduleIdInvalid
+	}
+
+	sc, err := s.priceRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sc == nil {
+		return pricing.ErrScheduleNotFound
+	}
+	if sc.ItemType != itemType || sc.ItemID != itemID {
+		return pricing.ErrScheduleItemMismatched
+	}
+
+	resolved, err := s.priceRepo.ResolveSchedule(ctx, id, pricing.ScheduleStatusCanceled, time.Now())
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return pricing.ErrScheduleNotPending
+	}
+	return nil
+}
+
+// ApplyDuePriceChanges aplica os agendamentos vencidos em ordem de vigência.
+// Agendamentos de itens removidos são cancelados; as demais falhas ficam
+// pendentes e são tentadas de novo na próxima execução.
+func (s *pricingService) ApplyDuePriceChanges(ctx context.Context) error {
+	due, err := s.priceRepo.ListDueSchedules(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sc := range due {
+		if err := s.apply(ctx, sc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *pricingService) apply(ctx context.Context, sc *pricing.ScheduledChange) error {
+	ctx = audit.WithActor(ctx, audit.Actor{Name: pricing.SchedulerActor})
+	ctx = pricing.WithScheduledChange(ctx, sc.ID)
+
+	var err error
+	switch sc.ItemType {
+	case pricing.ItemProduct:
+		var p *product.Product
+		if p, err = s.productService.GetProductByID(ctx, sc.ItemID); err == nil {
+			updated := *p
+			updated.Price = sc.Price
+			if err = updated.Validate(); err != nil {
+				return err
+			}
+			return s.applySchedule(ctx, sc, audit.EntityProduct, p.Price, p, &updated)
+		}
+	case pricing.ItemAddition:
+		var a *addition.Addition
+		if a, err = s.additionService.GetAdditionByID(ctx, sc.ItemID); err == nil {
+			updated := *a
+			updated.Price = sc.Price
+			if err = updated.Validate(); err != nil {
+				return err
+			}
+			return s.applySchedule(ctx, sc, audit.EntityAddition, a.Price, a, &updated)
+		}
+	default:
+		err = pricing.ErrItemTypeInvalid
+	}
+
+	switch err {
+	case product.ErrProductNotFound, addition.ErrAdditionNotFound, pricing.ErrItemTypeInvalid:
+		_, err = s.priceRepo.ResolveSchedule(ctx, sc.ID, pricing.ScheduleStatusCanceled, time.Now())
+	}
+	return err
+}
+
+// applySchedule grava o novo preço, o histórico, a auditoria e a baixa do
+// agendamento na mesma transação. Um agendamento que já não está pendente,
+// aplicado ou cancelado por outra execução, é ignorado.
+func (s *pricingService) applySchedule(ctx context.Context, sc *pricing.ScheduledChange, entityType audit.EntityType, oldPrice float64, before, after any) error {
+	if oldPrice == sc.Price {
+		_, err := s.priceRepo.ResolveSchedule(ctx, sc.ID, pricing.ScheduleStatusApplied, time.Now())
+		return err
+	}
+
+	entry, err := audit.NewEntry(ctx, audit.ActionUpdate, entityType, sc.ItemID, before, after)
+	if err != nil {
+		return err
+	}
+	applied, err := s.adjustmentRepo.ApplySchedule(ctx, sc.ID, newPriceChange(ctx, sc.ItemType, sc.ItemID, &oldPrice, sc.Price), entry)
+	if err != nil {
+		return err
+	}
+	if applied {
+		invalidateMenu(s.menu)
+	}
+	return nil
+}
+
+// AdjustPrices calcula o reajuste dos itens do escopo e, fora da prévia,
+// grava os preços, o histórico e a auditoria numa única transação. Um item
+// que ficaria com preço inválido cancela o lote inteiro.
+func (s *pricingService) AdjustPrices(ctx context.Context, adjustment *pricing.Adjustment) (*pricing.AdjustmentResult, error) {
+	if err := adjustment.Validate(); err != nil {
+		return nil, err
+	}
+
+	products, err := s.adjustedProducts(ctx, adjustment)
+	if err != nil {
+		return nil, err
+	}
+	additions, err := s.adjustedAdditions(ctx, adjustment)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pricing.AdjustmentResult{DryRun: adjustment.DryRun, Items: []pricing.AdjustmentItem{}}
+	var changes []*pricing.PriceChange
+	var entries []*audit.Entry
+	add := func(itemType pricing.ItemType, entityType audit.EntityType, id uuid.UUID, name string, oldPrice, newPrice float64, before, after any) error {
+		entry, err := audit.NewEntry(ctx, audit.ActionUpdate, entityType, id, before, after)
+		if err != nil {
+			return err
+		}
+		result.Items = append(result.Items, pricing.AdjustmentItem{ItemType: itemType, ItemID: id, Name: name, OldPrice: oldPrice, NewPrice: newPrice})
+		changes = append(changes, newPriceChange(ctx, itemType, id, &oldPrice, newPrice))
+		entries = append(entries, entry)
+		return nil
+	}
+
+	for _, p := range products {
+		updated := *p
+		if updated.Price = adjustment.Apply(p.Price); updated.Price == p.Price {
+			continue
+		}
+		if err := updated.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", pricing.ErrAdjustedPriceInvalid, p.Name, err)
+		}
+		if err := add(pricing.ItemProduct, audit.EntityProduct, p.ID, p.Name, p.Price, updated.Price, p, &updated); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range additions {
+		updated := *a
+		if updated.Price = adjustment.Apply(a.Price); updated.Price == a.Price {
+			continue
+		}
+		if err := updated.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", pricing.ErrAdjustedPriceInvalid, a.Name, err)
+		}
+		if err := add(pricing.ItemAddition, audit.EntityAddition, a.ID, a.Name, a.Price, updated.Price, a, &updated); err != nil {
+			return nil, err
+		}
+	}
+
+	if adjustment.DryRun || len(changes) == 0 {
+		return result, nil
+	}
+	if err := s.adjustmentRepo.ApplyAdjustment(ctx, changes, entries); err != nil {
+		return nil, err
+	}
+	invalidateMenu(s.menu)
+	return result, nil
+}
+
+// adjustedProducts devolve os produtos das categorias e os informados por
+// ID, em ordem de nome. Um ID que não existe cancela o reajuste.
+func (s *pricingService) adjustedProducts(ctx context.Context, adjustment *pricing.Adjustment) ([]*product.Product, error) {
+	if len(adjustment.CategoryIDs) == 0 && len(adjustment.ProductIDs) == 0 {
+		return nil, nil
+	}
+	all, err := s.productService.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(adjustment.ProductIDs))
+	for _, id := range adjustment.ProductIDs {
+		wanted[id] = true
+	}
+	var products []*product.Product
+	for _, p := range all {
+		if wanted[p.ID] || slices.Contains(adjustment.CategoryIDs, p.CategoryID) {
+			products = append(products, p)
+		}
+		delete(wanted, p.ID)
+	}
+	if len(wanted) > 0 {
+		return nil, product.ErrProductNotFound
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Name < products[j].Name
+	})
+	return products, nil
+}
+
+func (s *pricingService) adjustedAdditions(ctx context.Context, adjustment *pricing.Adjustment) ([]*addition.Addition, error) {
+	if len(adjustment.AdditionIDs) == 0 {
+		return nil, nil
+	}
+	all, err := s.additionService.ListAdditions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(adjustment.AdditionIDs))
+	for _, id := range adjustment.AdditionIDs {
+		wanted[id] = true
+	}
+	var additions []*addition.Addition
+	for _, a := range all {
+		if wanted[a.ID] {
+			additions = append(additions, a)
+			delete(wanted, a.ID)
+		}
+	}
+	if len(wanted) > 0 {
+		return nil, addition.ErrAdditionNotFound
+	}
+
+	sort.Slice(additions, func(i, j int) bool {
+		return additions[i].Name < additions[j].Name
+	})
+	return additions, nil
+}
+
+// checkPrice confere se o item existe e, com price, se o preço passa nas
+// validações do cadastro.
+func (s *pricingService) checkPrice(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID, price *float64) error {
+	switch itemType {
+	case pricing.ItemProduct:
+		p, err := s.productService.GetProductByID(ctx, itemID)
+		if err != nil || price == nil {
+			return err
+		}
+		candidate := *p
+		candidate.Price = *price
+		return candidate.Validate()
+	case pricing.ItemAddition:
+		a, err := s.additionService.GetAdditionByID(ctx, itemID)
+		if err != nil || price == nil {
+			return err
+		}
+		candidate := *a
+		candidate.Price = *price
+		return candidate.Validate()
+	default:
+		return pricing.ErrItemTypeInvalid
+	}
+}
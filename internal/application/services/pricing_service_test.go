@@ -0,0 +1,221 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPriceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceRepository) AppendChange(ctx context.Context, change *pricing.PriceChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockPriceRepository) ListChanges(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.PriceChange, error) {
+	args := m.Called(ctx, itemType, itemID)
+	return args.Get(0).([]*pricing.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceRepository) CreateSchedule(ctx context.Context, schedule *pricing.ScheduledChange) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockPriceRepository) GetSchedule(ctx context.Context, id uuid.UUID) (*pricing.ScheduledChange, error) {
+	args := m.Called(ctx, id)
+	s := args.Get(0)
+	if s == nil {
+		return nil, args.Error(1)
+	}
+	return s.(*pricing.ScheduledChange), args.Error(1)
+}
+
+func (m *MockPriceRepository) ListSchedules(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.ScheduledChange, error) {
+	args := m.Called(ctx, itemType, itemID)
+	return args.Get(0).([]*pricing.ScheduledChange), args.Error(1)
+}
+
+func (m *MockPriceRepository) ListDueSchedules(ctx context.Context, now time.Time) ([]*pricing.ScheduledChange, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]*pricing.ScheduledChange), args.Error(1)
+}
+
+func (m *MockPriceRepository) ResolveSchedule(ctx context.Context, id uuid.UUID, status pricing.ScheduleStatus, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, status, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestProductService_UpdateProduct_RecordsPriceChange(t *testing.T) {
+	userID := uuid.New()
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: &userID, Name: "gerente"})
+	productRepo := new(MockProductRepository)
+	service := NewProductService(productRepo, nil)
+
+	existing := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()}
+	updated := *existing
+	updated.Price = 21
+
+	var recorded *pricing.PriceChange
+	var entry *audit.Entry
+	productRepo.On("GetByID", ctx, existing.ID).Return(existing, nil)
+	productRepo.On("Update", ctx, &updated, 18.5, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(3).(*pricing.PriceChange)
+		entry = args.Get(4).(*audit.Entry)
+	}).Return(nil).Once()
+
+	// A troca de preço e a auditoria vão na mesma gravação do produto, que
+	// confere o preço lido.
+	require.NoError(t, service.UpdateProduct(ctx, &updated))
+	require.NotNil(t, recorded)
+	assert.Equal(t, pricing.ItemProduct, recorded.ItemType)
+	assert.Equal(t, 18.5, *recorded.OldPrice)
+	assert.Equal(t, 21.0, recorded.NewPrice)
+	assert.Equal(t, &userID, recorded.ChangedBy)
+	assert.Nil(t, recorded.ScheduledChangeID)
+	require.NotNil(t, entry)
+	assert.Equal(t, audit.ActionUpdate, entry.Action)
+	assert.JSONEq(t, `{"price":{"before":18.5,"after":21}}`, string(entry.Changes))
+
+	// Salvar com o mesmo preço não gera histórico.
+	same := updated
+	same.Price = existing.Price
+	same.Name = "X-Salada especial"
+	productRepo.On("Update", ctx, &same, 18.5, (*pricing.PriceChange)(nil), mock.AnythingOfType("*audit.Entry")).Return(nil).Once()
+	require.NoError(t, service.UpdateProduct(ctx, &same))
+
+	// Um preço alterado por outra requisição no meio do caminho vira conflito.
+	productRepo.On("Update", ctx, &updated, 18.5, mock.Anything, mock.Anything).Return(product.ErrProductConflict).Once()
+	assert.Equal(t, product.ErrProductConflict, service.UpdateProduct(ctx, &updated))
+	productRepo.AssertExpectations(t)
+}
+
+func TestPricingService_SchedulePriceChange(t *testing.T) {
+	ctx := context.Background()
+	productRepo := new(MockProductRepository)
+	priceRepo := new(MockPriceRepository)
+	service := NewPricingService(priceRepo, nil, NewProductService(productRepo, nil), NewAdditionService(new(MockAdditionRepository), nil), nil)
+
+	p := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()}
+	productRepo.On("GetByID", ctx, p.ID).Return(p, nil)
+	priceRepo.On("CreateSchedule", ctx, mock.AnythingOfType("*pricing.ScheduledChange")).Return(nil).Once()
+
+	schedule := &pricing.ScheduledChange{ItemType: pricing.ItemProduct, ItemID: p.ID, Price: 22, EffectiveAt: time.Now().Add(time.Hour)}
+	require.NoError(t, service.SchedulePriceChange(ctx, schedule))
+	assert.Equal(t, pricing.ScheduleStatusPending, schedule.Status)
+
+	past := &pricing.ScheduledChange{ItemType: pricing.ItemProduct, ItemID: p.ID, Price: 22, EffectiveAt: time.Now().Add(-time.Hour)}
+	assert.Equal(t, pricing.ErrEffectiveAtPast, service.SchedulePriceChange(ctx, past))
+
+	// O preço agendado passa pelas mesmas regras do cadastro do produto.
+	free := &pricing.ScheduledChange{ItemType: pricing.ItemProduct, ItemID: p.ID, Price: 0, EffectiveAt: time.Now().Add(time.Hour)}
+	assert.Equal(t, product.ErrProductPricePositive, service.SchedulePriceChange(ctx, free))
+	assert.Equal(t, 18.5, p.Price)
+
+	missing := uuid.New()
+	productRepo.On("GetByID", ctx, missing).Return(nil, nil)
+	schedule = &pricing.ScheduledChange{ItemType: pricing.ItemProduct, ItemID: missing, Price: 22, EffectiveAt: time.Now().Add(time.Hour)}
+	assert.Equal(t, product.ErrProductNotFound, service.SchedulePriceChange(ctx, schedule))
+	priceRepo.AssertExpectations(t)
+}
+
+func TestPricingService_ApplyDuePriceChanges(t *testing.T) {
+	ctx := context.Background()
+	productRepo := new(MockProductRepository)
+	priceRepo := new(MockPriceRepository)
+	adjustmentRepo := new(MockAdjustmentRepository)
+	productService := NewProductService(productRepo, nil)
+	service := NewPricingService(priceRepo, adjustmentRepo, productService, NewAdditionService(new(MockAdditionRepository), nil), nil)
+
+	p := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()}
+	due := &pricing.ScheduledChange{ID: uuid.New(), ItemType: pricing.ItemProduct, ItemID: p.ID, Price: 22}
+	orphan := &pricing.ScheduledChange{ID: uuid.New(), ItemType: pricing.ItemProduct, ItemID: uuid.New(), Price: 9}
+
+	var recorded *pricing.PriceChange
+	var entry *audit.Entry
+	priceRepo.On("ListDueSchedules", ctx, mock.Anything).Return([]*pricing.ScheduledChange{due, orphan}, nil)
+	productRepo.On("GetByID", mock.Anything, p.ID).Return(p, nil)
+	productRepo.On("GetByID", mock.Anything, orphan.ItemID).Return(nil, nil)
+	adjustmentRepo.On("ApplySchedule", mock.Anything, due.ID, mock.AnythingOfType("*pricing.PriceChange"), mock.AnythingOfType("*audit.Entry")).Run(func(args mock.Arguments) {
+		recorded = args.Get(2).(*pricing.PriceChange)
+		entry = args.Get(3).(*audit.Entry)
+	}).Return(true, nil).Once()
+	priceRepo.On("ResolveSchedule", mock.Anything, orphan.ID, pricing.ScheduleStatusCanceled, mock.Anything).Return(true, nil).Once()
+
+	require.NoError(t, service.ApplyDuePriceChanges(ctx))
+	require.NotNil(t, recorded)
+	assert.Equal(t, 18.5, *recorded.OldPrice)
+	assert.Equal(t, 22.0, recorded.NewPrice)
+	assert.Equal(t, pricing.SchedulerActor, recorded.Actor)
+	assert.Equal(t, &due.ID, recorded.ScheduledChangeID)
+	require.NotNil(t, entry)
+	assert.Equal(t, pricing.SchedulerActor, entry.Actor)
+	assert.Equal(t, 18.5, p.Price, "o produto lido não é alterado")
+	priceRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+	productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	adjustmentRepo.AssertExpectations(t)
+}
+
+type MockAdjustmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAdjustmentRepository) ApplyAdjustment(ctx context.Context, changes []*pricing.PriceChange, entries []*audit.Entry) error {
+	args := m.Called(ctx, changes, entries)
+	return args.Error(0)
+}
+
+func (m *MockAdjustmentRepository) ApplySchedule(ctx context.Context, id uuid.UUID, change *pricing.PriceChange, entry *audit.Entry) (bool, error) {
+	args := m.Called(ctx, id, change, entry)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestPricingService_AdjustPrices(t *testing.T) {
+	ctx := context.Background()
+	productRepo := new(MockProductRepository)
+	adjustmentRepo := new(MockAdjustmentRepository)
+	service := NewPricingService(new(MockPriceRepository), adjustmentRepo, NewProductService(productRepo, nil), NewAdditionService(new(MockAdditionRepository), nil), nil)
+
+	burger := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()}
+	productRepo.On("List", ctx).Return([]*product.Product{burger}, nil)
+
+	adjustment := &pricing.Adjustment{Mode: pricing.AdjustmentFixed, Value: 1, ProductIDs: []uuid.UUID{burger.ID}, DryRun: true}
+	result, err := service.AdjustPrices(ctx, adjustment)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, 19.5, result.Items[0].NewPrice)
+	adjustmentRepo.AssertNotCalled(t, "ApplyAdjustment", mock.Anything, mock.Anything, mock.Anything)
+
+	var changes []*pricing.PriceChange
+	var entries []*audit.Entry
+	adjustmentRepo.On("ApplyAdjustment", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		changes = args.Get(1).([]*pricing.PriceChange)
+		entries = args.Get(2).([]*audit.Entry)
+	}).Return(nil).Once()
+
+	adjustment.DryRun = false
+	_, err = service.AdjustPrices(ctx, adjustment)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 18.5, *changes[0].OldPrice)
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, `{"price":{"before":18.5,"after":19.5}}`, string(entries[0].Changes))
+	assert.Equal(t, 18.5, burger.Price)
+
+	// O conflito da transação volta para quem pediu o reajuste.
+	adjustmentRepo.On("ApplyAdjustment", ctx, mock.Anything, mock.Anything).Return(pricing.ErrAdjustmentConflict).Once()
+	_, err = service.AdjustPrices(ctx, adjustment)
+	assert.Equal(t, pricing.ErrAdjustmentConflict, err)
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 	"context"
 	"errors"
@@ -18,11 +20,13 @@ type ProductService interface {
 
 type productService struct {
 	productRepo product.Repository
+	menu        MenuInvalidator
 }
 
-func NewProductService(productRepo product.Repository) ProductService {
+func NewProductService(productRepo product.Repository, menu MenuInvalidator) ProductService {
 	return &productService{
 		productRepo: productRepo,
+		menu:        menu,
 	}
 }
 
@@ -31,7 +35,19 @@ func (s *productService) CreateProduct(ctx context.Context, p *product.Product)
 		return err
 	}
 
-	return s.productRepo.Create(ctx, p)
+	// O ID é definido antes da gravação para entrar no histórico e na
+	// auditoria gravados na mesma transação.
+	p.ID = uuid.New()
+	entry, err := audit.NewEntry(ctx, audit.ActionCreate, audit.EntityProduct, p.ID, nil, p)
+	if err != nil {
+		return err
+	}
+	change := newPriceChange(ctx, pricing.ItemProduct, p.ID, nil, p.Price)
+	if err := s.productRepo.Create(ctx, p, change, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*product.Product, error) {
@@ -39,15 +55,15 @@ func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*pro
 		return nil, errors.New("ID do produto inválido")
 	}
 
-	product, err := s.productRepo.GetByID(ctx, id)
+	p, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if product == nil {
-		return nil, errors.New("produto não encontrado")
+	if p == nil {
+		return nil, product.ErrProductNotFound
 	}
 
-	return product, nil
+	return p, nil
 }
 
 func (s *productService) UpdateProduct(ctx context.Context, p *product.Product) error {
@@ -66,7 +82,22 @@ func (s *productService) UpdateProduct(ctx context.Context, p *product.Product)
 		return product.ErrProductNotFound
 	}
 
-	return s.productRepo.Update(ctx, p)
+	var change *pricing.PriceChange
+	if existingProduct.Price != p.Price {
+		oldPrice := existingProduct.Price
+		change = newPriceChange(ctx, pricing.ItemProduct, p.ID, &oldPrice, p.Price)
+	}
+	entry, err := audit.NewEntry(ctx, audit.ActionUpdate, audit.EntityProduct, p.ID, existingProduct, p)
+	if err != nil {
+		return err
+	}
+	// A gravação confere que o preço ainda é o lido, para que o histórico
+	// não registre uma troca a partir de um preço que já mudou.
+	if err := s.productRepo.Update(ctx, p, existingProduct.Price, change, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *productService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
@@ -82,7 +113,15 @@ func (s *productService) DeleteProduct(ctx context.Context, id uuid.UUID) error
 		return errors.New("produto não encontrado")
 	}
 
-	return s.productRepo.Delete(ctx, id)
+	entry, err := audit.NewEntry(ctx, audit.ActionDelete, audit.EntityProduct, id, existingProduct, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.productRepo.Delete(ctx, id, entry); err != nil {
+		return err
+	}
+	invalidateMenu(s.menu)
+	return nil
 }
 
 func (s *productService) ListProducts(ctx context.Context) ([]*product.Product, error) {
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 	"context"
 	"testing"
@@ -14,8 +16,8 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) Create(ctx context.Context, p *product.Product) error {
-	args := m.Called(ctx, p)
+func (m *MockProductRepository) Create(ctx context.Context, p *product.Product, change *pricing.PriceChange, entry *audit.Entry) error {
+	args := m.Called(ctx, p, change, entry)
 	return args.Error(0)
 }
 
@@ -28,13 +30,13 @@ func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*pro
 	return p.(*product.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) Update(ctx context.Context, p *product.Product) error {
-	args := m.Called(ctx, p)
+func (m *MockProductRepository) Update(ctx context.Context, p *product.Product, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error {
+	args := m.Called(ctx, p, oldPrice, change, entry)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
+	args := m.Called(ctx, id, entry)
 	return args.Error(0)
 }
 
@@ -46,7 +48,7 @@ func (m *MockProductRepository) List(ctx context.Context) ([]*product.Product, e
 func TestProductService_CreateProduct_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	testProduct := &product.Product{
 		Name:        "Sanduíche",
@@ -55,7 +57,7 @@ func TestProductService_CreateProduct_Success(t *testing.T) {
 		CategoryID:  uuid.New(),
 	}
 
-	mockRepo.On("Create", ctx, testProduct).Return(nil)
+	mockRepo.On("Create", ctx, testProduct, mock.AnythingOfType("*pricing.PriceChange"), mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.CreateProduct(ctx, testProduct)
 
@@ -66,7 +68,7 @@ func TestProductService_CreateProduct_Success(t *testing.T) {
 func TestProductService_CreateProduct_InvalidName(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	testProduct := &product.Product{
 		Name:  "",
@@ -83,7 +85,7 @@ func TestProductService_CreateProduct_InvalidName(t *testing.T) {
 func TestProductService_CreateProduct_InvalidPrice(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	testProduct := &product.Product{
 		Name:  "Sanduíche",
@@ -100,7 +102,7 @@ func TestProductService_CreateProduct_InvalidPrice(t *testing.T) {
 func TestProductService_GetProductByID_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	productID := uuid.New()
 	expectedProduct := &product.Product{
@@ -121,7 +123,7 @@ func TestProductService_GetProductByID_Success(t *testing.T) {
 func TestProductService_GetProductByID_NotFound(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	productID := uuid.New()
 
@@ -138,7 +140,7 @@ func TestProductService_GetProductByID_NotFound(t *testing.T) {
 func TestProductService_UpdateProduct_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	productID := uuid.New()
 	updatedProduct := &product.Product{
@@ -149,7 +151,7 @@ func TestProductService_UpdateProduct_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetByID", ctx, productID).Return(updatedProduct, nil)
-	mockRepo.On("Update", ctx, updatedProduct).Return(nil)
+	mockRepo.On("Update", ctx, updatedProduct, updatedProduct.Price, (*pricing.PriceChange)(nil), (*audit.Entry)(nil)).Return(nil)
 
 	err := service.UpdateProduct(ctx, updatedProduct)
 
@@ -160,12 +162,12 @@ func TestProductService_UpdateProduct_Success(t *testing.T) {
 func TestProductService_DeleteProduct_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	productID := uuid.New()
 
 	mockRepo.On("GetByID", ctx, productID).Return(&product.Product{}, nil)
-	mockRepo.On("Delete", ctx, productID).Return(nil)
+	mockRepo.On("Delete", ctx, productID, mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.DeleteProduct(ctx, productID)
 
@@ -176,7 +178,7 @@ func TestProductService_DeleteProduct_Success(t *testing.T) {
 func TestProductService_ListProducts_Success(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, nil)
 
 	expectedProducts := []*product.Product{
 		{
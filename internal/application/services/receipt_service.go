@@ -0,0 +1,82 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type ReceiptService interface {
+	RenderReceipt(ctx context.Context, saleID uuid.UUID, opts receipt.Options) ([]byte, error)
+	PrintReceipt(ctx context.Context, saleID uuid.UUID, opts receipt.Options) error
+	RenderDailyClosing(ctx context.Context, closing *sale.DailyClosing, format receipt.Format) ([]byte, error)
+}
+
+type receiptService struct {
+	saleRepo sale.Repository
+	renderer receipt.Renderer
+	printer  receipt.Printer
+}
+
+// NewReceiptService cria o serviço de comprovantes. printer pode ser nil
+// quando não há impressora configurada; nesse caso apenas a renderização
+// fica disponível.
+func NewReceiptService(saleRepo sale.Repository, renderer receipt.Renderer, printer receipt.Printer) ReceiptService {
+	return &receiptService{
+		saleRepo: saleRepo,
+		renderer: renderer,
+		printer:  printer,
+	}
+}
+
+func (s *receiptService) RenderReceipt(ctx context.Context, saleID uuid.UUID, opts receipt.Options) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.findSale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderer.Render(current, opts)
+}
+
+func (s *receiptService) PrintReceipt(ctx context.Context, saleID uuid.UUID, opts receipt.Options) error {
+	if s.printer == nil {
+		return receipt.ErrPrinterNotConfigured
+	}
+
+	opts.Format = receipt.FormatESCPOS
+	data, err := s.RenderReceipt(ctx, saleID, opts)
+	if err != nil {
+		return err
+	}
+
+	return s.printer.Print(ctx, data)
+}
+
+func (s *receiptService) RenderDailyClosing(ctx context.Context, closing *sale.DailyClosing, format receipt.Format) ([]byte, error) {
+	if !format.IsValid() {
+		return nil, receipt.ErrFormatInvalid
+	}
+	return s.renderer.RenderDailyClosing(closing, format)
+}
+
+func (s *receiptService) findSale(ctx context.Context, id uuid.UUID) (*sale.Sale, error) {
+	if id == uuid.Nil {
+		return nil, errors.New("ID da venda inválido")
+	}
+
+	current, err := s.saleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sale.ErrSaleNotFound
+	}
+	return current, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReceiptRenderer struct {
+	mock.Mock
+}
+
+func (m *MockReceiptRenderer) Render(s *sale.Sale, opts receipt.Options) ([]byte, error) {
+	args := m.Called(s, opts)
+	data := args.Get(0)
+	if data == nil {
+		return nil, args.Error(1)
+	}
+	return data.([]byte), args.Error(1)
+}
+
+func (m *MockReceiptRenderer) RenderDailyClosing(c *sale.DailyClosing, format receipt.Format) ([]byte, error) {
+	args := m.Called(c, format)
+	data := args.Get(0)
+	if data == nil {
+		return nil, args.Error(1)
+	}
+	return data.([]byte), args.Error(1)
+}
+
+type MockPrinter struct {
+	mock.Mock
+}
+
+func (m *MockPrinter) Print(ctx context.Context, data []byte) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func TestReceiptService_RenderReceipt_Success(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockRenderer := new(MockReceiptRenderer)
+	service := NewReceiptService(mockSaleRepo, mockRenderer, nil)
+
+	saleID := uuid.New()
+	existingSale := &sale.Sale{ID: saleID, OrderNumber: 7}
+	opts := receipt.Options{Kind: receipt.KindCustomer, Format: receipt.FormatText}
+
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(existingSale, nil)
+	mockRenderer.On("Render", existingSale, opts).Return([]byte("Pedido 7"), nil)
+
+	data, err := service.RenderReceipt(ctx, saleID, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("Pedido 7"), data)
+	mockSaleRepo.AssertExpectations(t)
+	mockRenderer.AssertExpectations(t)
+}
+
+func TestReceiptService_RenderReceipt_SaleNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockRenderer := new(MockReceiptRenderer)
+	service := NewReceiptService(mockSaleRepo, mockRenderer, nil)
+
+	saleID := uuid.New()
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(nil, nil)
+
+	_, err := service.RenderReceipt(ctx, saleID, receipt.Options{Kind: receipt.KindCustomer, Format: receipt.FormatText})
+
+	assert.Equal(t, sale.ErrSaleNotFound, err)
+	mockRenderer.AssertNotCalled(t, "Render")
+}
+
+func TestReceiptService_RenderReceipt_InvalidOptions(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockRenderer := new(MockReceiptRenderer)
+	service := NewReceiptService(mockSaleRepo, mockRenderer, nil)
+
+	_, err := service.RenderReceipt(ctx, uuid.New(), receipt.Options{Kind: "invoice", Format: receipt.FormatText})
+
+	assert.Equal(t, receipt.ErrKindInvalid, err)
+	mockSaleRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestReceiptService_PrintReceipt_ForcesESCPOS(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockRenderer := new(MockReceiptRenderer)
+	mockPrinter := new(MockPrinter)
+	service := NewReceiptService(mockSaleRepo, mockRenderer, mockPrinter)
+
+	saleID := uuid.New()
+	existingSale := &sale.Sale{ID: saleID}
+	expectedOpts := receipt.Options{Kind: receipt.KindKitchen, Format: receipt.FormatESCPOS}
+
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(existingSale, nil)
+	mockRenderer.On("Render", existingSale, expectedOpts).Return([]byte{0x1b, 0x40}, nil)
+	mockPrinter.On("Print", ctx, []byte{0x1b, 0x40}).Return(nil)
+
+	err := service.PrintReceipt(ctx, saleID, receipt.Options{Kind: receipt.KindKitchen, Format: receipt.FormatText})
+
+	assert.NoError(t, err)
+	mockRenderer.AssertExpectations(t)
+	mockPrinter.AssertExpectations(t)
+}
+
+func TestReceiptService_PrintReceipt_PrinterNotConfigured(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockRenderer := new(MockReceiptRenderer)
+	service := NewReceiptService(mockSaleRepo, mockRenderer, nil)
+
+	err := service.PrintReceipt(ctx, uuid.New(), receipt.Options{Kind: receipt.KindCustomer})
+
+	assert.Equal(t, receipt.ErrPrinterNotConfigured, err)
+	mockSaleRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestReceiptService_RenderDailyClosing_InvalidFormat(t *testing.T) {
+	ctx := context.Background()
+	mockRenderer := new(MockReceiptRenderer)
+	service := NewReceiptService(new(MockSaleRepository), mockRenderer, nil)
+
+	_, err := service.RenderDailyClosing(ctx, &sale.DailyClosing{}, "docx")
+
+	assert.Equal(t, receipt.ErrFormatInvalid, err)
+	mockRenderer.AssertNotCalled(t, "RenderDailyClosing")
+}
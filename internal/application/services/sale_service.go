@@ -2,10 +2,17 @@ package services
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/kitchen"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
 	"context"
 	"errors"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,36 +22,84 @@ type SaleService interface {
 	CreateSale(ctx context.Context, s *sale.Sale) error
 	GetSaleByID(ctx context.Context, id uuid.UUID) (*sale.Sale, error)
 	ListSales(ctx context.Context) ([]*sale.Sale, error)
+	FindSalesByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*sale.Sale, error)
 	DeleteSale(ctx context.Context, id uuid.UUID) error
+	UpdateSaleStatus(ctx context.Context, id uuid.UUID, status sale.Status) error
+	UpdateSaleItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string) error
+	UpdateSaleItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status sale.ItemStatus) error
+	BumpStationTicket(ctx context.Context, saleID, stationID uuid.UUID) error
+	ListStationTickets(ctx context.Context, stationID uuid.UUID) ([]sale.StationTicket, error)
+	// CurrentBusinessDate devolve o dia de operação em andamento.
+	CurrentBusinessDate() time.Time
+	// Os relatórios recebem datas, não instantes: o período [start, end) vai
+	// do dia de operação start até o dia anterior a end, no fuso da loja.
+	TipsReport(ctx context.Context, start, end time.Time) ([]sale.TipsSummary, error)
+	DailyClosingReport(ctx context.Context, businessDate time.Time) (*sale.DailyClosing, error)
+	MarginReport(ctx context.Context, start, end time.Time) (*sale.MarginReport, error)
+	ABCReport(ctx context.Context, start, end time.Time, metric sale.ABCMetric) (*sale.ABCReport, error)
+	HeatmapReport(ctx context.Context, start, end time.Time) (*sale.Heatmap, error)
 }
 
 type saleService struct {
-	saleRepo     sale.Repository
-	productRepo  product.Repository
-	additionRepo addition.Repository
+	saleRepo      sale.Repository
+	productRepo   product.Repository
+	additionRepo  addition.Repository
+	categoryRepo  category.Repository
+	userRepo      user.Repository
+	serviceCharge sale.ServiceChargePolicy
+	calendar      sale.Calendar
+	kitchenEvents kitchen.Publisher
 }
 
 func NewSaleService(
 	saleRepo sale.Repository,
 	productRepo product.Repository,
 	additionRepo addition.Repository,
+	categoryRepo category.Repository,
+	userRepo user.Repository,
+	serviceCharge sale.ServiceChargePolicy,
+	calendar sale.Calendar,
+	kitchenEvents kitchen.Publisher,
 ) SaleService {
 	return &saleService{
-		saleRepo:     saleRepo,
-		productRepo:  productRepo,
-		additionRepo: additionRepo,
+		saleRepo:      saleRepo,
+		productRepo:   productRepo,
+		additionRepo:  additionRepo,
+		categoryRepo:  categoryRepo,
+		userRepo:      userRepo,
+		serviceCharge: serviceCharge,
+		calendar:      calendar,
+		kitchenEvents: kitchenEvents,
 	}
 }
 
-func (s *saleService) CreateSale(ctx context.Context, sale *sale.Sale) error {
-	if sale.Date.IsZero() {
-		sale.Date = time.Now()
+func (s *saleService) CreateSale(ctx context.Context, newSale *sale.Sale) error {
+	if newSale.Date.IsZero() {
+		newSale.Date = time.Now()
 	}
+	newSale.BusinessDate = s.calendar.BusinessDateOf(newSale.Date)
+
+	if newSale.OrderType == "" {
+		newSale.OrderType = sale.OrderTypeTakeaway
+	}
+	if !newSale.OrderType.IsValid() {
+		return sale.ErrOrderTypeInvalid
+	}
+	if newSale.ServiceChargeWaived && newSale.ServiceChargeWaiverReason == "" {
+		return sale.ErrServiceChargeWaiverReason
+	}
+	for _, payment := range newSale.Payments {
+		if err := payment.Validate(); err != nil {
+			return err
+		}
+	}
+	newSale.Status = sale.StatusReceived
 
 	var totalSaleAmount float64
+	var stations map[uuid.UUID]*uuid.UUID
 
-	for i := range sale.Items {
-		item := &sale.Items[i]
+	for i := range newSale.Items {
+		item := &newSale.Items[i]
 
 		if item.ProductID == uuid.Nil {
 			return errors.New("ID do produto é obrigatório para o item da venda")
@@ -55,12 +110,24 @@ func (s *saleService) CreateSale(ctx context.Context, sale *sale.Sale) error {
 			return errors.New("produto não encontrado")
 		}
 		item.UnitPrice = prod.Price
+		item.UnitCost = prod.UnitCost
+		item.ProductName = prod.Name
+		item.CategoryID = prod.CategoryID
+		item.Status = sale.ItemStatusPending
+
+		if stations == nil && prod.CategoryID != uuid.Nil {
+			if stations, err = s.categoryStations(ctx); err != nil {
+				return err
+			}
+		}
+		item.StationID = stations[prod.CategoryID]
 
 		if item.Quantity <= 0 {
 			return errors.New("a quantidade deve ser positiva")
 		}
 
 		var totalAdditionsPrice float64
+		unitCost := prod.UnitCost
 		for j := range item.Additions {
 			additionID := item.Additions[j].ID
 			if additionID == uuid.Nil {
@@ -72,16 +139,42 @@ func (s *saleService) CreateSale(ctx context.Context, sale *sale.Sale) error {
 				return errors.New("acréscimo não encontrado")
 			}
 			totalAdditionsPrice += add.Price
+			unitCost = addCost(unitCost, add.UnitCost)
 			item.Additions[j] = *add
 		}
 
 		item.TotalPrice = (item.UnitPrice + totalAdditionsPrice) * float64(item.Quantity)
+		item.TotalCost = nil
+		if unitCost != nil {
+			totalCost := math.Round(*unitCost*float64(item.Quantity)*100) / 100
+			item.TotalCost = &totalCost
+		}
 		totalSaleAmount += item.TotalPrice
 	}
 
-	sale.TotalAmount = totalSaleAmount - sale.Discount + sale.AdditionalCharges
+	newSale.ServiceCharge = 0
+	if !newSale.ServiceChargeWaived && s.serviceCharge.AppliesTo(newSale.OrderType) {
+		base := totalSaleAmount - newSale.Discount
+		if base > 0 {
+			newSale.ServiceCharge = math.Round(base*s.serviceCharge.Percentage) / 100
+		}
+	}
+
+	newSale.TotalAmount = totalSaleAmount - newSale.Discount + newSale.AdditionalCharges + newSale.ServiceCharge
+
+	// O ID é definido antes da gravação para entrar na auditoria gravada na
+	// mesma transação.
+	newSale.ID = uuid.New()
+	entry, err := audit.NewEntry(ctx, audit.ActionCreate, audit.EntitySale, newSale.ID, nil, newSale)
+	if err != nil {
+		return err
+	}
+	if err := s.saleRepo.Create(ctx, newSale, entry); err != nil {
+		return err
+	}
 
-	return s.saleRepo.Create(ctx, sale)
+	s.publishKitchenEvent(ctx, kitchen.EventSaleCreated, newSale)
+	return nil
 }
 
 func (s *saleService) GetSaleByID(ctx context.Context, id uuid.UUID) (*sale.Sale, error) {
@@ -108,6 +201,14 @@ func (s *saleService) ListSales(ctx context.Context) ([]*sale.Sale, error) {
 	return sales, nil
 }
 
+func (s *saleService) FindSalesByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*sale.Sale, error) {
+	if orderNumber <= 0 {
+		return nil, sale.ErrOrderNumberInvalid
+	}
+
+	return s.saleRepo.ListByOrderNumber(ctx, s.calendar.Date(businessDate), orderNumber)
+}
+
 func (s *saleService) DeleteSale(ctx context.Context, id uuid.UUID) error {
 	if id == uuid.Nil {
 		return errors.New("ID da venda inválido")
@@ -121,5 +222,434 @@ func (s *saleService) DeleteSale(ctx context.Context, id uuid.UUID) error {
 		return errors.New("venda não encontrada")
 	}
 
-	return s.saleRepo.Delete(ctx, id)
+	entry, err := audit.NewEntry(ctx, audit.ActionDelete, audit.EntitySale, id, existingSale, nil)
+	if err != nil {
+		return err
+	}
+	return s.saleRepo.Delete(ctx, id, entry)
+}
+
+func (s *saleService) UpdateSaleStatus(ctx context.Context, id uuid.UUID, status sale.Status) error {
+	if id == uuid.Nil {
+		return errors.New("ID da venda inválido")
+	}
+	if !status.IsValid() {
+		return sale.ErrSaleStatusInvalid
+	}
+
+	before, err := s.currentSale(ctx, id)
+	if err != nil {
+		return err
+	}
+	entry, err := saleUpdateEntry(ctx, before, func(after *sale.Sale) {
+		after.Status = status
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.saleRepo.UpdateStatus(ctx, id, status, entry); err != nil {
+		return err
+	}
+
+	_, err = s.afterSaleChange(ctx, kitchen.EventSaleStatusChanged, before)
+	return err
+}
+
+func (s *saleService) UpdateSaleItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string) error {
+	if saleID == uuid.Nil {
+		return errors.New("ID da venda inválido")
+	}
+
+	before, err := s.currentSale(ctx, saleID)
+	if err != nil {
+		return err
+	}
+	entry, err := saleUpdateEntry(ctx, before, func(after *sale.Sale) {
+		for i := range after.Items {
+			if after.Items[i].ItemID == itemID {
+				after.Items[i].Notes = notes
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.saleRepo.UpdateItemNotes(ctx, saleID, itemID, notes, entry); err != nil {
+		return err
+	}
+
+	_, err = s.afterSaleChange(ctx, kitchen.EventItemNotesChanged, before)
+	return err
+}
+
+func (s *saleService) UpdateSaleItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status sale.ItemStatus) error {
+	if saleID == uuid.Nil {
+		return errors.New("ID da venda inválido")
+	}
+	if !status.IsValid() {
+		return sale.ErrItemStatusInvalid
+	}
+
+	before, err := s.currentSale(ctx, saleID)
+	if err != nil {
+		return err
+	}
+	entry, err := saleUpdateEntry(ctx, before, func(after *sale.Sale) {
+		for i := range after.Items {
+			if after.Items[i].ItemID == itemID {
+				after.Items[i].Status = status
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.saleRepo.UpdateItemStatus(ctx, saleID, itemID, status, entry); err != nil {
+		return err
+	}
+
+	return s.afterItemsStatusChange(ctx, before)
+}
+
+func (s *saleService) BumpStationTicket(ctx context.Context, saleID, stationID uuid.UUID) error {
+	if saleID == uuid.Nil {
+		return errors.New("ID da venda inválido")
+	}
+
+	before, err := s.currentSale(ctx, saleID)
+	if err != nil {
+		return err
+	}
+	entry, err := saleUpdateEntry(ctx, before, func(after *sale.Sale) {
+		for i := range after.Items {
+			if stationItem := after.Items[i].StationID; stationItem != nil && *stationItem == stationID {
+				after.Items[i].Status = sale.ItemStatusBumped
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.saleRepo.UpdateStationItemsStatus(ctx, saleID, stationID, sale.ItemStatusBumped, entry); err != nil {
+		return err
+	}
+
+	return s.afterItemsStatusChange(ctx, before)
+}
+
+func (s *saleService) ListStationTickets(ctx context.Context, stationID uuid.UUID) ([]sale.StationTicket, error) {
+	openSales, err := s.saleRepo.ListByStatus(ctx, sale.StatusReceived, sale.StatusPreparing)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]sale.StationTicket, 0)
+	for _, sl := range openSales {
+		for _, ticket := range sl.Tickets() {
+			if ticket.StationID == nil || *ticket.StationID != stationID {
+				continue
+			}
+			if ticket.Status != sale.ItemStatusBumped {
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets, nil
+}
+
+// afterItemsStatusChange notifica a cozinha e acompanha o status da venda:
+// ela entra em preparo quando o primeiro item fica pronto, fica pronta
+// quando todas as comandas das estações foram concluídas e volta ao preparo
+// quando um item de uma venda pronta é reaberto.
+func (s *saleService) afterItemsStatusChange(ctx context.Context, before *sale.Sale) error {
+	current, err := s.afterSaleChange(ctx, kitchen.EventItemStatusChanged, before)
+	if err != nil {
+		return err
+	}
+
+	if current.Status != sale.StatusReceived && current.Status != sale.StatusPreparing && current.Status != sale.StatusReady {
+		return nil
+	}
+
+	next := current.Status
+	if current.Status == sale.StatusReady {
+		if !current.TicketsDone() {
+			next = sale.StatusPreparing
+		}
+	} else if current.TicketsDone() {
+		next = sale.StatusReady
+	} else if current.Status == sale.StatusReceived {
+		for _, item := range current.Items {
+			if item.Status.IsDone() {
+				next = sale.StatusPreparing
+				break
+			}
+		}
+	}
+	if next == current.Status {
+		return nil
+	}
+
+	return s.UpdateSaleStatus(ctx, current.ID, next)
+}
+
+// addCost soma o custo de um acréscimo ao custo do item; sem algum dos
+// custos, o custo do item fica desconhecido.
+func addCost(total, cost *float64) *float64 {
+	if total == nil || cost == nil {
+		return nil
+	}
+	sum := *total + *cost
+	return &sum
+}
+
+// categoryStations devolve a estação de preparo de cada categoria. O
+// cadastro é lido uma vez por venda; categorias sem estação ou que não
+// existem mais ficam sem estação.
+func (s *saleService) categoryStations(ctx context.Context) (map[uuid.UUID]*uuid.UUID, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stations := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	for _, c := range categories {
+		stations[c.ID] = c.StationID
+	}
+	return stations, nil
+}
+
+func (s *saleService) currentSale(ctx context.Context, id uuid.UUID) (*sale.Sale, error) {
+	current, err := s.saleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sale.ErrSaleNotFound
+	}
+	return current, nil
+}
+
+// saleUpdateEntry monta a auditoria de uma alteração aplicando change a uma
+// cópia da venda, para que o registro seja gravado junto com a alteração.
+func saleUpdateEntry(ctx context.Context, before *sale.Sale, change func(after *sale.Sale)) (*audit.Entry, error) {
+	after := saleSnapshot(before)
+	change(after)
+	return audit.NewEntry(ctx, audit.ActionUpdate, audit.EntitySale, before.ID, before, after)
+}
+
+// afterSaleChange relê a venda alterada e notifica a cozinha.
+func (s *saleService) afterSaleChange(ctx context.Context, eventType kitchen.EventType, before *sale.Sale) (*sale.Sale, error) {
+	current, err := s.currentSale(ctx, before.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishKitchenEvent(ctx, eventType, current)
+	return current, nil
+}
+
+// publishKitchenEvent envia uma cópia da venda para que alterações
+// posteriores não afetem eventos já publicados.
+func (s *saleService) publishKitchenEvent(ctx context.Context, eventType kitchen.EventType, current *sale.Sale) {
+	if s.kitchenEvents == nil {
+		return
+	}
+
+	s.kitchenEvents.Publish(ctx, kitchen.Event{
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Sale:       saleSnapshot(current),
+	})
+}
+
+// saleSnapshot copia a venda com os seus itens, que podem então ser
+// alterados sem afetar o original.
+func saleSnapshot(current *sale.Sale) *sale.Sale {
+	snapshot := *current
+	snapshot.Items = make([]sale.SaleItem, len(current.Items))
+	copy(snapshot.Items, current.Items)
+	return &snapshot
+}
+
+func (s *saleService) CurrentBusinessDate() time.Time {
+	return s.calendar.BusinessDateOf(time.Now())
+}
+
+// listByPeriod busca as vendas dos dias de operação de start até o dia
+// anterior a end.
+func (s *saleService) listByPeriod(ctx context.Context, start, end time.Time) ([]*sale.Sale, error) {
+	from, to := s.calendar.Period(start, end)
+	return s.saleRepo.ListByPeriod(ctx, from, to)
+}
+
+// TipsReport agrupa as taxas de serviço pelo operador que lançou a venda,
+// com o nome atual do cadastro de usuários. Vendas sem operador, anteriores
+// ao login dos funcionários ou lançadas por integrações, são agrupadas pelo
+// nome digitado em Employee.
+func (s *saleService) TipsReport(ctx context.Context, start, end time.Time) ([]sale.TipsSummary, error) {
+	sales, err := s.listByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type tipsKey struct {
+		operatorID uuid.UUID
+		employee   string
+	}
+
+	// Vendas canceladas não pagam a taxa de serviço e ficam fora do
+	// repasse, como no fechamento do caixa.
+	byEmployee := make(map[tipsKey]*sale.TipsSummary)
+	for _, sl := range sales {
+		if sl.Status == sale.StatusCanceled || sl.ServiceCharge <= 0 {
+			continue
+		}
+		key := tipsKey{employee: sl.Employee}
+		if sl.OperatorID != nil {
+			key = tipsKey{operatorID: *sl.OperatorID}
+		}
+		summary, ok := byEmployee[key]
+		if !ok {
+			summary = &sale.TipsSummary{Employee: sl.Employee, OperatorID: sl.OperatorID}
+			byEmployee[key] = summary
+		} else if summary.Employee == "" {
+			summary.Employee = sl.Employee
+		}
+		summary.SalesCount++
+		summary.ServiceCharge += sl.ServiceCharge
+	}
+
+	report := make([]sale.TipsSummary, 0, len(byEmployee))
+	for _, summary := range byEmployee {
+		if summary.OperatorID != nil {
+			name, err := s.operatorName(ctx, *summary.OperatorID)
+			if err != nil {
+				return nil, err
+			}
+			if name != "" {
+				summary.Employee = name
+			}
+		}
+		summary.ServiceCharge = math.Round(summary.ServiceCharge*100) / 100
+		report = append(report, *summary)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Employee != report[j].Employee {
+			return report[i].Employee < report[j].Employee
+		}
+		return operatorKey(report[i].OperatorID) < operatorKey(report[j].OperatorID)
+	})
+
+	return report, nil
+}
+
+// operatorName devolve o nome do usuário ou, sem ele, o nome de login. Fica
+// vazio quando o usuário foi removido ou não há cadastro de usuários, e o
+// relatório mantém o nome digitado na venda.
+func (s *saleService) operatorName(ctx context.Context, id uuid.UUID) (string, error) {
+	if s.userRepo == nil {
+		return "", nil
+	}
+	u, err := s.userRepo.GetByID(ctx, id)
+	if err != nil || u == nil {
+		return "", err
+	}
+	if u.Name != "" {
+		return u.Name, nil
+	}
+	return u.Username, nil
+}
+
+func operatorKey(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func (s *saleService) DailyClosingReport(ctx context.Context, businessDate time.Time) (*sale.DailyClosing, error) {
+	businessDate = s.calendar.Date(businessDate)
+	sales, err := s.listByPeriod(ctx, businessDate, businessDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	return sale.NewDailyClosing(businessDate, sales), nil
+}
+
+func (s *saleService) MarginReport(ctx context.Context, start, end time.Time) (*sale.MarginReport, error) {
+	sales, err := s.listByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+	return sale.NewMarginReport(sales, names), nil
+}
+
+// ABCReport compara o período com o período imediatamente anterior com o
+// mesmo número de dias.
+func (s *saleService) ABCReport(ctx context.Context, start, end time.Time, metric sale.ABCMetric) (*sale.ABCReport, error) {
+	if !metric.IsValid() {
+		return nil, sale.ErrABCMetricInvalid
+	}
+
+	days := int(math.Round(end.Sub(start).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	previousStart := start.AddDate(0, 0, -days)
+
+	from, to := s.calendar.Period(start, end)
+	current, err := s.saleRepo.SumItemsByPeriod(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	from, to = s.calendar.Period(previousStart, start)
+	previous, err := s.saleRepo.SumItemsByPeriod(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.productRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	additions, err := s.additionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make([]sale.ABCItem, 0, len(products)+len(additions))
+	for _, p := range products {
+		catalog = append(catalog, sale.ABCItem{ItemType: pricing.ItemProduct, ItemID: p.ID, Name: p.Name})
+	}
+	for _, a := range additions {
+		catalog = append(catalog, sale.ABCItem{ItemType: pricing.ItemAddition, ItemID: a.ID, Name: a.Name})
+	}
+
+	report := sale.NewABCReport(metric, current, previous, catalog)
+	report.Start = s.calendar.Date(start)
+	report.End = s.calendar.Date(end).AddDate(0, 0, -1)
+	report.PreviousStart = s.calendar.Date(previousStart)
+	report.PreviousEnd = s.calendar.Date(start).AddDate(0, 0, -1)
+	return report, nil
+}
+
+func (s *saleService) HeatmapReport(ctx context.Context, start, end time.Time) (*sale.Heatmap, error) {
+	from, to := s.calendar.Period(start, end)
+	cells, err := s.saleRepo.SumByWeekdayHour(ctx, from, to, s.calendar.Timezone())
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := sale.NewHeatmap(cells, s.calendar)
+	heatmap.Start = s.calendar.Date(start)
+	heatmap.End = s.calendar.Date(end).AddDate(0, 0, -1)
+	return heatmap, nil
 }
@@ -2,8 +2,12 @@ package services
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/kitchen"
 	"andressa-lanches/internal/domain/product"
 	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
 	"context"
 	"testing"
 	"time"
@@ -13,13 +17,20 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var testServiceCharge = sale.ServiceChargePolicy{
+	Percentage: 10,
+	OrderTypes: []sale.OrderType{sale.OrderTypeDineIn},
+}
+
+var testCalendar = sale.Calendar{Location: time.UTC}
+
 // Mocks dos repositórios
 type MockSaleRepository struct {
 	mock.Mock
 }
 
-func (m *MockSaleRepository) Create(ctx context.Context, s *sale.Sale) error {
-	args := m.Called(ctx, s)
+func (m *MockSaleRepository) Create(ctx context.Context, s *sale.Sale, entry *audit.Entry) error {
+	args := m.Called(ctx, s, entry)
 	return args.Error(0)
 }
 
@@ -37,17 +48,71 @@ func (m *MockSaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 	return args.Get(0).([]*sale.Sale), args.Error(1)
 }
 
-func (m *MockSaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MockSaleRepository) ListByPeriod(ctx context.Context, start, end time.Time) ([]*sale.Sale, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]*sale.Sale), args.Error(1)
+}
+
+func (m *MockSaleRepository) SumItemsByPeriod(ctx context.Context, start, end time.Time) ([]sale.ItemSales, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]sale.ItemSales), args.Error(1)
+}
+
+func (m *MockSaleRepository) SumByWeekdayHour(ctx context.Context, start, end time.Time, timezone string) ([]sale.HeatmapCell, error) {
+	args := m.Called(ctx, start, end, timezone)
+	return args.Get(0).([]sale.HeatmapCell), args.Error(1)
+}
+
+func (m *MockSaleRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status sale.Status, entry *audit.Entry) error {
+	args := m.Called(ctx, id, status, entry)
 	return args.Error(0)
 }
 
+func (m *MockSaleRepository) UpdateItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string, entry *audit.Entry) error {
+	args := m.Called(ctx, saleID, itemID, notes, entry)
+	return args.Error(0)
+}
+
+func (m *MockSaleRepository) ListByStatus(ctx context.Context, statuses ...sale.Status) ([]*sale.Sale, error) {
+	args := m.Called(ctx, statuses)
+	return args.Get(0).([]*sale.Sale), args.Error(1)
+}
+
+func (m *MockSaleRepository) UpdateItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status sale.ItemStatus, entry *audit.Entry) error {
+	args := m.Called(ctx, saleID, itemID, status, entry)
+	return args.Error(0)
+}
+
+func (m *MockSaleRepository) UpdateStationItemsStatus(ctx context.Context, saleID, stationID uuid.UUID, status sale.ItemStatus, entry *audit.Entry) error {
+	args := m.Called(ctx, saleID, stationID, status, entry)
+	return args.Error(0)
+}
+
+func (m *MockSaleRepository) ListByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*sale.Sale, error) {
+	args := m.Called(ctx, businessDate, orderNumber)
+	return args.Get(0).([]*sale.Sale), args.Error(1)
+}
+
+func (m *MockSaleRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
+	args := m.Called(ctx, id, entry)
+	return args.Error(0)
+}
+
+type MockKitchenPublisher struct {
+	mock.Mock
+}
+
+func (m *MockKitchenPublisher) Publish(ctx context.Context, event kitchen.Event) {
+	m.Called(ctx, event)
+}
+
 func TestSaleService_CreateSale_Success(t *testing.T) {
 	ctx := context.Background()
 	mockSaleRepo := new(MockSaleRepository)
 	mockProductRepo := new(MockProductRepository)
 	mockAdditionRepo := new(MockAdditionRepository)
-	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
 
 	productID := uuid.New()
 	additionID := uuid.New()
@@ -78,7 +143,7 @@ func TestSaleService_CreateSale_Success(t *testing.T) {
 		Price: 2.50,
 	}, nil)
 
-	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale")).Return(nil)
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.CreateSale(ctx, testSale)
 
@@ -96,7 +161,8 @@ func TestSaleService_CreateSale_InvalidProductID(t *testing.T) {
 	mockSaleRepo := new(MockSaleRepository)
 	mockProductRepo := new(MockProductRepository)
 	mockAdditionRepo := new(MockAdditionRepository)
-	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
 
 	testSale := &sale.Sale{
 		Items: []sale.SaleItem{
@@ -120,7 +186,8 @@ func TestSaleService_GetSaleByID_Success(t *testing.T) {
 	mockSaleRepo := new(MockSaleRepository)
 	mockProductRepo := new(MockProductRepository)
 	mockAdditionRepo := new(MockAdditionRepository)
-	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
 
 	saleID := uuid.New()
 	expectedSale := &sale.Sale{
@@ -143,12 +210,13 @@ func TestSaleService_DeleteSale_Success(t *testing.T) {
 	mockSaleRepo := new(MockSaleRepository)
 	mockProductRepo := new(MockProductRepository)
 	mockAdditionRepo := new(MockAdditionRepository)
-	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
 
 	saleID := uuid.New()
 
 	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{}, nil)
-	mockSaleRepo.On("Delete", ctx, saleID).Return(nil)
+	mockSaleRepo.On("Delete", ctx, saleID, mock.AnythingOfType("*audit.Entry")).Return(nil)
 
 	err := service.DeleteSale(ctx, saleID)
 
@@ -161,7 +229,8 @@ func TestSaleService_ListSales_Success(t *testing.T) {
 	mockSaleRepo := new(MockSaleRepository)
 	mockProductRepo := new(MockProductRepository)
 	mockAdditionRepo := new(MockAdditionRepository)
-	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
 
 	expectedSales := []*sale.Sale{
 		{
@@ -184,3 +253,552 @@ func TestSaleService_ListSales_Success(t *testing.T) {
 	assert.Equal(t, expectedSales, result)
 	mockSaleRepo.AssertExpectations(t)
 }
+
+func TestSaleService_CreateSale_ServiceChargeDineIn(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	productID := uuid.New()
+
+	testSale := &sale.Sale{
+		OrderType:         sale.OrderTypeDineIn,
+		Discount:          5.00,
+		AdditionalCharges: 3.00,
+		Items: []sale.SaleItem{
+			{ProductID: productID, Quantity: 3},
+		},
+	}
+
+	mockProductRepo.On("GetByID", ctx, productID).Return(&product.Product{
+		ID:    productID,
+		Name:  "X-Burguer",
+		Price: 25.00,
+	}, nil)
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7.00, testSale.ServiceCharge)
+	assert.Equal(t, 75.00-5.00+3.00+7.00, testSale.TotalAmount)
+	mockSaleRepo.AssertExpectations(t)
+}
+
+func TestSaleService_CreateSale_ServiceChargeNotEligible(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	productID := uuid.New()
+
+	testSale := &sale.Sale{
+		Items: []sale.SaleItem{
+			{ProductID: productID, Quantity: 1},
+		},
+	}
+
+	mockProductRepo.On("GetByID", ctx, productID).Return(&product.Product{
+		ID:    productID,
+		Price: 20.00,
+	}, nil)
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.NoError(t, err)
+	assert.Equal(t, sale.OrderTypeTakeaway, testSale.OrderType)
+	assert.Zero(t, testSale.ServiceCharge)
+	assert.Equal(t, 20.00, testSale.TotalAmount)
+}
+
+func TestSaleService_CreateSale_ServiceChargeWaived(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	productID := uuid.New()
+
+	testSale := &sale.Sale{
+		OrderType:                 sale.OrderTypeDineIn,
+		ServiceChargeWaived:       true,
+		ServiceChargeWaiverReason: "Cliente reclamou da demora",
+		Items: []sale.SaleItem{
+			{ProductID: productID, Quantity: 1},
+		},
+	}
+
+	mockProductRepo.On("GetByID", ctx, productID).Return(&product.Product{
+		ID:    productID,
+		Price: 20.00,
+	}, nil)
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.NoError(t, err)
+	assert.Zero(t, testSale.ServiceCharge)
+	assert.Equal(t, 20.00, testSale.TotalAmount)
+}
+
+func TestSaleService_CreateSale_WaiverWithoutReason(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	testSale := &sale.Sale{
+		OrderType:           sale.OrderTypeDineIn,
+		ServiceChargeWaived: true,
+	}
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.Equal(t, sale.ErrServiceChargeWaiverReason, err)
+	mockSaleRepo.AssertNotCalled(t, "Create")
+}
+
+func TestSaleService_TipsReport(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	mockSaleRepo.On("ListByPeriod", ctx, start, end).Return([]*sale.Sale{
+		{Employee: "Maria", ServiceCharge: 5.50},
+		{Employee: "João", ServiceCharge: 3.20},
+		{Employee: "Maria", ServiceCharge: 2.30},
+		{Employee: "João"},
+		// Venda cancelada não entra no repasse.
+		{Employee: "João", ServiceCharge: 4.10, Status: sale.StatusCanceled},
+	}, nil)
+
+	report, err := service.TipsReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []sale.TipsSummary{
+		{Employee: "João", SalesCount: 1, ServiceCharge: 3.20},
+		{Employee: "Maria", SalesCount: 2, ServiceCharge: 7.80},
+	}, report)
+}
+
+func TestSaleService_TipsReport_GroupsByOperator(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := NewSaleService(mockSaleRepo, new(MockProductRepository), new(MockAdditionRepository), new(MockCategoryRepository), mockUserRepo,
+		testServiceCharge, testCalendar, nil)
+
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	maria, removed := uuid.New(), uuid.New()
+
+	// O nome digitado varia entre as vendas do mesmo operador; o relatório
+	// usa o do cadastro. Vendas antigas, sem operador, ficam pelo nome
+	// digitado, e o operador removido fica com o nome da venda.
+	mockSaleRepo.On("ListByPeriod", ctx, start, end).Return([]*sale.Sale{
+		{Employee: "Maria", OperatorID: &maria, ServiceCharge: 5.50},
+		{Employee: "maria s.", OperatorID: &maria, ServiceCharge: 2.30},
+		{Employee: "Maria", ServiceCharge: 1.00},
+		{Employee: "Pedro", OperatorID: &removed, ServiceCharge: 4.00},
+	}, nil)
+	mockUserRepo.On("GetByID", ctx, maria).Return(&user.User{ID: maria, Username: "maria", Name: "Maria Silva"}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, removed).Return(nil, nil).Once()
+
+	report, err := service.TipsReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []sale.TipsSummary{
+		{Employee: "Maria", SalesCount: 1, ServiceCharge: 1.00},
+		{Employee: "Maria Silva", OperatorID: &maria, SalesCount: 2, ServiceCharge: 7.80},
+		{Employee: "Pedro", OperatorID: &removed, SalesCount: 1, ServiceCharge: 4.00},
+	}, report)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSaleService_HeatmapReport_UsesStoreTimezone(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	store := time.FixedZone("BRT", -3*60*60)
+	service := NewSaleService(mockSaleRepo, new(MockProductRepository), new(MockAdditionRepository), new(MockCategoryRepository), nil,
+		testServiceCharge, sale.Calendar{Location: store}, nil)
+
+	// Os dias do período valem no fuso da loja, não no fuso de start e end.
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, store)
+	to := time.Date(2024, 9, 2, 0, 0, 0, 0, store)
+
+	// O repositório agrupa no fuso da loja e devolve só as células com vendas.
+	mockSaleRepo.On("SumByWeekdayHour", ctx, from, to, "BRT").Return([]sale.HeatmapCell{
+		{Weekday: time.Sunday, Hour: 22, Orders: 2, Revenue: 42.5},
+		{Weekday: time.Sunday, Hour: 12, Orders: 1, Revenue: 20},
+	}, nil)
+
+	heatmap, err := service.HeatmapReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BRT", heatmap.Timezone)
+	assert.Equal(t, from, heatmap.Start)
+	assert.Equal(t, from, heatmap.End)
+	assert.Len(t, heatmap.Cells, 168)
+	assert.Equal(t, 3, heatmap.Orders)
+	assert.Equal(t, 62.5, heatmap.Revenue)
+	assert.Equal(t, sale.HeatmapCell{Weekday: time.Sunday, Hour: 22, Orders: 2, Revenue: 42.5}, *heatmap.Cell(time.Sunday, 22))
+	assert.Equal(t, 1, heatmap.Cell(time.Sunday, 12).Orders)
+	assert.Equal(t, 0, heatmap.Cell(time.Monday, 1).Orders)
+}
+
+func TestSaleService_CreateSale_InvalidPayment(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	err := service.CreateSale(ctx, &sale.Sale{Payments: []sale.Payment{{Method: "cheque", Amount: 10}}})
+	assert.Equal(t, sale.ErrPaymentMethodInvalid, err)
+
+	err = service.CreateSale(ctx, &sale.Sale{Payments: []sale.Payment{{Method: sale.PaymentMethodPix}}})
+	assert.Equal(t, sale.ErrPaymentAmountPositive, err)
+
+	mockSaleRepo.AssertNotCalled(t, "Create")
+}
+
+func TestSaleService_DailyClosingReport(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	day := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	burgerID := uuid.New()
+	sodaID := uuid.New()
+
+	mockSaleRepo.On("ListByPeriod", ctx, day, day.AddDate(0, 0, 1)).Return([]*sale.Sale{
+		{
+			OrderType:     sale.OrderTypeDineIn,
+			TotalAmount:   33,
+			ServiceCharge: 3,
+			Items: []sale.SaleItem{
+				{ProductID: burgerID, ProductName: "X-Bacon", Quantity: 1, TotalPrice: 22},
+				{ProductID: sodaID, ProductName: "Refrigerante", Quantity: 2, TotalPrice: 8},
+			},
+			Payments: []sale.Payment{{Method: sale.PaymentMethodCash, Amount: 50}},
+		},
+		{
+			OrderType:   sale.OrderTypeTakeaway,
+			TotalAmount: 20,
+			Discount:    2,
+			Items: []sale.SaleItem{
+				{ProductID: burgerID, ProductName: "X-Bacon", Quantity: 1, TotalPrice: 22},
+			},
+			Payments: []sale.Payment{{Method: sale.PaymentMethodPix, Amount: 15}},
+		},
+		{
+			Status:      sale.StatusCanceled,
+			TotalAmount: 100,
+			Items: []sale.SaleItem{
+				{ProductID: sodaID, ProductName: "Refrigerante", Quantity: 25, TotalPrice: 100},
+			},
+		},
+	}, nil)
+
+	report, err := service.DailyClosingReport(ctx, day.Add(15*time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, day, report.BusinessDate)
+	assert.Equal(t, 2, report.SalesCount)
+	assert.Equal(t, 1, report.CanceledCount)
+	assert.Equal(t, 52.0, report.ItemsAmount)
+	assert.Equal(t, 2.0, report.Discount)
+	assert.Equal(t, 3.0, report.ServiceCharge)
+	assert.Equal(t, 53.0, report.TotalAmount)
+	assert.Equal(t, 26.5, report.AverageTicket)
+	assert.Equal(t, []sale.OrderTypeSummary{
+		{OrderType: sale.OrderTypeDineIn, SalesCount: 1, TotalAmount: 33},
+		{OrderType: sale.OrderTypeTakeaway, SalesCount: 1, TotalAmount: 20},
+	}, report.OrderTypes)
+	// O troco devolvido em dinheiro não entra no caixa
+	assert.Equal(t, []sale.PaymentSummary{
+		{Method: sale.PaymentMethodCash, Amount: 33},
+		{Method: sale.PaymentMethodPix, Amount: 15},
+	}, report.Payments)
+	assert.Equal(t, 5.0, report.Unpaid)
+	assert.Equal(t, []sale.ProductSalesSummary{
+		{ProductID: burgerID, ProductName: "X-Bacon", Quantity: 2, TotalAmount: 44},
+		{ProductID: sodaID, ProductName: "Refrigerante", Quantity: 2, TotalAmount: 8},
+	}, report.Products)
+}
+
+func TestSaleService_CreateSale_PublishesKitchenEvent(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockPublisher := new(MockKitchenPublisher)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, mockPublisher)
+
+	productID := uuid.New()
+	categoryID := uuid.New()
+	stationID := uuid.New()
+
+	testSale := &sale.Sale{
+		Items: []sale.SaleItem{
+			{ProductID: productID, Quantity: 1, Notes: "sem cebola"},
+		},
+	}
+
+	mockProductRepo.On("GetByID", ctx, productID).Return(&product.Product{
+		ID:         productID,
+		Name:       "X-Salada",
+		Price:      18.00,
+		CategoryID: categoryID,
+	}, nil)
+	mockCategoryRepo.On("List", ctx).Return([]*category.Category{
+		{ID: categoryID, Name: "Lanches", StationID: &stationID},
+	}, nil)
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+	mockPublisher.On("Publish", ctx, mock.MatchedBy(func(e kitchen.Event) bool {
+		item := e.Sale.Items[0]
+		return e.Type == kitchen.EventSaleCreated &&
+			e.Sale.Status == sale.StatusReceived &&
+			item.ProductName == "X-Salada" &&
+			item.CategoryID == categoryID &&
+			*item.StationID == stationID &&
+			item.Status == sale.ItemStatusPending &&
+			item.Notes == "sem cebola"
+	})).Return()
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.NoError(t, err)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestSaleService_UpdateSaleStatus_Success(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockPublisher := new(MockKitchenPublisher)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, mockPublisher)
+
+	saleID := uuid.New()
+
+	mockSaleRepo.On("UpdateStatus", ctx, saleID, sale.StatusReady, mock.AnythingOfType("*audit.Entry")).Return(nil)
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{ID: saleID, Status: sale.StatusReady}, nil)
+	mockPublisher.On("Publish", ctx, mock.MatchedBy(func(e kitchen.Event) bool {
+		return e.Type == kitchen.EventSaleStatusChanged && e.Sale.ID == saleID
+	})).Return()
+
+	err := service.UpdateSaleStatus(ctx, saleID, sale.StatusReady)
+
+	assert.NoError(t, err)
+	mockSaleRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestSaleService_UpdateSaleStatus_InvalidStatus(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	err := service.UpdateSaleStatus(ctx, uuid.New(), sale.Status("burnt"))
+
+	assert.Equal(t, sale.ErrSaleStatusInvalid, err)
+	mockSaleRepo.AssertNotCalled(t, "UpdateStatus")
+}
+
+func TestSaleService_UpdateSaleItemStatus_CompletesSale(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	saleID := uuid.New()
+	grill := uuid.New()
+	counter := uuid.New()
+
+	mockSaleRepo.On("UpdateItemStatus", ctx, saleID, 2, sale.ItemStatusReady, mock.AnythingOfType("*audit.Entry")).Return(nil)
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{
+		ID:     saleID,
+		Status: sale.StatusPreparing,
+		Items: []sale.SaleItem{
+			{ItemID: 1, StationID: &grill, Status: sale.ItemStatusBumped},
+			{ItemID: 2, StationID: &counter, Status: sale.ItemStatusReady},
+		},
+	}, nil)
+	mockSaleRepo.On("UpdateStatus", ctx, saleID, sale.StatusReady, mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.UpdateSaleItemStatus(ctx, saleID, 2, sale.ItemStatusReady)
+
+	assert.NoError(t, err)
+	mockSaleRepo.AssertExpectations(t)
+}
+
+func TestSaleService_UpdateSaleItemStatus_PendingTicketKeepsSaleOpen(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	saleID := uuid.New()
+	grill := uuid.New()
+	counter := uuid.New()
+
+	mockSaleRepo.On("UpdateItemStatus", ctx, saleID, 2, sale.ItemStatusReady, mock.AnythingOfType("*audit.Entry")).Return(nil)
+	mockSaleRepo.On("GetByID", ctx, saleID).Return(&sale.Sale{
+		ID:     saleID,
+		Status: sale.StatusReceived,
+		Items: []sale.SaleItem{
+			{ItemID: 1, StationID: &grill, Status: sale.ItemStatusPending},
+			{ItemID: 2, StationID: &counter, Status: sale.ItemStatusReady},
+		},
+	}, nil)
+	mockSaleRepo.On("UpdateStatus", ctx, saleID, sale.StatusPreparing, mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.UpdateSaleItemStatus(ctx, saleID, 2, sale.ItemStatusReady)
+
+	assert.NoError(t, err)
+	mockSaleRepo.AssertExpectations(t)
+	mockSaleRepo.AssertNotCalled(t, "UpdateStatus", ctx, saleID, sale.StatusReady)
+}
+
+func TestSaleService_ListStationTickets(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	grill := uuid.New()
+	counter := uuid.New()
+	openSale := &sale.Sale{
+		ID:     uuid.New(),
+		Status: sale.StatusReceived,
+		Items: []sale.SaleItem{
+			{ItemID: 1, StationID: &grill, Status: sale.ItemStatusPending},
+			{ItemID: 2, StationID: &counter, Status: sale.ItemStatusPending},
+			{ItemID: 3, StationID: &grill, Status: sale.ItemStatusReady},
+		},
+	}
+	bumpedSale := &sale.Sale{
+		ID:     uuid.New(),
+		Status: sale.StatusPreparing,
+		Items: []sale.SaleItem{
+			{ItemID: 1, StationID: &grill, Status: sale.ItemStatusBumped},
+		},
+	}
+
+	mockSaleRepo.On("ListByStatus", ctx, []sale.Status{sale.StatusReceived, sale.StatusPreparing}).
+		Return([]*sale.Sale{openSale, bumpedSale}, nil)
+
+	tickets, err := service.ListStationTickets(ctx, grill)
+
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, openSale.ID, tickets[0].SaleID)
+	assert.Equal(t, sale.ItemStatusPending, tickets[0].Status)
+	assert.Len(t, tickets[0].Items, 2)
+}
+
+func TestSaleService_CreateSale_SetsBusinessDate(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	testSale := &sale.Sale{Date: time.Date(2024, 9, 1, 21, 30, 0, 0, time.UTC)}
+
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	err := service.CreateSale(ctx, testSale)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), testSale.BusinessDate)
+}
+
+func TestSaleService_BusinessDayCutoff(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	store := time.FixedZone("BRT", -3*60*60)
+	calendar := sale.Calendar{Location: store, CutoffHour: 2}
+	service := NewSaleService(mockSaleRepo, new(MockProductRepository), new(MockAdditionRepository), new(MockCategoryRepository), nil,
+		testServiceCharge, calendar, nil)
+
+	mockSaleRepo.On("Create", ctx, mock.AnythingOfType("*sale.Sale"), mock.AnythingOfType("*audit.Entry")).Return(nil)
+
+	// 04h30 UTC é 01h30 na loja: a venda ainda pertence ao dia anterior.
+	lateSale := &sale.Sale{Date: time.Date(2024, 9, 2, 4, 30, 0, 0, time.UTC)}
+	assert.NoError(t, service.CreateSale(ctx, lateSale))
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, store), lateSale.BusinessDate)
+
+	nextSale := &sale.Sale{Date: time.Date(2024, 9, 2, 2, 0, 0, 0, store)}
+	assert.NoError(t, service.CreateSale(ctx, nextSale))
+	assert.Equal(t, time.Date(2024, 9, 2, 0, 0, 0, 0, store), nextSale.BusinessDate)
+
+	// O fechamento do dia 1 vai das 2h do dia 1 às 2h do dia 2 no fuso da loja.
+	from := time.Date(2024, 9, 1, 2, 0, 0, 0, store)
+	to := time.Date(2024, 9, 2, 2, 0, 0, 0, store)
+	mockSaleRepo.On("ListByPeriod", ctx, from, to).Return([]*sale.Sale{lateSale}, nil)
+
+	report, err := service.DailyClosingReport(ctx, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, store), report.BusinessDate)
+	assert.Equal(t, 1, report.SalesCount)
+}
+
+func TestSaleService_FindSalesByOrderNumber(t *testing.T) {
+	ctx := context.Background()
+	mockSaleRepo := new(MockSaleRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockAdditionRepo := new(MockAdditionRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewSaleService(mockSaleRepo, mockProductRepo, mockAdditionRepo, mockCategoryRepo, nil, testServiceCharge, testCalendar, nil)
+
+	businessDate := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	expectedSales := []*sale.Sale{{ID: uuid.New(), OrderNumber: 7, BusinessDate: businessDate}}
+
+	mockSaleRepo.On("ListByOrderNumber", ctx, businessDate, 7).Return(expectedSales, nil)
+
+	result, err := service.FindSalesByOrderNumber(ctx, businessDate.Add(15*time.Hour), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSales, result)
+
+	_, err = service.FindSalesByOrderNumber(ctx, businessDate, 0)
+	assert.Equal(t, sale.ErrOrderNumberInvalid, err)
+}
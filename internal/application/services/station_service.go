@@ -0,0 +1,93 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/station"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type StationService interface {
+	CreateStation(ctx context.Context, st *station.Station) error
+	GetStationByID(ctx context.Context, id uuid.UUID) (*station.Station, error)
+	UpdateStation(ctx context.Context, st *station.Station) error
+	DeleteStation(ctx context.Context, id uuid.UUID) error
+	ListStations(ctx context.Context) ([]*station.Station, error)
+}
+
+type stationService struct {
+	stationRepo station.Repository
+}
+
+func NewStationService(stationRepo station.Repository) StationService {
+	return &stationService{
+		stationRepo: stationRepo,
+	}
+}
+
+func (s *stationService) CreateStation(ctx context.Context, st *station.Station) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+
+	return s.stationRepo.Create(ctx, st)
+}
+
+func (s *stationService) GetStationByID(ctx context.Context, id uuid.UUID) (*station.Station, error) {
+	if id == uuid.Nil {
+		return nil, station.ErrStationIdInvalid
+	}
+
+	st, err := s.stationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, station.ErrStationNotFound
+	}
+
+	return st, nil
+}
+
+func (s *stationService) UpdateStation(ctx context.Context, st *station.Station) error {
+	if st.ID == uuid.Nil {
+		return station.ErrStationIdInvalid
+	}
+	if err := st.Validate(); err != nil {
+		return err
+	}
+
+	existingStation, err := s.stationRepo.GetByID(ctx, st.ID)
+	if err != nil {
+		return err
+	}
+	if existingStation == nil {
+		return station.ErrStationNotFound
+	}
+
+	return s.stationRepo.Update(ctx, st)
+}
+
+func (s *stationService) DeleteStation(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return station.ErrStationIdInvalid
+	}
+
+	existingStation, err := s.stationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existingStation == nil {
+		return station.ErrStationNotFound
+	}
+
+	return s.stationRepo.Delete(ctx, id)
+}
+
+func (s *stationService) ListStations(ctx context.Context) ([]*station.Station, error) {
+	stations, err := s.stationRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/station"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStationRepository struct {
+	mock.Mock
+}
+
+func (m *MockStationRepository) Create(ctx context.Context, s *station.Station) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockStationRepository) GetByID(ctx context.Context, id uuid.UUID) (*station.Station, error) {
+	args := m.Called(ctx, id)
+	s := args.Get(0)
+	if s == nil {
+		return nil, args.Error(1)
+	}
+	return s.(*station.Station), args.Error(1)
+}
+
+func (m *MockStationRepository) Update(ctx context.Context, s *station.Station) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockStationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockStationRepository) List(ctx context.Context) ([]*station.Station, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*station.Station), args.Error(1)
+}
+
+func TestStationService_CreateStation_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockStationRepository)
+	service := NewStationService(mockRepo)
+
+	testStation := &station.Station{Name: "Chapa"}
+
+	mockRepo.On("Create", ctx, testStation).Return(nil)
+
+	err := service.CreateStation(ctx, testStation)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStationService_CreateStation_InvalidName(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockStationRepository)
+	service := NewStationService(mockRepo)
+
+	err := service.CreateStation(ctx, &station.Station{})
+
+	assert.Equal(t, station.ErrStationNameRequired, err)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestStationService_UpdateStation_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockStationRepository)
+	service := NewStationService(mockRepo)
+
+	stationID := uuid.New()
+	mockRepo.On("GetByID", ctx, stationID).Return(nil, nil)
+
+	err := service.UpdateStation(ctx, &station.Station{ID: stationID, Name: "Balcão"})
+
+	assert.Equal(t, station.ErrStationNotFound, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestStationService_DeleteStation_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockStationRepository)
+	service := NewStationService(mockRepo)
+
+	stationID := uuid.New()
+
+	mockRepo.On("GetByID", ctx, stationID).Return(&station.Station{ID: stationID}, nil)
+	mockRepo.On("Delete", ctx, stationID).Return(nil)
+
+	err := service.DeleteStation(ctx, stationID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/terminal"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TerminalService interface {
+	CreateTerminal(ctx context.Context, t *terminal.Terminal) (*terminal.Registration, error)
+	GetTerminalByID(ctx context.Context, id uuid.UUID) (*terminal.Terminal, error)
+	UpdateTerminal(ctx context.Context, t *terminal.Terminal) error
+	RotateDeviceToken(ctx context.Context, id uuid.UUID) (*terminal.Registration, error)
+	DeleteTerminal(ctx context.Context, id uuid.UUID) error
+	ListTerminals(ctx context.Context) ([]*terminal.Terminal, error)
+}
+
+type terminalService struct {
+	terminalRepo terminal.Repository
+}
+
+func NewTerminalService(terminalRepo terminal.Repository) TerminalService {
+	return &terminalService{
+		terminalRepo: terminalRepo,
+	}
+}
+
+// CreateTerminal cadastra o terminal e devolve o token do dispositivo, que
+// não pode ser consultado depois.
+func (s *terminalService) CreateTerminal(ctx context.Context, t *terminal.Terminal) (*terminal.Registration, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := terminal.NewDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	t.TokenHash = terminal.HashDeviceToken(token)
+	t.CreatedAt = time.Now()
+	if err := s.terminalRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return &terminal.Registration{Terminal: t, DeviceToken: token}, nil
+}
+
+func (s *terminalService) GetTerminalByID(ctx context.Context, id uuid.UUID) (*terminal.Terminal, error) {
+	if id == uuid.Nil {
+		return nil, terminal.ErrTerminalIdInvalid
+	}
+
+	t, err := s.terminalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, terminal.ErrTerminalNotFound
+	}
+	return t, nil
+}
+
+// UpdateTerminal altera o nome e a situação. Desativar o terminal encerra as
+// sessões de operador abertas nele.
+func (s *terminalService) UpdateTerminal(ctx context.Context, t *terminal.Terminal) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.GetTerminalByID(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	t.TokenHash = existing.TokenHash
+	t.LastSeenAt = existing.LastSeenAt
+	t.CreatedAt = existing.CreatedAt
+	return s.terminalRepo.Update(ctx, t)
+}
+
+// RotateDeviceToken troca o token do dispositivo, por exemplo quando o tablet
+// é perdido; o token anterior deixa de valer para novos logins e as sessões
+// de operador abertas com ele são encerradas.
+func (s *terminalService) RotateDeviceToken(ctx context.Context, id uuid.UUID) (*terminal.Registration, error) {
+	t, err := s.GetTerminalByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := terminal.NewDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	t.TokenHash = terminal.HashDeviceToken(token)
+	if err := s.terminalRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	return &terminal.Registration{Terminal: t, DeviceToken: token}, nil
+}
+
+func (s *terminalService) DeleteTerminal(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetTerminalByID(ctx, id); err != nil {
+		return err
+	}
+	return s.terminalRepo.Delete(ctx, id)
+}
+
+func (s *terminalService) ListTerminals(ctx context.Context) ([]*terminal.Terminal, error) {
+	return s.terminalRepo.List(ctx)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/user"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	CreateUser(ctx context.Context, u *user.User, password string) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (*user.User, error)
+	UpdateUser(ctx context.Context, u *user.User, password string) error
+	SetUserPIN(ctx context.Context, id uuid.UUID, pin string) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	ListUsers(ctx context.Context) ([]*user.User, error)
+	EnsureOwner(ctx context.Context, username, password string) error
+}
+
+type userService struct {
+	userRepo user.Repository
+}
+
+func NewUserService(userRepo user.Repository) UserService {
+	return &userService{
+		userRepo: userRepo,
+	}
+}
+
+func (s *userService) CreateUser(ctx context.Context, u *user.User, password string) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	if err := u.SetPassword(password); err != nil {
+		return err
+	}
+	return s.create(ctx, u)
+}
+
+// create confere o nome de usuário antes de gravar; o repositório devolve
+// ErrUsernameTaken quando outro cadastro com o mesmo nome chega primeiro.
+func (s *userService) create(ctx context.Context, u *user.User) error {
+	existing, err := s.userRepo.GetByUsername(ctx, u.Username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return user.ErrUsernameTaken
+	}
+
+	now := time.Now()
+	u.CreatedAt, u.UpdatedAt = now, now
+	return s.userRepo.Create(ctx, u)
+}
+
+func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if id == uuid.Nil {
+		return nil, user.ErrUserIdInvalid
+	}
+
+	u, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// UpdateUser mantém a senha atual quando password vem vazio.
+func (s *userService) UpdateUser(ctx context.Context, u *user.User, password string) error {
+	if u.ID == uuid.Nil {
+		return user.ErrUserIdInvalid
+	}
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.GetUserByID(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	if u.Username != existing.Username {
+		taken, err := s.userRepo.GetByUsername(ctx, u.Username)
+		if err != nil {
+			return err
+		}
+		if taken != nil {
+			return user.ErrUsernameTaken
+		}
+	}
+	if existing.Role == user.RoleOwner && existing.Active && (u.Role != user.RoleOwner || !u.Active) {
+		if err := s.ensureAnotherOwner(ctx); err != nil {
+			return err
+		}
+	}
+
+	u.PasswordHash, u.PINHash = existing.PasswordHash, existing.PINHash
+	if password != "" {
+		if err := u.SetPassword(password); err != nil {
+			return err
+		}
+	}
+	u.CreatedAt = existing.CreatedAt
+	u.UpdatedAt = time.Now()
+	return s.userRepo.Update(ctx, u)
+}
+
+// SetUserPIN define o PIN usado nos terminais; PIN vazio o remove.
+func (s *userService) SetUserPIN(ctx context.Context, id uuid.UUID, pin string) error {
+	u, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.SetPIN(pin); err != nil {
+		return err
+	}
+	u.UpdatedAt = time.Now()
+	return s.userRepo.Update(ctx, u)
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	existing, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Role == user.RoleOwner && existing.Active {
+		if err := s.ensureAnotherOwner(ctx); err != nil {
+			return err
+		}
+	}
+	return s.userRepo.Delete(ctx, id)
+}
+
+func (s *userService) ListUsers(ctx context.Context) ([]*user.User, error) {
+	return s.userRepo.List(ctx)
+}
+
+// EnsureOwner cria o primeiro proprietário com as credenciais da configuração
+// quando ainda não há usuários cadastrados. O AUTH_PASSWORD não passa pelo
+// tamanho mínimo da senha; veja user.SetBootstrapPassword.
+func (s *userService) EnsureOwner(ctx context.Context, username, password string) error {
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+	if username == "" || password == "" {
+		return user.ErrOwnerRequired
+	}
+	owner := &user.User{Username: username, Name: username, Role: user.RoleOwner, Active: true}
+	if err := owner.Validate(); err != nil {
+		return err
+	}
+	if err := owner.SetBootstrapPassword(password); err != nil {
+		return err
+	}
+	return s.create(ctx, owner)
+}
+
+func (s *userService) ensureAnotherOwner(ctx context.Context) error {
+	owners, err := s.userRepo.CountActiveByRole(ctx, user.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return user.ErrLastOwner
+	}
+	return nil
+}
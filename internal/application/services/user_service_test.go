@@ -0,0 +1,141 @@
+package services
+
+import (
+	"andressa-lanches/internal/domain/user"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	u := args.Get(0)
+	if u == nil {
+		return nil, args.Error(1)
+	}
+	return u.(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	u := args.Get(0)
+	if u == nil {
+		return nil, args.Error(1)
+	}
+	return u.(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]*user.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountActiveByRole(ctx context.Context, role user.Role) (int, error) {
+	args := m.Called(ctx, role)
+	return args.Int(0), args.Error(1)
+}
+
+func newTestUser(t *testing.T, role user.Role, password string) *user.User {
+	u := &user.User{ID: uuid.New(), Username: string(role), Role: role, Active: true}
+	require.NoError(t, u.SetPassword(password))
+	return u
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+
+	mockRepo.On("GetByUsername", ctx, "maria").Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+
+	u := &user.User{Username: "Maria", Role: user.RoleCashier, Active: true}
+	err := service.CreateUser(ctx, u, "segredo123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "maria", u.Username)
+	assert.True(t, u.CheckPassword("segredo123"))
+	assert.False(t, u.CreatedAt.IsZero())
+
+	mockRepo.On("GetByUsername", ctx, "joao").Return(&user.User{ID: uuid.New(), Username: "joao"}, nil)
+	err = service.CreateUser(ctx, &user.User{Username: "joao", Role: user.RoleKitchen}, "segredo123")
+	assert.Equal(t, user.ErrUsernameTaken, err)
+
+	err = service.CreateUser(ctx, &user.User{Username: "ana", Role: user.RoleKitchen}, "curta")
+	assert.Equal(t, user.ErrPasswordTooShort, err)
+}
+
+func TestUserService_UpdateUser_KeepsPasswordAndLastOwner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+
+	owner := newTestUser(t, user.RoleOwner, "segredo123")
+	mockRepo.On("GetByID", ctx, owner.ID).Return(owner, nil)
+	mockRepo.On("CountActiveByRole", ctx, user.RoleOwner).Return(1, nil)
+
+	demoted := &user.User{ID: owner.ID, Username: owner.Username, Role: user.RoleManager, Active: true}
+	assert.Equal(t, user.ErrLastOwner, service.UpdateUser(ctx, demoted, ""))
+	assert.Equal(t, user.ErrLastOwner, service.DeleteUser(ctx, owner.ID))
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+	renamed := &user.User{ID: owner.ID, Username: owner.Username, Name: "Andressa", Role: user.RoleOwner, Active: true}
+	require.NoError(t, service.UpdateUser(ctx, renamed, ""))
+	assert.Equal(t, owner.PasswordHash, renamed.PasswordHash)
+	assert.True(t, renamed.CheckPassword("segredo123"))
+}
+
+func TestUserService_EnsureOwner(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	mockRepo.On("List", ctx).Return([]*user.User{}, nil)
+	mockRepo.On("GetByUsername", ctx, "andressa").Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.Role == user.RoleOwner && u.Active && u.CheckPassword("segredo123")
+	})).Return(nil)
+
+	assert.NoError(t, service.EnsureOwner(ctx, "andressa", "segredo123"))
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	empty := NewUserService(mockRepo)
+	assert.Equal(t, user.ErrOwnerRequired, empty.EnsureOwner(ctx, "", ""))
+
+	legacy := new(MockUserRepository)
+	legacy.On("List", ctx).Return([]*user.User{}, nil)
+	legacy.On("GetByUsername", ctx, "admin").Return(nil, nil)
+	legacy.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.CheckPassword("1234")
+	})).Return(nil)
+	assert.NoError(t, NewUserService(legacy).EnsureOwner(ctx, "admin", "1234"), "a senha antiga da configuração continua valendo")
+
+	existing := new(MockUserRepository)
+	existing.On("List", ctx).Return([]*user.User{{ID: uuid.New()}}, nil)
+	assert.NoError(t, NewUserService(existing).EnsureOwner(ctx, "", ""))
+	existing.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
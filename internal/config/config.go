@@ -2,6 +2,8 @@ package config
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,6 +14,66 @@ var (
 	ServerAddress string
 	AuthUser      string
 	AuthPassword  string
+
+	AuthAccessTokenTTL     time.Duration
+	AuthRefreshTokenTTL    time.Duration
+	AuthOperatorSessionTTL time.Duration
+
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginLockout          time.Duration
+	LoginMaxLockout       time.Duration
+
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+	TrustedProxies    []string
+
+	ServiceChargePercentage float64
+	ServiceChargeOrderTypes []string
+
+	KitchenFeedHistory int
+
+	PriceSchedulerInterval time.Duration
+
+	StoreTimezone         string
+	BusinessDayCutoffHour int
+
+	PrinterAddress   string
+	PrinterDevice    string
+	PrinterWidth     int
+	PrinterAccents   string
+	PrinterQueueSize int
+	ReceiptHeader    []string
+	ReceiptFooter    []string
+
+	PixKey          string
+	PixMerchantName string
+	PixMerchantCity string
+
+	PaymentGateway       string
+	PaymentWebhookSecret string
+
+	FiscalEnvironment       string
+	FiscalSeries            int
+	FiscalCNPJ              string
+	FiscalStateRegistration string
+	FiscalCompanyName       string
+	FiscalTradeName         string
+	FiscalTaxRegime         int
+	FiscalStreet            string
+	FiscalStreetNumber      string
+	FiscalDistrict          string
+	FiscalCity              string
+	FiscalCityCode          string
+	FiscalState             string
+	FiscalZipCode           string
+	FiscalCSCID             string
+	FiscalCSC               string
+	FiscalQRCodeURL         string
+	FiscalConsultURL        string
+	FiscalCertFile          string
+	FiscalKeyFile           string
+	FiscalTransmitter       string
 )
 
 type Config struct {
@@ -20,12 +82,100 @@ type Config struct {
 	ServerAddress string
 	AuthUser      string
 	AuthPassword  string
+
+	AuthAccessTokenTTL     time.Duration
+	AuthRefreshTokenTTL    time.Duration
+	AuthOperatorSessionTTL time.Duration
+
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginLockout          time.Duration
+	LoginMaxLockout       time.Duration
+
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+	TrustedProxies    []string
+
+	ServiceChargePercentage float64
+	ServiceChargeOrderTypes []string
+
+	KitchenFeedHistory int
+
+	PriceSchedulerInterval time.Duration
+
+	StoreTimezone         string
+	BusinessDayCutoffHour int
+
+	PrinterAddress   string
+	PrinterDevice    string
+	PrinterWidth     int
+	PrinterAccents   string
+	PrinterQueueSize int
+	ReceiptHeader    []string
+	ReceiptFooter    []string
+
+	PixKey          string
+	PixMerchantName string
+	PixMerchantCity string
+
+	PaymentGateway       string
+	PaymentWebhookSecret string
+
+	FiscalEnvironment       string
+	FiscalSeries            int
+	FiscalCNPJ              string
+	FiscalStateRegistration string
+	FiscalCompanyName       string
+	FiscalTradeName         string
+	FiscalTaxRegime         int
+	FiscalStreet            string
+	FiscalStreetNumber      string
+	FiscalDistrict          string
+	FiscalCity              string
+	FiscalCityCode          string
+	FiscalState             string
+	FiscalZipCode           string
+	FiscalCSCID             string
+	FiscalCSC               string
+	FiscalQRCodeURL         string
+	FiscalConsultURL        string
+	FiscalCertFile          string
+	FiscalKeyFile           string
+	FiscalTransmitter       string
 }
 
 func LoadConfig() Config {
 	viper.SetConfigFile(".env")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("AUTH_ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("AUTH_REFRESH_TOKEN_TTL", "720h")
+	viper.SetDefault("AUTH_OPERATOR_SESSION_TTL", "30m")
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS_PER_IP", 20)
+	viper.SetDefault("LOGIN_LOCKOUT", "1m")
+	viper.SetDefault("LOGIN_MAX_LOCKOUT", "1h")
+	viper.SetDefault("RATE_LIMIT_REQUESTS", 300)
+	viper.SetDefault("RATE_LIMIT_WINDOW", "1m")
+	viper.SetDefault("SERVICE_CHARGE_PERCENTAGE", 10)
+	viper.SetDefault("SERVICE_CHARGE_ORDER_TYPES", "dine_in")
+	viper.SetDefault("KITCHEN_FEED_HISTORY", 500)
+	viper.SetDefault("PRICE_SCHEDULER_INTERVAL", "1m")
+	viper.SetDefault("STORE_TIMEZONE", "America/Sao_Paulo")
+	viper.SetDefault("BUSINESS_DAY_CUTOFF_HOUR", 2)
+	viper.SetDefault("PRINTER_WIDTH", 48)
+	viper.SetDefault("PRINTER_ACCENTS", "cp850")
+	viper.SetDefault("PRINTER_QUEUE_SIZE", 50)
+	viper.SetDefault("RECEIPT_HEADER", "Andressa Lanches")
+	viper.SetDefault("RECEIPT_FOOTER", "Obrigado pela preferência!")
+	viper.SetDefault("PIX_MERCHANT_NAME", "Andressa Lanches")
+	viper.SetDefault("PAYMENT_GATEWAY", "fake")
+	viper.SetDefault("FISCAL_ENVIRONMENT", "homologation")
+	viper.SetDefault("FISCAL_SERIES", 1)
+	viper.SetDefault("FISCAL_TRADE_NAME", "Andressa Lanches")
+	viper.SetDefault("FISCAL_TAX_REGIME", 1)
+	viper.SetDefault("FISCAL_TRANSMITTER", "fake")
+
 	err := viper.ReadInConfig()
 	if err != nil {
 		log.Printf("Nenhum arquivo de configuração encontrado. Usando variáveis de ambiente.")
@@ -37,10 +187,70 @@ func LoadConfig() Config {
 		ServerAddress: viper.GetString("SERVER_ADDRESS"),
 		AuthUser:      viper.GetString("AUTH_USER"),
 		AuthPassword:  viper.GetString("AUTH_PASSWORD"),
+
+		AuthAccessTokenTTL:     viper.GetDuration("AUTH_ACCESS_TOKEN_TTL"),
+		AuthRefreshTokenTTL:    viper.GetDuration("AUTH_REFRESH_TOKEN_TTL"),
+		AuthOperatorSessionTTL: viper.GetDuration("AUTH_OPERATOR_SESSION_TTL"),
+
+		LoginMaxAttempts:      viper.GetInt("LOGIN_MAX_ATTEMPTS"),
+		LoginMaxAttemptsPerIP: viper.GetInt("LOGIN_MAX_ATTEMPTS_PER_IP"),
+		LoginLockout:          viper.GetDuration("LOGIN_LOCKOUT"),
+		LoginMaxLockout:       viper.GetDuration("LOGIN_MAX_LOCKOUT"),
+
+		RateLimitRequests: viper.GetInt("RATE_LIMIT_REQUESTS"),
+		RateLimitWindow:   viper.GetDuration("RATE_LIMIT_WINDOW"),
+		TrustedProxies:    splitList(viper.GetString("TRUSTED_PROXIES")),
+
+		ServiceChargePercentage: viper.GetFloat64("SERVICE_CHARGE_PERCENTAGE"),
+		ServiceChargeOrderTypes: splitList(viper.GetString("SERVICE_CHARGE_ORDER_TYPES")),
+
+		KitchenFeedHistory: viper.GetInt("KITCHEN_FEED_HISTORY"),
+
+		PriceSchedulerInterval: viper.GetDuration("PRICE_SCHEDULER_INTERVAL"),
+
+		StoreTimezone:         viper.GetString("STORE_TIMEZONE"),
+		BusinessDayCutoffHour: viper.GetInt("BUSINESS_DAY_CUTOFF_HOUR"),
+
+		PrinterAddress:   viper.GetString("PRINTER_ADDRESS"),
+		PrinterDevice:    viper.GetString("PRINTER_DEVICE"),
+		PrinterWidth:     viper.GetInt("PRINTER_WIDTH"),
+		PrinterAccents:   viper.GetString("PRINTER_ACCENTS"),
+		PrinterQueueSize: viper.GetInt("PRINTER_QUEUE_SIZE"),
+		ReceiptHeader:    splitLines(viper.GetString("RECEIPT_HEADER")),
+		ReceiptFooter:    splitLines(viper.GetString("RECEIPT_FOOTER")),
+
+		PixKey:          viper.GetString("PIX_KEY"),
+		PixMerchantName: viper.GetString("PIX_MERCHANT_NAME"),
+		PixMerchantCity: viper.GetString("PIX_MERCHANT_CITY"),
+
+		PaymentGateway:       viper.GetString("PAYMENT_GATEWAY"),
+		PaymentWebhookSecret: viper.GetString("PAYMENT_WEBHOOK_SECRET"),
+
+		FiscalEnvironment:       viper.GetString("FISCAL_ENVIRONMENT"),
+		FiscalSeries:            viper.GetInt("FISCAL_SERIES"),
+		FiscalCNPJ:              viper.GetString("FISCAL_CNPJ"),
+		FiscalStateRegistration: viper.GetString("FISCAL_STATE_REGISTRATION"),
+		FiscalCompanyName:       viper.GetString("FISCAL_COMPANY_NAME"),
+		FiscalTradeName:         viper.GetString("FISCAL_TRADE_NAME"),
+		FiscalTaxRegime:         viper.GetInt("FISCAL_TAX_REGIME"),
+		FiscalStreet:            viper.GetString("FISCAL_STREET"),
+		FiscalStreetNumber:      viper.GetString("FISCAL_STREET_NUMBER"),
+		FiscalDistrict:          viper.GetString("FISCAL_DISTRICT"),
+		FiscalCity:              viper.GetString("FISCAL_CITY"),
+		FiscalCityCode:          viper.GetString("FISCAL_CITY_CODE"),
+		FiscalState:             viper.GetString("FISCAL_STATE"),
+		FiscalZipCode:           viper.GetString("FISCAL_ZIP_CODE"),
+		FiscalCSCID:             viper.GetString("FISCAL_CSC_ID"),
+		FiscalCSC:               viper.GetString("FISCAL_CSC"),
+		FiscalQRCodeURL:         viper.GetString("FISCAL_QRCODE_URL"),
+		FiscalConsultURL:        viper.GetString("FISCAL_CONSULT_URL"),
+		FiscalCertFile:          viper.GetString("FISCAL_CERT_FILE"),
+		FiscalKeyFile:           viper.GetString("FISCAL_KEY_FILE"),
+		FiscalTransmitter:       viper.GetString("FISCAL_TRANSMITTER"),
 	}
 
-	if config.DatabaseURL == "" || config.JWTSecret == "" || config.ServerAddress == "" || config.AuthUser == "" || config.AuthPassword == "" {
-		log.Fatal("Variáveis de ambiente DATABASE_URL, JWT_SECRET, SERVER_ADDRESS, AUTH_USER e AUTH_PASSWORD são obrigatórias")
+	if config.DatabaseURL == "" || config.JWTSecret == "" || config.ServerAddress == "" {
+		log.Fatal("Variáveis de ambiente DATABASE_URL, JWT_SECRET e SERVER_ADDRESS são obrigatórias")
 	}
 
 	JWTSecret = config.JWTSecret
@@ -48,6 +258,76 @@ func LoadConfig() Config {
 	ServerAddress = config.ServerAddress
 	AuthUser = config.AuthUser
 	AuthPassword = config.AuthPassword
+	AuthAccessTokenTTL = config.AuthAccessTokenTTL
+	AuthRefreshTokenTTL = config.AuthRefreshTokenTTL
+	AuthOperatorSessionTTL = config.AuthOperatorSessionTTL
+	LoginMaxAttempts = config.LoginMaxAttempts
+	LoginMaxAttemptsPerIP = config.LoginMaxAttemptsPerIP
+	LoginLockout = config.LoginLockout
+	LoginMaxLockout = config.LoginMaxLockout
+	RateLimitRequests = config.RateLimitRequests
+	RateLimitWindow = config.RateLimitWindow
+	TrustedProxies = config.TrustedProxies
+	ServiceChargePercentage = config.ServiceChargePercentage
+	ServiceChargeOrderTypes = config.ServiceChargeOrderTypes
+	KitchenFeedHistory = config.KitchenFeedHistory
+	PriceSchedulerInterval = config.PriceSchedulerInterval
+	StoreTimezone = config.StoreTimezone
+	BusinessDayCutoffHour = config.BusinessDayCutoffHour
+	PrinterAddress = config.PrinterAddress
+	PrinterDevice = config.PrinterDevice
+	PrinterWidth = config.PrinterWidth
+	PrinterAccents = config.PrinterAccents
+	PrinterQueueSize = config.PrinterQueueSize
+	ReceiptHeader = config.ReceiptHeader
+	ReceiptFooter = config.ReceiptFooter
+	PixKey = config.PixKey
+	PixMerchantName = config.PixMerchantName
+	PixMerchantCity = config.PixMerchantCity
+	PaymentGateway = config.PaymentGateway
+	PaymentWebhookSecret = config.PaymentWebhookSecret
+	FiscalEnvironment = config.FiscalEnvironment
+	FiscalSeries = config.FiscalSeries
+	FiscalCNPJ = config.FiscalCNPJ
+	FiscalStateRegistration = config.FiscalStateRegistration
+	FiscalCompanyName = config.FiscalCompanyName
+	FiscalTradeName = config.FiscalTradeName
+	FiscalTaxRegime = config.FiscalTaxRegime
+	FiscalStreet = config.FiscalStreet
+	FiscalStreetNumber = config.FiscalStreetNumber
+	FiscalDistrict = config.FiscalDistrict
+	FiscalCity = config.FiscalCity
+	FiscalCityCode = config.FiscalCityCode
+	FiscalState = config.FiscalState
+	FiscalZipCode = config.FiscalZipCode
+	FiscalCSCID = config.FiscalCSCID
+	FiscalCSC = config.FiscalCSC
+	FiscalQRCodeURL = config.FiscalQRCodeURL
+	FiscalConsultURL = config.FiscalConsultURL
+	FiscalCertFile = config.FiscalCertFile
+	FiscalKeyFile = config.FiscalKeyFile
+	FiscalTransmitter = config.FiscalTransmitter
 
 	return config
 }
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitLines separa valores com várias linhas usando "|" como delimitador.
+func splitLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "|") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -12,12 +12,18 @@ var (
 	ErrAdditionIdInvalid     = errors.New("ID do acréscimo inválido")
 	ErrAdditionNotFound      = errors.New("acréscimo não encontrado")
 	ErrAdditionIdMandatory   = errors.New("ID do acréscimo é obrigatório")
+	ErrAdditionCostNegative  = errors.New("o custo do acréscimo não pode ser negativo")
+	ErrAdditionConflict      = errors.New("o acréscimo foi alterado por outra operação; tente de novo")
 )
 
 type Addition struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Price float64   `json:"price"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Price    float64   `json:"price"`
+	UnitCost *float64  `json:"unit_cost,omitempty"`
+	// ExternalCode é o código do acréscimo em sistemas de fora; opcional e
+	// único quando informado.
+	ExternalCode string `json:"external_code,omitempty"`
 }
 
 func (a *Addition) Validate() error {
@@ -28,5 +34,8 @@ func (a *Addition) Validate() error {
 	if a.Price < 0 {
 		return ErrAdditionPriceRequired
 	}
+	if a.UnitCost != nil && *a.UnitCost < 0 {
+		return ErrAdditionCostNegative
+	}
 	return nil
 }
@@ -1,15 +1,22 @@
 package addition
 
 import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"context"
 
 	"github.com/google/uuid"
 )
 
+// Repository grava cada alteração junto com a troca de preço do histórico e
+// o registro de auditoria, numa única transação; change e entry podem ser
+// nulos quando não há o que registrar.
 type Repository interface {
-	Create(ctx context.Context, addition *Addition) error
+	Create(ctx context.Context, addition *Addition, change *pricing.PriceChange, entry *audit.Entry) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Addition, error)
-	Update(ctx context.Context, addition *Addition) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Update só grava enquanto o preço do acréscimo ainda é oldPrice,
+	// devolvendo ErrAdditionConflict quando outra alteração chegou antes.
+	Update(ctx context.Context, addition *Addition, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error
+	Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error
 	List(ctx context.Context) ([]*Addition, error)
 }
@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"andressa-lanches/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAPIKeyNameRequired = errors.New("o nome da chave de API é obrigatório")
+	ErrAPIKeyIdInvalid    = errors.New("ID da chave de API inválido")
+	ErrAPIKeyNotFound     = errors.New("chave de API não encontrada")
+	ErrAPIKeyRevoked      = errors.New("chave de API revogada")
+	ErrAPIKeyInvalid      = errors.New("chave de API inválida")
+	ErrScopesRequired     = errors.New("informe ao menos um escopo para a chave de API")
+	ErrScopeInvalid       = errors.New("escopo inválido para chave de API")
+	ErrLogoutUnsupported  = errors.New("chaves de API não têm sessão: revogue a chave em /api-keys")
+)
+
+// KeyPrefix abre toda chave emitida, para que ela seja reconhecida em logs e
+// varreduras de segredos. A chave completa tem o formato
+// al_<prefixo>_<segredo>.
+const KeyPrefix = "al"
+
+// lastUsedResolution evita uma escrita no banco a cada requisição: o último
+// uso só é atualizado quando o registro anterior tem mais que isso.
+const lastUsedResolution = time.Minute
+
+// forbiddenScopes não podem ser dados a integrações: uma chave que gerencia
+// usuários ou outras chaves escaparia do controle do proprietário.
+var forbiddenScopes = map[user.Permission]bool{
+	user.PermissionUsersManage: true,
+	user.PermissionAPIKeys:     true,
+}
+
+// APIKey autentica integrações (a ponte com o agregador de delivery, scripts
+// de relatório) sem usar a senha de um funcionário. A chave só é guardada
+// como hash; o prefixo, público, localiza o registro.
+type APIKey struct {
+	ID         uuid.UUID         `json:"id"`
+	Name       string            `json:"name"`
+	Prefix     string            `json:"prefix"`
+	KeyHash    string            `json:"-"`
+	Scopes     []user.Permission `json:"scopes"`
+	CreatedBy  *uuid.UUID        `json:"created_by,omitempty"`
+	LastUsedAt *time.Time        `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time        `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Issued é devolvida na criação e na rotação; a chave completa só é exibida
+// nesse momento.
+type Issued struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
+
+func (k *APIKey) Validate() error {
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" {
+		return ErrAPIKeyNameRequired
+	}
+	if len(k.Scopes) == 0 {
+		return ErrScopesRequired
+	}
+
+	seen := make(map[user.Permission]bool, len(k.Scopes))
+	scopes := k.Scopes[:0]
+	for _, scope := range k.Scopes {
+		if !scope.IsValid() || forbiddenScopes[scope] {
+			return ErrScopeInvalid
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	k.Scopes = scopes
+	return nil
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) HasScope(p user.Permission) bool {
+	for _, scope := range k.Scopes {
+		if scope == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches confere a chave apresentada com o hash guardado em tempo constante.
+func (k *APIKey) Matches(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashKey(key)), []byte(k.KeyHash)) == 1
+}
+
+// ShouldTouch indica se o último uso deve ser gravado.
+func (k *APIKey) ShouldTouch(now time.Time) bool {
+	return k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedResolution
+}
+
+// NewKey gera uma chave com prefixo de 8 caracteres hexadecimais e segredo
+// de 256 bits, e devolve também o prefixo a guardar.
+func NewKey() (key, prefix string, err error) {
+	p := make([]byte, 4)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(p)
+	return KeyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// ParsePrefix extrai o prefixo de uma chave no formato al_<prefixo>_<segredo>.
+func ParsePrefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != KeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashKey é o valor guardado no lugar da chave.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
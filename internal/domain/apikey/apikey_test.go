@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"testing"
+
+	"andressa-lanches/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey_Validate(t *testing.T) {
+	k := &APIKey{Name: "  relatórios ", Scopes: []user.Permission{user.PermissionReports, user.PermissionSalesRead, user.PermissionReports}}
+	require.NoError(t, k.Validate())
+	assert.Equal(t, "relatórios", k.Name)
+	assert.Equal(t, []user.Permission{user.PermissionReports, user.PermissionSalesRead}, k.Scopes)
+
+	assert.Equal(t, ErrAPIKeyNameRequired, (&APIKey{Scopes: []user.Permission{user.PermissionReports}}).Validate())
+	assert.Equal(t, ErrScopesRequired, (&APIKey{Name: "ponte"}).Validate())
+	assert.Equal(t, ErrScopeInvalid, (&APIKey{Name: "ponte", Scopes: []user.Permission{"sales:everything"}}).Validate())
+	assert.Equal(t, ErrScopeInvalid, (&APIKey{Name: "ponte", Scopes: []user.Permission{user.PermissionUsersManage}}).Validate())
+	assert.Equal(t, ErrScopeInvalid, (&APIKey{Name: "ponte", Scopes: []user.Permission{user.PermissionAPIKeys}}).Validate())
+}
+
+func TestNewKey(t *testing.T) {
+	key, prefix, err := NewKey()
+	require.NoError(t, err)
+	assert.Len(t, prefix, 8)
+
+	parsed, ok := ParsePrefix(key)
+	require.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	k := &APIKey{KeyHash: HashKey(key)}
+	assert.True(t, k.Matches(key))
+	assert.False(t, k.Matches(key+"x"))
+
+	for _, invalid := range []string{"", "al_", "al_abc", "xx_abc_def", "al__def"} {
+		_, ok := ParsePrefix(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
@@ -0,0 +1,18 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+	// UpdateLastUsed registra o último uso da chave.
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+	List(ctx context.Context) ([]*APIKey, error)
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFilterInvalid = errors.New("filtro da auditoria inválido")
+)
+
+// Action identifica o evento registrado.
+type Action string
+
+const (
+	ActionLoginFailed    Action = "auth.login_failed"
+	ActionPINLoginFailed Action = "auth.pin_login_failed"
+
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// EntityType identifica o cadastro alterado nas ações de criação, alteração
+// e remoção.
+type EntityType string
+
+const (
+	EntityProduct  EntityType = "product"
+	EntityCategory EntityType = "category"
+	EntityAddition EntityType = "addition"
+	EntitySale     EntityType = "sale"
+	EntityCharge   EntityType = "payment_charge"
+)
+
+// DefaultLimit e MaxLimit limitam a quantidade de registros por consulta.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 500
+)
+
+// MaxActorLength é o tamanho da coluna actor.
+const MaxActorLength = 50
+
+// Entry é um registro da trilha de auditoria; nunca é alterado depois de
+// gravado. Actor é o nome de usuário informado, mesmo quando não existe, ou
+// o nome da chave de API, limitado por TruncateActor. Changes traz apenas os
+// campos alterados.
+type Entry struct {
+	ID         uuid.UUID       `json:"id"`
+	Action     Action          `json:"action"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	APIKeyID   *uuid.UUID      `json:"api_key_id,omitempty"`
+	Actor      string          `json:"actor"`
+	IP         string          `json:"ip,omitempty"`
+	EntityType EntityType      `json:"entity_type,omitempty"`
+	EntityID   *uuid.UUID      `json:"entity_id,omitempty"`
+	Changes    json.RawMessage `json:"changes,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// TruncateActor corta o nome em MaxActorLength caracteres, já que o nome
+// informado numa tentativa de login pode ter qualquer tamanho.
+func TruncateActor(name string) string {
+	runes := []rune(name)
+	if len(runes) <= MaxActorLength {
+		return name
+	}
+	return string(runes[:MaxActorLength])
+}
+
+// FieldChange é o valor de um campo antes e depois da operação; na criação
+// Before é nulo e na remoção After é nulo.
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Filter restringe a consulta; campos vazios não filtram. Start é inclusivo
+// e End exclusivo.
+type Filter struct {
+	Action     Action
+	EntityType EntityType
+	EntityID   *uuid.UUID
+	ActorID    *uuid.UUID
+	RequestID  string
+	Start      time.Time
+	End        time.Time
+	Limit      int
+}
+
+// Normalize aplica o limite padrão e confere o intervalo.
+func (f *Filter) Normalize() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxLimit {
+		return ErrFilterInvalid
+	}
+	if !f.Start.IsZero() && !f.End.IsZero() && !f.Start.Before(f.End) {
+		return ErrFilterInvalid
+	}
+	return nil
+}
+
+// Matches confere o registro com o filtro, para os repositórios em memória.
+func (f Filter) Matches(e *Entry) bool {
+	switch {
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case f.EntityType != "" && e.EntityType != f.EntityType:
+		return false
+	case f.EntityID != nil && (e.EntityID == nil || *e.EntityID != *f.EntityID):
+		return false
+	case f.ActorID != nil && (e.ActorID == nil || *e.ActorID != *f.ActorID):
+		return false
+	case f.RequestID != "" && e.RequestID != f.RequestID:
+		return false
+	case !f.Start.IsZero() && e.CreatedAt.Before(f.Start):
+		return false
+	case !f.End.IsZero() && !e.CreatedAt.Before(f.End):
+		return false
+	}
+	return true
+}
+
+// Diff compara as representações JSON de before e after campo a campo e
+// devolve só os campos que mudaram. before ou after nulos representam a
+// criação e a remoção. O resultado é nulo quando nada mudou.
+func Diff(before, after any) (json.RawMessage, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+	for name, value := range beforeFields {
+		if next, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, next) {
+			changes[name] = FieldChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = FieldChange{After: value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+func fields(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
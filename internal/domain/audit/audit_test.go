@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+	Notes string  `json:"notes,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	changes, err := Diff(&item{Name: "X-Salada", Price: 18.5}, &item{Name: "X-Salada", Price: 21, Notes: "sem cebola"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":{"before":18.5,"after":21},"notes":{"before":null,"after":"sem cebola"}}`, string(changes))
+
+	changes, err = Diff(nil, &item{Name: "Bacon", Price: 4})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":{"before":null,"after":"Bacon"},"price":{"before":null,"after":4}}`, string(changes))
+
+	var deleted *item
+	changes, err = Diff(&item{Name: "Bacon", Price: 4}, deleted)
+	require.NoError(t, err)
+	var fields map[string]FieldChange
+	require.NoError(t, json.Unmarshal(changes, &fields))
+	assert.Equal(t, "Bacon", fields["name"].Before)
+	assert.Nil(t, fields["name"].After)
+
+	changes, err = Diff(&item{Name: "Bacon"}, &item{Name: "Bacon"})
+	require.NoError(t, err)
+	assert.Nil(t, changes)
+}
+
+func TestNewEntry(t *testing.T) {
+	userID := uuid.New()
+	ctx := WithRequestID(WithActor(context.Background(), Actor{UserID: &userID, Name: "gerente", IP: "10.0.0.2"}), "req-1")
+	id := uuid.New()
+
+	entry, err := NewEntry(ctx, ActionUpdate, EntityProduct, id, &item{Name: "X-Salada", Price: 18.5}, &item{Name: "X-Salada", Price: 21})
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, ActionUpdate, entry.Action)
+	assert.Equal(t, EntityProduct, entry.EntityType)
+	assert.Equal(t, id, *entry.EntityID)
+	assert.Equal(t, &userID, entry.ActorID)
+	assert.Equal(t, "gerente", entry.Actor)
+	assert.Equal(t, "10.0.0.2", entry.IP)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.JSONEq(t, `{"price":{"before":18.5,"after":21}}`, string(entry.Changes))
+
+	// Salvar sem mudar nada não gera registro.
+	entry, err = NewEntry(ctx, ActionUpdate, EntityProduct, id, &item{Name: "Bacon"}, &item{Name: "Bacon"})
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestFilter(t *testing.T) {
+	var f Filter
+	require.NoError(t, f.Normalize())
+	assert.Equal(t, DefaultLimit, f.Limit)
+	assert.Equal(t, ErrFilterInvalid, (&Filter{Limit: MaxLimit + 1}).Normalize())
+
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, ErrFilterInvalid, (&Filter{Start: day, End: day}).Normalize())
+
+	entityID := uuid.New()
+	entry := &Entry{Action: ActionUpdate, EntityType: EntityProduct, EntityID: &entityID, CreatedAt: day.Add(time.Hour)}
+	assert.True(t, Filter{EntityType: EntityProduct, EntityID: &entityID, Start: day, End: day.AddDate(0, 0, 1)}.Matches(entry))
+	assert.False(t, Filter{Action: ActionDelete}.Matches(entry))
+	assert.False(t, Filter{ActorID: &entityID}.Matches(entry))
+	assert.False(t, Filter{End: day}.Matches(entry))
+}
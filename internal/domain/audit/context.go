@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor é quem fez a requisição: um funcionário, identificado pelo JWT, ou
+// uma integração, identificada pela chave de API.
+type Actor struct {
+	UserID   *uuid.UUID
+	APIKeyID *uuid.UUID
+	Name     string
+	IP       string
+}
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+// WithActor guarda no contexto da requisição quem a fez, para que os
+// serviços registrem a auditoria sem depender do HTTP.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// WithRequestID guarda o identificador da requisição, que liga os registros
+// de auditoria aos logs.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewEntry monta o registro de uma alteração com o autor e a requisição
+// lidos do contexto e os campos que mudaram entre before e after. Devolve
+// nil quando uma atualização não muda nada.
+func NewEntry(ctx context.Context, action Action, entityType EntityType, entityID uuid.UUID, before, after any) (*Entry, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil && action == ActionUpdate {
+		return nil, nil
+	}
+
+	entry := &Entry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   &entityID,
+		Changes:    changes,
+		RequestID:  RequestIDFrom(ctx),
+		CreatedAt:  time.Now(),
+	}
+	if actor, ok := ActorFrom(ctx); ok {
+		entry.ActorID = actor.UserID
+		entry.APIKeyID = actor.APIKeyID
+		entry.Actor = TruncateActor(actor.Name)
+		entry.IP = actor.IP
+	}
+	return entry, nil
+}
@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+// Repository só acrescenta registros; List devolve os mais recentes
+// primeiro.
+type Repository interface {
+	Append(ctx context.Context, entry *Entry) error
+	List(ctx context.Context, filter Filter) ([]*Entry, error)
+}
@@ -0,0 +1,161 @@
+package catalog
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/taxation"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFormatInvalid  = errors.New("formato do cardápio inválido: use json ou csv")
+	ErrFileInvalid    = errors.New("arquivo do cardápio inválido")
+	ErrImportConflict = errors.New("o cardápio mudou durante a importação; importe de novo")
+)
+
+// Format é o formato do arquivo de importação e exportação do cardápio.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+func (f Format) IsValid() bool {
+	return f == FormatJSON || f == FormatCSV
+}
+
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// EntryType é o tipo de uma linha do arquivo.
+type EntryType string
+
+const (
+	EntryCategory EntryType = "category"
+	EntryProduct  EntryType = "product"
+	EntryAddition EntryType = "addition"
+)
+
+// CategoryEntry é uma categoria no arquivo. Os itens são ligados pelo nome
+// ou pelo código externo, nunca pelo ID, para que o arquivo sirva em outra
+// instalação.
+type CategoryEntry struct {
+	// Row é a linha no CSV, ou a posição na lista do JSON, a partir de 1.
+	Row          int    `json:"-"`
+	ExternalCode string `json:"external_code,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	// Station é o nome da estação de preparo, que precisa já existir.
+	Station string `json:"station,omitempty"`
+	taxation.FiscalData
+}
+
+// ProductEntry é um produto no arquivo. A categoria é procurada pelo
+// CategoryCode, quando informado, ou pelo nome em Category, entre as já
+// cadastradas e as que o próprio arquivo cria ou renomeia.
+type ProductEntry struct {
+	Row          int      `json:"-"`
+	ExternalCode string   `json:"external_code,omitempty"`
+	Name         string   `json:"name"`
+	Price        float64  `json:"price"`
+	UnitCost     *float64 `json:"unit_cost,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	CategoryCode string   `json:"category_code,omitempty"`
+	taxation.FiscalData
+}
+
+type AdditionEntry struct {
+	Row          int      `json:"-"`
+	ExternalCode string   `json:"external_code,omitempty"`
+	Name         string   `json:"name"`
+	Price        float64  `json:"price"`
+	UnitCost     *float64 `json:"unit_cost,omitempty"`
+}
+
+// Catalog é o cardápio inteiro como é importado e exportado.
+type Catalog struct {
+	Categories []CategoryEntry `json:"categories"`
+	Products   []ProductEntry  `json:"products"`
+	Additions  []AdditionEntry `json:"additions"`
+}
+
+// Key normaliza o nome para a comparação entre o arquivo e o cadastro, que
+// ignora maiúsculas e espaços nas pontas.
+func Key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Action é o que a importação faz com um item.
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+)
+
+// RowError é um problema de uma linha do arquivo. Basta um para que nada
+// seja gravado.
+type RowError struct {
+	Type  EntryType `json:"type,omitempty"`
+	Row   int       `json:"row"`
+	Name  string    `json:"name,omitempty"`
+	Error string    `json:"error"`
+}
+
+// ImportItem é o resultado de uma linha. O ID dos itens novos só é
+// conhecido depois que a importação é gravada.
+type ImportItem struct {
+	Type   EntryType  `json:"type"`
+	Row    int        `json:"row"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	Name   string     `json:"name"`
+	Action Action     `json:"action"`
+}
+
+type ImportResult struct {
+	DryRun    bool         `json:"dry_run"`
+	Applied   bool         `json:"applied"`
+	Created   int          `json:"created"`
+	Updated   int          `json:"updated"`
+	Unchanged int          `json:"unchanged"`
+	Items     []ImportItem `json:"items"`
+	Errors    []RowError   `json:"errors"`
+}
+
+// Plan é o que a importação grava. Os itens novos já chegam com o ID
+// definido, para que os produtos possam apontar para categorias criadas no
+// mesmo arquivo.
+type Plan struct {
+	NewCategories     []*category.Category
+	UpdatedCategories []*category.Category
+	NewProducts       []*product.Product
+	UpdatedProducts   []*product.Product
+	NewAdditions      []*addition.Addition
+	UpdatedAdditions  []*addition.Addition
+	PriceChanges      []*pricing.PriceChange
+	Entries           []*audit.Entry
+	// Os itens atualizados como estavam na leitura do cadastro, pelo ID. A
+	// gravação só atualiza o item que continua igual a essa leitura.
+	PreviousCategories map[uuid.UUID]*category.Category
+	PreviousProducts   map[uuid.UUID]*product.Product
+	PreviousAdditions  map[uuid.UUID]*addition.Addition
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.NewCategories) == 0 && len(p.UpdatedCategories) == 0 &&
+		len(p.NewProducts) == 0 && len(p.UpdatedProducts) == 0 &&
+		len(p.NewAdditions) == 0 && len(p.UpdatedAdditions) == 0
+}
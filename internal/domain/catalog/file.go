@@ -0,0 +1,371 @@
+package catalog
+
+import (
+	"andressa-lanches/internal/domain/taxation"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Colunas do CSV, na ordem em que são exportadas. Na importação a ordem é
+// livre e só tipo e nome são obrigatórias.
+const (
+	colType         = "tipo"
+	colExternalCode = "codigo_externo"
+	colName         = "nome"
+	colDescription  = "descricao"
+	colPrice        = "preco"
+	colUnitCost     = "custo_unitario"
+	colCategory     = "categoria"
+	colCategoryCode = "codigo_categoria"
+	colStation      = "estacao"
+	colNCM          = "ncm"
+	colCEST         = "cest"
+	colCFOP         = "cfop"
+	colCST          = "cst"
+	colOrigin       = "origem"
+	colUnit         = "unidade"
+	colICMSRate     = "aliquota_icms"
+	colPISRate      = "aliquota_pis"
+	colCOFINSRate   = "aliquota_cofins"
+)
+
+var csvHeader = []string{
+	colType, colExternalCode, colName, colDescription, colPrice, colUnitCost, colCategory, colCategoryCode, colStation,
+	colNCM, colCEST, colCFOP, colCST, colOrigin, colUnit, colICMSRate, colPISRate, colCOFINSRate,
+}
+
+var csvColumnIndex = func() map[string]int {
+	index := make(map[string]int, len(csvHeader))
+	for i, column := range csvHeader {
+		index[column] = i
+	}
+	return index
+}()
+
+// Valores da coluna tipo.
+var csvTypes = map[string]EntryType{
+	"categoria": EntryCategory,
+	"produto":   EntryProduct,
+	"acrescimo": EntryAddition,
+	"acréscimo": EntryAddition,
+}
+
+// Read lê o cardápio no formato indicado. Os erros de uma linha, como um
+// preço que não é número, voltam na lista de RowError e a linha fica de
+// fora; o erro só é devolvido quando o arquivo inteiro não pode ser lido.
+func Read(r io.Reader, format Format) (*Catalog, []RowError, error) {
+	switch format {
+	case FormatJSON:
+		c, err := readJSON(r)
+		return c, nil, err
+	case FormatCSV:
+		return readCSV(r)
+	}
+	return nil, nil, ErrFormatInvalid
+}
+
+// Write grava o cardápio no formato indicado.
+func Write(w io.Writer, format Format, c *Catalog) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(c)
+	case FormatCSV:
+		return writeCSV(w, c)
+	}
+	return ErrFormatInvalid
+}
+
+func readJSON(r io.Reader) (*Catalog, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var c Catalog
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFileInvalid, err)
+	}
+	for i := range c.Categories {
+		c.Categories[i].Row = i + 1
+	}
+	for i := range c.Products {
+		c.Products[i].Row = i + 1
+	}
+	for i := range c.Additions {
+		c.Additions[i].Row = i + 1
+	}
+	return &c, nil
+}
+
+// readCSV aceita vírgula ou ponto e vírgula como separador, conforme o
+// cabeçalho, e vírgula decimal nos números, como o Excel grava em português.
+func readCSV(r io.Reader) (*Catalog, []RowError, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	firstLine, _, _ := bytes.Cut(content, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: o arquivo está vazio", ErrFileInvalid)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrFileInvalid, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = Key(name)
+		if _, known := csvColumnIndex[name]; !known {
+			return nil, nil, fmt.Errorf("%w: coluna desconhecida %q", ErrFileInvalid, name)
+		}
+		if _, repeated := columns[name]; repeated {
+			return nil, nil, fmt.Errorf("%w: coluna repetida %q", ErrFileInvalid, name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{colType, colName} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: falta a coluna %q", ErrFileInvalid, required)
+		}
+	}
+
+	c := &Catalog{}
+	var rowErrors []RowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrFileInvalid, err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := csvRow{record: record, columns: columns}
+		if len(record) != len(header) {
+			rowErrors = append(rowErrors, RowError{Row: line, Error: fmt.Sprintf("a linha tem %d colunas e o cabeçalho tem %d", len(record), len(header))})
+			continue
+		}
+
+		entryType, ok := csvTypes[Key(row.text(colType))]
+		if !ok {
+			rowErrors = append(rowErrors, RowError{Row: line, Name: row.text(colName), Error: fmt.Sprintf("tipo %q inválido: use categoria, produto ou acrescimo", row.text(colType))})
+			continue
+		}
+		switch entryType {
+		case EntryCategory:
+			entry := CategoryEntry{
+				Row:          line,
+				ExternalCode: row.text(colExternalCode),
+				Name:         row.text(colName),
+				Description:  row.text(colDescription),
+				Station:      row.text(colStation),
+			}
+			entry.FiscalData = row.fiscalData()
+			if row.err == nil {
+				c.Categories = append(c.Categories, entry)
+			}
+		case EntryProduct:
+			entry := ProductEntry{
+				Row:          line,
+				ExternalCode: row.text(colExternalCode),
+				Name:         row.text(colName),
+				Price:        row.number(colPrice),
+				UnitCost:     row.optionalNumber(colUnitCost),
+				Description:  row.text(colDescription),
+				Category:     row.text(colCategory),
+				CategoryCode: row.text(colCategoryCode),
+			}
+			entry.FiscalData = row.fiscalData()
+			if row.err == nil {
+				c.Products = append(c.Products, entry)
+			}
+		case EntryAddition:
+			entry := AdditionEntry{
+				Row:          line,
+				ExternalCode: row.text(colExternalCode),
+				Name:         row.text(colName),
+				Price:        row.number(colPrice),
+				UnitCost:     row.optionalNumber(colUnitCost),
+			}
+			if row.err == nil {
+				c.Additions = append(c.Additions, entry)
+			}
+		}
+		if row.err != nil {
+			rowErrors = append(rowErrors, RowError{Type: entryType, Row: line, Name: row.text(colName), Error: row.err.Error()})
+		}
+	}
+	return c, rowErrors, nil
+}
+
+// csvRow lê os campos de uma linha pelo nome da coluna e guarda o primeiro
+// erro de conversão.
+type csvRow struct {
+	record  []string
+	columns map[string]int
+	err     error
+}
+
+func (r *csvRow) text(column string) string {
+	i, ok := r.columns[column]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRow) optionalNumber(column string) *float64 {
+	value := r.text(column)
+	if value == "" {
+		return nil
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.fail(fmt.Errorf("%s: %q não é um número", column, r.text(column)))
+		return nil
+	}
+	return &number
+}
+
+func (r *csvRow) number(column string) float64 {
+	if number := r.optionalNumber(column); number != nil {
+		return *number
+	}
+	return 0
+}
+
+func (r *csvRow) optionalInt(column string) *int {
+	value := r.text(column)
+	if value == "" {
+		return nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(fmt.Errorf("%s: %q não é um número inteiro", column, value))
+		return nil
+	}
+	return &number
+}
+
+func (r *csvRow) fiscalData() taxation.FiscalData {
+	return taxation.FiscalData{
+		NCM:        r.text(colNCM),
+		CEST:       r.text(colCEST),
+		CFOP:       r.text(colCFOP),
+		CST:        r.text(colCST),
+		Origin:     r.optionalInt(colOrigin),
+		Unit:       r.text(colUnit),
+		ICMSRate:   r.optionalNumber(colICMSRate),
+		PISRate:    r.optionalNumber(colPISRate),
+		COFINSRate: r.optionalNumber(colCOFINSRate),
+	}
+}
+
+func (r *csvRow) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// writeCSV grava as categorias, os produtos e os acréscimos, nessa ordem,
+// com ponto decimal e separados por vírgula.
+func writeCSV(w io.Writer, c *Catalog) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range c.Categories {
+		record := newCSVRecord()
+		record.set(colType, "categoria")
+		record.set(colExternalCode, e.ExternalCode)
+		record.set(colName, e.Name)
+		record.set(colDescription, e.Description)
+		record.set(colStation, e.Station)
+		record.setFiscalData(&e.FiscalData)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	for _, e := range c.Products {
+		record := newCSVRecord()
+		record.set(colType, "produto")
+		record.set(colExternalCode, e.ExternalCode)
+		record.set(colName, e.Name)
+		record.set(colDescription, e.Description)
+		record.set(colPrice, formatMoney(&e.Price))
+		record.set(colUnitCost, formatMoney(e.UnitCost))
+		record.set(colCategory, e.Category)
+		record.set(colCategoryCode, e.CategoryCode)
+		record.setFiscalData(&e.FiscalData)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	for _, e := range c.Additions {
+		record := newCSVRecord()
+		record.set(colType, "acrescimo")
+		record.set(colExternalCode, e.ExternalCode)
+		record.set(colName, e.Name)
+		record.set(colPrice, formatMoney(&e.Price))
+		record.set(colUnitCost, formatMoney(e.UnitCost))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type csvRecord []string
+
+func newCSVRecord() csvRecord {
+	return make(csvRecord, len(csvHeader))
+}
+
+func (r csvRecord) set(column, value string) {
+	r[csvColumnIndex[column]] = value
+}
+
+func (r csvRecord) setFiscalData(d *taxation.FiscalData) {
+	r.set(colNCM, d.NCM)
+	r.set(colCEST, d.CEST)
+	r.set(colCFOP, d.CFOP)
+	r.set(colCST, d.CST)
+	if d.Origin != nil {
+		r.set(colOrigin, strconv.Itoa(*d.Origin))
+	}
+	r.set(colUnit, d.Unit)
+	r.set(colICMSRate, formatRate(d.ICMSRate))
+	r.set(colPISRate, formatRate(d.PISRate))
+	r.set(colCOFINSRate, formatRate(d.COFINSRate))
+}
+
+func formatMoney(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 2, 64)
+}
+
+func formatRate(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
@@ -0,0 +1,12 @@
+package catalog
+
+import "context"
+
+// Repository grava a importação numa única transação: categorias, produtos,
+// acréscimos, histórico de preços e auditoria são gravados juntos ou nada é
+// gravado.
+type Repository interface {
+	// Apply devolve ErrImportConflict quando um item a atualizar foi removido
+	// ou alterado depois da leitura do cadastro.
+	Apply(ctx context.Context, plan *Plan) error
+}
@@ -1,6 +1,7 @@
 package category
 
 import (
+	"andressa-lanches/internal/domain/taxation"
 	"errors"
 
 	"github.com/google/uuid"
@@ -11,17 +12,24 @@ var (
 	ErrCategoryIdInvalid    = errors.New("ID da categoria inválido")
 	ErrCategoryIdRequired   = errors.New("ID da categoria é obrigatório")
 	ErrCategoryNotFound     = errors.New("categoria não encontrada")
+	ErrStationNotFound      = errors.New("estação de preparo da categoria não encontrada")
 )
 
 type Category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	StationID   *uuid.UUID `json:"station_id,omitempty"`
+	// ExternalCode identifica a categoria em sistemas de fora, como a
+	// planilha de importação do cardápio. É opcional e único quando informado.
+	ExternalCode string `json:"external_code,omitempty"`
+	// Dados fiscais herdados pelos produtos que não os informam.
+	taxation.FiscalData
 }
 
 func (p *Category) Validate() error {
 	if p.Name == "" {
 		return ErrCategoryNameRequired
 	}
-	return nil
+	return p.FiscalData.Validate()
 }
@@ -1,15 +1,18 @@
 package category
 
 import (
+	"andressa-lanches/internal/domain/audit"
 	"context"
 
 	"github.com/google/uuid"
 )
 
+// Repository grava cada alteração junto com o registro de auditoria, numa
+// única transação; entry pode ser nulo quando não há o que registrar.
 type Repository interface {
-	Create(ctx context.Context, category *Category) error
+	Create(ctx context.Context, category *Category, entry *audit.Entry) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Category, error)
-	Update(ctx context.Context, category *Category) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, category *Category, entry *audit.Entry) error
+	Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error
 	List(ctx context.Context) ([]*Category, error)
 }
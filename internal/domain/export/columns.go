@@ -0,0 +1,76 @@
+package export
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/sale"
+	"math"
+)
+
+var headers = map[Dataset][]string{
+	DatasetSales: {
+		"venda_id", "pedido", "dia_operacao", "data_hora", "tipo_pedido", "status", "funcionario",
+		"desconto", "outras_cobrancas", "taxa_servico", "total",
+	},
+	DatasetItems: {
+		"venda_id", "pedido", "dia_operacao", "data_hora", "status_venda", "item", "produto_id", "produto",
+		"categoria_id", "status_item", "quantidade", "preco_unitario", "total", "custo_unitario", "custo_total", "observacoes",
+	},
+	DatasetAdditions: {
+		"venda_id", "pedido", "dia_operacao", "data_hora", "status_venda", "item", "produto", "acrescimo_id",
+		"acrescimo", "quantidade", "preco_unitario", "total", "custo_unitario", "custo_total",
+	},
+}
+
+var sheetNames = map[Dataset]string{
+	DatasetSales:     "Vendas",
+	DatasetItems:     "Itens",
+	DatasetAdditions: "Acréscimos",
+}
+
+func SheetName(d Dataset) string {
+	return sheetNames[d]
+}
+
+func Header(d Dataset) []Cell {
+	cells := make([]Cell, len(headers[d]))
+	for i, name := range headers[d] {
+		cells[i] = Text(name)
+	}
+	return cells
+}
+
+func SaleRow(s *sale.Sale) []Cell {
+	return []Cell{
+		Text(s.ID.String()), Integer(s.OrderNumber), Date(s.BusinessDate), DateTime(s.Date),
+		Text(string(s.OrderType)), Text(string(s.Status)), Text(s.Employee),
+		Money(s.Discount), Money(s.AdditionalCharges), Money(s.ServiceCharge), Money(s.TotalAmount),
+	}
+}
+
+func ItemRow(s *sale.Sale, item *sale.SaleItem) []Cell {
+	return []Cell{
+		Text(s.ID.String()), Integer(s.OrderNumber), Date(s.BusinessDate), DateTime(s.Date), Text(string(s.Status)),
+		Integer(item.ItemID), Text(item.ProductID.String()), Text(item.ProductName), Text(item.CategoryID.String()),
+		Text(string(item.Status)), Integer(item.Quantity), Money(item.UnitPrice), Money(item.TotalPrice),
+		OptionalMoney(item.UnitCost), OptionalMoney(item.TotalCost), Text(item.Notes),
+	}
+}
+
+// AdditionRow traz o acréscimo multiplicado pela quantidade do item.
+func AdditionRow(s *sale.Sale, item *sale.SaleItem, a *addition.Addition) []Cell {
+	quantity := float64(item.Quantity)
+	totalCost := Cell{Kind: KindMoney, Empty: true}
+	if a.UnitCost != nil {
+		totalCost = Money(round(*a.UnitCost * quantity))
+	}
+	return []Cell{
+		Text(s.ID.String()), Integer(s.OrderNumber), Date(s.BusinessDate), DateTime(s.Date), Text(string(s.Status)),
+		Integer(item.ItemID), Text(item.ProductName), Text(a.ID.String()), Text(a.Name),
+		Integer(item.Quantity), Money(a.Price), Money(round(a.Price * quantity)),
+		OptionalMoney(a.UnitCost), totalCost,
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrFormatInvalid     = errors.New("formato de exportação inválido: use csv ou xlsx")
+	ErrDatasetInvalid    = errors.New("dados de exportação inválidos: use sales, items ou additions")
+	ErrDecimalInvalid    = errors.New("separador decimal inválido: use point ou comma")
+	ErrDateFormatInvalid = errors.New("formato de data inválido: use iso ou br")
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Dataset escolhe o que é exportado: uma linha por venda, por item vendido
+// ou por acréscimo de item.
+type Dataset string
+
+const (
+	DatasetSales     Dataset = "sales"
+	DatasetItems     Dataset = "items"
+	DatasetAdditions Dataset = "additions"
+)
+
+func (d Dataset) IsValid() bool {
+	switch d {
+	case DatasetSales, DatasetItems, DatasetAdditions:
+		return true
+	}
+	return false
+}
+
+// Decimal é o separador decimal dos valores no CSV. Com vírgula, as colunas
+// passam a ser separadas por ponto e vírgula, como espera o Excel em
+// português.
+type Decimal string
+
+const (
+	DecimalPoint Decimal = "point"
+	DecimalComma Decimal = "comma"
+)
+
+func (d Decimal) IsValid() bool {
+	return d == DecimalPoint || d == DecimalComma
+}
+
+// DateFormat é o formato das datas: ISO 8601 ou dia/mês/ano.
+type DateFormat string
+
+const (
+	DateISO DateFormat = "iso"
+	DateBR  DateFormat = "br"
+)
+
+func (f DateFormat) IsValid() bool {
+	return f == DateISO || f == DateBR
+}
+
+type Options struct {
+	Format     Format
+	Dataset    Dataset
+	Decimal    Decimal
+	DateFormat DateFormat
+}
+
+func (o Options) Validate() error {
+	if !o.Format.IsValid() {
+		return ErrFormatInvalid
+	}
+	if !o.Dataset.IsValid() {
+		return ErrDatasetInvalid
+	}
+	if !o.Decimal.IsValid() {
+		return ErrDecimalInvalid
+	}
+	if !o.DateFormat.IsValid() {
+		return ErrDateFormatInvalid
+	}
+	return nil
+}
+
+// Kind diz como o valor de uma célula é formatado.
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindInteger
+	KindMoney
+	KindDateTime
+	KindDate
+)
+
+// Cell é o valor de uma célula da planilha; Empty marca valores ausentes,
+// como o custo de um item sem custo cadastrado.
+type Cell struct {
+	Kind   Kind
+	Text   string
+	Number float64
+	Time   time.Time
+	Empty  bool
+}
+
+func Text(value string) Cell {
+	return Cell{Kind: KindText, Text: value}
+}
+
+func Integer(value int) Cell {
+	return Cell{Kind: KindInteger, Number: float64(value)}
+}
+
+func Money(value float64) Cell {
+	return Cell{Kind: KindMoney, Number: value}
+}
+
+func OptionalMoney(value *float64) Cell {
+	if value == nil {
+		return Cell{Kind: KindMoney, Empty: true}
+	}
+	return Money(*value)
+}
+
+func DateTime(value time.Time) Cell {
+	return Cell{Kind: KindDateTime, Time: value}
+}
+
+func Date(value time.Time) Cell {
+	return Cell{Kind: KindDate, Time: value}
+}
+
+// Writer grava a planilha uma linha por vez; Close conclui o arquivo.
+type Writer interface {
+	WriteRow(cells []Cell) error
+	Close() error
+}
+
+// Encoder cria o Writer do formato pedido sobre w. sheet é o nome da aba,
+// quando o formato tem abas.
+type Encoder interface {
+	NewWriter(w io.Writer, sheet string, opts Options) (Writer, error)
+}
+
+// Repository percorre as vendas de um período [start, end) em ordem de data,
+// entregando uma linha por vez a fn em vez de carregar o período em memória.
+// As vendas entregues com itens e acréscimos trazem só os dados da venda, sem
+// os itens e pagamentos. Um erro devolvido por fn interrompe a leitura.
+type Repository interface {
+	StreamSales(ctx context.Context, start, end time.Time, fn func(s *sale.Sale) error) error
+	StreamItems(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem) error) error
+	StreamAdditions(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem, a *addition.Addition) error) error
+}
@@ -0,0 +1,76 @@
+package fiscal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EmissionNormal é o tipo de emissão (tpEmis) sem contingência.
+const EmissionNormal = 1
+
+var stateCodes = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27", "SE": "28", "BA": "29",
+	"MG": "31", "ES": "32", "RJ": "33", "SP": "35",
+	"PR": "41", "SC": "42", "RS": "43",
+	"MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// StateCode devolve o código IBGE da UF (cUF).
+func StateCode(uf string) (string, bool) {
+	code, ok := stateCodes[strings.ToUpper(strings.TrimSpace(uf))]
+	return code, ok
+}
+
+// AccessKey compõe a chave de acesso de 44 dígitos da NFC-e.
+type AccessKey struct {
+	StateCode    string
+	IssuedAt     time.Time
+	CNPJ         string
+	Series       int
+	Number       int
+	EmissionType int
+	Code         int
+}
+
+// String devolve a chave completa, terminada pelo dígito verificador.
+func (k AccessKey) String() string {
+	base := k.base()
+	return fmt.Sprintf("%s%d", base, Mod11CheckDigit(base))
+}
+
+// CheckDigit é o dígito verificador (cDV) da chave.
+func (k AccessKey) CheckDigit() int {
+	return Mod11CheckDigit(k.base())
+}
+
+func (k AccessKey) base() string {
+	return fmt.Sprintf("%s%s%s%s%03d%09d%d%08d",
+		k.StateCode, k.IssuedAt.Format("0601"), k.CNPJ, Model, k.Series, k.Number, k.EmissionType, k.Code)
+}
+
+// Mod11CheckDigit calcula o dígito verificador em módulo 11 com pesos de 2 a
+// 9 aplicados da direita para a esquerda; restos 0 e 1 resultam em zero.
+func Mod11CheckDigit(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		if weight++; weight > 9 {
+			weight = 2
+		}
+	}
+	if rest := sum % 11; rest > 1 {
+		return 11 - rest
+	}
+	return 0
+}
+
+// ValidCNPJ confere o tamanho e os dois dígitos verificadores do CNPJ.
+func ValidCNPJ(cnpj string) bool {
+	if len(cnpj) != 14 || strings.Trim(cnpj, "0123456789") != "" || strings.Count(cnpj, cnpj[:1]) == 14 {
+		return false
+	}
+	// Os pesos do CNPJ seguem a mesma regra da chave de acesso.
+	return Mod11CheckDigit(cnpj[:12]) == int(cnpj[12]-'0') && Mod11CheckDigit(cnpj[:13]) == int(cnpj[13]-'0')
+}
@@ -0,0 +1,171 @@
+package fiscal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotConfigured           = errors.New("NFC-e não configurada: informe os dados do emitente, o CSC e o certificado")
+	ErrCNPJInvalid             = errors.New("CNPJ do emitente inválido")
+	ErrStateInvalid            = errors.New("UF do emitente inválida")
+	ErrTaxRegimeInvalid        = errors.New("regime tributário (CRT) do emitente inválido")
+	ErrEnvironmentInvalid      = errors.New("ambiente da NFC-e inválido: use production ou homologation")
+	ErrSeriesInvalid           = errors.New("a série da NFC-e deve estar entre 0 e 999")
+	ErrProductFiscalData       = errors.New("produto sem dados fiscais: informe NCM, CFOP e CST")
+	ErrTaxSituationUnsupported = errors.New("situação tributária (CST/CSOSN) não suportada")
+	ErrSaleNotPaid             = errors.New("a venda precisa estar paga para emitir a NFC-e")
+	ErrDocumentNotFound        = errors.New("NFC-e não encontrada para a venda")
+	ErrDocumentAuthorized      = errors.New("a NFC-e da venda já foi autorizada")
+	ErrIssueInProgress         = errors.New("a NFC-e da venda já está sendo emitida")
+	ErrTransmissionFailed      = errors.New("falha ao transmitir a NFC-e para a SEFAZ")
+)
+
+// Model é o modelo do documento fiscal: 65 identifica a NFC-e.
+const Model = "65"
+
+// Environment é o ambiente de emissão (tpAmb); documentos de homologação não
+// têm valor fiscal.
+type Environment int
+
+const (
+	EnvironmentProduction   Environment = 1
+	EnvironmentHomologation Environment = 2
+)
+
+// ParseEnvironment converte o nome usado na configuração.
+func ParseEnvironment(name string) (Environment, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "production":
+		return EnvironmentProduction, nil
+	case "homologation", "":
+		return EnvironmentHomologation, nil
+	}
+	return 0, ErrEnvironmentInvalid
+}
+
+// TaxRegime é o código de regime tributário (CRT) do emitente.
+type TaxRegime int
+
+const (
+	TaxRegimeSimples       TaxRegime = 1
+	TaxRegimeSimplesExcess TaxRegime = 2
+	TaxRegimeNormal        TaxRegime = 3
+)
+
+func (r TaxRegime) IsValid() bool {
+	return r >= TaxRegimeSimples && r <= TaxRegimeNormal
+}
+
+// IsSimples indica se os itens são tributados por CSOSN em vez de CST.
+func (r TaxRegime) IsSimples() bool {
+	return r == TaxRegimeSimples || r == TaxRegimeSimplesExcess
+}
+
+type Address struct {
+	Street   string
+	Number   string
+	District string
+	CityCode string
+	City     string
+	State    string
+	ZipCode  string
+}
+
+// Issuer reúne os dados do emitente e do CSC (código de segurança do
+// contribuinte) usados no QR Code da NFC-e.
+type Issuer struct {
+	CNPJ              string
+	StateRegistration string
+	Name              string
+	TradeName         string
+	TaxRegime         TaxRegime
+	Address           Address
+	CSCID             string
+	CSC               string
+	QRCodeURL         string
+	ConsultURL        string
+}
+
+func (i Issuer) Validate() error {
+	if i.CNPJ == "" || i.StateRegistration == "" || i.Name == "" || i.CSCID == "" || i.CSC == "" ||
+		i.QRCodeURL == "" || i.ConsultURL == "" || i.Address.CityCode == "" {
+		return ErrNotConfigured
+	}
+	if !ValidCNPJ(i.CNPJ) {
+		return ErrCNPJInvalid
+	}
+	if _, ok := StateCode(i.Address.State); !ok {
+		return ErrStateInvalid
+	}
+	if !i.TaxRegime.IsValid() {
+		return ErrTaxRegimeInvalid
+	}
+	return nil
+}
+
+// Status acompanha o documento junto à SEFAZ.
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusAuthorized Status = "authorized"
+	StatusRejected   Status = "rejected"
+)
+
+// Document é a NFC-e emitida para uma venda. XML guarda o documento assinado
+// exatamente como foi transmitido.
+type Document struct {
+	ID           uuid.UUID   `json:"id"`
+	SaleID       uuid.UUID   `json:"sale_id"`
+	Series       int         `json:"series"`
+	Number       int         `json:"number"`
+	AccessKey    string      `json:"access_key"`
+	Environment  Environment `json:"environment"`
+	Status       Status      `json:"status"`
+	XML          []byte      `json:"-"`
+	Protocol     string      `json:"protocol,omitempty"`
+	StatusCode   int         `json:"status_code,omitempty"`
+	Message      string      `json:"message,omitempty"`
+	IssuedAt     time.Time   `json:"issued_at"`
+	AuthorizedAt *time.Time  `json:"authorized_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// Authorization é a resposta da SEFAZ a uma transmissão.
+type Authorization struct {
+	StatusCode int
+	Message    string
+	Protocol   string
+	ReceivedAt time.Time
+}
+
+// Authorized segue os códigos da SEFAZ: 100 autoriza o uso e 150 autoriza
+// fora do prazo.
+func (a Authorization) Authorized() bool {
+	return a.StatusCode == 100 || a.StatusCode == 150
+}
+
+// MissingFiscalData aponta um produto sem os dados fiscais obrigatórios e
+// quais campos faltam depois de aplicados os padrões da categoria.
+type MissingFiscalData struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	Name       string    `json:"name"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Fields     []string  `json:"missing_fields"`
+}
+
+// Encoder gera o XML assinado de uma NFC-e.
+type Encoder interface {
+	Encode(n *NFCe) ([]byte, error)
+}
+
+// Transmitter envia o XML assinado para autorização na SEFAZ.
+type Transmitter interface {
+	Transmit(ctx context.Context, accessKey string, xml []byte) (*Authorization, error)
+}
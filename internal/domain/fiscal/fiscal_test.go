@@ -0,0 +1,137 @@
+package fiscal
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIssuer = Issuer{
+	CNPJ:              "11222333000181",
+	StateRegistration: "123456789012",
+	Name:              "Andressa Lanches LTDA",
+	TaxRegime:         TaxRegimeSimples,
+	Address:           Address{CityCode: "3550308", City: "Sao Paulo", State: "SP"},
+	CSCID:             "000001",
+	CSC:               "123456",
+	QRCodeURL:         "https://nfce.example.com/qrcode",
+	ConsultURL:        "https://nfce.example.com/consulta",
+}
+
+func TestMod11CheckDigit_ManualExample(t *testing.T) {
+	// Chave de exemplo do Manual de Orientação do Contribuinte.
+	key := "52060433009911002506550120000007800267301615"
+	assert.Equal(t, 5, Mod11CheckDigit(key[:43]))
+}
+
+func TestAccessKey_String(t *testing.T) {
+	key := AccessKey{
+		StateCode:    "35",
+		IssuedAt:     time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC),
+		CNPJ:         testIssuer.CNPJ,
+		Series:       1,
+		Number:       42,
+		EmissionType: EmissionNormal,
+		Code:         12345678,
+	}
+
+	value := key.String()
+	require.Len(t, value, 44)
+	assert.Equal(t, "3524091122233300018165001000000042112345678", value[:43])
+	assert.Equal(t, Mod11CheckDigit(value[:43]), key.CheckDigit())
+	assert.Equal(t, byte('0'+key.CheckDigit()), value[43])
+}
+
+func TestValidCNPJ(t *testing.T) {
+	assert.True(t, ValidCNPJ("11222333000181"))
+	assert.False(t, ValidCNPJ("11222333000182"))
+	assert.False(t, ValidCNPJ("11111111111111"))
+	assert.False(t, ValidCNPJ("11.222.333/0001-81"))
+}
+
+func TestIssuer_Validate(t *testing.T) {
+	assert.NoError(t, testIssuer.Validate())
+
+	missing := testIssuer
+	missing.CSC = ""
+	assert.Equal(t, ErrNotConfigured, missing.Validate())
+
+	state := testIssuer
+	state.Address.State = "XX"
+	assert.Equal(t, ErrStateInvalid, state.Validate())
+
+	regime := testIssuer
+	regime.TaxRegime = 4
+	assert.Equal(t, ErrTaxRegimeInvalid, regime.Validate())
+}
+
+func newFiscalTestSale(products ...*product.Product) *sale.Sale {
+	s := &sale.Sale{ID: uuid.New(), OrderNumber: 7}
+	for _, p := range products {
+		s.Items = append(s.Items, sale.SaleItem{ProductID: p.ID, ProductName: p.Name, Quantity: 2, UnitPrice: p.Price, TotalPrice: 2 * p.Price})
+		s.TotalAmount += 2 * p.Price
+	}
+	return s
+}
+
+func TestNewNFCe_AllocatesDiscountAndCharges(t *testing.T) {
+	burger := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 10, FiscalData: taxation.FiscalData{NCM: "21069090", CFOP: "5102", CST: "102"}}
+	juice := &product.Product{ID: uuid.New(), Name: "Suco", Price: 5, FiscalData: taxation.FiscalData{NCM: "22029900", CFOP: "5102", CST: "102"}}
+	products := map[uuid.UUID]*product.Product{burger.ID: burger, juice.ID: juice}
+
+	s := newFiscalTestSale(burger, juice)
+	s.Items[0].Additions = []addition.Addition{{Name: "Bacon"}}
+	s.Discount = 1
+	s.ServiceCharge = 2.9
+	s.TotalAmount = 30 - 1 + 2.9
+	s.Payments = []sale.Payment{{Method: sale.PaymentMethodCash, Amount: 40}}
+
+	n, err := NewNFCe(testIssuer, EnvironmentProduction, 1, 42, time.Now(), s, products)
+	require.NoError(t, err)
+
+	require.Len(t, n.Items, 2)
+	assert.Equal(t, "X-Salada + Bacon", n.Items[0].Description)
+	assert.Equal(t, 10.0, n.Items[0].UnitPrice)
+	assert.Equal(t, 0.66, n.Items[0].Discount)
+	assert.Equal(t, 0.34, n.Items[1].Discount)
+	assert.Equal(t, 1.93, n.Items[0].Other)
+	assert.Equal(t, 0.97, n.Items[1].Other)
+	assert.Equal(t, 30.0, n.ProductsTotal())
+	assert.Equal(t, s.TotalAmount, n.Total())
+	assert.Equal(t, []Payment{{Type: PaymentTypeCash, Amount: 40}}, n.Payments)
+	assert.Equal(t, 8.1, n.Change)
+}
+
+func TestNewNFCe_HomologationDescription(t *testing.T) {
+	burger := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 10, FiscalData: taxation.FiscalData{NCM: "21069090", CFOP: "5102", CST: "102"}}
+	s := newFiscalTestSale(burger)
+	s.Payments = []sale.Payment{{Method: sale.PaymentMethodPix, Amount: s.TotalAmount}}
+
+	n, err := NewNFCe(testIssuer, EnvironmentHomologation, 1, 1, time.Now(), s, map[uuid.UUID]*product.Product{burger.ID: burger})
+	require.NoError(t, err)
+	assert.Equal(t, HomologationDescription, n.Items[0].Description)
+}
+
+func TestNewNFCe_Errors(t *testing.T) {
+	withoutNCM := &product.Product{ID: uuid.New(), Name: "X-Salada", Price: 10, FiscalData: taxation.FiscalData{CFOP: "5102", CST: "102"}}
+	products := map[uuid.UUID]*product.Product{withoutNCM.ID: withoutNCM}
+
+	unpaid := newFiscalTestSale(withoutNCM)
+	_, err := NewNFCe(testIssuer, EnvironmentProduction, 1, 1, time.Now(), unpaid, products)
+	assert.Equal(t, ErrSaleNotPaid, err)
+
+	paid := newFiscalTestSale(withoutNCM)
+	paid.Payments = []sale.Payment{{Method: sale.PaymentMethodCash, Amount: paid.TotalAmount}}
+	_, err = NewNFCe(testIssuer, EnvironmentProduction, 1, 1, time.Now(), paid, products)
+	assert.Equal(t, ErrProductFiscalData, err)
+
+	_, err = NewNFCe(testIssuer, EnvironmentProduction, 1000, 1, time.Now(), paid, products)
+	assert.Equal(t, ErrSeriesInvalid, err)
+}
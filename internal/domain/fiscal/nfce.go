@@ -0,0 +1,287 @@
+package fiscal
+
+import (
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"encoding/binary"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HomologationDescription substitui a descrição do primeiro item em
+// homologação, como exige a SEFAZ.
+const HomologationDescription = "NOTA FISCAL EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+
+// PaymentType é o meio de pagamento (tPag) da NFC-e.
+type PaymentType string
+
+const (
+	PaymentTypeCash       PaymentType = "01"
+	PaymentTypeCreditCard PaymentType = "03"
+	PaymentTypeDebitCard  PaymentType = "04"
+	PaymentTypePix        PaymentType = "17"
+)
+
+var paymentTypes = map[sale.PaymentMethod]PaymentType{
+	sale.PaymentMethodCash:       PaymentTypeCash,
+	sale.PaymentMethodCreditCard: PaymentTypeCreditCard,
+	sale.PaymentMethodDebitCard:  PaymentTypeDebitCard,
+	sale.PaymentMethodPix:        PaymentTypePix,
+}
+
+// IsCard indica os meios que exigem o grupo de cartões no XML.
+func (t PaymentType) IsCard() bool {
+	return t == PaymentTypeCreditCard || t == PaymentTypeDebitCard
+}
+
+type Item struct {
+	Code        string
+	Description string
+	NCM         string
+	CEST        string
+	CFOP        string
+	CST         string
+	Origin      int
+	Unit        string
+	Quantity    float64
+	UnitPrice   float64
+	Total       float64
+	Discount    float64
+	Other       float64
+	ICMSRate    float64
+	PISRate     float64
+	COFINSRate  float64
+}
+
+// TaxBase é a base de cálculo dos tributos do item: o valor com desconto e
+// acréscimos rateados.
+func (i Item) TaxBase() float64 {
+	return roundMoney(i.Total - i.Discount + i.Other)
+}
+
+func (i Item) ICMS() float64 {
+	return roundMoney(i.TaxBase() * i.ICMSRate / 100)
+}
+
+func (i Item) PIS() float64 {
+	return roundMoney(i.TaxBase() * i.PISRate / 100)
+}
+
+func (i Item) COFINS() float64 {
+	return roundMoney(i.TaxBase() * i.COFINSRate / 100)
+}
+
+type Payment struct {
+	Type   PaymentType
+	Amount float64
+}
+
+// NFCe é a nota fiscal de consumidor eletrônica de uma venda, já com os
+// valores rateados por item como o leiaute exige.
+type NFCe struct {
+	Issuer      Issuer
+	Environment Environment
+	AccessKey   AccessKey
+	OrderNumber int
+	Delivery    bool
+	Items       []Item
+	Payments    []Payment
+	Change      float64
+}
+
+// NewNFCe monta a NFC-e da venda a partir dos dados fiscais dos produtos,
+// que já devem trazer os padrões herdados da categoria. Desconto, acréscimos
+// e taxa de serviço são rateados entre os itens na proporção do valor de
+// cada um.
+func NewNFCe(issuer Issuer, env Environment, series, number int, issuedAt time.Time, s *sale.Sale, products map[uuid.UUID]*product.Product) (*NFCe, error) {
+	if err := issuer.Validate(); err != nil {
+		return nil, err
+	}
+	if series < 0 || series > 999 {
+		return nil, ErrSeriesInvalid
+	}
+	if len(s.Payments) == 0 || s.AmountPaid() < s.TotalAmount-0.005 {
+		return nil, ErrSaleNotPaid
+	}
+
+	stateCode, _ := StateCode(issuer.Address.State)
+	n := &NFCe{
+		Issuer:      issuer,
+		Environment: env,
+		AccessKey: AccessKey{
+			StateCode:    stateCode,
+			IssuedAt:     issuedAt,
+			CNPJ:         issuer.CNPJ,
+			Series:       series,
+			Number:       number,
+			EmissionType: EmissionNormal,
+			Code:         numericCode(s.ID, number),
+		},
+		OrderNumber: s.OrderNumber,
+		Delivery:    s.OrderType == sale.OrderTypeDelivery,
+		Change:      s.Change(),
+	}
+
+	weights := make([]float64, len(s.Items))
+	for i, si := range s.Items {
+		p := products[si.ProductID]
+		if p == nil || len(p.Missing()) > 0 {
+			return nil, ErrProductFiscalData
+		}
+		if !issuer.TaxRegime.IsSimples() && p.CST == "00" && p.ICMSRate == nil {
+			return nil, taxation.ErrICMSRequired
+		}
+
+		description := si.ProductName
+		if description == "" {
+			description = p.Name
+		}
+		for _, a := range si.Additions {
+			description += " + " + a.Name
+		}
+		if i == 0 && env == EnvironmentHomologation {
+			description = HomologationDescription
+		}
+
+		n.Items = append(n.Items, Item{
+			Code:        p.ID.String(),
+			Description: truncate(description, 120),
+			NCM:         p.NCM,
+			CEST:        p.CEST,
+			CFOP:        p.CFOP,
+			CST:         p.CST,
+			Origin:      p.OriginOrDefault(),
+			Unit:        p.UnitOrDefault(),
+			Quantity:    float64(si.Quantity),
+			UnitPrice:   roundMoney(si.TotalPrice / float64(si.Quantity)),
+			Total:       roundMoney(si.TotalPrice),
+			ICMSRate:    icmsRate(issuer.TaxRegime, p.FiscalData),
+			PISRate:     taxation.Rate(p.PISRate),
+			COFINSRate:  taxation.Rate(p.COFINSRate),
+		})
+		weights[i] = si.TotalPrice
+	}
+
+	for i, v := range allocate(s.Discount, weights) {
+		n.Items[i].Discount = v
+	}
+	for i, v := range allocate(s.AdditionalCharges+s.ServiceCharge, weights) {
+		n.Items[i].Other = v
+	}
+
+	for _, p := range s.Payments {
+		n.Payments = append(n.Payments, Payment{Type: paymentTypes[p.Method], Amount: roundMoney(p.Amount)})
+	}
+	return n, nil
+}
+
+// ProductsTotal é o valor bruto dos itens (vProd).
+func (n *NFCe) ProductsTotal() float64 {
+	return n.sum(func(i Item) float64 { return i.Total })
+}
+
+// DiscountTotal é o desconto rateado nos itens (vDesc).
+func (n *NFCe) DiscountTotal() float64 {
+	return n.sum(func(i Item) float64 { return i.Discount })
+}
+
+// OtherTotal reúne acréscimos e taxa de serviço (vOutro).
+func (n *NFCe) OtherTotal() float64 {
+	return n.sum(func(i Item) float64 { return i.Other })
+}
+
+// TaxBaseTotal soma as bases de cálculo do ICMS destacado (vBC).
+func (n *NFCe) TaxBaseTotal() float64 {
+	return n.sum(func(i Item) float64 {
+		if i.ICMSRate > 0 {
+			return i.TaxBase()
+		}
+		return 0
+	})
+}
+
+func (n *NFCe) ICMSTotal() float64 {
+	return n.sum(Item.ICMS)
+}
+
+func (n *NFCe) PISTotal() float64 {
+	return n.sum(Item.PIS)
+}
+
+func (n *NFCe) COFINSTotal() float64 {
+	return n.sum(Item.COFINS)
+}
+
+// Total é o valor da nota (vNF).
+func (n *NFCe) Total() float64 {
+	return roundMoney(n.ProductsTotal() - n.DiscountTotal() + n.OtherTotal())
+}
+
+func (n *NFCe) sum(value func(Item) float64) float64 {
+	var total float64
+	for _, item := range n.Items {
+		total += value(item)
+	}
+	return roundMoney(total)
+}
+
+// icmsRate devolve a alíquota do ICMS destacado na nota, que só existe na
+// tributação integral (CST 00) do regime normal.
+func icmsRate(regime TaxRegime, data taxation.FiscalData) float64 {
+	if regime.IsSimples() || data.CST != "00" {
+		return 0
+	}
+	return taxation.Rate(data.ICMSRate)
+}
+
+// allocate divide um valor em centavos proporcionalmente aos pesos; a
+// diferença de arredondamento fica no último item.
+func allocate(amount float64, weights []float64) []float64 {
+	result := make([]float64, len(weights))
+	cents := int64(math.Round(amount * 100))
+	if cents <= 0 || len(weights) == 0 {
+		return result
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	var allocated int64
+	for i, w := range weights {
+		share := cents - allocated
+		if i < len(weights)-1 {
+			share = int64(math.Floor(float64(cents) * w / total))
+		}
+		allocated += share
+		result[i] = float64(share) / 100
+	}
+	return result
+}
+
+// numericCode deriva o código numérico (cNF) da venda, que precisa ser
+// diferente do número da nota.
+func numericCode(saleID uuid.UUID, number int) int {
+	code := int(binary.BigEndian.Uint32(saleID[:4]) % 100000000)
+	if code == number {
+		code = (code + 1) % 100000000
+	}
+	return code
+}
+
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if r := []rune(value); len(r) > max {
+		return string(r[:max])
+	}
+	return value
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,17 @@
+package fiscal
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Reserve grava o documento pendente da venda com o próximo número da
+	// série na mesma transação, de forma segura para emissões concorrentes.
+	// Retorna ErrIssueInProgress, sem consumir o número, quando a venda já
+	// tem documento.
+	Reserve(ctx context.Context, doc *Document) error
+	Update(ctx context.Context, doc *Document) error
+	GetBySale(ctx context.Context, saleID uuid.UUID) (*Document, error)
+}
@@ -0,0 +1,95 @@
+package kitchen
+
+import (
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventSaleCreated       EventType = "sale.created"
+	EventSaleStatusChanged EventType = "sale.status_changed"
+	EventItemNotesChanged  EventType = "sale.item_notes_changed"
+	EventItemStatusChanged EventType = "sale.item_status_changed"
+	// EventResync avisa que o Last-Event-ID informado não é conhecido, por
+	// exemplo após um reinício do servidor: a tela deve descartar as vendas
+	// que exibe e remontá-las com os eventos que vêm em seguida.
+	EventResync EventType = "resync"
+)
+
+// Event é uma notificação enviada às telas da cozinha. O ID é crescente e
+// permite que um cliente reconectado peça apenas os eventos que perdeu. O
+// evento de resync não traz venda.
+type Event struct {
+	ID         uint64     `json:"id"`
+	Type       EventType  `json:"type"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	Sale       *sale.Sale `json:"sale"`
+}
+
+// Filter devolve uma cópia do evento contendo apenas os itens das categorias
+// e estações informadas; listas vazias não restringem. Retorna false quando
+// nenhum item da venda passa pelo filtro.
+func (e Event) Filter(categoryIDs, stationIDs []uuid.UUID) (Event, bool) {
+	if (len(categoryIDs) == 0 && len(stationIDs) == 0) || e.Sale == nil {
+		return e, true
+	}
+
+	filteredSale := *e.Sale
+	filteredSale.Items = nil
+	for _, item := range e.Sale.Items {
+		if matchesCategory(item, categoryIDs) && matchesStation(item, stationIDs) {
+			filteredSale.Items = append(filteredSale.Items, item)
+		}
+	}
+	if len(filteredSale.Items) == 0 {
+		return e, false
+	}
+
+	e.Sale = &filteredSale
+	return e, true
+}
+
+func matchesCategory(item sale.SaleItem, categoryIDs []uuid.UUID) bool {
+	if len(categoryIDs) == 0 {
+		return true
+	}
+	for _, id := range categoryIDs {
+		if item.CategoryID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesStation(item sale.SaleItem, stationIDs []uuid.UUID) bool {
+	if len(stationIDs) == 0 {
+		return true
+	}
+	if item.StationID == nil {
+		return false
+	}
+	for _, id := range stationIDs {
+		if *item.StationID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Feed entrega os eventos da cozinha aos assinantes. Subscribe devolve os
+// eventos posteriores a lastEventID ainda mantidos no histórico, seguidos de
+// um canal com os novos eventos; o canal é fechado quando o assinante fica
+// para trás e deve reconectar. Um lastEventID desconhecido gera um evento
+// de resync seguido de todo o histórico.
+type Feed interface {
+	Subscribe(lastEventID uint64) (missed []Event, events <-chan Event, cancel func())
+}
@@ -0,0 +1,112 @@
+package menu
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/product"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Menu é o cardápio mostrado aos clientes, sem custos, dados fiscais nem
+// códigos internos. O cadastro não marca categorias ou produtos como
+// inativos; as categorias sem produtos ficam de fora e os acréscimos, que
+// valem para qualquer produto, vêm numa lista só.
+type Menu struct {
+	Categories []Category `json:"categories"`
+	Additions  []Addition `json:"additions"`
+}
+
+type Category struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Products    []Product `json:"products"`
+}
+
+type Product struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Price       float64   `json:"price"`
+}
+
+type Addition struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Price float64   `json:"price"`
+}
+
+// New monta o cardápio em ordem alfabética. Produtos de categorias que não
+// existem mais ficam de fora.
+func New(categories []*category.Category, products []*product.Product, additions []*addition.Addition) *Menu {
+	byCategory := make(map[uuid.UUID][]Product)
+	for _, p := range products {
+		byCategory[p.CategoryID] = append(byCategory[p.CategoryID], Product{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+		})
+	}
+
+	m := &Menu{Categories: []Category{}, Additions: []Addition{}}
+	for _, c := range categories {
+		items := byCategory[c.ID]
+		if len(items) == 0 {
+			continue
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return less(items[i].Name, items[i].ID, items[j].Name, items[j].ID)
+		})
+		m.Categories = append(m.Categories, Category{ID: c.ID, Name: c.Name, Description: c.Description, Products: items})
+	}
+	sort.Slice(m.Categories, func(i, j int) bool {
+		return less(m.Categories[i].Name, m.Categories[i].ID, m.Categories[j].Name, m.Categories[j].ID)
+	})
+
+	for _, a := range additions {
+		m.Additions = append(m.Additions, Addition{ID: a.ID, Name: a.Name, Price: a.Price})
+	}
+	sort.Slice(m.Additions, func(i, j int) bool {
+		return less(m.Additions[i].Name, m.Additions[i].ID, m.Additions[j].Name, m.Additions[j].ID)
+	})
+	return m
+}
+
+// less ordena pelo nome sem diferenciar maiúsculas e, no empate, pelo ID,
+// para que o mesmo cadastro gere sempre o mesmo ETag.
+func less(nameA string, idA uuid.UUID, nameB string, idB uuid.UUID) bool {
+	if a, b := strings.ToLower(nameA), strings.ToLower(nameB); a != b {
+		return a < b
+	}
+	return idA.String() < idB.String()
+}
+
+// Snapshot é o cardápio já serializado, pronto para ser servido com cache
+// HTTP. O ETag vem do conteúdo, então vale entre instâncias e reinícios;
+// LastModified é a última alteração do cardápio vista por esta instância.
+type Snapshot struct {
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+func NewSnapshot(m *Menu, lastModified time.Time) (*Snapshot, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	return &Snapshot{
+		Body:         body,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastModified,
+	}, nil
+}
@@ -0,0 +1,106 @@
+package payment
+
+import (
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChargeNotFound          = errors.New("cobrança não encontrada")
+	ErrChargeNotRefundable     = errors.New("apenas cobranças confirmadas podem ser estornadas")
+	ErrChargeMethodInvalid     = errors.New("forma de pagamento não suportada pelo gateway")
+	ErrNothingToCharge         = errors.New("a venda não possui valor pendente de pagamento")
+	ErrChargeExceedsBalance    = errors.New("a cobrança excede o valor da venda ainda não pago nem cobrado")
+	ErrWebhookSignatureInvalid = errors.New("assinatura do webhook inválida")
+	ErrWebhookPayloadInvalid   = errors.New("conteúdo do webhook inválido")
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusFailed    Status = "failed"
+	StatusRefunded  Status = "refunded"
+)
+
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusConfirmed, StatusFailed, StatusRefunded:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo define as mudanças de status aceitas. Notificações repetidas
+// ou fora de ordem do gateway não revertem uma cobrança já resolvida.
+func (s Status) CanTransitionTo(next Status) bool {
+	switch s {
+	case StatusPending:
+		return next == StatusConfirmed || next == StatusFailed
+	case StatusConfirmed:
+		return next == StatusRefunded
+	}
+	return false
+}
+
+// Charge é uma cobrança de uma venda criada em um gateway de pagamento.
+type Charge struct {
+	ID               uuid.UUID          `json:"id"`
+	SaleID           uuid.UUID          `json:"sale_id"`
+	Provider         string             `json:"provider"`
+	ProviderChargeID string             `json:"provider_charge_id"`
+	Method           sale.PaymentMethod `json:"method"`
+	Amount           float64            `json:"amount"`
+	Status           Status             `json:"status"`
+	PixPayload       string             `json:"pix_payload,omitempty"`
+	CheckoutURL      string             `json:"checkout_url,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// Reference identifica o pagamento registrado na venda quando a cobrança é
+// confirmada.
+func (c *Charge) Reference() string {
+	return c.Provider + ":" + c.ProviderChargeID
+}
+
+// ChargeRequest descreve a cobrança solicitada ao gateway.
+type ChargeRequest struct {
+	SaleID      uuid.UUID
+	Reference   string
+	Method      sale.PaymentMethod
+	Amount      float64
+	Description string
+}
+
+// GatewayCharge é a visão do gateway sobre uma cobrança.
+type GatewayCharge struct {
+	ID          string
+	Status      Status
+	Amount      float64
+	PixPayload  string
+	CheckoutURL string
+}
+
+// WebhookEvent é uma notificação do gateway já com a assinatura verificada.
+type WebhookEvent struct {
+	ID       string
+	ChargeID string
+	Status   Status
+}
+
+// Gateway abstrai o provedor de pagamentos (PIX e cartão).
+type Gateway interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (*GatewayCharge, error)
+	GetCharge(ctx context.Context, id string) (*GatewayCharge, error)
+	Refund(ctx context.Context, id string) (*GatewayCharge, error)
+	// ParseWebhook confere a assinatura enviada pelo gateway e interpreta o
+	// corpo da notificação.
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
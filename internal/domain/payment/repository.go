@@ -0,0 +1,30 @@
+package payment
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create grava a cobrança desde que ela, somada aos pagamentos e às
+	// cobranças pendentes da venda, não passe do total da venda; caso
+	// contrário retorna ErrChargeExceedsBalance. Assim cobranças criadas ao
+	// mesmo tempo não pagam a venda duas vezes.
+	Create(ctx context.Context, c *Charge) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Charge, error)
+	GetByProviderChargeID(ctx context.Context, provider, providerChargeID string) (*Charge, error)
+	ListBySale(ctx context.Context, saleID uuid.UUID) ([]*Charge, error)
+	// UpdateStatus muda o status apenas se a cobrança ainda estiver no
+	// status de c, indicando se a alteração ocorreu. Na mesma transação,
+	// registra na venda o pagamento confirmado ou remove o estornado e grava
+	// o registro de auditoria, quando houver.
+	UpdateStatus(ctx context.Context, c *Charge, to Status, entry *audit.Entry) (bool, error)
+	// RegisterWebhookEvent grava o identificador da notificação e indica se ela
+	// é inédita.
+	RegisterWebhookEvent(ctx context.Context, provider, eventID string) (bool, error)
+	// ReleaseWebhookEvent apaga o identificador da notificação, para que o
+	// gateway possa reenviá-la quando o processamento falha.
+	ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error
+}
@@ -0,0 +1,228 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrNotConfigured         = errors.New("PIX não configurado: informe a chave, o nome e a cidade do recebedor")
+	ErrKeyRequired           = errors.New("a chave PIX é obrigatória")
+	ErrMerchantNameRequired  = errors.New("o nome do recebedor é obrigatório")
+	ErrMerchantCityRequired  = errors.New("a cidade do recebedor é obrigatória")
+	ErrMerchantAccountLength = errors.New("a chave PIX e a descrição excedem o tamanho permitido")
+	ErrAmountInvalid         = errors.New("o valor do PIX deve ser positivo")
+	ErrTxIDInvalid           = errors.New("o identificador da transação deve ter até 25 letras ou números")
+)
+
+// Identificadores dos campos do BR Code (padrão EMV® QRCPS-MPM).
+const (
+	idPayloadFormatIndicator = "00"
+	idPointOfInitiation      = "01"
+	idMerchantAccountInfo    = "26"
+	idMerchantCategoryCode   = "52"
+	idTransactionCurrency    = "53"
+	idTransactionAmount      = "54"
+	idCountryCode            = "58"
+	idMerchantName           = "59"
+	idMerchantCity           = "60"
+	idAdditionalData         = "62"
+	idCRC16                  = "63"
+
+	idGUI         = "00"
+	idKey         = "01"
+	idDescription = "02"
+	idLocation    = "25"
+	idTxID        = "05"
+
+	gui                = "br.gov.bcb.pix"
+	currencyBRL        = "986"
+	countryBR          = "BR"
+	oneTimeInitiation  = "12"
+	noMerchantCategory = "0000"
+	noTxID             = "***"
+
+	maxNameLength  = 25
+	maxCityLength  = 15
+	maxTxIDLength  = 25
+	maxFieldLength = 99
+)
+
+// Merchant identifica o recebedor dos pagamentos.
+type Merchant struct {
+	Key  string
+	Name string
+	City string
+}
+
+func (m Merchant) Validate() error {
+	if strings.TrimSpace(m.Key) == "" {
+		return ErrKeyRequired
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		return ErrMerchantNameRequired
+	}
+	if strings.TrimSpace(m.City) == "" {
+		return ErrMerchantCityRequired
+	}
+	return nil
+}
+
+// Payload descreve um BR Code. Sem Location o código é estático e leva a
+// chave do recebedor; com Location (a URL devolvida pelo PSP) é dinâmico.
+type Payload struct {
+	Merchant    Merchant
+	Amount      float64
+	TxID        string
+	Description string
+	Location    string
+}
+
+func (p Payload) Validate() error {
+	if p.Location == "" {
+		if err := p.Merchant.Validate(); err != nil {
+			return err
+		}
+	} else if strings.TrimSpace(p.Merchant.Name) == "" {
+		return ErrMerchantNameRequired
+	} else if strings.TrimSpace(p.Merchant.City) == "" {
+		return ErrMerchantCityRequired
+	}
+	if p.Amount < 0 {
+		return ErrAmountInvalid
+	}
+	if p.TxID != "" && p.TxID != noTxID && !validTxID(p.TxID) {
+		return ErrTxIDInvalid
+	}
+	if len(p.merchantAccountInfo()) > maxFieldLength {
+		return ErrMerchantAccountLength
+	}
+	return nil
+}
+
+// Encode gera o "PIX copia e cola" terminado pelo CRC16 do próprio texto.
+func (p Payload) Encode() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(field(idPayloadFormatIndicator, "01"))
+	if p.Location != "" {
+		b.WriteString(field(idPointOfInitiation, oneTimeInitiation))
+	}
+	b.WriteString(field(idMerchantAccountInfo, p.merchantAccountInfo()))
+	b.WriteString(field(idMerchantCategoryCode, noMerchantCategory))
+	b.WriteString(field(idTransactionCurrency, currencyBRL))
+	if p.Amount > 0 {
+		b.WriteString(field(idTransactionAmount, fmt.Sprintf("%.2f", p.Amount)))
+	}
+	b.WriteString(field(idCountryCode, countryBR))
+	b.WriteString(field(idMerchantName, sanitize(p.Merchant.Name, maxNameLength)))
+	b.WriteString(field(idMerchantCity, sanitize(p.Merchant.City, maxCityLength)))
+
+	txID := p.TxID
+	if txID == "" || p.Location != "" {
+		// No código dinâmico o identificador fica no PSP, atrás da URL.
+		txID = noTxID
+	}
+	b.WriteString(field(idAdditionalData, field(idTxID, txID)))
+
+	b.WriteString(idCRC16 + "04")
+	return b.String() + fmt.Sprintf("%04X", CRC16([]byte(b.String()))), nil
+}
+
+func (p Payload) merchantAccountInfo() string {
+	info := field(idGUI, gui)
+	if p.Location != "" {
+		return info + field(idLocation, strings.TrimPrefix(strings.TrimPrefix(p.Location, "https://"), "http://"))
+	}
+	info += field(idKey, strings.TrimSpace(p.Merchant.Key))
+	if p.Description != "" {
+		info += field(idDescription, p.Description)
+	}
+	return info
+}
+
+// CRC16 calcula o CRC-16/CCITT-FALSE (polinômio 0x1021, valor inicial
+// 0xFFFF) exigido pelo BR Code.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// TxIDForOrder deriva o identificador da transação do dia de operação e do
+// número do pedido, que juntos identificam a venda de forma única.
+func TxIDForOrder(businessDate time.Time, orderNumber int) string {
+	return fmt.Sprintf("PED%s%04d", businessDate.Format("20060102"), orderNumber)
+}
+
+// Charge é a cobrança PIX de uma venda, pronta para exibição ao cliente.
+type Charge struct {
+	SaleID      uuid.UUID `json:"sale_id"`
+	OrderNumber int       `json:"order_number"`
+	TxID        string    `json:"txid"`
+	Amount      float64   `json:"amount"`
+	Payload     string    `json:"payload"`
+}
+
+// QRCodeEncoder gera a imagem do código QR de um BR Code.
+type QRCodeEncoder interface {
+	EncodePNG(content string, size int) ([]byte, error)
+}
+
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func validTxID(txID string) bool {
+	if len(txID) > maxTxIDLength {
+		return false
+	}
+	for _, r := range txID {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitize remove acentos e caracteres fora do ASCII, que nem todos os
+// aplicativos de banco aceitam, e limita o tamanho do campo.
+func sanitize(value string, maxLength int) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, strings.TrimSpace(value))
+	if err != nil {
+		result = value
+	}
+
+	var b strings.Builder
+	for _, r := range result {
+		if r <= unicode.MaxASCII && unicode.IsPrint(r) {
+			b.WriteRune(r)
+		}
+	}
+	result = b.String()
+	if len(result) > maxLength {
+		result = strings.TrimSpace(result[:maxLength])
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package pix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exampleMerchant = Merchant{
+	Key:  "123e4567-e12b-12d1-a456-426655440000",
+	Name: "Fulano de Tal",
+	City: "BRASILIA",
+}
+
+func TestCRC16_CheckValue(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16([]byte("123456789")))
+}
+
+func TestPayload_StaticManualExample(t *testing.T) {
+	// Exemplo do Manual de Padrões para Iniciação do PIX (BACEN).
+	payload, err := Payload{Merchant: exampleMerchant}.Encode()
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		payload)
+}
+
+func TestPayload_StaticWithAmountAndTxID(t *testing.T) {
+	payload, err := Payload{
+		Merchant: Merchant{Key: exampleMerchant.Key, Name: "Andressa Lanches", City: "São Paulo"},
+		Amount:   25.9,
+		TxID:     TxIDForOrder(time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), 7),
+	}.Encode()
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000520400005303986540525.905802BR5916Andressa Lanches6009Sao Paulo62190515PED202409010007630457F2",
+		payload)
+}
+
+func TestPayload_Dynamic(t *testing.T) {
+	payload, err := Payload{
+		Merchant: Merchant{Name: exampleMerchant.Name, City: exampleMerchant.City},
+		Amount:   10,
+		TxID:     "PED202409010001",
+		Location: "https://pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25",
+	}.Encode()
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"00020101021226760014br.gov.bcb.pix2554pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25520400005303986540510.005802BR5913Fulano de Tal6008BRASILIA62070503***6304E607",
+		payload)
+}
+
+func TestPayload_TruncatesNameAndCity(t *testing.T) {
+	payload, err := Payload{
+		Merchant: Merchant{Key: "+5511999999999", Name: "Lanchonete da Andressa e Família", City: "São José dos Campos"},
+	}.Encode()
+
+	require.NoError(t, err)
+	assert.Contains(t, payload, "5924Lanchonete da Andressa e6015")
+	assert.Contains(t, payload, "6015Sao Jose dos Ca")
+}
+
+func TestPayload_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload Payload
+		err     error
+	}{
+		{"sem chave", Payload{Merchant: Merchant{Name: "A", City: "B"}}, ErrKeyRequired},
+		{"sem nome", Payload{Merchant: Merchant{Key: "k", City: "B"}}, ErrMerchantNameRequired},
+		{"sem cidade", Payload{Merchant: Merchant{Key: "k", Name: "A"}}, ErrMerchantCityRequired},
+		{"valor negativo", Payload{Merchant: exampleMerchant, Amount: -1}, ErrAmountInvalid},
+		{"txid com símbolos", Payload{Merchant: exampleMerchant, TxID: "PED-1"}, ErrTxIDInvalid},
+		{"txid longo", Payload{Merchant: exampleMerchant, TxID: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"}, ErrTxIDInvalid},
+		{"descrição longa", Payload{Merchant: exampleMerchant, Description: string(make([]byte, 60))}, ErrMerchantAccountLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.payload.Encode()
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAdjustmentModeInvalid   = errors.New("tipo de reajuste inválido: use percentage ou fixed")
+	ErrAdjustmentValueRequired = errors.New("o valor do reajuste é obrigatório")
+	ErrAdjustmentValueInvalid  = errors.New("o reajuste percentual deve ser maior que -100")
+	ErrAdjustmentScopeRequired = errors.New("informe as categorias, os produtos ou os acréscimos do reajuste")
+	ErrRoundingInvalid         = errors.New("arredondamento inválido: use ending entre 0 e 0.99 ou multiple maior que zero")
+	ErrAdjustedPriceInvalid    = errors.New("o reajuste deixa um item com preço inválido")
+	ErrAdjustmentConflict      = errors.New("um preço mudou durante o reajuste; gere a prévia de novo")
+)
+
+// AdjustmentMode indica como Value altera o preço.
+type AdjustmentMode string
+
+const (
+	// AdjustmentPercentage soma Value por cento ao preço (10 = +10%).
+	AdjustmentPercentage AdjustmentMode = "percentage"
+	// AdjustmentFixed soma Value reais ao preço.
+	AdjustmentFixed AdjustmentMode = "fixed"
+)
+
+func (m AdjustmentMode) IsValid() bool {
+	return m == AdjustmentPercentage || m == AdjustmentFixed
+}
+
+// RoundingMode define o acabamento do preço reajustado. Sem modo, o preço é
+// só arredondado para centavos.
+type RoundingMode string
+
+const (
+	// RoundingEnding sobe para o próximo preço terminado em Value centavos
+	// (0.90 faz 18,37 virar 18,90).
+	RoundingEnding RoundingMode = "ending"
+	// RoundingMultiple arredonda para o múltiplo de Value mais próximo
+	// (0.50 faz 18,37 virar 18,50).
+	RoundingMultiple RoundingMode = "multiple"
+)
+
+type Rounding struct {
+	Mode  RoundingMode `json:"mode,omitempty"`
+	Value float64      `json:"value,omitempty"`
+}
+
+func (r Rounding) Validate() error {
+	switch r.Mode {
+	case "":
+		return nil
+	case RoundingEnding:
+		if r.Value < 0 || toCents(r.Value) > 99 {
+			return ErrRoundingInvalid
+		}
+	case RoundingMultiple:
+		if toCents(r.Value) <= 0 {
+			return ErrRoundingInvalid
+		}
+	default:
+		return ErrRoundingInvalid
+	}
+	return nil
+}
+
+// Adjustment é um reajuste em lote. Os produtos entram pela categoria ou
+// pelo ID; os acréscimos, que não têm categoria, só pelo ID.
+type Adjustment struct {
+	Mode        AdjustmentMode `json:"mode"`
+	Value       float64        `json:"value"`
+	CategoryIDs []uuid.UUID    `json:"category_ids,omitempty"`
+	ProductIDs  []uuid.UUID    `json:"product_ids,omitempty"`
+	AdditionIDs []uuid.UUID    `json:"addition_ids,omitempty"`
+	Rounding    Rounding       `json:"rounding"`
+	// DryRun devolve a prévia sem gravar nada.
+	DryRun bool `json:"dry_run"`
+}
+
+func (a *Adjustment) Validate() error {
+	if !a.Mode.IsValid() {
+		return ErrAdjustmentModeInvalid
+	}
+	if a.Value == 0 {
+		return ErrAdjustmentValueRequired
+	}
+	if a.Mode == AdjustmentPercentage && a.Value <= -100 {
+		return ErrAdjustmentValueInvalid
+	}
+	if len(a.CategoryIDs) == 0 && len(a.ProductIDs) == 0 && len(a.AdditionIDs) == 0 {
+		return ErrAdjustmentScopeRequired
+	}
+	return a.Rounding.Validate()
+}
+
+// Apply calcula o preço reajustado e arredondado. As contas são feitas em
+// centavos para não acumular erro de ponto flutuante.
+func (a *Adjustment) Apply(price float64) float64 {
+	var cents int64
+	switch a.Mode {
+	case AdjustmentPercentage:
+		cents = int64(math.Round(price * (100 + a.Value)))
+	default:
+		cents = toCents(price) + toCents(a.Value)
+	}
+
+	switch a.Rounding.Mode {
+	case RoundingEnding:
+		ending := toCents(a.Rounding.Value)
+		rounded := cents - mod(cents, 100) + ending
+		if rounded < cents {
+			rounded += 100
+		}
+		cents = rounded
+	case RoundingMultiple:
+		step := toCents(a.Rounding.Value)
+		cents = int64(math.Round(float64(cents)/float64(step))) * step
+	}
+	return float64(cents) / 100
+}
+
+// AdjustmentItem é um item afetado pelo reajuste. Itens cujo preço não muda
+// depois do arredondamento ficam de fora.
+type AdjustmentItem struct {
+	ItemType ItemType  `json:"item_type"`
+	ItemID   uuid.UUID `json:"item_id"`
+	Name     string    `json:"name"`
+	OldPrice float64   `json:"old_price"`
+	NewPrice float64   `json:"new_price"`
+}
+
+type AdjustmentResult struct {
+	DryRun bool             `json:"dry_run"`
+	Items  []AdjustmentItem `json:"items"`
+}
+
+func toCents(value float64) int64 {
+	return int64(math.Round(value * 100))
+}
+
+// mod devolve o resto sempre positivo, para preços negativos no meio da
+// conta não inverterem o arredondamento.
+func mod(a, b int64) int64 {
+	return (a%b + b) % b
+}
@@ -0,0 +1,41 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdjustment_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjustment Adjustment
+		price      float64
+		want       float64
+	}{
+		{"percentual", Adjustment{Mode: AdjustmentPercentage, Value: 10}, 18.5, 20.35},
+		{"percentual negativo", Adjustment{Mode: AdjustmentPercentage, Value: -15}, 20, 17},
+		{"valor fixo", Adjustment{Mode: AdjustmentFixed, Value: 1.2}, 18.5, 19.7},
+		{"terminado em 90", Adjustment{Mode: AdjustmentPercentage, Value: 10, Rounding: Rounding{Mode: RoundingEnding, Value: 0.9}}, 18.5, 20.9},
+		{"terminado em 90 acima do final", Adjustment{Mode: AdjustmentFixed, Value: 0.95, Rounding: Rounding{Mode: RoundingEnding, Value: 0.9}}, 18, 19.9},
+		{"já terminado em 90", Adjustment{Mode: AdjustmentFixed, Value: 1, Rounding: Rounding{Mode: RoundingEnding, Value: 0.9}}, 17.9, 18.9},
+		{"múltiplo de 50 centavos", Adjustment{Mode: AdjustmentPercentage, Value: 5, Rounding: Rounding{Mode: RoundingMultiple, Value: 0.5}}, 17.5, 18.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.adjustment.Apply(tt.price))
+		})
+	}
+}
+
+func TestAdjustment_Validate(t *testing.T) {
+	scope := []uuid.UUID{uuid.New()}
+	assert.NoError(t, (&Adjustment{Mode: AdjustmentFixed, Value: -1, AdditionIDs: scope}).Validate())
+	assert.Equal(t, ErrAdjustmentModeInvalid, (&Adjustment{Mode: "double", Value: 1, ProductIDs: scope}).Validate())
+	assert.Equal(t, ErrAdjustmentValueRequired, (&Adjustment{Mode: AdjustmentFixed, ProductIDs: scope}).Validate())
+	assert.Equal(t, ErrAdjustmentValueInvalid, (&Adjustment{Mode: AdjustmentPercentage, Value: -100, ProductIDs: scope}).Validate())
+	assert.Equal(t, ErrAdjustmentScopeRequired, (&Adjustment{Mode: AdjustmentPercentage, Value: 10}).Validate())
+	assert.Equal(t, ErrRoundingInvalid, (&Adjustment{Mode: AdjustmentPercentage, Value: 10, CategoryIDs: scope, Rounding: Rounding{Mode: RoundingEnding, Value: 1}}).Validate())
+	assert.Equal(t, ErrRoundingInvalid, (&Adjustment{Mode: AdjustmentPercentage, Value: 10, CategoryIDs: scope, Rounding: Rounding{Mode: RoundingMultiple}}).Validate())
+}
@@ -0,0 +1,99 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrItemTypeInvalid        = errors.New("tipo de item inválido: use product ou addition")
+	ErrPriceNegative          = errors.New("o preço não pode ser negativo")
+	ErrEffectiveAtPast        = errors.New("a data de vigência deve ser futura")
+	ErrScheduleIdInvalid      = errors.New("ID do agendamento inválido")
+	ErrScheduleNotFound       = errors.New("agendamento de preço não encontrado")
+	ErrScheduleNotPending     = errors.New("o agendamento já foi aplicado ou cancelado")
+	ErrScheduleItemMismatched = errors.New("o agendamento não pertence a este item")
+)
+
+// SchedulerActor é o autor registrado na auditoria quando o agendador aplica
+// um preço.
+const SchedulerActor = "price-scheduler"
+
+// ItemType identifica o cadastro com preço: produtos e acréscimos.
+type ItemType string
+
+const (
+	ItemProduct  ItemType = "product"
+	ItemAddition ItemType = "addition"
+)
+
+func (t ItemType) IsValid() bool {
+	return t == ItemProduct || t == ItemAddition
+}
+
+// PriceChange é um registro do histórico de preços. OldPrice é nulo no
+// cadastro do item; ScheduledChangeID indica a troca feita pelo agendador.
+type PriceChange struct {
+	ID                uuid.UUID  `json:"id"`
+	ItemType          ItemType   `json:"item_type"`
+	ItemID            uuid.UUID  `json:"item_id"`
+	OldPrice          *float64   `json:"old_price"`
+	NewPrice          float64    `json:"new_price"`
+	ChangedBy         *uuid.UUID `json:"changed_by,omitempty"`
+	Actor             string     `json:"actor,omitempty"`
+	ScheduledChangeID *uuid.UUID `json:"scheduled_change_id,omitempty"`
+	ChangedAt         time.Time  `json:"changed_at"`
+}
+
+// ScheduleStatus acompanha o agendamento até ele ser aplicado ou cancelado.
+type ScheduleStatus string
+
+const (
+	ScheduleStatusPending  ScheduleStatus = "pending"
+	ScheduleStatusApplied  ScheduleStatus = "applied"
+	ScheduleStatusCanceled ScheduleStatus = "canceled"
+)
+
+// ScheduledChange é uma troca de preço futura, aplicada pelo agendador a
+// partir de EffectiveAt (por exemplo, o cardápio novo no dia 1º do mês).
+type ScheduledChange struct {
+	ID          uuid.UUID      `json:"id"`
+	ItemType    ItemType       `json:"item_type"`
+	ItemID      uuid.UUID      `json:"item_id"`
+	Price       float64        `json:"price"`
+	EffectiveAt time.Time      `json:"effective_at"`
+	Status      ScheduleStatus `json:"status"`
+	CreatedBy   *uuid.UUID     `json:"created_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	AppliedAt   *time.Time     `json:"applied_at,omitempty"`
+	CanceledAt  *time.Time     `json:"canceled_at,omitempty"`
+}
+
+func (s *ScheduledChange) Validate(now time.Time) error {
+	if !s.ItemType.IsValid() {
+		return ErrItemTypeInvalid
+	}
+	if s.Price < 0 {
+		return ErrPriceNegative
+	}
+	if !s.EffectiveAt.After(now) {
+		return ErrEffectiveAtPast
+	}
+	return nil
+}
+
+type scheduledChangeKey struct{}
+
+// WithScheduledChange marca o contexto da aplicação de um agendamento, para
+// que o histórico aponte a origem da troca.
+func WithScheduledChange(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, scheduledChangeKey{}, id)
+}
+
+func ScheduledChangeFrom(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(scheduledChangeKey{}).(uuid.UUID)
+	return id, ok
+}
@@ -0,0 +1,39 @@
+package pricing
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	AppendChange(ctx context.Context, change *PriceChange) error
+	// ListChanges devolve o histórico do item, mais recente primeiro.
+	ListChanges(ctx context.Context, itemType ItemType, itemID uuid.UUID) ([]*PriceChange, error)
+
+	CreateSchedule(ctx context.Context, schedule *ScheduledChange) error
+	GetSchedule(ctx context.Context, id uuid.UUID) (*ScheduledChange, error)
+	// ListSchedules devolve os agendamentos do item em ordem de vigência.
+	ListSchedules(ctx context.Context, itemType ItemType, itemID uuid.UUID) ([]*ScheduledChange, error)
+	// ListDueSchedules devolve os agendamentos pendentes com vigência até now,
+	// em ordem de vigência.
+	ListDueSchedules(ctx context.Context, now time.Time) ([]*ScheduledChange, error)
+	// ResolveSchedule tira o agendamento de pendente; devolve false quando ele
+	// já tinha sido aplicado ou cancelado.
+	ResolveSchedule(ctx context.Context, id uuid.UUID, status ScheduleStatus, at time.Time) (bool, error)
+}
+
+// AdjustmentRepository grava um reajuste em lote, ou um agendamento, numa
+// única transação: os preços, o histórico e a auditoria são gravados juntos
+// ou nada é gravado.
+type AdjustmentRepository interface {
+	// ApplyAdjustment devolve ErrAdjustmentConflict quando o preço de algum
+	// item já não é OldPrice, ou quando o item foi removido.
+	ApplyAdjustment(ctx context.Context, changes []*PriceChange, entries []*audit.Entry) error
+	// ApplySchedule marca o agendamento como aplicado e grava a troca de
+	// preço. Devolve false, sem gravar nada, quando o agendamento já não está
+	// pendente, e ErrAdjustmentConflict quando o preço mudou desde a leitura.
+	ApplySchedule(ctx context.Context, id uuid.UUID, change *PriceChange, entry *audit.Entry) (bool, error)
+}
@@ -1,6 +1,7 @@
 package product
 
 import (
+	"andressa-lanches/internal/domain/taxation"
 	"errors"
 
 	"github.com/google/uuid"
@@ -11,14 +12,21 @@ var (
 	ErrProductPricePositive = errors.New("o preço do produto deve ser positivo")
 	ErrProductCategoryID    = errors.New("o ID da categoria do produto é obrigatório")
 	ErrProductNotFound      = errors.New("produto não encontrado")
+	ErrProductCostNegative  = errors.New("o custo do produto não pode ser negativo")
+	ErrProductConflict      = errors.New("o produto foi alterado por outra operação; tente de novo")
 )
 
 type Product struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Price       float64   `json:"price"`
+	UnitCost    *float64  `json:"unit_cost,omitempty"`
 	Description string    `json:"description,omitempty"`
 	CategoryID  uuid.UUID `json:"category_id"`
+	// ExternalCode é o código do produto em sistemas de fora; opcional e
+	// único quando informado.
+	ExternalCode string `json:"external_code,omitempty"`
+	taxation.FiscalData
 }
 
 func (p *Product) Validate() error {
@@ -31,5 +39,8 @@ func (p *Product) Validate() error {
 	if p.CategoryID == uuid.Nil {
 		return ErrProductCategoryID
 	}
-	return nil
+	if p.UnitCost != nil && *p.UnitCost < 0 {
+		return ErrProductCostNegative
+	}
+	return p.FiscalData.Validate()
 }
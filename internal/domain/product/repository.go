@@ -1,15 +1,22 @@
 package product
 
 import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"context"
 
 	"github.com/google/uuid"
 )
 
+// Repository grava cada alteração junto com a troca de preço do histórico e
+// o registro de auditoria, numa única transação; change e entry podem ser
+// nulos quando não há o que registrar.
 type Repository interface {
-	Create(ctx context.Context, product *Product) error
+	Create(ctx context.Context, product *Product, change *pricing.PriceChange, entry *audit.Entry) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Product, error)
-	Update(ctx context.Context, product *Product) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Update só grava enquanto o preço do produto ainda é oldPrice,
+	// devolvendo ErrProductConflict quando outra alteração chegou antes.
+	Update(ctx context.Context, product *Product, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error
+	Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error
 	List(ctx context.Context) ([]*Product, error)
 }
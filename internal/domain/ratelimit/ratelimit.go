@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrLimitExceeded = errors.New("limite de requisições excedido; tente novamente mais tarde")
+
+// LockedError indica que o login está bloqueado por excesso de falhas.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return "muitas tentativas de login sem sucesso; tente novamente mais tarde"
+}
+
+// Counter é o estado de uma chave no Store.
+type Counter struct {
+	Count     int
+	LastHit   time.Time
+	ExpiresAt time.Time
+}
+
+// Limit é o limite geral de requisições por janela; zero desativa.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// maxLockout é o maior bloqueio representável.
+const maxLockout = time.Duration(math.MaxInt64)
+
+// LockoutPolicy bloqueia a chave a partir de MaxAttempts falhas. O bloqueio
+// começa em BaseLockout e dobra a cada nova falha, até MaxLockout.
+type LockoutPolicy struct {
+	MaxAttempts int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+// LockedUntil devolve o fim do bloqueio, contado da última falha, ou o tempo
+// zero quando a chave não está bloqueada.
+func (p LockoutPolicy) LockedUntil(c Counter) time.Time {
+	if p.MaxAttempts <= 0 || c.Count < p.MaxAttempts {
+		return time.Time{}
+	}
+	lockout := p.BaseLockout
+	for i := p.MaxAttempts; i < c.Count && (p.MaxLockout <= 0 || lockout < p.MaxLockout); i++ {
+		// Sem MaxLockout, o bloqueio para de dobrar antes de estourar o
+		// time.Duration.
+		if lockout > maxLockout/2 {
+			lockout = maxLockout
+			break
+		}
+		lockout *= 2
+	}
+	if p.MaxLockout > 0 && lockout > p.MaxLockout {
+		lockout = p.MaxLockout
+	}
+	return c.LastHit.Add(lockout)
+}
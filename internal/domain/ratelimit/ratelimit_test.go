@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockoutPolicy_LockedUntil(t *testing.T) {
+	policy := LockoutPolicy{MaxAttempts: 3, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+	last := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, policy.LockedUntil(Counter{Count: 2, LastHit: last}).IsZero())
+	assert.Equal(t, last.Add(time.Minute), policy.LockedUntil(Counter{Count: 3, LastHit: last}))
+	assert.Equal(t, last.Add(2*time.Minute), policy.LockedUntil(Counter{Count: 4, LastHit: last}))
+	assert.Equal(t, last.Add(4*time.Minute), policy.LockedUntil(Counter{Count: 5, LastHit: last}))
+	assert.Equal(t, last.Add(5*time.Minute), policy.LockedUntil(Counter{Count: 6, LastHit: last}))
+	assert.Equal(t, last.Add(5*time.Minute), policy.LockedUntil(Counter{Count: 60, LastHit: last}))
+
+	assert.True(t, LockoutPolicy{}.LockedUntil(Counter{Count: 100, LastHit: last}).IsZero(), "sem limite")
+
+	unbounded := LockoutPolicy{MaxAttempts: 1, BaseLockout: time.Minute}
+	assert.True(t, unbounded.LockedUntil(Counter{Count: 200, LastHit: last}).After(last), "sem MaxLockout o bloqueio não estoura")
+}
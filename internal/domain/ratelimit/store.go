@@ -0,0 +1,19 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store guarda contadores com expiração. A implementação em memória atende uma
+// única instância da API; com várias instâncias, troque por um store
+// compartilhado.
+type Store interface {
+	// Increment soma um ao contador da chave. A janela começa no primeiro
+	// incremento e o contador volta a zero quando ela termina.
+	Increment(ctx context.Context, key string, window time.Duration, now time.Time) (Counter, error)
+	Get(ctx context.Context, key string, now time.Time) (Counter, error)
+	// Decrement desfaz um incremento, sem deixar o contador negativo.
+	Decrement(ctx context.Context, key string, now time.Time) error
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,82 @@
+package receipt
+
+import (
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrKindInvalid          = errors.New("tipo de comprovante inválido")
+	ErrFormatInvalid        = errors.New("formato de comprovante inválido")
+	ErrPrinterNotConfigured = errors.New("nenhuma impressora configurada")
+	ErrPrintQueueFull       = errors.New("fila de impressão cheia")
+)
+
+// Kind identifica o layout impresso: o cupom do cliente ou a comanda da cozinha.
+type Kind string
+
+const (
+	KindCustomer Kind = "customer"
+	KindKitchen  Kind = "kitchen"
+)
+
+func (k Kind) IsValid() bool {
+	return k == KindCustomer || k == KindKitchen
+}
+
+type Format string
+
+const (
+	FormatESCPOS Format = "escpos"
+	FormatText   Format = "text"
+	FormatPDF    Format = "pdf"
+)
+
+func (f Format) IsValid() bool {
+	switch f {
+	case FormatESCPOS, FormatText, FormatPDF:
+		return true
+	}
+	return false
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Options seleciona o layout e o formato; StationID restringe a comanda da
+// cozinha aos itens de uma estação.
+type Options struct {
+	Kind      Kind
+	Format    Format
+	StationID *uuid.UUID
+}
+
+func (o Options) Validate() error {
+	if !o.Kind.IsValid() {
+		return ErrKindInvalid
+	}
+	if !o.Format.IsValid() {
+		return ErrFormatInvalid
+	}
+	return nil
+}
+
+type Renderer interface {
+	Render(s *sale.Sale, opts Options) ([]byte, error)
+	RenderDailyClosing(c *sale.DailyClosing, format Format) ([]byte, error)
+}
+
+// Printer envia um documento já renderizado para a impressora.
+type Printer interface {
+	Print(ctx context.Context, data []byte) error
+}
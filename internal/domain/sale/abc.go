@@ -0,0 +1,241 @@
+package sale
+
+import (
+	"andressa-lanches/internal/domain/pricing"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrABCMetricInvalid = errors.New("critério da curva ABC inválido: use quantity, revenue ou margin")
+
+// ABCMetric é o critério usado para ordenar e classificar os itens.
+type ABCMetric string
+
+const (
+	ABCByQuantity ABCMetric = "quantity"
+	ABCByRevenue  ABCMetric = "revenue"
+	ABCByMargin   ABCMetric = "margin"
+)
+
+func (m ABCMetric) IsValid() bool {
+	switch m {
+	case ABCByQuantity, ABCByRevenue, ABCByMargin:
+		return true
+	}
+	return false
+}
+
+type ABCClass string
+
+const (
+	ABCClassA ABCClass = "A"
+	ABCClassB ABCClass = "B"
+	ABCClassC ABCClass = "C"
+)
+
+// Limites da participação acumulada, em percentual, de cada classe: os itens
+// que somam os primeiros 80% são A, os que levam até 95% são B e o resto C.
+const (
+	abcLimitA = 80
+	abcLimitB = 95
+)
+
+// ABCPrevious é a posição do item no período anterior equivalente.
+type ABCPrevious struct {
+	Rank        int      `json:"rank"`
+	Class       ABCClass `json:"class"`
+	Quantity    int      `json:"quantity"`
+	Revenue     float64  `json:"revenue"`
+	GrossMargin float64  `json:"gross_margin"`
+}
+
+// ABCItem é um produto ou acréscimo na curva. A receita do produto é só a do
+// produto; a dos acréscimos fica nos próprios acréscimos. Share e
+// CumulativeShare são percentuais do critério escolhido; itens com valor
+// negativo ou zero entram no fim da curva, na classe C. Previous fica nulo
+// quando o item não vendeu no período anterior.
+type ABCItem struct {
+	ItemType         pricing.ItemType `json:"item_type"`
+	ItemID           uuid.UUID        `json:"item_id"`
+	Name             string           `json:"name"`
+	Rank             int              `json:"rank"`
+	Class            ABCClass         `json:"class"`
+	Quantity         int              `json:"quantity"`
+	Revenue          float64          `json:"revenue"`
+	Cost             float64          `json:"cost"`
+	GrossMargin      float64          `json:"gross_margin"`
+	UncostedQuantity int              `json:"uncosted_quantity"`
+	Share            float64          `json:"share"`
+	CumulativeShare  float64          `json:"cumulative_share"`
+	Previous         *ABCPrevious     `json:"previous,omitempty"`
+}
+
+type ABCClassSummary struct {
+	Class ABCClass `json:"class"`
+	Items int      `json:"items"`
+	Share float64  `json:"share"`
+}
+
+// ABCReport é a curva ABC dos produtos e acréscimos num período, comparada
+// com o período anterior de mesma duração. As datas são inclusivas.
+type ABCReport struct {
+	RankBy        ABCMetric         `json:"rank_by"`
+	Start         time.Time         `json:"start"`
+	End           time.Time         `json:"end"`
+	PreviousStart time.Time         `json:"previous_start"`
+	PreviousEnd   time.Time         `json:"previous_end"`
+	Classes       []ABCClassSummary `json:"classes"`
+	Items         []ABCItem         `json:"items"`
+}
+
+// ItemSales é o total vendido de um produto ou acréscimo num período, sem as
+// vendas canceladas. Revenue e Cost usam o preço e o custo gravados na
+// venda; a quantidade dos itens sem custo fica em UncostedQuantity.
+type ItemSales struct {
+	ItemType         pricing.ItemType
+	ItemID           uuid.UUID
+	Name             string
+	Quantity         int
+	Revenue          float64
+	Cost             float64
+	UncostedQuantity int
+}
+
+type abcKey struct {
+	itemType pricing.ItemType
+	id       uuid.UUID
+}
+
+// NewABCReport monta a curva dos totais do período (current) e a compara com
+// os do período anterior (previous). Os itens de catalog entram mesmo sem
+// vendas, para que o que não vende também apareça; o nome deles prevalece
+// sobre o registrado nas vendas.
+func NewABCReport(metric ABCMetric, current, previous []ItemSales, catalog []ABCItem) *ABCReport {
+	items := collectABC(current, catalog)
+	rankABC(items, metric)
+
+	before := collectABC(previous, nil)
+	rankABC(before, metric)
+	positions := make(map[abcKey]ABCPrevious, len(before))
+	for _, item := range before {
+		if item.Quantity == 0 {
+			continue
+		}
+		positions[abcKey{item.ItemType, item.ItemID}] = ABCPrevious{
+			Rank:        item.Rank,
+			Class:       item.Class,
+			Quantity:    item.Quantity,
+			Revenue:     item.Revenue,
+			GrossMargin: item.GrossMargin,
+		}
+	}
+
+	report := &ABCReport{RankBy: metric, Items: items}
+	byClass := make(map[ABCClass]*ABCClassSummary)
+	for _, class := range []ABCClass{ABCClassA, ABCClassB, ABCClassC} {
+		report.Classes = append(report.Classes, ABCClassSummary{Class: class})
+	}
+	for i := range report.Classes {
+		byClass[report.Classes[i].Class] = &report.Classes[i]
+	}
+	for i := range report.Items {
+		item := &report.Items[i]
+		if position, ok := positions[abcKey{item.ItemType, item.ItemID}]; ok {
+			item.Previous = &position
+		}
+		summary := byClass[item.Class]
+		summary.Items++
+		summary.Share += item.Share
+	}
+	for i := range report.Classes {
+		report.Classes[i].Share = roundMoney(report.Classes[i].Share)
+	}
+	return report
+}
+
+func collectABC(totals []ItemSales, catalog []ABCItem) []ABCItem {
+	items := make([]ABCItem, 0, len(catalog))
+	index := make(map[abcKey]int)
+	find := func(itemType pricing.ItemType, id uuid.UUID, name string) *ABCItem {
+		key := abcKey{itemType, id}
+		i, ok := index[key]
+		if !ok {
+			i = len(items)
+			index[key] = i
+			items = append(items, ABCItem{ItemType: itemType, ItemID: id, Name: name})
+		}
+		return &items[i]
+	}
+	for _, entry := range catalog {
+		find(entry.ItemType, entry.ItemID, entry.Name)
+	}
+
+	for _, total := range totals {
+		item := find(total.ItemType, total.ItemID, total.Name)
+		item.Quantity += total.Quantity
+		item.Revenue += total.Revenue
+		item.Cost += total.Cost
+		item.UncostedQuantity += total.UncostedQuantity
+	}
+	return items
+}
+
+func (i *ABCItem) value(metric ABCMetric) float64 {
+	switch metric {
+	case ABCByQuantity:
+		return float64(i.Quantity)
+	case ABCByMargin:
+		return i.GrossMargin
+	}
+	return i.Revenue
+}
+
+// rankABC arredonda os valores, ordena os itens pelo critério e os
+// classifica pela participação acumulada antes de cada item, de modo que o
+// item que cruza um limite ainda fica na classe de cima.
+func rankABC(items []ABCItem, metric ABCMetric) {
+	var total float64
+	for i := range items {
+		item := &items[i]
+		item.GrossMargin = roundMoney(item.Revenue - item.Cost)
+		item.Revenue = roundMoney(item.Revenue)
+		item.Cost = roundMoney(item.Cost)
+		if value := item.value(metric); value > 0 {
+			total += value
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		vi, vj := items[i].value(metric), items[j].value(metric)
+		if vi != vj {
+			return vi > vj
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	var cumulative float64
+	for i := range items {
+		item := &items[i]
+		item.Rank = i + 1
+		item.Class = ABCClassC
+		value := item.value(metric)
+		if value <= 0 || total == 0 {
+			item.CumulativeShare = roundMoney(cumulative)
+			continue
+		}
+
+		switch {
+		case cumulative < abcLimitA:
+			item.Class = ABCClassA
+		case cumulative < abcLimitB:
+			item.Class = ABCClassB
+		}
+		share := value / total * 100
+		cumulative += share
+		item.Share = roundMoney(share)
+		item.CumulativeShare = roundMoney(cumulative)
+	}
+}
@@ -0,0 +1,70 @@
+package sale
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrCutoffHourInvalid = errors.New("a hora de virada do dia de operação deve estar entre 0 e 23")
+
+// Calendar situa as vendas no fuso horário da loja, que pode ser diferente
+// do fuso do servidor, e nos dias de operação. Um dia de operação começa na
+// hora de virada (CutoffHour) e vai até a mesma hora do dia seguinte, de modo
+// que as vendas da madrugada contam no dia anterior. O valor zero usa o fuso
+// do servidor e vira o dia à meia-noite.
+type Calendar struct {
+	Location   *time.Location
+	CutoffHour int
+}
+
+func (c Calendar) Validate() error {
+	if c.CutoffHour < 0 || c.CutoffHour > 23 {
+		return ErrCutoffHourInvalid
+	}
+	return nil
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+// Timezone é o nome IANA do fuso da loja, usado também nas consultas que
+// agrupam as vendas por hora.
+func (c Calendar) Timezone() string {
+	return c.location().String()
+}
+
+// In devolve o instante t no fuso da loja.
+func (c Calendar) In(t time.Time) time.Time {
+	return t.In(c.location())
+}
+
+// Date devolve date como uma data no fuso da loja; só o ano, o mês e o dia
+// de date são considerados.
+func (c Calendar) Date(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.location())
+}
+
+// BusinessDateOf devolve o dia de operação ao qual o instante t pertence; a
+// numeração dos pedidos reinicia a cada dia de operação.
+func (c Calendar) BusinessDateOf(t time.Time) time.Time {
+	local := c.In(t)
+	if local.Hour() < c.CutoffHour {
+		local = local.AddDate(0, 0, -1)
+	}
+	return c.Date(local)
+}
+
+// DayStart devolve o instante em que começa o dia de operação date.
+func (c Calendar) DayStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), c.CutoffHour, 0, 0, 0, c.location())
+}
+
+// Period devolve o intervalo semiaberto [início, fim) que cobre os dias de
+// operação de start até o dia anterior a end.
+func (c Calendar) Period(start, end time.Time) (time.Time, time.Time) {
+	return c.DayStart(start), c.DayStart(end)
+}
@@ -0,0 +1,154 @@
+package sale
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyClosing resume as vendas de um dia de operação para o fechamento do
+// caixa. Vendas canceladas são apenas contadas e não entram nos totais.
+type DailyClosing struct {
+	BusinessDate      time.Time             `json:"business_date"`
+	SalesCount        int                   `json:"sales_count"`
+	CanceledCount     int                   `json:"canceled_count"`
+	ItemsAmount       float64               `json:"items_amount"`
+	Discount          float64               `json:"discount"`
+	AdditionalCharges float64               `json:"additional_charges"`
+	ServiceCharge     float64               `json:"service_charge"`
+	TotalAmount       float64               `json:"total_amount"`
+	AverageTicket     float64               `json:"average_ticket"`
+	OrderTypes        []OrderTypeSummary    `json:"order_types"`
+	Payments          []PaymentSummary      `json:"payments"`
+	Unpaid            float64               `json:"unpaid"`
+	Products          []ProductSalesSummary `json:"products"`
+}
+
+type OrderTypeSummary struct {
+	OrderType   OrderType `json:"order_type"`
+	SalesCount  int       `json:"sales_count"`
+	TotalAmount float64   `json:"total_amount"`
+}
+
+// PaymentSummary soma o valor recebido em uma forma de pagamento, já
+// descontado o troco devolvido em dinheiro.
+type PaymentSummary struct {
+	Method PaymentMethod `json:"method"`
+	Amount float64       `json:"amount"`
+}
+
+type ProductSalesSummary struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int       `json:"quantity"`
+	TotalAmount float64   `json:"total_amount"`
+}
+
+// NewDailyClosing consolida as vendas informadas no fechamento do dia.
+func NewDailyClosing(businessDate time.Time, sales []*Sale) *DailyClosing {
+	closing := &DailyClosing{
+		BusinessDate: businessDate,
+		OrderTypes:   []OrderTypeSummary{},
+		Payments:     []PaymentSummary{},
+		Products:     []ProductSalesSummary{},
+	}
+
+	orderTypes := make(map[OrderType]int)
+	payments := make(map[PaymentMethod]int)
+	products := make(map[uuid.UUID]int)
+
+	for _, s := range sales {
+		if s.Status == StatusCanceled {
+			closing.CanceledCount++
+			continue
+		}
+
+		closing.SalesCount++
+		closing.Discount += s.Discount
+		closing.AdditionalCharges += s.AdditionalCharges
+		closing.ServiceCharge += s.ServiceCharge
+		closing.TotalAmount += s.TotalAmount
+
+		i, ok := orderTypes[s.OrderType]
+		if !ok {
+			i = len(closing.OrderTypes)
+			orderTypes[s.OrderType] = i
+			closing.OrderTypes = append(closing.OrderTypes, OrderTypeSummary{OrderType: s.OrderType})
+		}
+		closing.OrderTypes[i].SalesCount++
+		closing.OrderTypes[i].TotalAmount += s.TotalAmount
+
+		for _, item := range s.Items {
+			closing.ItemsAmount += item.TotalPrice
+
+			i, ok := products[item.ProductID]
+			if !ok {
+				i = len(closing.Products)
+				products[item.ProductID] = i
+				closing.Products = append(closing.Products, ProductSalesSummary{ProductID: item.ProductID, ProductName: item.ProductName})
+			}
+			closing.Products[i].Quantity += item.Quantity
+			closing.Products[i].TotalAmount += item.TotalPrice
+		}
+
+		change := s.Change()
+		for _, p := range s.Payments {
+			amount := p.Amount
+			if p.Method == PaymentMethodCash && change > 0 {
+				returned := min(change, amount)
+				amount -= returned
+				change -= returned
+			}
+
+			i, ok := payments[p.Method]
+			if !ok {
+				i = len(closing.Payments)
+				payments[p.Method] = i
+				closing.Payments = append(closing.Payments, PaymentSummary{Method: p.Method})
+			}
+			closing.Payments[i].Amount += amount
+		}
+		if unpaid := s.TotalAmount - s.AmountPaid(); unpaid > 0 {
+			closing.Unpaid += unpaid
+		}
+	}
+
+	if closing.SalesCount > 0 {
+		closing.AverageTicket = closing.TotalAmount / float64(closing.SalesCount)
+	}
+	closing.round()
+
+	sort.SliceStable(closing.Products, func(i, j int) bool {
+		a, b := closing.Products[i], closing.Products[j]
+		if a.Quantity != b.Quantity {
+			return a.Quantity > b.Quantity
+		}
+		return a.TotalAmount > b.TotalAmount
+	})
+	return closing
+}
+
+// round elimina os resíduos de ponto flutuante das somas.
+func (c *DailyClosing) round() {
+	for _, value := range []*float64{
+		&c.ItemsAmount, &c.Discount, &c.AdditionalCharges, &c.ServiceCharge,
+		&c.TotalAmount, &c.AverageTicket, &c.Unpaid,
+	} {
+		*value = roundMoney(*value)
+	}
+	for i := range c.OrderTypes {
+		c.OrderTypes[i].TotalAmount = roundMoney(c.OrderTypes[i].TotalAmount)
+	}
+	for i := range c.Payments {
+		c.Payments[i].Amount = roundMoney(c.Payments[i].Amount)
+	}
+	for i := range c.Products {
+		c.Products[i].TotalAmount = roundMoney(c.Products[i].TotalAmount)
+	}
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,78 @@
+package sale
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrHeatmapMetricInvalid = errors.New("métrica do mapa de calor inválida: use orders ou revenue")
+
+type HeatmapMetric string
+
+const (
+	HeatmapOrders  HeatmapMetric = "orders"
+	HeatmapRevenue HeatmapMetric = "revenue"
+)
+
+func (m HeatmapMetric) IsValid() bool {
+	return m == HeatmapOrders || m == HeatmapRevenue
+}
+
+var weekdayNames = [...]string{"domingo", "segunda", "terça", "quarta", "quinta", "sexta", "sábado"}
+
+// WeekdayName devolve o nome do dia da semana em português.
+func WeekdayName(d time.Weekday) string {
+	return weekdayNames[d]
+}
+
+type HeatmapCell struct {
+	Weekday time.Weekday `json:"weekday"`
+	Hour    int          `json:"hour"`
+	Orders  int          `json:"orders"`
+	Revenue float64      `json:"revenue"`
+}
+
+// Heatmap soma pedidos e faturamento por dia da semana e hora, no fuso da
+// loja. O dia da semana é o do dia de operação, então uma venda de sábado à
+// 1h, antes da virada, conta na sexta às 1h. Cells tem as 168 combinações, de
+// domingo 0h a sábado 23h, inclusive as sem vendas; Weekday segue
+// time.Weekday (0 é domingo). As datas são inclusivas e as vendas canceladas
+// ficam de fora.
+type Heatmap struct {
+	Timezone string        `json:"timezone"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Orders   int           `json:"orders"`
+	Revenue  float64       `json:"revenue"`
+	Cells    []HeatmapCell `json:"cells"`
+}
+
+// NewHeatmap distribui os totais já somados pelo repositório nas 168 células.
+func NewHeatmap(totals []HeatmapCell, calendar Calendar) *Heatmap {
+	heatmap := &Heatmap{
+		Timezone: calendar.Timezone(),
+		Cells:    make([]HeatmapCell, 7*24),
+	}
+	for i := range heatmap.Cells {
+		heatmap.Cells[i].Weekday = time.Weekday(i / 24)
+		heatmap.Cells[i].Hour = i % 24
+	}
+
+	for _, total := range totals {
+		cell := heatmap.Cell(total.Weekday, total.Hour)
+		cell.Orders += total.Orders
+		cell.Revenue += total.Revenue
+		heatmap.Orders += total.Orders
+		heatmap.Revenue += total.Revenue
+	}
+
+	for i := range heatmap.Cells {
+		heatmap.Cells[i].Revenue = roundMoney(heatmap.Cells[i].Revenue)
+	}
+	heatmap.Revenue = roundMoney(heatmap.Revenue)
+	return heatmap
+}
+
+func (h *Heatmap) Cell(weekday time.Weekday, hour int) *HeatmapCell {
+	return &h.Cells[int(weekday)*24+hour]
+}
@@ -0,0 +1,140 @@
+package sale
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Margin é a margem bruta de um conjunto de itens: receita menos custo.
+// Itens vendidos sem custo cadastrado entram só na receita e são contados em
+// UncostedQuantity, para que a margem inflada por eles fique visível.
+type Margin struct {
+	Revenue          float64 `json:"revenue"`
+	Cost             float64 `json:"cost"`
+	GrossMargin      float64 `json:"gross_margin"`
+	MarginPercentage float64 `json:"margin_percentage"`
+	UncostedQuantity int     `json:"uncosted_quantity"`
+}
+
+func (m *Margin) add(item SaleItem) {
+	m.Revenue += item.TotalPrice
+	if item.TotalCost != nil {
+		m.Cost += *item.TotalCost
+	} else {
+		m.UncostedQuantity += item.Quantity
+	}
+}
+
+// finish desconta o desconto da receita, calcula a margem e arredonda os
+// valores.
+func (m *Margin) finish(discount float64) {
+	revenue := m.Revenue - discount
+	m.GrossMargin = roundMoney(revenue - m.Cost)
+	m.MarginPercentage = 0
+	if revenue > 0 {
+		m.MarginPercentage = roundMoney((revenue - m.Cost) / revenue * 100)
+	}
+	m.Revenue = roundMoney(m.Revenue)
+	m.Cost = roundMoney(m.Cost)
+}
+
+type SaleMargin struct {
+	SaleID      uuid.UUID `json:"sale_id"`
+	OrderNumber int       `json:"order_number"`
+	Date        time.Time `json:"date"`
+	Discount    float64   `json:"discount"`
+	Margin
+}
+
+type ProductMargin struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int       `json:"quantity"`
+	Margin
+}
+
+type CategoryMargin struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name,omitempty"`
+	Quantity     int       `json:"quantity"`
+	Margin
+}
+
+// MarginReport mostra a margem bruta das vendas de um período pelo custo
+// registrado em cada item no momento da venda. A receita é a dos itens; os
+// descontos, que não têm como ser divididos entre os itens, entram só na
+// margem das vendas e no total. Vendas canceladas ficam de fora.
+type MarginReport struct {
+	Margin
+	Discount   float64          `json:"discount"`
+	Sales      []SaleMargin     `json:"sales"`
+	Products   []ProductMargin  `json:"products"`
+	Categories []CategoryMargin `json:"categories"`
+}
+
+// NewMarginReport consolida as vendas informadas; categoryNames dá o nome
+// das categorias dos itens.
+func NewMarginReport(sales []*Sale, categoryNames map[uuid.UUID]string) *MarginReport {
+	report := &MarginReport{
+		Sales:      []SaleMargin{},
+		Products:   []ProductMargin{},
+		Categories: []CategoryMargin{},
+	}
+	products := make(map[uuid.UUID]int)
+	categories := make(map[uuid.UUID]int)
+
+	for _, s := range sales {
+		if s.Status == StatusCanceled {
+			continue
+		}
+
+		saleMargin := SaleMargin{SaleID: s.ID, OrderNumber: s.OrderNumber, Date: s.Date, Discount: roundMoney(s.Discount)}
+		for _, item := range s.Items {
+			saleMargin.add(item)
+			report.add(item)
+
+			i, ok := products[item.ProductID]
+			if !ok {
+				i = len(report.Products)
+				products[item.ProductID] = i
+				report.Products = append(report.Products, ProductMargin{ProductID: item.ProductID, ProductName: item.ProductName})
+			}
+			report.Products[i].Quantity += item.Quantity
+			report.Products[i].add(item)
+
+			i, ok = categories[item.CategoryID]
+			if !ok {
+				i = len(report.Categories)
+				categories[item.CategoryID] = i
+				report.Categories = append(report.Categories, CategoryMargin{CategoryID: item.CategoryID, CategoryName: categoryNames[item.CategoryID]})
+			}
+			report.Categories[i].Quantity += item.Quantity
+			report.Categories[i].add(item)
+		}
+		saleMargin.finish(s.Discount)
+		report.Discount += s.Discount
+		report.Sales = append(report.Sales, saleMargin)
+	}
+
+	report.finish(report.Discount)
+	report.Discount = roundMoney(report.Discount)
+	for i := range report.Products {
+		report.Products[i].finish(0)
+	}
+	for i := range report.Categories {
+		report.Categories[i].finish(0)
+	}
+
+	sort.SliceStable(report.Sales, func(i, j int) bool {
+		return report.Sales[i].Date.Before(report.Sales[j].Date)
+	})
+	sort.SliceStable(report.Products, func(i, j int) bool {
+		return report.Products[i].GrossMargin > report.Products[j].GrossMargin
+	})
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return report.Categories[i].GrossMargin > report.Categories[j].GrossMargin
+	})
+	return report
+}
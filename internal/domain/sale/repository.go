@@ -1,14 +1,40 @@
 package sale
 
 import (
+	"andressa-lanches/internal/domain/audit"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Repository grava as alterações da venda junto com o registro de
+// auditoria, numa única transação; entry pode ser nulo quando não há o que
+// registrar.
 type Repository interface {
-	Create(ctx context.Context, sale *Sale) error
+	// Create persiste a venda e atribui o próximo número de pedido do seu dia
+	// de operação de forma segura para criações concorrentes. As alterações
+	// de entry são recalculadas depois da numeração, para incluir o número
+	// do pedido e os itens.
+	Create(ctx context.Context, sale *Sale, entry *audit.Entry) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Sale, error)
 	List(ctx context.Context) ([]*Sale, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	ListByPeriod(ctx context.Context, start, end time.Time) ([]*Sale, error)
+	// SumItemsByPeriod soma as vendas do período por produto e por
+	// acréscimo, sem carregar as vendas.
+	SumItemsByPeriod(ctx context.Context, start, end time.Time) ([]ItemSales, error)
+	// SumByWeekdayHour soma pedidos e faturamento do período pelo dia da
+	// semana do dia de operação e pela hora no fuso timezone. Só devolve as
+	// combinações com vendas.
+	SumByWeekdayHour(ctx context.Context, start, end time.Time, timezone string) ([]HeatmapCell, error)
+	ListByStatus(ctx context.Context, statuses ...Status) ([]*Sale, error)
+	ListByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*Sale, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status Status, entry *audit.Entry) error
+	UpdateItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string, entry *audit.Entry) error
+	UpdateItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status ItemStatus, entry *audit.Entry) error
+	// UpdateStationItemsStatus altera de uma vez o status de todos os itens
+	// da venda preparados na estação, retornando ErrStationTicketNotFound
+	// quando a venda não tem itens da estação.
+	UpdateStationItemsStatus(ctx context.Context, saleID, stationID uuid.UUID, status ItemStatus, entry *audit.Entry) error
+	Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error
 }
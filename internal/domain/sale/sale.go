@@ -2,26 +2,274 @@ package sale
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrOrderTypeInvalid           = errors.New("tipo de pedido inválido")
+	ErrServiceChargeWaiverReason  = errors.New("o motivo da dispensa da taxa de serviço é obrigatório")
+	ErrServiceChargePercentageNeg = errors.New("o percentual da taxa de serviço não pode ser negativo")
+	ErrSaleStatusInvalid          = errors.New("status da venda inválido")
+	ErrSaleNotFound               = errors.New("venda não encontrada")
+	ErrSaleItemNotFound           = errors.New("item da venda não encontrado")
+	ErrItemStatusInvalid          = errors.New("status do item inválido")
+	ErrStationTicketNotFound      = errors.New("comanda da estação não encontrada")
+	ErrOrderNumberInvalid         = errors.New("número do pedido inválido")
+	ErrPaymentMethodInvalid       = errors.New("forma de pagamento inválida")
+	ErrPaymentAmountPositive      = errors.New("o valor do pagamento deve ser positivo")
+	ErrSaleHasDocuments           = errors.New("a venda possui cobranças ou documentos fiscais e não pode ser excluída")
+)
+
+type OrderType string
+
+const (
+	OrderTypeDineIn   OrderType = "dine_in"
+	OrderTypeTakeaway OrderType = "takeaway"
+	OrderTypeDelivery OrderType = "delivery"
+)
+
+func (t OrderType) IsValid() bool {
+	switch t {
+	case OrderTypeDineIn, OrderTypeTakeaway, OrderTypeDelivery:
+		return true
+	}
+	return false
+}
+
+type Status string
+
+const (
+	StatusReceived  Status = "received"
+	StatusPreparing Status = "preparing"
+	StatusReady     Status = "ready"
+	StatusDelivered Status = "delivered"
+	StatusCanceled  Status = "canceled"
+)
+
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusReceived, StatusPreparing, StatusReady, StatusDelivered, StatusCanceled:
+		return true
+	}
+	return false
+}
+
+// ItemStatus acompanha o preparo de um item na sua estação.
+type ItemStatus string
+
+const (
+	ItemStatusPending ItemStatus = "pending"
+	ItemStatusReady   ItemStatus = "ready"
+	ItemStatusBumped  ItemStatus = "bumped"
+)
+
+func (s ItemStatus) IsValid() bool {
+	switch s {
+	case ItemStatusPending, ItemStatusReady, ItemStatusBumped:
+		return true
+	}
+	return false
+}
+
+// IsDone indica que o item já saiu da estação (pronto ou despachado).
+func (s ItemStatus) IsDone() bool {
+	return s == ItemStatusReady || s == ItemStatusBumped
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash       PaymentMethod = "cash"
+	PaymentMethodPix        PaymentMethod = "pix"
+	PaymentMethodCreditCard PaymentMethod = "credit_card"
+	PaymentMethodDebitCard  PaymentMethod = "debit_card"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case PaymentMethodCash, PaymentMethodPix, PaymentMethodCreditCard, PaymentMethodDebitCard:
+		return true
+	}
+	return false
+}
+
+// Payment registra quanto foi pago em cada forma de pagamento; uma venda pode
+// ser dividida entre várias formas. Reference identifica a cobrança do gateway
+// que originou o pagamento, quando houver.
+type Payment struct {
+	Method    PaymentMethod `json:"method"`
+	Amount    float64       `json:"amount"`
+	Reference string        `json:"reference,omitempty"`
+}
+
+func (p Payment) Validate() error {
+	if !p.Method.IsValid() {
+		return ErrPaymentMethodInvalid
+	}
+	if p.Amount <= 0 {
+		return ErrPaymentAmountPositive
+	}
+	return nil
+}
+
 type Sale struct {
-	ID                uuid.UUID  `json:"id"`
-	Date              time.Time  `json:"date"`
-	TotalAmount       float64    `json:"total_amount"`
-	Discount          float64    `json:"discount,omitempty"`
-	AdditionalCharges float64    `json:"additional_charges,omitempty"`
-	Items             []SaleItem `json:"items"`
+	ID           uuid.UUID `json:"id"`
+	OrderNumber  int       `json:"order_number,omitempty"`
+	Date         time.Time `json:"date"`
+	BusinessDate time.Time `json:"business_date"`
+	OrderType    OrderType `json:"order_type,omitempty"`
+	Status       Status    `json:"status,omitempty"`
+	Employee     string    `json:"employee,omitempty"`
+	// OperatorID e TerminalID registram quem lançou a venda e em qual
+	// terminal; são preenchidos a partir da sessão, nunca pelo cliente.
+	OperatorID                *uuid.UUID `json:"operator_id,omitempty"`
+	TerminalID                *uuid.UUID `json:"terminal_id,omitempty"`
+	TotalAmount               float64    `json:"total_amount"`
+	Discount                  float64    `json:"discount,omitempty"`
+	AdditionalCharges         float64    `json:"additional_charges,omitempty"`
+	ServiceCharge             float64    `json:"service_charge,omitempty"`
+	ServiceChargeWaived       bool       `json:"service_charge_waived,omitempty"`
+	ServiceChargeWaiverReason string     `json:"service_charge_waiver_reason,omitempty"`
+	Items                     []SaleItem `json:"items"`
+	Payments                  []Payment  `json:"payments,omitempty"`
 }
 
+// SaleItem guarda os preços e os custos do momento da venda: UnitPrice e
+// UnitCost são os do produto; TotalPrice e TotalCost incluem os acréscimos.
+// Os custos ficam nulos quando algum deles não estava cadastrado.
 type SaleItem struct {
-	SaleID     uuid.UUID           `json:"sale_id"`
-	ItemID     int                 `json:"item_id"`
-	ProductID  uuid.UUID           `json:"product_id"`
-	Quantity   int                 `json:"quantity"`
-	UnitPrice  float64             `json:"unit_price"`
-	TotalPrice float64             `json:"total_price"`
-	Additions  []addition.Addition `json:"additions,omitempty"`
+	SaleID      uuid.UUID           `json:"sale_id"`
+	ItemID      int                 `json:"item_id"`
+	ProductID   uuid.UUID           `json:"product_id"`
+	ProductName string              `json:"product_name,omitempty"`
+	CategoryID  uuid.UUID           `json:"category_id,omitempty"`
+	StationID   *uuid.UUID          `json:"station_id,omitempty"`
+	Status      ItemStatus          `json:"status,omitempty"`
+	Quantity    int                 `json:"quantity"`
+	UnitPrice   float64             `json:"unit_price"`
+	TotalPrice  float64             `json:"total_price"`
+	UnitCost    *float64            `json:"unit_cost,omitempty"`
+	TotalCost   *float64            `json:"total_cost,omitempty"`
+	Notes       string              `json:"notes,omitempty"`
+	Additions   []addition.Addition `json:"additions,omitempty"`
+}
+
+// AmountPaid soma os pagamentos registrados na venda.
+func (s *Sale) AmountPaid() float64 {
+	var paid float64
+	for _, p := range s.Payments {
+		paid += p.Amount
+	}
+	return paid
+}
+
+// Change devolve o troco, isto é, quanto foi pago além do total da venda.
+func (s *Sale) Change() float64 {
+	if change := s.AmountPaid() - s.TotalAmount; change > 0.005 {
+		return roundMoney(change)
+	}
+	return 0
+}
+
+// StationTicket agrupa os itens de uma venda preparados na mesma estação.
+// Itens sem estação configurada formam uma comanda com StationID nulo.
+type StationTicket struct {
+	SaleID    uuid.UUID  `json:"sale_id"`
+	StationID *uuid.UUID `json:"station_id,omitempty"`
+	Status    ItemStatus `json:"status"`
+	Items     []SaleItem `json:"items"`
+}
+
+// Tickets divide os itens da venda em comandas por estação, na ordem em que
+// as estações aparecem nos itens.
+func (s *Sale) Tickets() []StationTicket {
+	var tickets []StationTicket
+	index := make(map[uuid.UUID]int)
+
+	for _, item := range s.Items {
+		key := uuid.Nil
+		if item.StationID != nil {
+			key = *item.StationID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(tickets)
+			index[key] = i
+			tickets = append(tickets, StationTicket{SaleID: s.ID, StationID: item.StationID})
+		}
+		tickets[i].Items = append(tickets[i].Items, item)
+	}
+
+	for i := range tickets {
+		tickets[i].Status = ticketStatus(tickets[i].Items)
+	}
+	return tickets
+}
+
+// TicketsDone indica que todas as comandas da venda foram concluídas.
+func (s *Sale) TicketsDone() bool {
+	if len(s.Items) == 0 {
+		return false
+	}
+	for _, item := range s.Items {
+		if !item.Status.IsDone() {
+			return false
+		}
+	}
+	return true
+}
+
+func ticketStatus(items []SaleItem) ItemStatus {
+	status := ItemStatusBumped
+	for _, item := range items {
+		switch {
+		case !item.Status.IsDone():
+			return ItemStatusPending
+		case item.Status == ItemStatusReady:
+			status = ItemStatusReady
+		}
+	}
+	return status
+}
+
+// ServiceChargePolicy define o percentual da taxa de serviço (taxa do garçom)
+// e os tipos de pedido sobre os quais ela incide.
+type ServiceChargePolicy struct {
+	Percentage float64
+	OrderTypes []OrderType
+}
+
+func (p ServiceChargePolicy) Validate() error {
+	if p.Percentage < 0 {
+		return ErrServiceChargePercentageNeg
+	}
+	for _, t := range p.OrderTypes {
+		if !t.IsValid() {
+			return ErrOrderTypeInvalid
+		}
+	}
+	return nil
+}
+
+func (p ServiceChargePolicy) AppliesTo(t OrderType) bool {
+	for _, eligible := range p.OrderTypes {
+		if eligible == t {
+			return true
+		}
+	}
+	return false
+}
+
+// TipsSummary agrega as taxas de serviço recebidas por um funcionário em um
+// período. OperatorID fica vazio nas vendas sem operador, agrupadas pelo nome
+// digitado.
+type TipsSummary struct {
+	Employee      string     `json:"employee"`
+	OperatorID    *uuid.UUID `json:"operator_id,omitempty"`
+	SalesCount    int        `json:"sales_count"`
+	ServiceCharge float64    `json:"service_charge"`
 }
@@ -0,0 +1,25 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// MarkUsed marca o token como usado apenas se ainda estiver disponível;
+	// devolve false quando outro pedido já o usou ou a família foi revogada.
+	MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// RevokeFamily revoga os refresh tokens da família e inclui na lista de
+	// revogação os access tokens ainda válidos emitidos com eles.
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+	// RevokeAccessToken inclui um access token avulso na lista de revogação,
+	// como os das sessões de operador, que não têm refresh token.
+	RevokeAccessToken(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error)
+	// DeleteExpired remove tokens e revogações que já expiraram.
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
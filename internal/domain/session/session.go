@@ -0,0 +1,119 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"andressa-lanches/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAccessTokenInvalid  = errors.New("token inválido ou expirado")
+	ErrAccessTokenRevoked  = errors.New("token revogado")
+	ErrRefreshTokenInvalid = errors.New("refresh token inválido ou expirado")
+	ErrRefreshTokenReused  = errors.New("refresh token já utilizado: a sessão foi encerrada por segurança")
+)
+
+// TokenType é o esquema do cabeçalho Authorization.
+const TokenType = "Bearer"
+
+// RefreshToken é guardado apenas como hash. Cada uso gera um novo token na
+// mesma família (a sessão aberta no login) e marca o anterior como usado;
+// reapresentar um token usado indica vazamento e encerra a família inteira.
+type RefreshToken struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	// AccessTokenID é o jti do access token emitido junto, revogado com a
+	// família.
+	AccessTokenID        uuid.UUID
+	AccessTokenExpiresAt time.Time
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	UsedAt               *time.Time
+	RevokedAt            *time.Time
+}
+
+// Claims são os dados do access token já validado. TerminalID só é
+// preenchido nas sessões abertas com PIN em um terminal. Nas requisições
+// autenticadas com chave de API não há usuário nem sessão: APIKeyID e Scopes
+// substituem o papel, e Username recebe o nome da chave.
+type Claims struct {
+	TokenID    uuid.UUID
+	SessionID  uuid.UUID
+	UserID     uuid.UUID
+	Username   string
+	Role       user.Role
+	TerminalID *uuid.UUID
+	APIKeyID   *uuid.UUID
+	Scopes     []user.Permission
+	ExpiresAt  time.Time
+}
+
+// IsAPIKey indica se a requisição foi autenticada com uma chave de API.
+func (c *Claims) IsAPIKey() bool {
+	return c.APIKeyID != nil
+}
+
+// Can confere a permissão pelo papel do usuário ou, nas chaves de API, pelos
+// escopos da chave.
+func (c *Claims) Can(p user.Permission) bool {
+	if c.IsAPIKey() {
+		for _, scope := range c.Scopes {
+			if scope == p {
+				return true
+			}
+		}
+		return false
+	}
+	return c.Role.Can(p)
+}
+
+// TokenPair é devolvido no login e em cada renovação. O login com PIN não
+// tem refresh token: quando a sessão expira, o operador digita o PIN de novo.
+type TokenPair struct {
+	AccessToken  string    `json:"token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+}
+
+// LoginMethod distingue o login com senha do login com PIN nos terminais.
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodPIN      LoginMethod = "pin"
+)
+
+// LoginAttempt descreve uma tentativa de login para o controle de força bruta
+// e a auditoria. UserID só é preenchido quando o usuário existe.
+type LoginAttempt struct {
+	Method     LoginMethod
+	IP         string
+	Username   string
+	UserID     *uuid.UUID
+	TerminalID *uuid.UUID
+}
+
+// NewRefreshToken gera um token opaco de 256 bits.
+func NewRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken é o valor guardado e consultado no lugar do refresh token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
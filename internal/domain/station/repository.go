@@ -0,0 +1,15 @@
+package station
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, station *Station) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Station, error)
+	Update(ctx context.Context, station *Station) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]*Station, error)
+}
@@ -0,0 +1,26 @@
+package station
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStationNameRequired = errors.New("o nome da estação é obrigatório")
+	ErrStationIdInvalid    = errors.New("ID da estação inválido")
+	ErrStationNotFound     = errors.New("estação não encontrada")
+)
+
+// Station é um ponto de preparo da cozinha (chapa, balcão de bebidas, fritadeira...).
+type Station struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (s *Station) Validate() error {
+	if s.Name == "" {
+		return ErrStationNameRequired
+	}
+	return nil
+}
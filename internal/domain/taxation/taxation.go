@@ -0,0 +1,177 @@
+package taxation
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNCMInvalid     = errors.New("NCM inválido: informe os 8 dígitos")
+	ErrCESTInvalid    = errors.New("CEST inválido: informe os 7 dígitos")
+	ErrCFOPInvalid    = errors.New("CFOP inválido: informe um código de 4 dígitos de uma faixa existente")
+	ErrCSTInvalid     = errors.New("CST/CSOSN inválido: informe 2 dígitos (CST) ou 3 dígitos (CSOSN)")
+	ErrOriginInvalid  = errors.New("origem da mercadoria inválida: use um código de 0 a 8")
+	ErrUnitInvalid    = errors.New("unidade de medida inválida: use até 6 caracteres")
+	ErrTaxRateInvalid = errors.New("alíquota inválida: use um percentual entre 0 e 100")
+	ErrICMSRequired   = errors.New("a tributação integral (CST 00) exige a alíquota do ICMS")
+)
+
+// DefaultUnit é a unidade comercial usada quando nem o produto nem a
+// categoria informam uma.
+const DefaultUnit = "UN"
+
+// FiscalData reúne a classificação fiscal de um produto. Na categoria os
+// mesmos campos servem de padrão para os produtos que não os informam;
+// Origin e as alíquotas são ponteiros para distinguir zero de "não
+// informado".
+type FiscalData struct {
+	NCM        string   `json:"ncm,omitempty"`
+	CEST       string   `json:"cest,omitempty"`
+	CFOP       string   `json:"cfop,omitempty"`
+	CST        string   `json:"cst,omitempty"`
+	Origin     *int     `json:"origin,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	ICMSRate   *float64 `json:"icms_rate,omitempty"`
+	PISRate    *float64 `json:"pis_rate,omitempty"`
+	COFINSRate *float64 `json:"cofins_rate,omitempty"`
+}
+
+// Validate confere apenas os campos informados; a ausência é tratada por
+// Missing depois de aplicados os padrões da categoria. A exceção é a
+// tributação integral (CST 00), que não existe sem a alíquota do ICMS.
+func (d FiscalData) Validate() error {
+	if d.NCM != "" && !digits(d.NCM, 8) {
+		return ErrNCMInvalid
+	}
+	if d.CEST != "" && !digits(d.CEST, 7) {
+		return ErrCESTInvalid
+	}
+	if d.CFOP != "" && !ValidCFOP(d.CFOP) {
+		return ErrCFOPInvalid
+	}
+	if d.CST != "" && !digits(d.CST, 2) && !digits(d.CST, 3) {
+		return ErrCSTInvalid
+	}
+	if d.CST == "00" && d.ICMSRate == nil {
+		return ErrICMSRequired
+	}
+	if d.Origin != nil && (*d.Origin < 0 || *d.Origin > 8) {
+		return ErrOriginInvalid
+	}
+	if len(d.Unit) > 6 {
+		return ErrUnitInvalid
+	}
+	for _, rate := range []*float64{d.ICMSRate, d.PISRate, d.COFINSRate} {
+		if rate != nil && (*rate < 0 || *rate > 100) {
+			return ErrTaxRateInvalid
+		}
+	}
+	return nil
+}
+
+// WithDefaults devolve os dados preenchendo os campos ausentes com os
+// padrões informados, normalmente os da categoria do produto.
+func (d FiscalData) WithDefaults(defaults FiscalData) FiscalData {
+	if d.NCM == "" {
+		d.NCM = defaults.NCM
+	}
+	if d.CEST == "" {
+		d.CEST = defaults.CEST
+	}
+	if d.CFOP == "" {
+		d.CFOP = defaults.CFOP
+	}
+	if d.CST == "" {
+		d.CST = defaults.CST
+	}
+	if d.Origin == nil {
+		d.Origin = defaults.Origin
+	}
+	if d.Unit == "" {
+		d.Unit = defaults.Unit
+	}
+	if d.ICMSRate == nil {
+		d.ICMSRate = defaults.ICMSRate
+	}
+	if d.PISRate == nil {
+		d.PISRate = defaults.PISRate
+	}
+	if d.COFINSRate == nil {
+		d.COFINSRate = defaults.COFINSRate
+	}
+	return d
+}
+
+// Missing lista os campos obrigatórios para a emissão da NFC-e que não foram
+// informados. Origem e unidade têm padrão (nacional e UN).
+func (d FiscalData) Missing() []string {
+	var missing []string
+	if d.NCM == "" {
+		missing = append(missing, "ncm")
+	}
+	if d.CFOP == "" {
+		missing = append(missing, "cfop")
+	}
+	if d.CST == "" {
+		missing = append(missing, "cst")
+	}
+	return missing
+}
+
+// OriginOrDefault devolve a origem informada ou 0 (nacional).
+func (d FiscalData) OriginOrDefault() int {
+	if d.Origin == nil {
+		return 0
+	}
+	return *d.Origin
+}
+
+// UnitOrDefault devolve a unidade informada ou UN.
+func (d FiscalData) UnitOrDefault() string {
+	if d.Unit == "" {
+		return DefaultUnit
+	}
+	return strings.ToUpper(d.Unit)
+}
+
+// Rate devolve o valor da alíquota ou zero quando não informada.
+func Rate(rate *float64) float64 {
+	if rate == nil {
+		return 0
+	}
+	return *rate
+}
+
+// cfopGroups são as faixas de CFOP da tabela do Convênio s/nº de 1970: o
+// primeiro dígito indica entrada (1 a 3) ou saída (5 a 7) e os demais o grupo
+// da operação.
+var cfopGroups = [][2]int{
+	{101, 129}, {151, 159}, {201, 212}, {251, 258}, {301, 303}, {351, 360},
+	{401, 415}, {451, 451}, {501, 505}, {551, 557}, {601, 605}, {651, 667},
+	{901, 949},
+}
+
+// ValidCFOP confere o formato e se o código pertence a uma das faixas da
+// tabela de CFOP.
+func ValidCFOP(cfop string) bool {
+	if !digits(cfop, 4) {
+		return false
+	}
+	switch cfop[0] {
+	case '1', '2', '3', '5', '6', '7':
+	default:
+		return false
+	}
+
+	group := int(cfop[1]-'0')*100 + int(cfop[2]-'0')*10 + int(cfop[3]-'0')
+	for _, r := range cfopGroups {
+		if group >= r[0] && group <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func digits(value string, length int) bool {
+	return len(value) == length && strings.Trim(value, "0123456789") == ""
+}
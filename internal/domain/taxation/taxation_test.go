@@ -0,0 +1,66 @@
+package taxation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFiscalData_Validate(t *testing.T) {
+	valid := FiscalData{NCM: "21069090", CEST: "1704900", CFOP: "5102", CST: "102", Origin: ptr(0), Unit: "un", ICMSRate: ptr(18.0)}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, FiscalData{}.Validate())
+	assert.NoError(t, FiscalData{CST: "00", ICMSRate: ptr(18.0)}.Validate())
+
+	tests := []struct {
+		data FiscalData
+		err  error
+	}{
+		{FiscalData{NCM: "2106909"}, ErrNCMInvalid},
+		{FiscalData{NCM: "2106.90.90"}, ErrNCMInvalid},
+		{FiscalData{CEST: "17049"}, ErrCESTInvalid},
+		{FiscalData{CFOP: "5999"}, ErrCFOPInvalid},
+		{FiscalData{CFOP: "4102"}, ErrCFOPInvalid},
+		{FiscalData{CST: "1"}, ErrCSTInvalid},
+		{FiscalData{CST: "00"}, ErrICMSRequired},
+		{FiscalData{Origin: ptr(9)}, ErrOriginInvalid},
+		{FiscalData{Unit: "UNIDADE"}, ErrUnitInvalid},
+		{FiscalData{PISRate: ptr(-1.0)}, ErrTaxRateInvalid},
+		{FiscalData{COFINSRate: ptr(101.0)}, ErrTaxRateInvalid},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.err, tt.data.Validate(), "%+v", tt.data)
+	}
+}
+
+func TestValidCFOP(t *testing.T) {
+	for _, cfop := range []string{"5102", "5405", "5949", "1102", "6108", "7101"} {
+		assert.True(t, ValidCFOP(cfop), cfop)
+	}
+	for _, cfop := range []string{"", "510", "51020", "0102", "8102", "5130", "5950", "51O2"} {
+		assert.False(t, ValidCFOP(cfop), cfop)
+	}
+}
+
+func TestFiscalData_WithDefaultsAndMissing(t *testing.T) {
+	defaults := FiscalData{NCM: "21069090", CFOP: "5102", CST: "102", Origin: ptr(0), Unit: "UN", PISRate: ptr(0.65)}
+	data := FiscalData{NCM: "22029900", Origin: ptr(2), PISRate: ptr(0.0)}
+
+	assert.Equal(t, []string{"cfop", "cst"}, data.Missing())
+
+	merged := data.WithDefaults(defaults)
+	assert.Equal(t, "22029900", merged.NCM)
+	assert.Equal(t, "5102", merged.CFOP)
+	assert.Equal(t, "102", merged.CST)
+	assert.Equal(t, 2, merged.OriginOrDefault())
+	assert.Equal(t, 0.0, Rate(merged.PISRate), "zero informado não herda a alíquota da categoria")
+	assert.Empty(t, merged.Missing())
+
+	assert.Equal(t, 0, FiscalData{}.OriginOrDefault())
+	assert.Equal(t, DefaultUnit, FiscalData{}.UnitOrDefault())
+	assert.Equal(t, "KG", FiscalData{Unit: "kg"}.UnitOrDefault())
+}
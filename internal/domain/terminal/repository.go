@@ -0,0 +1,19 @@
+package terminal
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, terminal *Terminal) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Terminal, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Terminal, error)
+	Update(ctx context.Context, terminal *Terminal) error
+	// UpdateLastSeen registra o último login de operador no terminal.
+	UpdateLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]*Terminal, error)
+}
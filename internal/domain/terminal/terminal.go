@@ -0,0 +1,62 @@
+package terminal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTerminalNameRequired = errors.New("o nome do terminal é obrigatório")
+	ErrTerminalIdInvalid    = errors.New("ID do terminal inválido")
+	ErrTerminalNotFound     = errors.New("terminal não encontrado")
+	ErrDeviceTokenInvalid   = errors.New("dispositivo não autorizado")
+)
+
+// Terminal é um caixa compartilhado (tablet ou computador do balcão). Ele se
+// identifica com um token de dispositivo de longa duração, guardado apenas
+// como hash, e os operadores entram nele com o PIN.
+type Terminal struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Active     bool       `json:"active"`
+	TokenHash  string     `json:"-"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Registration é devolvida no cadastro e na troca do token; o token do
+// dispositivo só é exibido nesse momento.
+type Registration struct {
+	Terminal    *Terminal `json:"terminal"`
+	DeviceToken string    `json:"device_token"`
+}
+
+func (t *Terminal) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return ErrTerminalNameRequired
+	}
+	return nil
+}
+
+// NewDeviceToken gera um token opaco de 256 bits.
+func NewDeviceToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashDeviceToken é o valor guardado e consultado no lugar do token.
+func HashDeviceToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
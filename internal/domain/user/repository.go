@@ -0,0 +1,18 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, user *User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context) ([]*User, error)
+	// CountActiveByRole conta os usuários ativos com o papel.
+	CountActiveByRole(ctx context.Context, role Role) (int, error)
+}
@@ -0,0 +1,90 @@
+package user
+
+// Role define o que o funcionário pode fazer no sistema.
+type Role string
+
+const (
+	RoleOwner   Role = "owner"
+	RoleManager Role = "manager"
+	RoleCashier Role = "cashier"
+	RoleKitchen Role = "kitchen"
+	RoleCourier Role = "courier"
+)
+
+// Permission é verificada em cada rota protegida.
+type Permission string
+
+const (
+	PermissionCatalogRead    Permission = "catalog:read"
+	PermissionCatalogWrite   Permission = "catalog:write"
+	PermissionSalesRead      Permission = "sales:read"
+	PermissionSalesWrite     Permission = "sales:write"
+	PermissionSalesStatus    Permission = "sales:status"
+	PermissionSalesDelete    Permission = "sales:delete"
+	PermissionPaymentsRefund Permission = "payments:refund"
+	PermissionKitchen        Permission = "kitchen:operate"
+	PermissionReports        Permission = "reports:read"
+	PermissionUsersManage    Permission = "users:manage"
+	PermissionTerminals      Permission = "terminals:manage"
+	PermissionAPIKeys        Permission = "api_keys:manage"
+	PermissionAuditRead      Permission = "audit:read"
+)
+
+// permissions lista todas as permissões conhecidas, usadas também como
+// escopos das chaves de API.
+var permissions = []Permission{
+	PermissionCatalogRead, PermissionCatalogWrite,
+	PermissionSalesRead, PermissionSalesWrite, PermissionSalesStatus, PermissionSalesDelete,
+	PermissionPaymentsRefund, PermissionKitchen, PermissionReports,
+	PermissionUsersManage, PermissionTerminals, PermissionAPIKeys, PermissionAuditRead,
+}
+
+// rolePermissions concentra a matriz de acesso. O proprietário tem todas as
+// permissões e não aparece aqui.
+var rolePermissions = map[Role][]Permission{
+	RoleManager: {
+		PermissionCatalogRead, PermissionCatalogWrite,
+		PermissionSalesRead, PermissionSalesWrite, PermissionSalesStatus, PermissionSalesDelete,
+		PermissionPaymentsRefund, PermissionKitchen, PermissionReports, PermissionTerminals,
+	},
+	RoleCashier: {
+		PermissionCatalogRead,
+		PermissionSalesRead, PermissionSalesWrite, PermissionSalesStatus,
+	},
+	RoleKitchen: {
+		PermissionCatalogRead,
+		PermissionSalesRead, PermissionSalesStatus, PermissionKitchen,
+	},
+	RoleCourier: {
+		PermissionSalesRead,
+	},
+}
+
+func (r Role) IsValid() bool {
+	if r == RoleOwner {
+		return true
+	}
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func (r Role) Can(p Permission) bool {
+	if r == RoleOwner {
+		return true
+	}
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Permission) IsValid() bool {
+	for _, known := range permissions {
+		if known == p {
+			return true
+		}
+	}
+	return false
+}
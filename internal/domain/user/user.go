@@ -0,0 +1,153 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUsernameRequired   = errors.New("o nome de usuário é obrigatório")
+	ErrUsernameInvalid    = errors.New("nome de usuário inválido: use de 3 a 50 letras, números, ponto, hífen ou sublinhado")
+	ErrUsernameTaken      = errors.New("nome de usuário já está em uso")
+	ErrRoleInvalid        = errors.New("papel inválido: use owner, manager, cashier, kitchen ou courier")
+	ErrPasswordTooShort   = errors.New("a senha deve ter pelo menos 8 caracteres")
+	ErrPasswordTooLong    = errors.New("a senha deve ter no máximo 72 bytes")
+	ErrPINInvalid         = errors.New("o PIN deve ter de 4 a 6 dígitos")
+	ErrUserIdInvalid      = errors.New("ID do usuário inválido")
+	ErrUserNotFound       = errors.New("usuário não encontrado")
+	ErrInvalidCredentials = errors.New("credenciais inválidas")
+	ErrLastOwner          = errors.New("a loja precisa de pelo menos um proprietário ativo")
+	ErrOwnerRequired      = errors.New("nenhum usuário cadastrado: informe AUTH_USER e AUTH_PASSWORD para criar o proprietário")
+)
+
+const (
+	MinPasswordLength = 8
+	// maxPasswordLength é o limite do bcrypt; bytes além dele seriam ignorados.
+	maxPasswordLength = 72
+
+	MinPINLength = 4
+	MaxPINLength = 6
+)
+
+// User é um funcionário com acesso ao sistema. A senha e o PIN usado nos
+// terminais do balcão só são guardados como hash bcrypt.
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	Name         string    `json:"name"`
+	Role         Role      `json:"role"`
+	Active       bool      `json:"active"`
+	PasswordHash string    `json:"-"`
+	PINHash      string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate normaliza o nome de usuário para minúsculas e confere o papel.
+func (u *User) Validate() error {
+	u.Username = strings.ToLower(strings.TrimSpace(u.Username))
+	if u.Username == "" {
+		return ErrUsernameRequired
+	}
+	if !validUsername(u.Username) {
+		return ErrUsernameInvalid
+	}
+	if !u.Role.IsValid() {
+		return ErrRoleInvalid
+	}
+	return nil
+}
+
+// SetPassword valida a senha e guarda o seu hash.
+func (u *User) SetPassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	return u.SetBootstrapPassword(password)
+}
+
+// SetBootstrapPassword guarda a senha do proprietário criado a partir do
+// AUTH_PASSWORD. A variável é anterior ao mínimo de 8 caracteres, então só o
+// limite do bcrypt é conferido, para que instalações antigas continuem
+// subindo; a senha curta vale até ser trocada pelo cadastro de usuários.
+func (u *User) SetBootstrapPassword(password string) error {
+	if password == "" {
+		return ErrPasswordTooShort
+	}
+	if len(password) > maxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword compara a senha com o hash em tempo constante.
+func (u *User) CheckPassword(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// SetPIN valida o PIN numérico e guarda o seu hash; PIN vazio remove o acesso
+// pelos terminais.
+func (u *User) SetPIN(pin string) error {
+	if pin == "" {
+		u.PINHash = ""
+		return nil
+	}
+	if len(pin) < MinPINLength || len(pin) > MaxPINLength {
+		return ErrPINInvalid
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return ErrPINInvalid
+		}
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PINHash = string(hash)
+	return nil
+}
+
+// HasPIN indica se o usuário pode entrar nos terminais com PIN.
+func (u *User) HasPIN() bool {
+	return u.PINHash != ""
+}
+
+// CheckPIN compara o PIN com o hash em tempo constante.
+func (u *User) CheckPIN(pin string) bool {
+	if u.PINHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.PINHash), []byte(pin)) == nil
+}
+
+// Can indica se o usuário está ativo e o seu papel concede a permissão.
+func (u *User) Can(p Permission) bool {
+	return u.Active && u.Role.Can(p)
+}
+
+func validUsername(username string) bool {
+	if len(username) < 3 || len(username) > 50 {
+		return false
+	}
+	for _, r := range username {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
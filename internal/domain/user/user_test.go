@@ -0,0 +1,71 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_Validate(t *testing.T) {
+	u := &User{Username: "  Maria.Silva ", Role: RoleCashier}
+	require.NoError(t, u.Validate())
+	assert.Equal(t, "maria.silva", u.Username)
+
+	assert.Equal(t, ErrUsernameRequired, (&User{Role: RoleCashier}).Validate())
+	assert.Equal(t, ErrUsernameInvalid, (&User{Username: "jo", Role: RoleCashier}).Validate())
+	assert.Equal(t, ErrUsernameInvalid, (&User{Username: "maria silva", Role: RoleCashier}).Validate())
+	assert.Equal(t, ErrRoleInvalid, (&User{Username: "maria", Role: "admin"}).Validate())
+}
+
+func TestUser_Password(t *testing.T) {
+	u := &User{Username: "maria", Role: RoleCashier, Active: true}
+	assert.Equal(t, ErrPasswordTooShort, u.SetPassword("1234567"))
+	assert.Equal(t, ErrPasswordTooLong, u.SetPassword(strings.Repeat("a", 73)))
+	require.NoError(t, u.SetBootstrapPassword("1234"))
+	assert.True(t, u.CheckPassword("1234"))
+	assert.False(t, u.CheckPassword(""))
+
+	require.NoError(t, u.SetPassword("segredo123"))
+	assert.NotContains(t, u.PasswordHash, "segredo123")
+	assert.True(t, u.CheckPassword("segredo123"))
+	assert.False(t, u.CheckPassword("segredo124"))
+}
+
+func TestUser_PIN(t *testing.T) {
+	u := &User{Username: "maria", Role: RoleCashier, Active: true}
+	for _, pin := range []string{"123", "1234567", "12a4", "12 34"} {
+		assert.Equal(t, ErrPINInvalid, u.SetPIN(pin), pin)
+	}
+	assert.False(t, u.HasPIN())
+	assert.False(t, u.CheckPIN(""))
+
+	require.NoError(t, u.SetPIN("4321"))
+	assert.True(t, u.HasPIN())
+	assert.True(t, u.CheckPIN("4321"))
+	assert.False(t, u.CheckPIN("1234"))
+
+	require.NoError(t, u.SetPIN(""))
+	assert.False(t, u.CheckPIN("4321"))
+}
+
+func TestRole_Can(t *testing.T) {
+	assert.True(t, RoleOwner.Can(PermissionUsersManage))
+	assert.False(t, RoleManager.Can(PermissionUsersManage))
+	assert.True(t, RoleManager.Can(PermissionPaymentsRefund))
+
+	assert.True(t, RoleCashier.Can(PermissionSalesWrite))
+	assert.False(t, RoleCashier.Can(PermissionCatalogWrite))
+	assert.False(t, RoleCashier.Can(PermissionSalesDelete))
+
+	assert.True(t, RoleKitchen.Can(PermissionKitchen))
+	assert.False(t, RoleKitchen.Can(PermissionSalesWrite))
+
+	assert.True(t, RoleCourier.Can(PermissionSalesRead))
+	assert.False(t, RoleCourier.Can(PermissionSalesStatus))
+	assert.False(t, RoleCourier.Can(PermissionCatalogRead))
+
+	assert.False(t, Role("admin").Can(PermissionSalesRead))
+	assert.False(t, (&User{Role: RoleOwner}).Can(PermissionSalesRead), "usuário inativo")
+}
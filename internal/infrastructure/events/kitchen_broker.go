@@ -0,0 +1,113 @@
+package events
+
+import (
+	"andressa-lanches/internal/domain/kitchen"
+	"context"
+	"sync"
+	"time"
+)
+
+const subscriberBuffer = 64
+
+// KitchenBroker distribui os eventos da cozinha em memória e guarda os
+// últimos eventos publicados para que clientes reconectados se atualizem.
+// Os IDs partem de uma época tirada do relógio na criação do broker, então
+// os IDs de uma execução anterior do servidor são sempre menores que os da
+// atual e não se confundem com eles.
+type KitchenBroker struct {
+	mu          sync.Mutex
+	epoch       uint64
+	lastID      uint64
+	history     []kitchen.Event
+	capacity    int
+	subscribers map[chan kitchen.Event]struct{}
+}
+
+func NewKitchenBroker(capacity int) *KitchenBroker {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	// Milissegundos vezes mil cabem nos inteiros exatos do JavaScript e deixam
+	// espaço para mil eventos por milissegundo de execução.
+	epoch := uint64(time.Now().UnixMilli()) * 1000
+	return &KitchenBroker{
+		epoch:       epoch,
+		lastID:      epoch,
+		capacity:    capacity,
+		subscribers: make(map[chan kitchen.Event]struct{}),
+	}
+}
+
+func (b *KitchenBroker) Publish(ctx context.Context, event kitchen.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.capacity {
+		b.history = b.history[len(b.history)-b.capacity:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Assinante lento: encerra a conexão para que ele reconecte
+			// informando o último evento recebido.
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *KitchenBroker) Subscribe(lastEventID uint64) ([]kitchen.Event, <-chan kitchen.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []kitchen.Event
+	if lastEventID != 0 && !b.knows(lastEventID) {
+		// O cliente vem de outra execução do servidor ou perdeu eventos que
+		// já saíram do histórico: ele descarta o que tem e recebe todo o
+		// histórico disponível.
+		resyncID := b.lastID
+		if len(b.history) > 0 {
+			resyncID = b.history[0].ID - 1
+		}
+		missed = append(missed, kitchen.Event{ID: resyncID, Type: kitchen.EventResync, OccurredAt: time.Now()})
+		lastEventID = 0
+	}
+
+	for _, event := range b.history {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+
+	ch := make(chan kitchen.Event, subscriberBuffer)
+	b.subscribers[ch] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return missed, ch, cancel
+}
+
+// knows informa se os eventos posteriores a id ainda estão todos no
+// histórico desta execução.
+func (b *KitchenBroker) knows(id uint64) bool {
+	if id < b.epoch || id > b.lastID {
+		return false
+	}
+	return len(b.history) == 0 || id >= b.history[0].ID-1
+}
@@ -0,0 +1,304 @@
+package nfce
+
+import (
+	"andressa-lanches/internal/domain/fiscal"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	namespace     = "http://www.portalfiscal.inf.br/nfe"
+	layoutVersion = "4.00"
+	qrCodeVersion = "2"
+	appVersion    = "andressa-lanches 1.0"
+	countryCode   = "1058"
+	countryName   = "Brasil"
+	noGTIN        = "SEM GTIN"
+	xmlHeader     = `<?xml version="1.0" encoding="UTF-8"?>`
+)
+
+// Encoder gera o XML da NFC-e no leiaute 4.00 da SEFAZ e o assina.
+type Encoder struct {
+	signer *Signer
+}
+
+func NewEncoder(signer *Signer) *Encoder {
+	return &Encoder{signer: signer}
+}
+
+func (e *Encoder) Encode(n *fiscal.NFCe) ([]byte, error) {
+	if e.signer == nil {
+		return nil, fiscal.ErrNotConfigured
+	}
+
+	key := n.AccessKey.String()
+	infNFe, err := buildInfNFe(n, key)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := e.signer.sign(infNFe, "NFe"+key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := newElement("NFe", infNFe, buildInfNFeSupl(n, key), signature)
+	return []byte(xmlHeader + doc.canonical(namespace)), nil
+}
+
+func buildInfNFe(n *fiscal.NFCe, key string) (*element, error) {
+	infNFe := newElement("infNFe").attr("Id", "NFe"+key).attr("versao", layoutVersion)
+	infNFe.add(buildIde(n), buildEmit(n.Issuer))
+
+	for i, item := range n.Items {
+		tax, err := buildImposto(n.Issuer.TaxRegime, item)
+		if err != nil {
+			return nil, err
+		}
+		det := newElement("det", buildProd(item), tax).attr("nItem", strconv.Itoa(i+1))
+		infNFe.add(det)
+	}
+
+	infNFe.add(
+		buildTotal(n),
+		newElement("transp", textElement("modFrete", "9")),
+		buildPag(n),
+	)
+	if n.OrderNumber > 0 {
+		infNFe.add(newElement("infAdic", textElement("infCpl", fmt.Sprintf("Pedido %d", n.OrderNumber))))
+	}
+	return infNFe, nil
+}
+
+func buildIde(n *fiscal.NFCe) *element {
+	k := n.AccessKey
+
+	// Entregas em domicílio usam indPres 4 e exigem o indicador de intermediador.
+	presence, intermediary := "1", (*element)(nil)
+	if n.Delivery {
+		presence, intermediary = "4", textElement("indIntermed", "0")
+	}
+
+	return newElement("ide",
+		textElement("cUF", k.StateCode),
+		textElement("cNF", fmt.Sprintf("%08d", k.Code)),
+		textElement("natOp", "VENDA"),
+		textElement("mod", fiscal.Model),
+		textElement("serie", strconv.Itoa(k.Series)),
+		textElement("nNF", strconv.Itoa(k.Number)),
+		textElement("dhEmi", k.IssuedAt.Format("2006-01-02T15:04:05-07:00")),
+		textElement("tpNF", "1"),
+		textElement("idDest", "1"),
+		textElement("cMunFG", n.Issuer.Address.CityCode),
+		textElement("tpImp", "4"),
+		textElement("tpEmis", strconv.Itoa(k.EmissionType)),
+		textElement("cDV", strconv.Itoa(k.CheckDigit())),
+		textElement("tpAmb", strconv.Itoa(int(n.Environment))),
+		textElement("finNFe", "1"),
+		textElement("indFinal", "1"),
+		textElement("indPres", presence),
+	).add(
+		intermediary,
+		textElement("procEmi", "0"),
+		textElement("verProc", appVersion),
+	)
+}
+
+func buildEmit(issuer fiscal.Issuer) *element {
+	a := issuer.Address
+	emit := newElement("emit",
+		textElement("CNPJ", issuer.CNPJ),
+		textElement("xNome", issuer.Name),
+	)
+	if issuer.TradeName != "" {
+		emit.add(textElement("xFant", issuer.TradeName))
+	}
+	return emit.add(
+		newElement("enderEmit",
+			textElement("xLgr", a.Street),
+			textElement("nro", a.Number),
+			textElement("xBairro", a.District),
+			textElement("cMun", a.CityCode),
+			textElement("xMun", a.City),
+			textElement("UF", strings.ToUpper(a.State)),
+			textElement("CEP", a.ZipCode),
+			textElement("cPais", countryCode),
+			textElement("xPais", countryName),
+		),
+		textElement("IE", issuer.StateRegistration),
+		textElement("CRT", strconv.Itoa(int(issuer.TaxRegime))),
+	)
+}
+
+func buildProd(item fiscal.Item) *element {
+	quantity := fmt.Sprintf("%.4f", item.Quantity)
+	unitPrice := fmt.Sprintf("%.2f", item.UnitPrice)
+
+	prod := newElement("prod",
+		textElement("cProd", item.Code),
+		textElement("cEAN", noGTIN),
+		textElement("xProd", item.Description),
+		textElement("NCM", item.NCM),
+	)
+	if item.CEST != "" {
+		prod.add(textElement("CEST", item.CEST))
+	}
+	prod.add(
+		textElement("CFOP", item.CFOP),
+		textElement("uCom", item.Unit),
+		textElement("qCom", quantity),
+		textElement("vUnCom", unitPrice),
+		textElement("vProd", money(item.Total)),
+		textElement("cEANTrib", noGTIN),
+		textElement("uTrib", item.Unit),
+		textElement("qTrib", quantity),
+		textElement("vUnTrib", unitPrice),
+	)
+	if item.Discount > 0 {
+		prod.add(textElement("vDesc", money(item.Discount)))
+	}
+	if item.Other > 0 {
+		prod.add(textElement("vOutro", money(item.Other)))
+	}
+	return prod.add(textElement("indTot", "1"))
+}
+
+// buildImposto escolhe o grupo de ICMS pelo regime do emitente e pela
+// situação tributária. O ICMS só é destacado na tributação integral (CST 00)
+// do regime normal; PIS e COFINS usam a alíquota do item quando houver.
+func buildImposto(regime fiscal.TaxRegime, item fiscal.Item) (*element, error) {
+	origin := textElement("orig", strconv.Itoa(item.Origin))
+
+	var group *element
+	if regime.IsSimples() {
+		switch item.CST {
+		case "102", "103", "300", "400":
+			group = newElement("ICMSSN102", origin, textElement("CSOSN", item.CST))
+		case "500":
+			group = newElement("ICMSSN500", origin, textElement("CSOSN", item.CST))
+		}
+	} else {
+		switch item.CST {
+		case "00":
+			if item.ICMSRate > 0 {
+				group = newElement("ICMS00",
+					origin,
+					textElement("CST", item.CST),
+					textElement("modBC", "3"),
+					textElement("vBC", money(item.TaxBase())),
+					textElement("pICMS", rate(item.ICMSRate)),
+					textElement("vICMS", money(item.ICMS())),
+				)
+			}
+		case "40", "41", "50":
+			group = newElement("ICMS40", origin, textElement("CST", item.CST))
+		case "60":
+			group = newElement("ICMS60", origin, textElement("CST", item.CST))
+		}
+	}
+	if group == nil {
+		return nil, fiscal.ErrTaxSituationUnsupported
+	}
+
+	return newElement("imposto",
+		newElement("ICMS", group),
+		newElement("PIS", contribution("PIS", item.TaxBase(), item.PISRate, item.PIS())),
+		newElement("COFINS", contribution("COFINS", item.TaxBase(), item.COFINSRate, item.COFINS())),
+	), nil
+}
+
+// contribution monta o grupo de PIS ou COFINS: tributado pela alíquota
+// (CST 01) ou, sem alíquota, como outras operações com valores zerados.
+func contribution(name string, base, percentage, value float64) *element {
+	if percentage > 0 {
+		return newElement(name+"Aliq",
+			textElement("CST", "01"),
+			textElement("vBC", money(base)),
+			textElement("p"+name, rate(percentage)),
+			textElement("v"+name, money(value)),
+		)
+	}
+	return newElement(name+"Outr",
+		textElement("CST", "99"),
+		textElement("vBC", money(0)),
+		textElement("p"+name, rate(0)),
+		textElement("v"+name, money(0)),
+	)
+}
+
+func buildTotal(n *fiscal.NFCe) *element {
+	zero := money(0)
+	return newElement("total", newElement("ICMSTot",
+		textElement("vBC", money(n.TaxBaseTotal())),
+		textElement("vICMS", money(n.ICMSTotal())),
+		textElement("vICMSDeson", zero),
+		textElement("vFCP", zero),
+		textElement("vBCST", zero),
+		textElement("vST", zero),
+		textElement("vFCPST", zero),
+		textElement("vFCPSTRet", zero),
+		textElement("vProd", money(n.ProductsTotal())),
+		textElement("vFrete", zero),
+		textElement("vSeg", zero),
+		textElement("vDesc", money(n.DiscountTotal())),
+		textElement("vII", zero),
+		textElement("vIPI", zero),
+		textElement("vIPIDevol", zero),
+		textElement("vPIS", money(n.PISTotal())),
+		textElement("vCOFINS", money(n.COFINSTotal())),
+		textElement("vOutro", money(n.OtherTotal())),
+		textElement("vNF", money(n.Total())),
+	))
+}
+
+func buildPag(n *fiscal.NFCe) *element {
+	pag := newElement("pag")
+	for _, p := range n.Payments {
+		detPag := newElement("detPag",
+			textElement("tPag", string(p.Type)),
+			textElement("vPag", money(p.Amount)),
+		)
+		if p.Type.IsCard() {
+			// Pagamento com cartão sem integração com a automação comercial.
+			detPag.add(newElement("card", textElement("tpIntegra", "2")))
+		}
+		pag.add(detPag)
+	}
+	if n.Change > 0 {
+		pag.add(textElement("vTroco", money(n.Change)))
+	}
+	return pag
+}
+
+func buildInfNFeSupl(n *fiscal.NFCe, key string) *element {
+	return newElement("infNFeSupl",
+		textElement("qrCode", QRCode(n.Issuer, n.Environment, key)),
+		textElement("urlChave", n.Issuer.ConsultURL),
+	)
+}
+
+// QRCode monta a URL do QR Code (versão 2, emissão online): a chave, a
+// versão, o ambiente e o identificador do CSC, seguidos do hash SHA-1 desses
+// campos concatenados ao CSC.
+func QRCode(issuer fiscal.Issuer, env fiscal.Environment, key string) string {
+	cscID := strings.TrimLeft(issuer.CSCID, "0")
+	params := strings.Join([]string{key, qrCodeVersion, strconv.Itoa(int(env)), cscID}, "|")
+	hash := sha1.Sum([]byte(params + issuer.CSC))
+
+	separator := "?"
+	if strings.Contains(issuer.QRCodeURL, "?") {
+		separator = "&"
+	}
+	return issuer.QRCodeURL + separator + "p=" + params + "|" + strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+func money(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+func rate(value float64) string {
+	return fmt.Sprintf("%.4f", value)
+}
@@ -0,0 +1,45 @@
+package nfce
+
+import (
+	"andressa-lanches/internal/domain/fiscal"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const FakeTransmitterName = "fake"
+
+// FakeTransmitter simula a autorização da SEFAZ em execuções locais: confere
+// a assinatura e autoriza o documento com um protocolo sequencial.
+type FakeTransmitter struct {
+	mu       sync.Mutex
+	sequence int64
+	received map[string]bool
+}
+
+func NewFakeTransmitter() *FakeTransmitter {
+	return &FakeTransmitter{received: make(map[string]bool)}
+}
+
+func (t *FakeTransmitter) Transmit(ctx context.Context, accessKey string, xml []byte) (*fiscal.Authorization, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if err := Verify(xml); err != nil {
+		return &fiscal.Authorization{StatusCode: 297, Message: "Rejeição: Assinatura difere do calculado", ReceivedAt: now}, nil
+	}
+	if t.received[accessKey] {
+		return &fiscal.Authorization{StatusCode: 204, Message: "Rejeição: Duplicidade de NF-e", ReceivedAt: now}, nil
+	}
+
+	t.received[accessKey] = true
+	t.sequence++
+	return &fiscal.Authorization{
+		StatusCode: 100,
+		Message:    "Autorizado o uso da NF-e",
+		Protocol:   fmt.Sprintf("%s%s%011d", accessKey[:2], now.Format("06"), t.sequence),
+		ReceivedAt: now,
+	}, nil
+}
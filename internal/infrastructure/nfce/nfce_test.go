@@ -0,0 +1,172 @@
+package nfce
+
+import (
+	"andressa-lanches/internal/domain/fiscal"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIssuer = fiscal.Issuer{
+	CNPJ:              "11222333000181",
+	StateRegistration: "123456789012",
+	Name:              "Andressa Lanches & Cia",
+	TaxRegime:         fiscal.TaxRegimeSimples,
+	Address:           fiscal.Address{Street: "Rua Exemplo", Number: "123", District: "Centro", CityCode: "3550308", City: "Sao Paulo", State: "SP", ZipCode: "01001000"},
+	CSCID:             "000001",
+	CSC:               "123456",
+	QRCodeURL:         "https://nfce.example.com/qrcode",
+	ConsultURL:        "https://nfce.example.com/consulta",
+}
+
+func newTestSigner(t *testing.T) *Signer {
+	certPEM, keyPEM, err := GenerateTestCertificate(testIssuer.Name, testIssuer.CNPJ)
+	require.NoError(t, err)
+	signer, err := ParseSigner(certPEM, keyPEM)
+	require.NoError(t, err)
+	return signer
+}
+
+func newTestNFCe() *fiscal.NFCe {
+	return &fiscal.NFCe{
+		Issuer:      testIssuer,
+		Environment: fiscal.EnvironmentHomologation,
+		AccessKey: fiscal.AccessKey{
+			StateCode:    "35",
+			IssuedAt:     time.Date(2024, 9, 1, 12, 30, 0, 0, time.FixedZone("BRT", -3*60*60)),
+			CNPJ:         testIssuer.CNPJ,
+			Series:       1,
+			Number:       42,
+			EmissionType: fiscal.EmissionNormal,
+			Code:         12345678,
+		},
+		OrderNumber: 7,
+		Items: []fiscal.Item{
+			{Code: "1", Description: "X-Salada <duplo>", NCM: "21069090", CFOP: "5102", CST: "102", Unit: "UN", Quantity: 2, UnitPrice: 10, Total: 20, Discount: 1},
+			{Code: "2", Description: "Suco", NCM: "22029900", CFOP: "5102", CST: "500", Unit: "UN", Quantity: 1, UnitPrice: 5, Total: 5, Other: 2.5},
+		},
+		Payments: []fiscal.Payment{{Type: fiscal.PaymentTypeCreditCard, Amount: 26.5}},
+	}
+}
+
+// assertWellFormed percorre o documento com o decodificador padrão.
+func assertWellFormed(t *testing.T, doc []byte) {
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestEncoder_EncodeAndVerify(t *testing.T) {
+	doc, err := NewEncoder(newTestSigner(t)).Encode(newTestNFCe())
+	require.NoError(t, err)
+	assertWellFormed(t, doc)
+
+	content := string(doc)
+	assert.True(t, strings.HasPrefix(content, xmlHeader+`<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe Id="NFe35240911222333000181650010000000421123456782" versao="4.00">`))
+	assert.Contains(t, content, "<cDV>2</cDV>")
+	assert.Contains(t, content, "<dhEmi>2024-09-01T12:30:00-03:00</dhEmi>")
+	assert.Contains(t, content, "<xNome>Andressa Lanches &amp; Cia</xNome>")
+	assert.Contains(t, content, "<xProd>X-Salada &lt;duplo&gt;</xProd>")
+	assert.Contains(t, content, "<ICMSSN102><orig>0</orig><CSOSN>102</CSOSN></ICMSSN102>")
+	assert.Contains(t, content, "<ICMSSN500><orig>0</orig><CSOSN>500</CSOSN></ICMSSN500>")
+	assert.Contains(t, content, "<vProd>25.00</vProd><vFrete>0.00</vFrete><vSeg>0.00</vSeg><vDesc>1.00</vDesc>")
+	assert.Contains(t, content, "<vOutro>2.50</vOutro><vNF>26.50</vNF>")
+	assert.Contains(t, content, "<detPag><tPag>03</tPag><vPag>26.50</vPag><card><tpIntegra>2</tpIntegra></card></detPag>")
+	assert.Contains(t, content, `<Reference URI="#NFe35240911222333000181650010000000421123456782">`)
+	assert.True(t, strings.HasSuffix(content, "</Signature></NFe>"))
+
+	assert.NoError(t, Verify(doc))
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	doc, err := NewEncoder(newTestSigner(t)).Encode(newTestNFCe())
+	require.NoError(t, err)
+
+	tampered := bytes.Replace(doc, []byte("<vNF>26.50</vNF>"), []byte("<vNF>16.50</vNF>"), 1)
+	assert.Equal(t, ErrSignatureInvalid, Verify(tampered))
+
+	// Certificado de outro emitente com a assinatura original.
+	resigned, err := NewEncoder(newTestSigner(t)).Encode(newTestNFCe())
+	require.NoError(t, err)
+	swapped := append(between(doc, "<?xml", "<X509Certificate>"), between(resigned, "<X509Certificate>", "</NFe>")[len("<X509Certificate>"):]...)
+	assert.Equal(t, ErrSignatureInvalid, Verify(swapped))
+}
+
+func TestEncoder_UnsupportedTaxSituation(t *testing.T) {
+	n := newTestNFCe()
+	n.Issuer.TaxRegime = fiscal.TaxRegimeNormal
+
+	_, err := NewEncoder(newTestSigner(t)).Encode(n)
+	assert.Equal(t, fiscal.ErrTaxSituationUnsupported, err)
+}
+
+func TestEncoder_NormalRegimeTaxes(t *testing.T) {
+	n := newTestNFCe()
+	n.Issuer.TaxRegime = fiscal.TaxRegimeNormal
+	n.Items[0].CST, n.Items[0].ICMSRate, n.Items[0].PISRate, n.Items[0].COFINSRate = "00", 18, 1.65, 7.6
+	n.Items[1].CST, n.Items[1].CEST = "60", "0300700"
+
+	doc, err := NewEncoder(newTestSigner(t)).Encode(n)
+	require.NoError(t, err)
+
+	content := string(doc)
+	assert.Contains(t, content, "<ICMS00><orig>0</orig><CST>00</CST><modBC>3</modBC><vBC>19.00</vBC><pICMS>18.0000</pICMS><vICMS>3.42</vICMS></ICMS00>")
+	assert.Contains(t, content, "<PISAliq><CST>01</CST><vBC>19.00</vBC><pPIS>1.6500</pPIS><vPIS>0.31</vPIS></PISAliq>")
+	assert.Contains(t, content, "<COFINSAliq><CST>01</CST><vBC>19.00</vBC><pCOFINS>7.6000</pCOFINS><vCOFINS>1.44</vCOFINS></COFINSAliq>")
+	assert.Contains(t, content, "<NCM>22029900</NCM><CEST>0300700</CEST><CFOP>5102</CFOP>")
+	assert.Contains(t, content, "<ICMS60><orig>0</orig><CST>60</CST></ICMS60>")
+	assert.Contains(t, content, "<PISOutr><CST>99</CST>")
+	assert.Contains(t, content, "<ICMSTot><vBC>19.00</vBC><vICMS>3.42</vICMS>")
+	assert.Contains(t, content, "<vPIS>0.31</vPIS><vCOFINS>1.44</vCOFINS><vOutro>2.50</vOutro>")
+	assert.NoError(t, Verify(doc))
+}
+
+func TestQRCode(t *testing.T) {
+	key := newTestNFCe().AccessKey.String()
+
+	assert.Equal(t,
+		"https://nfce.example.com/qrcode?p="+key+"|2|2|1|9A6F9FA65B9B412C9E82FF22D7019B7F2CA13512",
+		QRCode(testIssuer, fiscal.EnvironmentHomologation, key))
+}
+
+func TestParseSigner_RejectsMismatchedKey(t *testing.T) {
+	certPEM, _, err := GenerateTestCertificate("A", testIssuer.CNPJ)
+	require.NoError(t, err)
+	_, keyPEM, err := GenerateTestCertificate("B", testIssuer.CNPJ)
+	require.NoError(t, err)
+
+	_, err = ParseSigner(certPEM, keyPEM)
+	assert.Equal(t, ErrCertificateInvalid, err)
+}
+
+func TestFakeTransmitter(t *testing.T) {
+	doc, err := NewEncoder(newTestSigner(t)).Encode(newTestNFCe())
+	require.NoError(t, err)
+	key := newTestNFCe().AccessKey.String()
+	transmitter := NewFakeTransmitter()
+
+	auth, err := transmitter.Transmit(context.Background(), key, doc)
+	require.NoError(t, err)
+	assert.True(t, auth.Authorized())
+	assert.Len(t, auth.Protocol, 15)
+
+	auth, err = transmitter.Transmit(context.Background(), key, doc)
+	require.NoError(t, err)
+	assert.Equal(t, 204, auth.StatusCode)
+
+	auth, err = transmitter.Transmit(context.Background(), key, bytes.Replace(doc, []byte("<nNF>42</nNF>"), []byte("<nNF>43</nNF>"), 1))
+	require.NoError(t, err)
+	assert.Equal(t, 297, auth.StatusCode)
+}
@@ -0,0 +1,202 @@
+package nfce
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"time"
+)
+
+const (
+	dsigNamespace   = "http://www.w3.org/2000/09/xmldsig#"
+	c14nAlgorithm   = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	rsaSHA1         = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	sha1Digest      = "http://www.w3.org/2000/09/xmldsig#sha1"
+	envelopedMethod = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+)
+
+var (
+	ErrCertificateInvalid = errors.New("certificado digital inválido: informe o certificado e a chave RSA em PEM")
+	ErrSignatureInvalid   = errors.New("assinatura da NFC-e inválida")
+)
+
+// Signer assina a NFC-e com XMLDSig (RSA-SHA1 sobre o infNFe canonizado em
+// C14N), como exige o leiaute da SEFAZ.
+type Signer struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+func NewSigner(cert *x509.Certificate, key *rsa.PrivateKey) *Signer {
+	return &Signer{cert: cert, key: key}
+}
+
+// LoadSigner lê o certificado e a chave privada de arquivos PEM. Um
+// certificado A1 em PFX pode ser convertido com openssl pkcs12 -nodes.
+func LoadSigner(certFile, keyFile string) (*Signer, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSigner(certPEM, keyPEM)
+}
+
+func ParseSigner(certPEM, keyPEM []byte) (*Signer, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, ErrCertificateInvalid
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, ErrCertificateInvalid
+	}
+
+	var key *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes); err == nil {
+		key, _ = parsed.(*rsa.PrivateKey)
+	} else {
+		key, _ = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	}
+	if key == nil || !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, ErrCertificateInvalid
+	}
+	return NewSigner(cert, key), nil
+}
+
+// GenerateTestCertificate cria um certificado autoassinado no formato do
+// e-CNPJ ("RAZÃO SOCIAL:CNPJ"), útil em homologação local e nos testes. A
+// SEFAZ só aceita certificados emitidos pela ICP-Brasil.
+func GenerateTestCertificate(name, cnpj string) (certPEM, keyPEM []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name + ":" + cnpj, Country: []string{"BR"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
+
+// sign gera o elemento Signature que referencia o elemento de Id informado.
+func (s *Signer) sign(target *element, id string) (*element, error) {
+	digest := sha1.Sum([]byte(target.canonical(namespace)))
+	signedInfo := buildSignedInfo(id, base64.StdEncoding.EncodeToString(digest[:]))
+
+	hashed := sha1.Sum([]byte(signedInfo.canonical(dsigNamespace)))
+	value, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, hashed[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return newElement("Signature",
+		signedInfo,
+		textElement("SignatureValue", base64.StdEncoding.EncodeToString(value)),
+		newElement("KeyInfo", newElement("X509Data",
+			textElement("X509Certificate", base64.StdEncoding.EncodeToString(s.cert.Raw)),
+		)),
+	).attr("xmlns", dsigNamespace), nil
+}
+
+func buildSignedInfo(id, digest string) *element {
+	return newElement("SignedInfo",
+		newElement("CanonicalizationMethod").attr("Algorithm", c14nAlgorithm),
+		newElement("SignatureMethod").attr("Algorithm", rsaSHA1),
+		newElement("Reference",
+			newElement("Transforms",
+				newElement("Transform").attr("Algorithm", envelopedMethod),
+				newElement("Transform").attr("Algorithm", c14nAlgorithm),
+			),
+			newElement("DigestMethod").attr("Algorithm", sha1Digest),
+			textElement("DigestValue", digest),
+		).attr("URI", "#"+id),
+	)
+}
+
+// Verify confere a assinatura de uma NFC-e gerada pelo Encoder. Como o
+// documento já é escrito na forma canônica, basta reintroduzir os
+// namespaces herdados para recompor o que foi assinado.
+func Verify(doc []byte) error {
+	infNFe := between(doc, "<infNFe ", "</infNFe>")
+	signedInfo := between(doc, "<SignedInfo>", "</SignedInfo>")
+	digestValue := between(doc, "<DigestValue>", "</DigestValue>")
+	signatureValue := between(doc, "<SignatureValue>", "</SignatureValue>")
+	certificate := between(doc, "<X509Certificate>", "</X509Certificate>")
+	if infNFe == nil || signedInfo == nil || digestValue == nil || signatureValue == nil || certificate == nil {
+		return ErrSignatureInvalid
+	}
+
+	id := between(infNFe, `Id="`, `"`)
+	if id == nil || !bytes.Contains(signedInfo, []byte(`URI="#`+string(id[len(`Id="`):]))) {
+		return ErrSignatureInvalid
+	}
+
+	canonicalInfNFe := append([]byte(`<infNFe xmlns="`+namespace+`"`), infNFe[len("<infNFe"):]...)
+	digest := sha1.Sum(canonicalInfNFe)
+	if base64.StdEncoding.EncodeToString(digest[:]) != string(digestValue[len("<DigestValue>"):len(digestValue)-len("</DigestValue>")]) {
+		return ErrSignatureInvalid
+	}
+
+	der, err := base64.StdEncoding.DecodeString(string(certificate[len("<X509Certificate>") : len(certificate)-len("</X509Certificate>")]))
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ErrSignatureInvalid
+	}
+
+	value, err := base64.StdEncoding.DecodeString(string(signatureValue[len("<SignatureValue>") : len(signatureValue)-len("</SignatureValue>")]))
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	canonicalSignedInfo := append([]byte(`<SignedInfo xmlns="`+dsigNamespace+`">`), signedInfo[len("<SignedInfo>"):]...)
+	hashed := sha1.Sum(canonicalSignedInfo)
+	if rsa.VerifyPKCS1v15(publicKey, crypto.SHA1, hashed[:], value) != nil {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// between devolve o trecho de doc do início de start até o fim de end.
+func between(doc []byte, start, end string) []byte {
+	i := bytes.Index(doc, []byte(start))
+	if i < 0 {
+		return nil
+	}
+	j := bytes.Index(doc[i+len(start):], []byte(end))
+	if j < 0 {
+		return nil
+	}
+	return doc[i : i+len(start)+j+len(end)]
+}
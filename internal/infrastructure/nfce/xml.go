@@ -0,0 +1,89 @@
+package nfce
+
+import (
+	"strings"
+)
+
+// element é um nó XML escrito já na forma canônica (C14N 1.0): sem tags
+// vazias abreviadas, com atributos na ordem em que a especificação os
+// ordena e com o mesmo escape de texto. Assim o XML gerado é exatamente o
+// que é resumido na assinatura.
+type element struct {
+	name     string
+	attrs    []attribute
+	text     string
+	children []*element
+}
+
+type attribute struct {
+	name  string
+	value string
+}
+
+func newElement(name string, children ...*element) *element {
+	return &element{name: name, children: children}
+}
+
+func textElement(name, text string) *element {
+	return &element{name: name, text: text}
+}
+
+// attr acrescenta um atributo; as chamadas devem seguir a ordem canônica
+// (declaração de namespace primeiro, depois os nomes em ordem lexicográfica).
+func (e *element) attr(name, value string) *element {
+	e.attrs = append(e.attrs, attribute{name: name, value: value})
+	return e
+}
+
+// add acrescenta filhos, ignorando os nulos usados para grupos opcionais.
+func (e *element) add(children ...*element) *element {
+	for _, child := range children {
+		if child != nil {
+			e.children = append(e.children, child)
+		}
+	}
+	return e
+}
+
+func (e *element) String() string {
+	var b strings.Builder
+	e.write(&b, "")
+	return b.String()
+}
+
+// canonical escreve o elemento como raiz de um subconjunto do documento,
+// herdando a declaração do namespace padrão do elemento pai.
+func (e *element) canonical(namespace string) string {
+	var b strings.Builder
+	e.write(&b, namespace)
+	return b.String()
+}
+
+func (e *element) write(b *strings.Builder, namespace string) {
+	b.WriteString("<" + e.name)
+	if namespace != "" {
+		b.WriteString(` xmlns="` + escapeAttr(namespace) + `"`)
+	}
+	for _, a := range e.attrs {
+		b.WriteString(" " + a.name + `="` + escapeAttr(a.value) + `"`)
+	}
+	b.WriteString(">")
+	b.WriteString(escapeText(e.text))
+	for _, child := range e.children {
+		child.write(b, "")
+	}
+	b.WriteString("</" + e.name + ">")
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
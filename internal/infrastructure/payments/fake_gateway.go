@@ -0,0 +1,156 @@
+package payments
+
+import (
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const FakeGatewayName = "fake"
+
+// FakeGateway simula um provedor de pagamentos em memória, para testes e
+// execução local. As notificações são assinadas com HMAC-SHA256 como em um
+// provedor real, e Settle produz a notificação que o provedor enviaria.
+type FakeGateway struct {
+	secret   []byte
+	merchant pix.Merchant
+
+	mu      sync.Mutex
+	charges map[string]*payment.GatewayCharge
+}
+
+type fakeWebhookBody struct {
+	ID       string         `json:"id"`
+	ChargeID string         `json:"charge_id"`
+	Status   payment.Status `json:"status"`
+}
+
+// NewFakeGateway cria o gateway simulado. Quando o recebedor PIX estiver
+// configurado, as cobranças PIX trazem um BR Code válido.
+func NewFakeGateway(secret string, merchant pix.Merchant) *FakeGateway {
+	return &FakeGateway{
+		secret:   []byte(secret),
+		merchant: merchant,
+		charges:  make(map[string]*payment.GatewayCharge),
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeGatewayName
+}
+
+func (g *FakeGateway) CreateCharge(ctx context.Context, req payment.ChargeRequest) (*payment.GatewayCharge, error) {
+	charge := &payment.GatewayCharge{
+		ID:     "fake_" + uuid.NewString(),
+		Status: payment.StatusPending,
+		Amount: req.Amount,
+	}
+
+	switch req.Method {
+	case sale.PaymentMethodPix:
+		if g.merchant.Validate() == nil {
+			payload, err := pix.Payload{Merchant: g.merchant, Amount: req.Amount, TxID: req.Reference}.Encode()
+			if err != nil {
+				return nil, err
+			}
+			charge.PixPayload = payload
+		}
+	case sale.PaymentMethodCreditCard, sale.PaymentMethodDebitCard:
+		charge.CheckoutURL = "https://fake-gateway.local/checkout/" + charge.ID
+	default:
+		return nil, payment.ErrChargeMethodInvalid
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.charges[charge.ID] = charge
+	found := *charge
+	return &found, nil
+}
+
+func (g *FakeGateway) GetCharge(ctx context.Context, id string) (*payment.GatewayCharge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, exists := g.charges[id]
+	if !exists {
+		return nil, payment.ErrChargeNotFound
+	}
+	found := *charge
+	return &found, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, id string) (*payment.GatewayCharge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, exists := g.charges[id]
+	if !exists {
+		return nil, payment.ErrChargeNotFound
+	}
+	if charge.Status != payment.StatusConfirmed {
+		return nil, payment.ErrChargeNotRefundable
+	}
+	charge.Status = payment.StatusRefunded
+	found := *charge
+	return &found, nil
+}
+
+func (g *FakeGateway) ParseWebhook(payload []byte, signature string) (*payment.WebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, g.mac(payload)) {
+		return nil, payment.ErrWebhookSignatureInvalid
+	}
+
+	var body fakeWebhookBody
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, payment.ErrWebhookPayloadInvalid
+	}
+	if body.ID == "" || body.ChargeID == "" || !body.Status.IsValid() {
+		return nil, payment.ErrWebhookPayloadInvalid
+	}
+	return &payment.WebhookEvent{ID: body.ID, ChargeID: body.ChargeID, Status: body.Status}, nil
+}
+
+// Settle altera o status da cobrança como o cliente faria ao pagar e devolve
+// a notificação assinada correspondente.
+func (g *FakeGateway) Settle(ctx context.Context, id string, status payment.Status) ([]byte, string, error) {
+	g.mu.Lock()
+	charge, exists := g.charges[id]
+	if !exists {
+		g.mu.Unlock()
+		return nil, "", payment.ErrChargeNotFound
+	}
+	charge.Status = status
+	g.mu.Unlock()
+
+	// O ID do evento é único entre instâncias e reinícios, como num provedor
+	// real, para que a deduplicação das notificações não descarte eventos
+	// novos.
+	body := fakeWebhookBody{ID: "evt_" + uuid.NewString(), ChargeID: id, Status: status}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, g.Sign(payload), nil
+}
+
+// Sign devolve a assinatura que acompanha uma notificação.
+func (g *FakeGateway) Sign(payload []byte) string {
+	return hex.EncodeToString(g.mac(payload))
+}
+
+func (g *FakeGateway) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, g.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
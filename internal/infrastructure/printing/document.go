@@ -0,0 +1,135 @@
+package printing
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type alignment byte
+
+const (
+	alignLeft alignment = iota
+	alignCenter
+	alignRight
+)
+
+type line struct {
+	text  string
+	align alignment
+	bold  bool
+	large bool
+}
+
+// document é a representação intermediária de um comprovante, convertida
+// depois para texto puro ou para comandos ESC/POS.
+type document struct {
+	width int
+	lines []line
+}
+
+func newDocument(width int) *document {
+	return &document{width: width}
+}
+
+// columns devolve a largura útil da linha; texto ampliado ocupa o dobro.
+func (d *document) columns(large bool) int {
+	if large {
+		return d.width / 2
+	}
+	return d.width
+}
+
+func (d *document) add(text string, align alignment, bold, large bool) {
+	for _, wrapped := range wrap(text, d.columns(large)) {
+		d.lines = append(d.lines, line{text: wrapped, align: align, bold: bold, large: large})
+	}
+}
+
+func (d *document) text(text string) {
+	d.add(text, alignLeft, false, false)
+}
+
+func (d *document) centered(text string) {
+	d.add(text, alignCenter, false, false)
+}
+
+func (d *document) title(text string) {
+	d.add(text, alignCenter, true, true)
+}
+
+func (d *document) separator() {
+	d.lines = append(d.lines, line{text: strings.Repeat("-", d.width)})
+}
+
+func (d *document) blank() {
+	d.lines = append(d.lines, line{})
+}
+
+// pair escreve um texto à esquerda e um valor alinhado à direita, quebrando
+// o texto quando não houver espaço para os dois na mesma linha.
+func (d *document) pair(left, right string, bold bool) {
+	width := d.columns(false)
+	rightLen := utf8.RuneCountInString(right)
+	leftWidth := width - rightLen - 1
+	if leftWidth < 1 {
+		d.add(left, alignLeft, bold, false)
+		d.add(right, alignRight, bold, false)
+		return
+	}
+
+	wrapped := wrap(left, leftWidth)
+	for _, text := range wrapped[:len(wrapped)-1] {
+		d.lines = append(d.lines, line{text: text, bold: bold})
+	}
+	last := wrapped[len(wrapped)-1]
+	padding := width - utf8.RuneCountInString(last) - rightLen
+	d.lines = append(d.lines, line{text: last + strings.Repeat(" ", padding) + right, bold: bold})
+}
+
+// wrap quebra o texto em linhas de até width caracteres, preferindo espaços.
+func wrap(text string, width int) []string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= width:
+			current = append(append(current, ' '), runes...)
+		default:
+			lines = append(lines, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 || len(lines) == 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+func pad(text string, width int, align alignment) string {
+	free := width - utf8.RuneCountInString(text)
+	if free <= 0 {
+		return text
+	}
+	switch align {
+	case alignCenter:
+		return strings.Repeat(" ", free/2) + text
+	case alignRight:
+		return strings.Repeat(" ", free) + text
+	}
+	return text
+}
@@ -0,0 +1,105 @@
+package printing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// AccentMode define como os caracteres acentuados são enviados à impressora.
+type AccentMode string
+
+const (
+	// AccentsCP850 seleciona a página de código PC850, que cobre o português.
+	AccentsCP850 AccentMode = "cp850"
+	// AccentsASCII remove os acentos, para impressoras sem PC850.
+	AccentsASCII AccentMode = "ascii"
+)
+
+var ErrAccentModeInvalid = errors.New("modo de acentuação inválido: use cp850 ou ascii")
+
+func (m AccentMode) IsValid() bool {
+	return m == AccentsCP850 || m == AccentsASCII
+}
+
+const (
+	esc = 0x1B
+	gs  = 0x1D
+
+	codePagePC850 = 2
+	feedBeforeCut = 4
+)
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "Ê", "E", "È", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+	"º", "o", "ª", "a", "°", "o",
+)
+
+// encodeESCPOS converte o documento em comandos ESC/POS: inicializa a
+// impressora, aplica alinhamento, negrito e tamanho por linha e corta o papel.
+func encodeESCPOS(d *document, accents AccentMode) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{esc, '@'})
+	if accents == AccentsCP850 {
+		buf.Write([]byte{esc, 't', codePagePC850})
+	}
+
+	for _, l := range d.lines {
+		buf.Write([]byte{esc, 'a', byte(l.align)})
+		buf.Write([]byte{esc, 'E', boolByte(l.bold)})
+		size := byte(0x00)
+		if l.large {
+			size = 0x11
+		}
+		buf.Write([]byte{gs, '!', size})
+		buf.Write(encodeString(l.text, accents))
+		buf.WriteByte('\n')
+	}
+
+	buf.Write([]byte{esc, 'E', 0, gs, '!', 0, esc, 'a', 0})
+	buf.Write([]byte{esc, 'd', feedBeforeCut})
+	buf.Write([]byte{gs, 'V', 1})
+	return buf.Bytes()
+}
+
+func encodeString(text string, accents AccentMode) []byte {
+	if accents == AccentsASCII {
+		text = accentReplacer.Replace(text)
+	}
+
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x80 {
+			out = append(out, byte(r))
+			continue
+		}
+		if accents == AccentsCP850 {
+			if b, ok := charmap.CodePage850.EncodeRune(r); ok {
+				out = append(out, b)
+				continue
+			}
+		}
+		out = append(out, '?')
+	}
+	return out
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,168 @@
+package printing
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	pdfFontSize   = 9.0
+	pdfCharWidth  = pdfFontSize * 0.6 // largura fixa dos glifos da Courier
+	pdfLineHeight = pdfFontSize * 1.3
+	pdfMargin     = 24.0
+
+	// Dimensões de uma folha A4 em pontos.
+	a4Width  = 595.0
+	a4Height = 842.0
+)
+
+// pdfPage define o tamanho da página. Largura zero ajusta a página ao
+// documento e altura zero gera uma única página contínua, como uma bobina.
+type pdfPage struct {
+	Width  float64
+	Height float64
+}
+
+var pdfA4 = pdfPage{Width: a4Width, Height: a4Height}
+
+// encodePDF gera um PDF mínimo com as fontes padrão Courier, que dispensam
+// embutir arquivos de fonte e preservam o alinhamento em colunas do documento.
+func encodePDF(d *document, page pdfPage) []byte {
+	contentWidth := float64(d.width) * pdfCharWidth
+	if page.Width == 0 {
+		page.Width = contentWidth + 2*pdfMargin
+	}
+	left := (page.Width - contentWidth) / 2
+
+	pages := paginate(d.lines, page.Height)
+	if page.Height == 0 {
+		var height float64
+		for _, l := range d.lines {
+			height += lineHeight(l)
+		}
+		page.Height = height + 2*pdfMargin
+	}
+
+	w := &pdfWriter{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos fixos: catálogo, árvore de páginas e as duas fontes; em seguida
+	// cada página ocupa dois objetos (página e conteúdo).
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	w.object("<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		w.object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(page.Width), pdfNumber(page.Height), 6+2*i,
+		))
+		content := pageContent(lines, d.width, left, page.Height)
+		w.object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	return w.finish()
+}
+
+// paginate distribui as linhas em páginas da altura informada.
+func paginate(lines []line, pageHeight float64) [][]line {
+	if pageHeight == 0 || len(lines) == 0 {
+		return [][]line{lines}
+	}
+
+	var pages [][]line
+	var current []line
+	available := pageHeight - 2*pdfMargin
+	used := 0.0
+	for _, l := range lines {
+		if used+lineHeight(l) > available && len(current) > 0 {
+			pages = append(pages, current)
+			current, used = nil, 0
+		}
+		current = append(current, l)
+		used += lineHeight(l)
+	}
+	return append(pages, current)
+}
+
+func pageContent(lines []line, width int, left, pageHeight float64) []byte {
+	var buf bytes.Buffer
+	top := pageHeight - pdfMargin
+	for _, l := range lines {
+		size := pdfFontSize
+		columns := width
+		if l.large {
+			size *= 2
+			columns /= 2
+		}
+		top -= lineHeight(l)
+
+		text := strings.TrimRight(pad(l.text, columns, l.align), " ")
+		if text == "" {
+			continue
+		}
+		font := 1
+		if l.bold {
+			font = 2
+		}
+		fmt.Fprintf(&buf, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+			font, pdfNumber(size), pdfNumber(left), pdfNumber(top+size*0.25), pdfString(text))
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+func lineHeight(l line) float64 {
+	if l.large {
+		return 2 * pdfLineHeight
+	}
+	return pdfLineHeight
+}
+
+// pdfString converte o texto para WinAnsi e escapa os delimitadores de string.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func pdfNumber(value float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+}
+
+// pdfWriter acumula os objetos e monta a tabela de referências cruzadas.
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *pdfWriter) object(body string) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", len(w.offsets), body)
+}
+
+func (w *pdfWriter) finish() []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
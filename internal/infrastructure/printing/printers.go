@@ -0,0 +1,54 @@
+package printing
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+)
+
+const defaultNetworkTimeout = 5 * time.Second
+
+// DevicePrinter escreve no arquivo de dispositivo da impressora, como
+// /dev/usb/lp0.
+type DevicePrinter struct {
+	Path string
+}
+
+func (p *DevicePrinter) Print(ctx context.Context, data []byte) error {
+	f, err := os.OpenFile(p.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// NetworkPrinter envia os dados para uma impressora de rede (normalmente na
+// porta 9100) por TCP.
+type NetworkPrinter struct {
+	Address string
+	Timeout time.Duration
+}
+
+func (p *NetworkPrinter) Print(ctx context.Context, data []byte) error {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = defaultNetworkTimeout
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
+	return err
+}
@@ -0,0 +1,54 @@
+package printing
+
+import (
+	"andressa-lanches/internal/domain/receipt"
+	"context"
+	"log"
+	"sync"
+)
+
+// PrintQueue envia os documentos à impressora em segundo plano, um por vez,
+// para que as requisições não esperem pelo equipamento.
+type PrintQueue struct {
+	printer receipt.Printer
+	jobs    chan []byte
+	wg      sync.WaitGroup
+}
+
+func NewPrintQueue(printer receipt.Printer, size int) *PrintQueue {
+	if size <= 0 {
+		size = 1
+	}
+	q := &PrintQueue{
+		printer: printer,
+		jobs:    make(chan []byte, size),
+	}
+
+	q.wg.Add(1)
+	go q.run()
+	return q
+}
+
+func (q *PrintQueue) Print(ctx context.Context, data []byte) error {
+	select {
+	case q.jobs <- data:
+		return nil
+	default:
+		return receipt.ErrPrintQueueFull
+	}
+}
+
+// Close encerra a fila depois de imprimir os documentos pendentes.
+func (q *PrintQueue) Close() {
+	close(q.jobs)
+	q.wg.Wait()
+}
+
+func (q *PrintQueue) run() {
+	defer q.wg.Done()
+	for data := range q.jobs {
+		if err := q.printer.Print(context.Background(), data); err != nil {
+			log.Printf("Falha ao imprimir: %v", err)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package printing
+
+import (
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Width58mm e Width80mm são as colunas da fonte padrão nas bobinas usuais.
+	Width58mm = 32
+	Width80mm = 48
+
+	// reportPDFWidth é a largura, em colunas, dos relatórios em folha A4.
+	reportPDFWidth = 80
+
+	dateLayout     = "02/01/2006"
+	dateTimeLayout = "02/01/2006 15:04"
+	timeLayout     = "15:04"
+)
+
+// Layout personaliza os comprovantes: largura em colunas, linhas de
+// cabeçalho e rodapé do cupom e tratamento de acentos.
+// Location é o fuso em que os horários são impressos; nulo usa o fuso do
+// servidor.
+type Layout struct {
+	Width    int
+	Header   []string
+	Footer   []string
+	Accents  AccentMode
+	Location *time.Location
+}
+
+type Renderer struct {
+	layout Layout
+}
+
+func NewRenderer(layout Layout) *Renderer {
+	if layout.Width <= 0 {
+		layout.Width = Width80mm
+	}
+	if layout.Accents == "" {
+		layout.Accents = AccentsCP850
+	}
+	if layout.Location == nil {
+		layout.Location = time.Local
+	}
+	return &Renderer{layout: layout}
+}
+
+func (r *Renderer) Render(s *sale.Sale, opts receipt.Options) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var doc *document
+	switch opts.Kind {
+	case receipt.KindKitchen:
+		doc = r.kitchenTicket(s, opts)
+	default:
+		doc = r.customerReceipt(s)
+	}
+
+	return r.encode(doc, opts.Format, pdfPage{})
+}
+
+func (r *Renderer) RenderDailyClosing(c *sale.DailyClosing, format receipt.Format) ([]byte, error) {
+	if !format.IsValid() {
+		return nil, receipt.ErrFormatInvalid
+	}
+
+	width := r.layout.Width
+	if format == receipt.FormatPDF {
+		width = reportPDFWidth
+	}
+	return r.encode(r.dailyClosing(c, width), format, pdfA4)
+}
+
+func (r *Renderer) encode(doc *document, format receipt.Format, page pdfPage) ([]byte, error) {
+	switch format {
+	case receipt.FormatText:
+		return encodeText(doc), nil
+	case receipt.FormatESCPOS:
+		return encodeESCPOS(doc, r.layout.Accents), nil
+	case receipt.FormatPDF:
+		return encodePDF(doc, page), nil
+	}
+	return nil, receipt.ErrFormatInvalid
+}
+
+func (r *Renderer) header(doc *document) {
+	for i, header := range r.layout.Header {
+		if i == 0 {
+			doc.add(header, alignCenter, true, false)
+			continue
+		}
+		doc.centered(header)
+	}
+	if len(r.layout.Header) > 0 {
+		doc.separator()
+	}
+}
+
+func (r *Renderer) customerReceipt(s *sale.Sale) *document {
+	doc := newDocument(r.layout.Width)
+	r.header(doc)
+
+	doc.pair(orderLabel(s), s.Date.In(r.layout.Location).Format(dateTimeLayout), true)
+	doc.text(orderTypeLabel(s.OrderType))
+	if s.Employee != "" {
+		doc.text("Atendente: " + s.Employee)
+	}
+	doc.separator()
+
+	var subtotal float64
+	for _, item := range s.Items {
+		doc.pair(fmt.Sprintf("%dx %s", item.Quantity, itemName(item)), FormatMoney(item.TotalPrice), false)
+		doc.text("   un. " + FormatMoney(item.UnitPrice))
+		for _, add := range item.Additions {
+			doc.pair("   + "+add.Name, FormatMoney(add.Price), false)
+		}
+		if item.Notes != "" {
+			doc.text("   Obs: " + item.Notes)
+		}
+		subtotal += item.TotalPrice
+	}
+	doc.separator()
+
+	doc.pair("Subtotal", FormatMoney(subtotal), false)
+	if s.Discount != 0 {
+		doc.pair("Desconto", "-"+FormatMoney(s.Discount), false)
+	}
+	if s.AdditionalCharges != 0 {
+		doc.pair("Acréscimos", FormatMoney(s.AdditionalCharges), false)
+	}
+	if s.ServiceCharge != 0 {
+		doc.pair("Taxa de serviço", FormatMoney(s.ServiceCharge), false)
+	}
+	doc.pair("TOTAL", "R$ "+FormatMoney(s.TotalAmount), true)
+
+	if len(s.Payments) > 0 {
+		doc.separator()
+		for _, p := range s.Payments {
+			doc.pair(paymentMethodLabel(p.Method), FormatMoney(p.Amount), false)
+		}
+		if change := s.Change(); change > 0 {
+			doc.pair("Troco", FormatMoney(change), false)
+		}
+	}
+
+	if len(r.layout.Footer) > 0 {
+		doc.separator()
+		for _, footer := range r.layout.Footer {
+			doc.centered(footer)
+		}
+	}
+	return doc
+}
+
+func (r *Renderer) kitchenTicket(s *sale.Sale, opts receipt.Options) *document {
+	doc := newDocument(r.layout.Width)
+
+	doc.title(strings.ToUpper(orderLabel(s)))
+	doc.add(orderTypeLabel(s.OrderType), alignCenter, true, false)
+	doc.centered(s.Date.In(r.layout.Location).Format(timeLayout))
+	doc.separator()
+
+	for _, item := range kitchenItems(s, opts) {
+		doc.add(fmt.Sprintf("%dx %s", item.Quantity, itemName(item)), alignLeft, true, true)
+		for _, add := range item.Additions {
+			doc.text("   + " + add.Name)
+		}
+		if item.Notes != "" {
+			doc.add("   OBS: "+item.Notes, alignLeft, true, false)
+		}
+		doc.blank()
+	}
+	doc.separator()
+	return doc
+}
+
+func (r *Renderer) dailyClosing(c *sale.DailyClosing, width int) *document {
+	doc := newDocument(width)
+	r.header(doc)
+
+	doc.add("FECHAMENTO DO CAIXA", alignCenter, true, false)
+	doc.centered(c.BusinessDate.Format(dateLayout))
+	doc.separator()
+
+	doc.pair("Vendas", strconv.Itoa(c.SalesCount), false)
+	if c.CanceledCount > 0 {
+		doc.pair("Vendas canceladas", strconv.Itoa(c.CanceledCount), false)
+	}
+	doc.pair("Itens", FormatMoney(c.ItemsAmount), false)
+	if c.Discount != 0 {
+		doc.pair("Descontos", "-"+FormatMoney(c.Discount), false)
+	}
+	if c.AdditionalCharges != 0 {
+		doc.pair("Acréscimos", FormatMoney(c.AdditionalCharges), false)
+	}
+	if c.ServiceCharge != 0 {
+		doc.pair("Taxa de serviço", FormatMoney(c.ServiceCharge), false)
+	}
+	doc.pair("TOTAL", "R$ "+FormatMoney(c.TotalAmount), true)
+	doc.pair("Ticket médio", FormatMoney(c.AverageTicket), false)
+
+	if len(c.OrderTypes) > 0 {
+		doc.separator()
+		doc.add("Tipos de pedido", alignLeft, true, false)
+		for _, t := range c.OrderTypes {
+			doc.pair(fmt.Sprintf("%s (%d)", orderTypeLabel(t.OrderType), t.SalesCount), FormatMoney(t.TotalAmount), false)
+		}
+	}
+
+	if len(c.Payments) > 0 || c.Unpaid > 0 {
+		doc.separator()
+		doc.add("Formas de pagamento", alignLeft, true, false)
+		for _, p := range c.Payments {
+			doc.pair(paymentMethodLabel(p.Method), FormatMoney(p.Amount), false)
+		}
+		if c.Unpaid > 0 {
+			doc.pair("Sem pagamento registrado", FormatMoney(c.Unpaid), false)
+		}
+	}
+
+	if len(c.Products) > 0 {
+		doc.separator()
+		doc.add("Produtos vendidos", alignLeft, true, false)
+		for _, p := range c.Products {
+			name := p.ProductName
+			if name == "" {
+				name = "Produto " + p.ProductID.String()[:8]
+			}
+			doc.pair(fmt.Sprintf("%dx %s", p.Quantity, name), FormatMoney(p.TotalAmount), false)
+		}
+	}
+
+	doc.separator()
+	doc.centered("Emitido em " + time.Now().In(r.layout.Location).Format(dateTimeLayout))
+	return doc
+}
+
+// kitchenItems devolve os itens da comanda, restritos à estação pedida.
+func kitchenItems(s *sale.Sale, opts receipt.Options) []sale.SaleItem {
+	if opts.StationID == nil {
+		return s.Items
+	}
+	for _, ticket := range s.Tickets() {
+		if ticket.StationID != nil && *ticket.StationID == *opts.StationID {
+			return ticket.Items
+		}
+	}
+	return nil
+}
+
+func orderLabel(s *sale.Sale) string {
+	if s.OrderNumber > 0 {
+		return "Pedido " + strconv.Itoa(s.OrderNumber)
+	}
+	return "Pedido " + s.ID.String()[:8]
+}
+
+func orderTypeLabel(t sale.OrderType) string {
+	switch t {
+	case sale.OrderTypeDineIn:
+		return "Salão"
+	case sale.OrderTypeDelivery:
+		return "Entrega"
+	}
+	return "Para viagem"
+}
+
+func paymentMethodLabel(m sale.PaymentMethod) string {
+	switch m {
+	case sale.PaymentMethodCash:
+		return "Dinheiro"
+	case sale.PaymentMethodPix:
+		return "PIX"
+	case sale.PaymentMethodCreditCard:
+		return "Cartão de crédito"
+	case sale.PaymentMethodDebitCard:
+		return "Cartão de débito"
+	}
+	return string(m)
+}
+
+func itemName(item sale.SaleItem) string {
+	if item.ProductName != "" {
+		return item.ProductName
+	}
+	return "Produto " + item.ProductID.String()[:8]
+}
+
+// FormatMoney formata um valor no padrão brasileiro, por exemplo 1.234,50.
+func FormatMoney(value float64) string {
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	integer, decimals := formatted[:len(formatted)-3], formatted[len(formatted)-2:]
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String() + "," + decimals
+	if negative {
+		return "-" + result
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package printing
+
+import (
+	"bytes"
+	"strings"
+)
+
+// encodeText gera o comprovante em texto puro UTF-8, útil para pré-visualização
+// e para envio por mensagem.
+func encodeText(d *document) []byte {
+	var buf bytes.Buffer
+	for _, l := range d.lines {
+		text := l.text
+		if l.large {
+			text = strings.ToUpper(text)
+		}
+		buf.WriteString(strings.TrimRight(pad(text, d.width, l.align), " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
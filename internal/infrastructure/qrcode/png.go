@@ -0,0 +1,50 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+const quietZone = 4
+
+// PNG desenha o código em uma imagem quadrada de aproximadamente size pixels,
+// incluindo a zona de silêncio de quatro módulos exigida pelos leitores.
+func (c *Code) PNG(size int) ([]byte, error) {
+	modules := c.size + 2*quietZone
+	scale := max(size/modules, 1)
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, modules*scale, modules*scale), palette)
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PNGEncoder gera a imagem PNG do código QR de um conteúdo.
+type PNGEncoder struct{}
+
+func (PNGEncoder) EncodePNG(content string, size int) ([]byte, error) {
+	code, err := Encode(content)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(size)
+}
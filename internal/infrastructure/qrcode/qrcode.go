@@ -0,0 +1,465 @@
+// Package qrcode gera códigos QR em modo byte com correção de erros nível M,
+// suficiente para os BR Codes do PIX, sem depender de bibliotecas externas.
+package qrcode
+
+import (
+	"errors"
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+
+	// Bits do nível de correção M no campo de formato.
+	formatBitsLevelM = 0
+)
+
+var ErrContentTooLong = errors.New("conteúdo longo demais para um código QR")
+
+// Quantidade de bytes de correção por bloco e de blocos, por versão, para o
+// nível M (índice 0 não utilizado).
+var (
+	eccCodewordsPerBlock = [maxVersion + 1]int{
+		-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+		26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+	}
+	numEccBlocks = [maxVersion + 1]int{
+		-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+		17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49,
+	}
+)
+
+// Code é a matriz de módulos de um código QR; true representa um módulo escuro.
+type Code struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Encode gera o código QR de menor versão capaz de conter o conteúdo.
+func Encode(content string) (*Code, error) {
+	data := []byte(content)
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+8*len(data) <= numDataCodewords(version)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, ErrContentTooLong
+	}
+
+	code := newCode(version)
+	code.drawFunctionPatterns()
+	code.drawCodewords(addEccAndInterleave(encodeData(data, version), version))
+
+	mask, minPenalty := 0, -1
+	for m := 0; m < 8; m++ {
+		code.applyMask(m)
+		code.drawFormatBits(m)
+		if penalty := code.penalty(); minPenalty < 0 || penalty < minPenalty {
+			mask, minPenalty = m, penalty
+		}
+		code.applyMask(m) // desfaz a máscara (XOR)
+	}
+	code.applyMask(mask)
+	code.drawFormatBits(mask)
+	return code, nil
+}
+
+// Version devolve a versão (1 a 40) escolhida para o conteúdo.
+func (c *Code) Version() int {
+	return c.version
+}
+
+// Size devolve a quantidade de módulos de cada lado, sem a zona de silêncio.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark indica se o módulo da coluna x e linha y é escuro.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{version: version, size: size}
+	c.modules = make([][]bool, size)
+	c.function = make([][]bool, size)
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+	return c
+}
+
+// encodeData monta o segmento em modo byte com terminador e bytes de
+// preenchimento até a capacidade da versão.
+func encodeData(data []byte, version int) []byte {
+	capacity := numDataCodewords(version) * 8
+
+	var bits bitBuffer
+	bits.append(0x4, 4) // modo byte
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// addEccAndInterleave divide os dados em blocos, calcula a correção de cada
+// um e intercala os bytes na ordem em que são gravados na matriz.
+func addEccAndInterleave(data []byte, version int) []byte {
+	numBlocks := numEccBlocks[version]
+	blockEccLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := append([]byte{}, data[k:k+dataLen]...)
+		k += dataLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			// Os blocos curtos têm um byte fictício que não é gravado.
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.size-4, 3)
+	c.drawFinderPattern(3, c.size-4)
+
+	positions := alignmentPatternPositions(c.version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// As posições que coincidem com os padrões localizadores são ignoradas.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignmentPattern(x, y)
+		}
+	}
+
+	c.drawFormatBits(0) // reserva a área; o valor final é gravado após a máscara
+	c.drawVersion()
+}
+
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < c.size && yy >= 0 && yy < c.size {
+				c.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits grava as duas cópias do nível de correção e da máscara,
+// protegidas por um código BCH(15,5).
+func (c *Code) drawFormatBits(mask int) {
+	data := formatBitsLevelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.size-8, true) // módulo escuro fixo
+}
+
+// drawVersion grava a versão nos dois blocos 6x3, a partir da versão 7.
+func (c *Code) drawVersion() {
+	if c.version < 7 {
+		return
+	}
+	rem := c.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords percorre a matriz em colunas duplas, em zigue-zague da
+// direita para a esquerda, preenchendo os módulos que não são de função.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert
+				}
+				if !c.function[y][x] && i < len(data)*8 {
+					c.modules[y][x] = bit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.function[y][x] && maskApplies(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+func maskApplies(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	}
+	return ((x+y)%2+x*y%3)%2 == 0
+}
+
+// penalty avalia a matriz pelas quatro regras da norma; a máscara com menor
+// pontuação é a mais fácil de ler.
+func (c *Code) penalty() int {
+	penalty := 0
+
+	line := make([]bool, c.size)
+	for i := 0; i < c.size; i++ {
+		penalty += linePenalty(c.modules[i])
+		for j := 0; j < c.size; j++ {
+			line[j] = c.modules[j][i]
+		}
+		penalty += linePenalty(line)
+	}
+
+	for y := 0; y < c.size-1; y++ {
+		for x := 0; x < c.size-1; x++ {
+			color := c.modules[y][x]
+			if color == c.modules[y][x+1] && color == c.modules[y+1][x] && color == c.modules[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+
+	dark := 0
+	for _, row := range c.modules {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	total := c.size * c.size
+	deviation := abs(dark*100/total - 50)
+	penalty += deviation / 5 * 10
+
+	return penalty
+}
+
+// linePenalty aplica as regras de sequências de mesma cor e de padrões
+// semelhantes aos localizadores em uma linha ou coluna.
+func linePenalty(modules []bool) int {
+	penalty := 0
+
+	run := 1
+	for i := 1; i <= len(modules); i++ {
+		if i < len(modules) && modules[i] == modules[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += 3 + run - 5
+		}
+		run = 1
+	}
+
+	finderLike := []bool{true, false, true, true, true, false, true}
+	for i := 0; i+len(finderLike) <= len(modules); i++ {
+		if !matches(modules[i:], finderLike) {
+			continue
+		}
+		end := i + len(finderLike)
+		if lightRun(modules, i-4, i) || lightRun(modules, end, end+4) {
+			penalty += 40
+		}
+	}
+	return penalty
+}
+
+func matches(modules, pattern []bool) bool {
+	for i, p := range pattern {
+		if modules[i] != p {
+			return false
+		}
+	}
+	return true
+}
+
+// lightRun indica se o intervalo [from, to) é claro; posições fora da matriz
+// contam como zona de silêncio.
+func lightRun(modules []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(modules) && modules[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	}
+
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// numRawDataModules conta os módulos disponíveis para dados e correção,
+// descontados os padrões de função.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[version]*numEccBlocks[version]
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, bit(value, i))
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, set := range b {
+		if set {
+			result[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return result
+}
+
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,193 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomon_KnownVector(t *testing.T) {
+	// Exemplo "HELLO WORLD" 1-M da norma.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+
+	ecc := reedSolomonRemainder(data, reedSolomonDivisor(10))
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ecc)
+}
+
+func TestNumDataCodewords(t *testing.T) {
+	assert.Equal(t, 16, numDataCodewords(1))
+	assert.Equal(t, 216, numDataCodewords(10))
+	assert.Equal(t, 2334, numDataCodewords(40))
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	code := newCode(7)
+	code.drawFormatBits(0)
+	code.drawVersion()
+
+	assert.Equal(t, 0x5412, readFormatBits(code))
+
+	var version int
+	for i := 17; i >= 0; i-- {
+		version <<= 1
+		if code.Dark(code.size-11+i%3, i/3) {
+			version |= 1
+		}
+	}
+	assert.Equal(t, 0x07C94, version)
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	contents := []string{
+		"A",
+		"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		strings.Repeat("andressa-lanches ", 40),
+	}
+
+	for _, content := range contents {
+		code, err := Encode(content)
+		require.NoError(t, err)
+		assert.Equal(t, code.Version()*4+17, code.Size())
+		assert.Equal(t, content, decode(t, code))
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	_, err := Encode(strings.Repeat("x", 2332))
+
+	assert.Equal(t, ErrContentTooLong, err)
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode("PIX")
+	require.NoError(t, err)
+
+	data, err := code.PNG(300)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	// 21 módulos mais a zona de silêncio, 10 pixels por módulo
+	assert.Equal(t, 290, img.Bounds().Dx())
+}
+
+func readFormatBits(c *Code) int {
+	var bits int
+	set := func(i int, dark bool) {
+		if dark {
+			bits |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		set(i, c.Dark(8, i))
+	}
+	set(6, c.Dark(8, 7))
+	set(7, c.Dark(8, 8))
+	set(8, c.Dark(7, 8))
+	for i := 9; i < 15; i++ {
+		set(i, c.Dark(14-i, 8))
+	}
+	return bits
+}
+
+// decode lê a matriz como um leitor faria: identifica a máscara, remove-a,
+// separa os blocos, confere a correção de erros e interpreta o modo byte.
+func decode(t *testing.T, c *Code) string {
+	format := readFormatBits(c) ^ 0x5412
+	require.Equal(t, formatBitsLevelM, format>>13, "nível de correção")
+	mask := (format >> 10) & 7
+
+	// Reconstrói o mapa de módulos de função em uma matriz limpa.
+	reference := newCode(c.version)
+	reference.drawFunctionPatterns()
+
+	var codewords []byte
+	var current byte
+	count := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert
+				}
+				if reference.function[y][x] {
+					continue
+				}
+				dark := c.Dark(x, y) != maskApplies(mask, x, y)
+				current <<= 1
+				if dark {
+					current |= 1
+				}
+				if count++; count%8 == 0 {
+					codewords = append(codewords, current)
+					current = 0
+				}
+			}
+		}
+	}
+
+	numBlocks := numEccBlocks[c.version]
+	blockEccLen := eccCodewordsPerBlock[c.version]
+	rawCodewords := numRawDataModules(c.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortDataLen := rawCodewords/numBlocks - blockEccLen
+	require.Len(t, codewords, rawCodewords)
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortDataLen+1; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < blockEccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+
+	var data []byte
+	divisor := reedSolomonDivisor(blockEccLen)
+	for _, block := range blocks {
+		dataLen := len(block) - blockEccLen
+		require.Equal(t, block[dataLen:], reedSolomonRemainder(block[:dataLen], divisor))
+		data = append(data, block[:dataLen]...)
+	}
+
+	reader := bitReader{data: data}
+	require.Equal(t, 0x4, reader.read(4), "modo byte")
+	length := reader.read(charCountBits(c.version))
+	content := make([]byte, length)
+	for i := range content {
+		content[i] = byte(reader.read(8))
+	}
+	return string(content)
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(length int) int {
+	value := 0
+	for i := 0; i < length; i++ {
+		value = value<<1 | int(r.data[r.pos>>3]>>(7-r.pos&7)&1)
+		r.pos++
+	}
+	return value
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"context"
 
 	"github.com/google/uuid"
@@ -17,24 +19,38 @@ func NewAdditionRepository(pool *pgxpool.Pool) *AdditionRepository {
 	return &AdditionRepository{Pool: pool}
 }
 
-func (r *AdditionRepository) Create(ctx context.Context, a *addition.Addition) error {
+func (r *AdditionRepository) Create(ctx context.Context, a *addition.Addition, change *pricing.PriceChange, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
-        INSERT INTO additions (name, price)
-        VALUES ($1, $2)
-        RETURNING id
+        INSERT INTO additions (id, name, price, unit_cost, external_code)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''))
     `
-	err := r.Pool.QueryRow(ctx, query, a.Name, a.Price).Scan(&a.ID)
-	return err
+	if _, err = tx.Exec(ctx, query, a.ID, a.Name, a.Price, a.UnitCost, a.ExternalCode); err != nil {
+		return err
+	}
+	if err = insertHistory(ctx, tx, change, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *AdditionRepository) GetByID(ctx context.Context, id uuid.UUID) (*addition.Addition, error) {
 	query := `
-        SELECT id, name, price
+        SELECT ` + additionColumns + `
         FROM additions
         WHERE id = $1
     `
 	a := &addition.Addition{}
-	err := r.Pool.QueryRow(ctx, query, id).Scan(&a.ID, &a.Name, &a.Price)
+	err := r.Pool.QueryRow(ctx, query, id).Scan(additionDest(a)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -44,28 +60,62 @@ func (r *AdditionRepository) GetByID(ctx context.Context, id uuid.UUID) (*additi
 	return a, nil
 }
 
-func (r *AdditionRepository) Update(ctx context.Context, a *addition.Addition) error {
+func (r *AdditionRepository) Update(ctx context.Context, a *addition.Addition, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
         UPDATE additions
-        SET name = $1, price = $2
-        WHERE id = $3
+        SET name = $1, price = $2, unit_cost = $3, external_code = NULLIF($4, '')
+        WHERE id = $5 AND price = $6
     `
-	_, err := r.Pool.Exec(ctx, query, a.Name, a.Price, a.ID)
-	return err
+	result, err := tx.Exec(ctx, query, a.Name, a.Price, a.UnitCost, a.ExternalCode, a.ID, oldPrice)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return addition.ErrAdditionConflict
+	}
+	if err = insertHistory(ctx, tx, change, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *AdditionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *AdditionRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
         DELETE FROM additions
         WHERE id = $1
     `
-	_, err := r.Pool.Exec(ctx, query, id)
-	return err
+	if _, err = tx.Exec(ctx, query, id); err != nil {
+		return err
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *AdditionRepository) List(ctx context.Context) ([]*addition.Addition, error) {
 	query := `
-        SELECT id, name, price
+        SELECT ` + additionColumns + `
         FROM additions
     `
 	rows, err := r.Pool.Query(ctx, query)
@@ -77,7 +127,7 @@ func (r *AdditionRepository) List(ctx context.Context) ([]*addition.Addition, er
 	var additions []*addition.Addition
 	for rows.Next() {
 		a := &addition.Addition{}
-		err := rows.Scan(&a.ID, &a.Name, &a.Price)
+		err := rows.Scan(additionDest(a)...)
 		if err != nil {
 			return nil, err
 		}
@@ -85,3 +135,9 @@ func (r *AdditionRepository) List(ctx context.Context) ([]*addition.Addition, er
 	}
 	return additions, nil
 }
+
+const additionColumns = `id, name, price, unit_cost, COALESCE(external_code, '')`
+
+func additionDest(a *addition.Addition) []any {
+	return []any{&a.ID, &a.Name, &a.Price, &a.UnitCost, &a.ExternalCode}
+}
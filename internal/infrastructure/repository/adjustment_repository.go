@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdjustmentRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewAdjustmentRepository(pool *pgxpool.Pool) *AdjustmentRepository {
+	return &AdjustmentRepository{Pool: pool}
+}
+
+// ApplyAdjustment só troca o preço que ainda é o da prévia, para que uma
+// alteração feita no meio do reajuste não seja sobrescrita em silêncio.
+func (r *AdjustmentRepository) ApplyAdjustment(ctx context.Context, changes []*pricing.PriceChange, entries []*audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	for _, c := range changes {
+		if err = updatePrice(ctx, tx, c); err != nil {
+			return err
+		}
+		if err = insertPriceChange(ctx, tx, c); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range entries {
+		if err = insertAuditEntry(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ApplySchedule resolve o agendamento antes de trocar o preço, para que só
+// uma das execuções concorrentes do agendador o aplique.
+func (r *AdjustmentRepository) ApplySchedule(ctx context.Context, id uuid.UUID, change *pricing.PriceChange, entry *audit.Entry) (applied bool, err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !applied {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	query := `
+        UPDATE scheduled_price_changes
+        SET status = $1, applied_at = $2
+        WHERE id = $3 AND status = $4
+    `
+	result, err := tx.Exec(ctx, query, pricing.ScheduleStatusApplied, change.ChangedAt, id, pricing.ScheduleStatusPending)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err = updatePrice(ctx, tx, change); err != nil {
+		return false, err
+	}
+	if err = insertPriceChange(ctx, tx, change); err != nil {
+		return false, err
+	}
+	if entry != nil {
+		if err = insertAuditEntry(ctx, tx, entry); err != nil {
+			return false, err
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// updatePrice só troca o preço que ainda é OldPrice.
+func updatePrice(ctx context.Context, tx pgx.Tx, c *pricing.PriceChange) error {
+	table := "products"
+	if c.ItemType == pricing.ItemAddition {
+		table = "additions"
+	}
+	query := `
+        UPDATE ` + table + `
+        SET price = $1
+        WHERE id = $2 AND price = $3
+    `
+	result, err := tx.Exec(ctx, query, c.NewPrice, c.ItemID, c.OldPrice)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pricing.ErrAdjustmentConflict
+	}
+	return nil
+}
+
+// insertPriceChange grava a troca de preço dentro da transação de um lote.
+func insertPriceChange(ctx context.Context, tx pgx.Tx, c *pricing.PriceChange) error {
+	query := `
+        INSERT INTO price_history (item_type, item_id, old_price, new_price, changed_by, actor, scheduled_change_id, changed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id
+    `
+	return tx.QueryRow(ctx, query, c.ItemType, c.ItemID, c.OldPrice, c.NewPrice, c.ChangedBy, c.Actor, c.ScheduledChangeID, c.ChangedAt).Scan(&c.ID)
+}
+
+// insertAuditEntry grava o registro de auditoria dentro da transação de um
+// lote.
+func insertAuditEntry(ctx context.Context, tx pgx.Tx, e *audit.Entry) error {
+	query := `
+        INSERT INTO audit_log (action, actor_id, api_key_id, actor, ip, entity_type, entity_id, changes, details, request_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id
+    `
+	return tx.QueryRow(ctx, query, e.Action, e.ActorID, e.APIKeyID, e.Actor, e.IP, e.EntityType, e.EntityID,
+		nullableJSON(e.Changes), nullableJSON(e.Details), e.RequestID, e.CreatedAt).Scan(&e.ID)
+}
+
+// insertHistory grava a troca de preço e o registro de auditoria que
+// acompanham uma alteração do cadastro; os dois podem ser nulos.
+func insertHistory(ctx context.Context, tx pgx.Tx, change *pricing.PriceChange, entry *audit.Entry) error {
+	if change != nil {
+		if err := insertPriceChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+	if entry != nil {
+		return insertAuditEntry(ctx, tx, entry)
+	}
+	return nil
+}
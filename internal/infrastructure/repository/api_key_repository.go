@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/user"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_by, last_used_at, revoked_at, created_at`
+
+type APIKeyRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{Pool: pool}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, k *apikey.APIKey) error {
+	query := `
+        INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query, k.Name, k.Prefix, k.KeyHash, scopeStrings(k.Scopes), k.CreatedBy, k.CreatedAt).Scan(&k.ID)
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	query := `
+        SELECT ` + apiKeyColumns + `
+        FROM api_keys
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	query := `
+        SELECT ` + apiKeyColumns + `
+        FROM api_keys
+        WHERE prefix = $1
+    `
+	return r.get(ctx, query, prefix)
+}
+
+func (r *APIKeyRepository) Update(ctx context.Context, k *apikey.APIKey) error {
+	query := `
+        UPDATE api_keys
+        SET name = $1, prefix = $2, key_hash = $3, scopes = $4, revoked_at = $5
+        WHERE id = $6
+    `
+	_, err := r.Pool.Exec(ctx, query, k.Name, k.Prefix, k.KeyHash, scopeStrings(k.Scopes), k.RevokedAt, k.ID)
+	return err
+}
+
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+        UPDATE api_keys
+        SET last_used_at = $1
+        WHERE id = $2
+    `
+	_, err := r.Pool.Exec(ctx, query, at, id)
+	return err
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	query := `
+        SELECT ` + apiKeyColumns + `
+        FROM api_keys
+        ORDER BY created_at
+    `
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*apikey.APIKey
+	for rows.Next() {
+		var k apikey.APIKey
+		if err := scanAPIKey(rows, &k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepository) get(ctx context.Context, query string, args ...any) (*apikey.APIKey, error) {
+	var k apikey.APIKey
+	err := scanAPIKey(r.Pool.QueryRow(ctx, query, args...), &k)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+func scanAPIKey(row pgx.Row, k *apikey.APIKey) error {
+	var scopes []string
+	if err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &scopes, &k.CreatedBy, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt); err != nil {
+		return err
+	}
+	k.Scopes = make([]user.Permission, len(scopes))
+	for i, scope := range scopes {
+		k.Scopes[i] = user.Permission(scope)
+	}
+	return nil
+}
+
+func scopeStrings(scopes []user.Permission) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return values
+}
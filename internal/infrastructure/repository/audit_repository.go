@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const auditColumns = `id, action, actor_id, api_key_id, actor, ip, entity_type, entity_id, changes, details, request_id, created_at`
+
+type AuditRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{Pool: pool}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	query := `
+        INSERT INTO audit_log (action, actor_id, api_key_id, actor, ip, entity_type, entity_id, changes, details, request_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query, e.Action, e.ActorID, e.APIKeyID, e.Actor, e.IP, e.EntityType, e.EntityID,
+		nullableJSON(e.Changes), nullableJSON(e.Details), e.RequestID, e.CreatedAt).Scan(&e.ID)
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	var conditions []string
+	var args []any
+	where := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if filter.Action != "" {
+		where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.ActorID != nil {
+		where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.RequestID != "" {
+		where("request_id = ?", filter.RequestID)
+	}
+	if !filter.Start.IsZero() {
+		where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		where("created_at < ?", filter.End)
+	}
+
+	query := `
+        SELECT ` + auditColumns + `
+        FROM audit_log`
+	if len(conditions) > 0 {
+		query += `
+        WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += `
+        ORDER BY created_at DESC, id
+        LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := r.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*audit.Entry
+	for rows.Next() {
+		var e audit.Entry
+		if err := scanAuditEntry(rows, &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+func scanAuditEntry(row pgx.Row, e *audit.Entry) error {
+	var changes, details []byte
+	if err := row.Scan(&e.ID, &e.Action, &e.ActorID, &e.APIKeyID, &e.Actor, &e.IP, &e.EntityType, &e.EntityID,
+		&changes, &details, &e.RequestID, &e.CreatedAt); err != nil {
+		return err
+	}
+	e.Changes, e.Details = changes, details
+	return nil
+}
+
+// nullableJSON grava NULL no lugar de um JSON vazio.
+func nullableJSON(value []byte) []byte {
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/catalog"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CatalogRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewCatalogRepository(pool *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{Pool: pool}
+}
+
+// Apply grava as categorias antes dos produtos, que podem apontar para uma
+// categoria criada no mesmo plano. Cada atualização confere, no WHERE, que o
+// item ainda está como na leitura do cadastro.
+func (r *CatalogRepository) Apply(ctx context.Context, plan *catalog.Plan) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	for _, c := range plan.NewCategories {
+		query := `
+            INSERT INTO categories (id, name, description, station_id, external_code, ` + fiscalDataColumns + `)
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14)
+        `
+		args := append([]any{c.ID, c.Name, c.Description, c.StationID, c.ExternalCode}, fiscalDataValues(&c.FiscalData)...)
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	for _, c := range plan.UpdatedCategories {
+		query := `
+            UPDATE categories
+            SET name = $1, description = $2, station_id = $3, external_code = NULLIF($4, ''), ncm = $5, cest = $6, cfop = $7,
+                cst = $8, origin = $9, unit = $10, icms_rate = $11, pis_rate = $12, cofins_rate = $13
+            WHERE id = $14
+              AND (name, description, station_id, external_code, ` + fiscalDataColumns + `)
+                  IS NOT DISTINCT FROM ($15, $16, $17, NULLIF($18, ''), $19, $20, $21, $22, $23, $24, $25, $26, $27)
+        `
+		previous := plan.PreviousCategories[c.ID]
+		if previous == nil {
+			return catalog.ErrImportConflict
+		}
+		args := append([]any{c.Name, c.Description, c.StationID, c.ExternalCode}, fiscalDataValues(&c.FiscalData)...)
+		args = append(append(args, c.ID, previous.Name, previous.Description, previous.StationID, previous.ExternalCode), fiscalDataValues(&previous.FiscalData)...)
+		if err = execUpdate(ctx, tx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range plan.NewProducts {
+		query := `
+            INSERT INTO products (id, name, price, unit_cost, description, category_id, external_code, ` + fiscalDataColumns + `)
+            VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        `
+		args := append([]any{p.ID, p.Name, p.Price, p.UnitCost, p.Description, p.CategoryID, p.ExternalCode}, fiscalDataValues(&p.FiscalData)...)
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	for _, p := range plan.UpdatedProducts {
+		query := `
+            UPDATE products
+            SET name = $1, price = $2, unit_cost = $3, description = $4, category_id = $5, external_code = NULLIF($6, ''), ncm = $7,
+                cest = $8, cfop = $9, cst = $10, origin = $11, unit = $12, icms_rate = $13, pis_rate = $14, cofins_rate = $15
+            WHERE id = $16
+              AND (name, price, unit_cost, description, category_id, external_code, ` + fiscalDataColumns + `)
+                  IS NOT DISTINCT FROM ($17, $18, $19, $20, $21, NULLIF($22, ''), $23, $24, $25, $26, $27, $28, $29, $30, $31)
+        `
+		previous := plan.PreviousProducts[p.ID]
+		if previous == nil {
+			return catalog.ErrImportConflict
+		}
+		args := append([]any{p.Name, p.Price, p.UnitCost, p.Description, p.CategoryID, p.ExternalCode}, fiscalDataValues(&p.FiscalData)...)
+		args = append(append(args, p.ID, previous.Name, previous.Price, previous.UnitCost, previous.Description, previous.CategoryID, previous.ExternalCode),
+			fiscalDataValues(&previous.FiscalData)...)
+		if err = execUpdate(ctx, tx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range plan.NewAdditions {
+		query := `
+            INSERT INTO additions (id, name, price, unit_cost, external_code)
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+        `
+		if _, err = tx.Exec(ctx, query, a.ID, a.Name, a.Price, a.UnitCost, a.ExternalCode); err != nil {
+			return err
+		}
+	}
+	for _, a := range plan.UpdatedAdditions {
+		query := `
+            UPDATE additions
+            SET name = $1, price = $2, unit_cost = $3, external_code = NULLIF($4, '')
+            WHERE id = $5
+              AND (name, price, unit_cost, external_code) IS NOT DISTINCT FROM ($6, $7, $8, NULLIF($9, ''))
+        `
+		previous := plan.PreviousAdditions[a.ID]
+		if previous == nil {
+			return catalog.ErrImportConflict
+		}
+		if err = execUpdate(ctx, tx, query, a.Name, a.Price, a.UnitCost, a.ExternalCode, a.ID,
+			previous.Name, previous.Price, previous.UnitCost, previous.ExternalCode); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range plan.PriceChanges {
+		if err = insertPriceChange(ctx, tx, c); err != nil {
+			return err
+		}
+	}
+	for _, e := range plan.Entries {
+		if err = insertAuditEntry(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// execUpdate devolve ErrImportConflict quando o item não existe mais ou foi
+// alterado depois da leitura do cadastro.
+func execUpdate(ctx context.Context, tx pgx.Tx, query string, args ...any) error {
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return catalog.ErrImportConflict
+	}
+	return nil
+}
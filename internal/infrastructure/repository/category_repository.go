@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"andressa-lanches/internal/domain/audit"
 	"andressa-lanches/internal/domain/category"
 	"context"
 
@@ -17,26 +18,41 @@ func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
 	return &CategoryRepository{Pool: pool}
 }
 
-func (r *CategoryRepository) Create(ctx context.Context, c *category.Category) error {
+func (r *CategoryRepository) Create(ctx context.Context, c *category.Category, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
-        INSERT INTO categories (name, description)
-        VALUES ($1, $2)
-        RETURNING id
+        INSERT INTO categories (id, name, description, station_id, external_code, ` + fiscalDataColumns + `)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14)
     `
-	err := r.Pool.QueryRow(ctx, query, c.Name, c.Description).Scan(&c.ID)
-	return err
+	args := append([]any{c.ID, c.Name, c.Description, c.StationID, c.ExternalCode}, fiscalDataValues(&c.FiscalData)...)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*category.Category, error) {
 	query := `
-        SELECT id, name, description
+        SELECT ` + categoryColumns + `
         FROM categories
         WHERE id = $1
     `
 	row := r.Pool.QueryRow(ctx, query, id)
 
 	var c category.Category
-	err := row.Scan(&c.ID, &c.Name, &c.Description)
+	err := row.Scan(categoryDest(&c)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -46,28 +62,68 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*catego
 	return &c, nil
 }
 
-func (r *CategoryRepository) Update(ctx context.Context, c *category.Category) error {
+func (r *CategoryRepository) Update(ctx context.Context, c *category.Category, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
         UPDATE categories
-        SET name = $1, description = $2
-        WHERE id = $3
+        SET name = $1, description = $2, station_id = $3, external_code = NULLIF($4, ''), ncm = $5, cest = $6, cfop = $7,
+            cst = $8, origin = $9, unit = $10, icms_rate = $11, pis_rate = $12, cofins_rate = $13
+        WHERE id = $14
     `
-	_, err := r.Pool.Exec(ctx, query, c.Name, c.Description, c.ID)
-	return err
+	args := append([]any{c.Name, c.Description, c.StationID, c.ExternalCode}, fiscalDataValues(&c.FiscalData)...)
+	result, err := tx.Exec(ctx, query, append(args, c.ID)...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return category.ErrCategoryNotFound
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
         DELETE FROM categories
         WHERE id = $1
     `
-	_, err := r.Pool.Exec(ctx, query, id)
-	return err
+	result, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return category.ErrCategoryNotFound
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *CategoryRepository) List(ctx context.Context) ([]*category.Category, error) {
 	query := `
-        SELECT id, name, description
+        SELECT ` + categoryColumns + `
         FROM categories
     `
 	rows, err := r.Pool.Query(ctx, query)
@@ -79,7 +135,7 @@ func (r *CategoryRepository) List(ctx context.Context) ([]*category.Category, er
 	var categories []*category.Category
 	for rows.Next() {
 		var c category.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description)
+		err := rows.Scan(categoryDest(&c)...)
 		if err != nil {
 			return nil, err
 		}
@@ -87,3 +143,9 @@ func (r *CategoryRepository) List(ctx context.Context) ([]*category.Category, er
 	}
 	return categories, nil
 }
+
+const categoryColumns = `id, name, description, station_id, COALESCE(external_code, ''), ` + fiscalDataColumns
+
+func categoryDest(c *category.Category) []any {
+	return append([]any{&c.ID, &c.Name, &c.Description, &c.StationID, &c.ExternalCode}, fiscalDataDest(&c.FiscalData)...)
+}
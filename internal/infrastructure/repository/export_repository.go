@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExportRepository lê as vendas com uma consulta por exportação, juntando
+// vendas, itens e acréscimos no banco, e entrega as linhas conforme chegam.
+type ExportRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewExportRepository(pool *pgxpool.Pool) *ExportRepository {
+	return &ExportRepository{Pool: pool}
+}
+
+const exportSaleColumns = `s.id, s.order_number, s.date, s.business_date, s.order_type, s.status, s.employee`
+
+const exportItemColumns = `si.item_id, si.product_id, COALESCE(p.name, ''), COALESCE(p.category_id, '00000000-0000-0000-0000-000000000000'),
+               si.status, si.quantity, si.unit_price, si.total_price, si.unit_cost, si.total_cost, si.notes`
+
+func (r *ExportRepository) StreamSales(ctx context.Context, start, end time.Time, fn func(s *sale.Sale) error) error {
+	query := `
+        SELECT ` + saleColumns + `
+        FROM sales
+        WHERE date >= $1 AND date < $2
+        ORDER BY date, id
+    `
+	return r.stream(ctx, query, start, end, func(rows pgx.Rows) error {
+		var s sale.Sale
+		if err := scanSale(rows, &s); err != nil {
+			return err
+		}
+		return fn(&s)
+	})
+}
+
+func (r *ExportRepository) StreamItems(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem) error) error {
+	query := `
+        SELECT ` + exportSaleColumns + `, ` + exportItemColumns + `
+        FROM sale_items si
+        INNER JOIN sales s ON s.id = si.sale_id
+        LEFT JOIN products p ON p.id = si.product_id
+        WHERE s.date >= $1 AND s.date < $2
+        ORDER BY s.date, s.id, si.item_id
+    `
+	return r.stream(ctx, query, start, end, func(rows pgx.Rows) error {
+		var s sale.Sale
+		var item sale.SaleItem
+		if err := rows.Scan(append(exportSaleDest(&s), exportItemDest(&item)...)...); err != nil {
+			return err
+		}
+		item.SaleID = s.ID
+		return fn(&s, &item)
+	})
+}
+
+func (r *ExportRepository) StreamAdditions(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem, a *addition.Addition) error) error {
+	query := `
+        SELECT ` + exportSaleColumns + `, ` + exportItemColumns + `,
+               a.id, a.name, COALESCE(sia.unit_price, a.price), sia.unit_cost
+        FROM sale_item_additions sia
+        INNER JOIN sale_items si ON si.sale_id = sia.sale_id AND si.item_id = sia.item_id
+        INNER JOIN sales s ON s.id = sia.sale_id
+        INNER JOIN additions a ON a.id = sia.addition_id
+        LEFT JOIN products p ON p.id = si.product_id
+        WHERE s.date >= $1 AND s.date < $2
+        ORDER BY s.date, s.id, si.item_id
+    `
+	return r.stream(ctx, query, start, end, func(rows pgx.Rows) error {
+		var s sale.Sale
+		var item sale.SaleItem
+		var a addition.Addition
+		dest := append(exportSaleDest(&s), exportItemDest(&item)...)
+		dest = append(dest, &a.ID, &a.Name, &a.Price, &a.UnitCost)
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		item.SaleID = s.ID
+		return fn(&s, &item, &a)
+	})
+}
+
+func (r *ExportRepository) stream(ctx context.Context, query string, start, end time.Time, scan func(rows pgx.Rows) error) error {
+	rows, err := r.Pool.Query(ctx, query, start, end)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportSaleDest(s *sale.Sale) []any {
+	return []any{&s.ID, &s.OrderNumber, &s.Date, &s.BusinessDate, &s.OrderType, &s.Status, &s.Employee}
+}
+
+func exportItemDest(item *sale.SaleItem) []any {
+	return []any{
+		&item.ItemID, &item.ProductID, &item.ProductName, &item.CategoryID, &item.Status, &item.Quantity,
+		&item.UnitPrice, &item.TotalPrice, &item.UnitCost, &item.TotalCost, &item.Notes,
+	}
+}
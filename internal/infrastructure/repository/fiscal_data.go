@@ -0,0 +1,15 @@
+package repository
+
+import "andressa-lanches/internal/domain/taxation"
+
+// fiscalDataColumns são as colunas dos dados fiscais, comuns a produtos e
+// categorias, na ordem de fiscalDataValues e fiscalDataDest.
+const fiscalDataColumns = `ncm, cest, cfop, cst, origin, unit, icms_rate, pis_rate, cofins_rate`
+
+func fiscalDataValues(d *taxation.FiscalData) []any {
+	return []any{d.NCM, d.CEST, d.CFOP, d.CST, d.Origin, d.Unit, d.ICMSRate, d.PISRate, d.COFINSRate}
+}
+
+func fiscalDataDest(d *taxation.FiscalData) []any {
+	return []any{&d.NCM, &d.CEST, &d.CFOP, &d.CST, &d.Origin, &d.Unit, &d.ICMSRate, &d.PISRate, &d.COFINSRate}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/fiscal"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const fiscalDocumentColumns = `id, sale_id, series, number, COALESCE(access_key, ''), environment, status, xml, protocol, status_code, message,
+               issued_at, authorized_at, created_at, updated_at`
+
+type FiscalRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewFiscalRepository(pool *pgxpool.Pool) *FiscalRepository {
+	return &FiscalRepository{Pool: pool}
+}
+
+func (r *FiscalRepository) Reserve(ctx context.Context, doc *fiscal.Document) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// O upsert trava a linha da série, serializando a numeração; se a venda
+	// já tiver documento, o rollback devolve o número.
+	numberQuery := `
+        INSERT INTO fiscal_number_sequences (series, last_number)
+        VALUES ($1, 1)
+        ON CONFLICT (series)
+        DO UPDATE SET last_number = fiscal_number_sequences.last_number + 1
+        RETURNING last_number
+    `
+	if err = tx.QueryRow(ctx, numberQuery, doc.Series).Scan(&doc.Number); err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO fiscal_documents (sale_id, series, number, environment, status, issued_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (sale_id) DO NOTHING
+        RETURNING id
+    `
+	err = tx.QueryRow(ctx, query,
+		doc.SaleID, doc.Series, doc.Number, doc.Environment, doc.Status, doc.IssuedAt, doc.CreatedAt, doc.UpdatedAt,
+	).Scan(&doc.ID)
+	if err == pgx.ErrNoRows {
+		err = fiscal.ErrIssueInProgress
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *FiscalRepository) Update(ctx context.Context, doc *fiscal.Document) error {
+	query := `
+        UPDATE fiscal_documents
+        SET access_key = $1, status = $2, xml = $3, protocol = $4, status_code = $5, message = $6,
+            issued_at = $7, authorized_at = $8, updated_at = $9
+        WHERE id = $10
+    `
+	_, err := r.Pool.Exec(ctx, query,
+		doc.AccessKey, doc.Status, string(doc.XML), doc.Protocol, doc.StatusCode, doc.Message,
+		doc.IssuedAt, doc.AuthorizedAt, doc.UpdatedAt, doc.ID,
+	)
+	return err
+}
+
+func (r *FiscalRepository) GetBySale(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error) {
+	query := `
+        SELECT ` + fiscalDocumentColumns + `
+        FROM fiscal_documents
+        WHERE sale_id = $1
+    `
+	var doc fiscal.Document
+	var xml string
+	err := r.Pool.QueryRow(ctx, query, saleID).Scan(
+		&doc.ID, &doc.SaleID, &doc.Series, &doc.Number, &doc.AccessKey, &doc.Environment, &doc.Status, &xml,
+		&doc.Protocol, &doc.StatusCode, &doc.Message, &doc.IssuedAt, &doc.AuthorizedAt, &doc.CreatedAt, &doc.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	doc.XML = []byte(xml)
+	return &doc, nil
+}
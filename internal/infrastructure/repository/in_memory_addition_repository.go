@@ -6,29 +6,37 @@ import (
 	"sync"
 
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 
 	"github.com/google/uuid"
 )
 
+// InMemoryAdditionRepository grava o histórico de preços e a auditoria em
+// prices e auditRepo, que podem ser nulos.
 type InMemoryAdditionRepository struct {
 	mu        sync.RWMutex
 	additions map[uuid.UUID]*addition.Addition
+	history   inMemoryHistory
 }
 
-func NewInMemoryAdditionRepository() *InMemoryAdditionRepository {
+func NewInMemoryAdditionRepository(prices *InMemoryPriceRepository, auditRepo *InMemoryAuditRepository) *InMemoryAdditionRepository {
 	return &InMemoryAdditionRepository{
 		additions: make(map[uuid.UUID]*addition.Addition),
+		history:   inMemoryHistory{prices: prices, audit: auditRepo},
 	}
 }
 
-func (repo *InMemoryAdditionRepository) Create(ctx context.Context, a *addition.Addition) error {
+func (repo *InMemoryAdditionRepository) Create(ctx context.Context, a *addition.Addition, change *pricing.PriceChange, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if a.ID == uuid.Nil {
 		a.ID = uuid.New()
 	}
 	repo.additions[a.ID] = a
+	repo.history.append(change, entry)
 	return nil
 }
 
@@ -39,26 +47,31 @@ func (repo *InMemoryAdditionRepository) GetByID(ctx context.Context, id uuid.UUI
 	if a, exists := repo.additions[id]; exists {
 		return a, nil
 	}
-	return nil, errors.New("addition not found")
+	return nil, nil
 }
 
-func (repo *InMemoryAdditionRepository) Update(ctx context.Context, a *addition.Addition) error {
+func (repo *InMemoryAdditionRepository) Update(ctx context.Context, a *addition.Addition, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
-	if _, exists := repo.additions[a.ID]; exists {
-		repo.additions[a.ID] = a
-		return nil
+	current, exists := repo.additions[a.ID]
+	if !exists || current.Price != oldPrice {
+		return addition.ErrAdditionConflict
 	}
-	return errors.New("addition not found")
+	repo.additions[a.ID] = a
+	repo.history.append(change, entry)
+	return nil
 }
 
-func (repo *InMemoryAdditionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (repo *InMemoryAdditionRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if _, exists := repo.additions[id]; exists {
 		delete(repo.additions, id)
+		repo.history.append(nil, entry)
 		return nil
 	}
 	return errors.New("addition not found")
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryAdjustmentRepository aplica o reajuste nos repositórios em memória
+// do cardápio, travando todos eles para que o lote seja gravado por inteiro
+// ou não seja gravado. Sem auditRepo, os registros de auditoria são
+// descartados.
+type InMemoryAdjustmentRepository struct {
+	products  *InMemoryProductRepository
+	additions *InMemoryAdditionRepository
+	prices    *InMemoryPriceRepository
+	audit     *InMemoryAuditRepository
+}
+
+func NewInMemoryAdjustmentRepository(products *InMemoryProductRepository, additions *InMemoryAdditionRepository, prices *InMemoryPriceRepository, auditRepo *InMemoryAuditRepository) *InMemoryAdjustmentRepository {
+	return &InMemoryAdjustmentRepository{
+		products:  products,
+		additions: additions,
+		prices:    prices,
+		audit:     auditRepo,
+	}
+}
+
+func (repo *InMemoryAdjustmentRepository) ApplyAdjustment(ctx context.Context, changes []*pricing.PriceChange, entries []*audit.Entry) error {
+	repo.products.mu.Lock()
+	defer repo.products.mu.Unlock()
+	repo.additions.mu.Lock()
+	defer repo.additions.mu.Unlock()
+	repo.prices.mu.Lock()
+	defer repo.prices.mu.Unlock()
+	if repo.audit != nil {
+		repo.audit.mu.Lock()
+		defer repo.audit.mu.Unlock()
+	}
+
+	for _, c := range changes {
+		if price, ok := repo.currentPrice(c); !ok || c.OldPrice == nil || price != *c.OldPrice {
+			return pricing.ErrAdjustmentConflict
+		}
+	}
+
+	for _, c := range changes {
+		repo.apply(c)
+	}
+	repo.appendEntries(entries)
+	return nil
+}
+
+func (repo *InMemoryAdjustmentRepository) ApplySchedule(ctx context.Context, id uuid.UUID, change *pricing.PriceChange, entry *audit.Entry) (bool, error) {
+	repo.products.mu.Lock()
+	defer repo.products.mu.Unlock()
+	repo.additions.mu.Lock()
+	defer repo.additions.mu.Unlock()
+	repo.prices.mu.Lock()
+	defer repo.prices.mu.Unlock()
+	if repo.audit != nil {
+		repo.audit.mu.Lock()
+		defer repo.audit.mu.Unlock()
+	}
+
+	s, exists := repo.prices.schedules[id]
+	if !exists || s.Status != pricing.ScheduleStatusPending {
+		return false, nil
+	}
+	if price, ok := repo.currentPrice(change); !ok || change.OldPrice == nil || price != *change.OldPrice {
+		return false, pricing.ErrAdjustmentConflict
+	}
+
+	s.Status = pricing.ScheduleStatusApplied
+	appliedAt := change.ChangedAt
+	s.AppliedAt = &appliedAt
+	repo.apply(change)
+	if entry != nil {
+		repo.appendEntries([]*audit.Entry{entry})
+	}
+	return true, nil
+}
+
+func (repo *InMemoryAdjustmentRepository) apply(c *pricing.PriceChange) {
+	switch c.ItemType {
+	case pricing.ItemProduct:
+		updated := *repo.products.products[c.ItemID]
+		updated.Price = c.NewPrice
+		repo.products.products[c.ItemID] = &updated
+	case pricing.ItemAddition:
+		updated := *repo.additions.additions[c.ItemID]
+		updated.Price = c.NewPrice
+		repo.additions.additions[c.ItemID] = &updated
+	}
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	repo.prices.changes = append(repo.prices.changes, *c)
+}
+
+func (repo *InMemoryAdjustmentRepository) appendEntries(entries []*audit.Entry) {
+	if repo.audit == nil {
+		return
+	}
+	for _, e := range entries {
+		if e.ID == uuid.Nil {
+			e.ID = uuid.New()
+		}
+		repo.audit.entries = append(repo.audit.entries, *e)
+	}
+}
+
+func (repo *InMemoryAdjustmentRepository) currentPrice(c *pricing.PriceChange) (float64, bool) {
+	switch c.ItemType {
+	case pricing.ItemProduct:
+		if p, ok := repo.products.products[c.ItemID]; ok {
+			return p.Price, true
+		}
+	case pricing.ItemAddition:
+		if a, ok := repo.additions.additions[c.ItemID]; ok {
+			return a.Price, true
+		}
+	}
+	return 0, false
+}
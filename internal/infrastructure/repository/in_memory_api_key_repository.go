@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]*apikey.APIKey
+}
+
+func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys: make(map[uuid.UUID]*apikey.APIKey),
+	}
+}
+
+func (repo *InMemoryAPIKeyRepository) Create(ctx context.Context, k *apikey.APIKey) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.keys {
+		if existing.Prefix == k.Prefix {
+			return errors.New("api key prefix already exists")
+		}
+	}
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	repo.keys[k.ID] = copyAPIKey(k)
+	return nil
+}
+
+func (repo *InMemoryAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if k, exists := repo.keys[id]; exists {
+		return copyAPIKey(k), nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, k := range repo.keys {
+		if k.Prefix == prefix {
+			return copyAPIKey(k), nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryAPIKeyRepository) Update(ctx context.Context, k *apikey.APIKey) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	existing, exists := repo.keys[k.ID]
+	if !exists {
+		return errors.New("api key not found")
+	}
+	stored := copyAPIKey(k)
+	stored.LastUsedAt = existing.LastUsedAt
+	repo.keys[k.ID] = stored
+	return nil
+}
+
+func (repo *InMemoryAPIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if k, exists := repo.keys[id]; exists {
+		k.LastUsedAt = &at
+	}
+	return nil
+}
+
+func (repo *InMemoryAPIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	keys := make([]*apikey.APIKey, 0, len(repo.keys))
+	for _, k := range repo.keys {
+		keys = append(keys, copyAPIKey(k))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func copyAPIKey(k *apikey.APIKey) *apikey.APIKey {
+	stored := *k
+	stored.Scopes = append([]user.Permission(nil), k.Scopes...)
+	return &stored
+}
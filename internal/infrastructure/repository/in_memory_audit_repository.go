@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"andressa-lanches/internal/domain/audit"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []audit.Entry
+}
+
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+func (repo *InMemoryAuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	repo.entries = append(repo.entries, *e)
+	return nil
+}
+
+func (repo *InMemoryAuditRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	entries := make([]*audit.Entry, 0)
+	for i := len(repo.entries) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		if filter.Matches(&repo.entries[i]) {
+			found := repo.entries[i]
+			entries = append(entries, &found)
+		}
+	}
+	return entries, nil
+}
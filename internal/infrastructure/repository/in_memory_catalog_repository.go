@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+
+	"andressa-lanches/internal/domain/catalog"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryCatalogRepository grava a importação nos repositórios em memória
+// do cardápio, travando todos eles para que o plano seja gravado por inteiro
+// ou não seja gravado. Sem prices ou auditRepo, o histórico de preços e a
+// auditoria são descartados.
+type InMemoryCatalogRepository struct {
+	categories *InMemoryCategoryRepository
+	products   *InMemoryProductRepository
+	additions  *InMemoryAdditionRepository
+	prices     *InMemoryPriceRepository
+	audit      *InMemoryAuditRepository
+}
+
+func NewInMemoryCatalogRepository(categories *InMemoryCategoryRepository, products *InMemoryProductRepository, additions *InMemoryAdditionRepository, prices *InMemoryPriceRepository, auditRepo *InMemoryAuditRepository) *InMemoryCatalogRepository {
+	return &InMemoryCatalogRepository{
+		categories: categories,
+		products:   products,
+		additions:  additions,
+		prices:     prices,
+		audit:      auditRepo,
+	}
+}
+
+func (repo *InMemoryCatalogRepository) Apply(ctx context.Context, plan *catalog.Plan) error {
+	repo.categories.mu.Lock()
+	defer repo.categories.mu.Unlock()
+	repo.products.mu.Lock()
+	defer repo.products.mu.Unlock()
+	repo.additions.mu.Lock()
+	defer repo.additions.mu.Unlock()
+	if repo.prices != nil {
+		repo.prices.mu.Lock()
+		defer repo.prices.mu.Unlock()
+	}
+	if repo.audit != nil {
+		repo.audit.mu.Lock()
+		defer repo.audit.mu.Unlock()
+	}
+
+	for _, c := range plan.UpdatedCategories {
+		current, ok := repo.categories.categories[c.ID]
+		if !ok || !reflect.DeepEqual(current, plan.PreviousCategories[c.ID]) {
+			return catalog.ErrImportConflict
+		}
+	}
+	for _, p := range plan.UpdatedProducts {
+		current, ok := repo.products.products[p.ID]
+		if !ok || !reflect.DeepEqual(current, plan.PreviousProducts[p.ID]) {
+			return catalog.ErrImportConflict
+		}
+	}
+	for _, a := range plan.UpdatedAdditions {
+		current, ok := repo.additions.additions[a.ID]
+		if !ok || !reflect.DeepEqual(current, plan.PreviousAdditions[a.ID]) {
+			return catalog.ErrImportConflict
+		}
+	}
+
+	for _, c := range append(plan.NewCategories, plan.UpdatedCategories...) {
+		copied := *c
+		repo.categories.categories[c.ID] = &copied
+	}
+	for _, p := range append(plan.NewProducts, plan.UpdatedProducts...) {
+		copied := *p
+		repo.products.products[p.ID] = &copied
+	}
+	for _, a := range append(plan.NewAdditions, plan.UpdatedAdditions...) {
+		copied := *a
+		repo.additions.additions[a.ID] = &copied
+	}
+	if repo.prices != nil {
+		for _, c := range plan.PriceChanges {
+			if c.ID == uuid.Nil {
+				c.ID = uuid.New()
+			}
+			repo.prices.changes = append(repo.prices.changes, *c)
+		}
+	}
+	if repo.audit != nil {
+		for _, e := range plan.Entries {
+			if e.ID == uuid.Nil {
+				e.ID = uuid.New()
+			}
+			repo.audit.entries = append(repo.audit.entries, *e)
+		}
+	}
+	return nil
+}
@@ -5,30 +5,37 @@ import (
 	"errors"
 	"sync"
 
+	"andressa-lanches/internal/domain/audit"
 	"andressa-lanches/internal/domain/category"
 
 	"github.com/google/uuid"
 )
 
+// InMemoryCategoryRepository grava a auditoria em auditRepo, que pode ser
+// nulo.
 type InMemoryCategoryRepository struct {
 	mu         sync.RWMutex
 	categories map[uuid.UUID]*category.Category
+	history    inMemoryHistory
 }
 
-func NewInMemoryCategoryRepository() *InMemoryCategoryRepository {
+func NewInMemoryCategoryRepository(auditRepo *InMemoryAuditRepository) *InMemoryCategoryRepository {
 	return &InMemoryCategoryRepository{
 		categories: make(map[uuid.UUID]*category.Category),
+		history:    inMemoryHistory{audit: auditRepo},
 	}
 }
 
-func (repo *InMemoryCategoryRepository) Create(ctx context.Context, c *category.Category) error {
+func (repo *InMemoryCategoryRepository) Create(ctx context.Context, c *category.Category, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
 	}
 	repo.categories[c.ID] = c
+	repo.history.append(nil, entry)
 	return nil
 }
 
@@ -42,23 +49,27 @@ func (repo *InMemoryCategoryRepository) GetByID(ctx context.Context, id uuid.UUI
 	return nil, errors.New("category not found")
 }
 
-func (repo *InMemoryCategoryRepository) Update(ctx context.Context, c *category.Category) error {
+func (repo *InMemoryCategoryRepository) Update(ctx context.Context, c *category.Category, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if _, exists := repo.categories[c.ID]; exists {
 		repo.categories[c.ID] = c
+		repo.history.append(nil, entry)
 		return nil
 	}
 	return errors.New("category not found")
 }
 
-func (repo *InMemoryCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (repo *InMemoryCategoryRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if _, exists := repo.categories[id]; exists {
 		delete(repo.categories, id)
+		repo.history.append(nil, entry)
 		return nil
 	}
 	return errors.New("category not found")
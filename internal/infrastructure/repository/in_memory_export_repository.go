@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/sale"
+	"context"
+	"sort"
+	"time"
+)
+
+// InMemoryExportRepository percorre as vendas do repositório em memória.
+type InMemoryExportRepository struct {
+	sales *InMemorySaleRepository
+}
+
+func NewInMemoryExportRepository(sales *InMemorySaleRepository) *InMemoryExportRepository {
+	return &InMemoryExportRepository{sales: sales}
+}
+
+func (r *InMemoryExportRepository) StreamSales(ctx context.Context, start, end time.Time, fn func(s *sale.Sale) error) error {
+	for _, s := range r.period(start, end) {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryExportRepository) StreamItems(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem) error) error {
+	for _, s := range r.period(start, end) {
+		for i := range s.Items {
+			if err := fn(s, &s.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryExportRepository) StreamAdditions(ctx context.Context, start, end time.Time, fn func(s *sale.Sale, item *sale.SaleItem, a *addition.Addition) error) error {
+	for _, s := range r.period(start, end) {
+		for i := range s.Items {
+			item := &s.Items[i]
+			for j := range item.Additions {
+				if err := fn(s, item, &item.Additions[j]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// period devolve cópias das vendas do período em ordem de data.
+func (r *InMemoryExportRepository) period(start, end time.Time) []*sale.Sale {
+	r.sales.mu.RLock()
+	defer r.sales.mu.RUnlock()
+
+	sales := make([]*sale.Sale, 0)
+	for _, s := range r.sales.sales {
+		if !s.Date.Before(start) && s.Date.Before(end) {
+			sales = append(sales, copySale(s))
+		}
+	}
+	sort.Slice(sales, func(i, j int) bool {
+		if sales[i].Date.Equal(sales[j].Date) {
+			return sales[i].ID.String() < sales[j].ID.String()
+		}
+		return sales[i].Date.Before(sales[j].Date)
+	})
+	return sales
+}
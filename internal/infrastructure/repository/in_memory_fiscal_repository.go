@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"andressa-lanches/internal/domain/fiscal"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryFiscalRepository struct {
+	mu        sync.RWMutex
+	documents map[uuid.UUID]*fiscal.Document
+	numbers   map[int]int
+}
+
+func NewInMemoryFiscalRepository() *InMemoryFiscalRepository {
+	return &InMemoryFiscalRepository{
+		documents: make(map[uuid.UUID]*fiscal.Document),
+		numbers:   make(map[int]int),
+	}
+}
+
+func (repo *InMemoryFiscalRepository) Reserve(ctx context.Context, doc *fiscal.Document) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.documents[doc.SaleID]; exists {
+		return fiscal.ErrIssueInProgress
+	}
+	repo.numbers[doc.Series]++
+	doc.Number = repo.numbers[doc.Series]
+	if doc.ID == uuid.Nil {
+		doc.ID = uuid.New()
+	}
+	stored := *doc
+	repo.documents[doc.SaleID] = &stored
+	return nil
+}
+
+func (repo *InMemoryFiscalRepository) Update(ctx context.Context, doc *fiscal.Document) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.documents[doc.SaleID]; !exists {
+		return errors.New("fiscal document not found")
+	}
+	stored := *doc
+	repo.documents[doc.SaleID] = &stored
+	return nil
+}
+
+func (repo *InMemoryFiscalRepository) GetBySale(ctx context.Context, saleID uuid.UUID) (*fiscal.Document, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if doc, exists := repo.documents[saleID]; exists {
+		found := *doc
+		return &found, nil
+	}
+	return nil, nil
+}
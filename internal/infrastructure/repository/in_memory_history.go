@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
+
+	"github.com/google/uuid"
+)
+
+// inMemoryHistory grava nos repositórios em memória o histórico de preços e
+// a auditoria que acompanham uma alteração do cadastro. Sem prices ou
+// audit, os registros correspondentes são descartados.
+type inMemoryHistory struct {
+	prices *InMemoryPriceRepository
+	audit  *InMemoryAuditRepository
+}
+
+// lock trava os repositórios do histórico depois do repositório do
+// cadastro, na mesma ordem dos reajustes, e devolve a função que os libera.
+func (h inMemoryHistory) lock() func() {
+	if h.prices != nil {
+		h.prices.mu.Lock()
+	}
+	if h.audit != nil {
+		h.audit.mu.Lock()
+	}
+	return func() {
+		if h.audit != nil {
+			h.audit.mu.Unlock()
+		}
+		if h.prices != nil {
+			h.prices.mu.Unlock()
+		}
+	}
+}
+
+func (h inMemoryHistory) append(change *pricing.PriceChange, entry *audit.Entry) {
+	if change != nil && h.prices != nil {
+		if change.ID == uuid.Nil {
+			change.ID = uuid.New()
+		}
+		h.prices.changes = append(h.prices.changes, *change)
+	}
+	if entry != nil && h.audit != nil {
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+		h.audit.entries = append(h.audit.entries, *entry)
+	}
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/sale"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryPaymentRepository trava também o repositório de vendas ao criar
+// ou resolver uma cobrança, para conferir o saldo e gravar o pagamento da
+// venda junto com a cobrança. Sem auditRepo, os registros de auditoria são
+// descartados.
+type InMemoryPaymentRepository struct {
+	mu            sync.RWMutex
+	sales         *InMemorySaleRepository
+	audit         *InMemoryAuditRepository
+	charges       map[uuid.UUID]*payment.Charge
+	webhookEvents map[string]bool
+}
+
+func NewInMemoryPaymentRepository(sales *InMemorySaleRepository, auditRepo *InMemoryAuditRepository) *InMemoryPaymentRepository {
+	return &InMemoryPaymentRepository{
+		sales:         sales,
+		audit:         auditRepo,
+		charges:       make(map[uuid.UUID]*payment.Charge),
+		webhookEvents: make(map[string]bool),
+	}
+}
+
+func (repo *InMemoryPaymentRepository) Create(ctx context.Context, c *payment.Charge) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.sales.mu.Lock()
+	defer repo.sales.mu.Unlock()
+
+	s, exists := repo.sales.sales[c.SaleID]
+	if !exists {
+		return sale.ErrSaleNotFound
+	}
+	committed := s.AmountPaid()
+	for _, other := range repo.charges {
+		if other.SaleID == c.SaleID && other.Status == payment.StatusPending {
+			committed += other.Amount
+		}
+	}
+	if c.Amount > s.TotalAmount-committed+0.005 {
+		return payment.ErrChargeExceedsBalance
+	}
+
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	stored := *c
+	repo.charges[c.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryPaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if c, exists := repo.charges[id]; exists {
+		found := *c
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryPaymentRepository) GetByProviderChargeID(ctx context.Context, provider, providerChargeID string) (*payment.Charge, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, c := range repo.charges {
+		if c.Provider == provider && c.ProviderChargeID == providerChargeID {
+			found := *c
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryPaymentRepository) ListBySale(ctx context.Context, saleID uuid.UUID) ([]*payment.Charge, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	charges := make([]*payment.Charge, 0)
+	for _, c := range repo.charges {
+		if c.SaleID == saleID {
+			found := *c
+			charges = append(charges, &found)
+		}
+	}
+	sort.Slice(charges, func(i, j int) bool {
+		return charges[i].CreatedAt.Before(charges[j].CreatedAt)
+	})
+	return charges, nil
+}
+
+func (repo *InMemoryPaymentRepository) UpdateStatus(ctx context.Context, charge *payment.Charge, to payment.Status, entry *audit.Entry) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.sales.mu.Lock()
+	defer repo.sales.mu.Unlock()
+	if repo.audit != nil {
+		repo.audit.mu.Lock()
+		defer repo.audit.mu.Unlock()
+	}
+
+	c, exists := repo.charges[charge.ID]
+	if !exists || c.Status != charge.Status {
+		return false, nil
+	}
+	s, exists := repo.sales.sales[c.SaleID]
+	if !exists {
+		return false, sale.ErrSaleNotFound
+	}
+
+	switch to {
+	case payment.StatusConfirmed:
+		s.Payments = append(s.Payments, sale.Payment{Method: c.Method, Amount: c.Amount, Reference: c.Reference()})
+	case payment.StatusRefunded:
+		payments := s.Payments[:0]
+		for _, p := range s.Payments {
+			if p.Reference != c.Reference() {
+				payments = append(payments, p)
+			}
+		}
+		s.Payments = payments
+	}
+	c.Status = to
+	c.UpdatedAt = time.Now()
+	if repo.audit != nil && entry != nil {
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+		repo.audit.entries = append(repo.audit.entries, *entry)
+	}
+	return true, nil
+}
+
+func (repo *InMemoryPaymentRepository) RegisterWebhookEvent(ctx context.Context, provider, eventID string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := provider + ":" + eventID
+	if repo.webhookEvents[key] {
+		return false, nil
+	}
+	repo.webhookEvents[key] = true
+	return true, nil
+}
+
+func (repo *InMemoryPaymentRepository) ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.webhookEvents, provider+":"+eventID)
+	return nil
+}
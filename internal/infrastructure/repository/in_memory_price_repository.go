@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/pricing"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryPriceRepository struct {
+	mu        sync.RWMutex
+	changes   []pricing.PriceChange
+	schedules map[uuid.UUID]*pricing.ScheduledChange
+}
+
+func NewInMemoryPriceRepository() *InMemoryPriceRepository {
+	return &InMemoryPriceRepository{
+		schedules: make(map[uuid.UUID]*pricing.ScheduledChange),
+	}
+}
+
+func (repo *InMemoryPriceRepository) AppendChange(ctx context.Context, c *pricing.PriceChange) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	repo.changes = append(repo.changes, *c)
+	return nil
+}
+
+func (repo *InMemoryPriceRepository) ListChanges(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.PriceChange, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	changes := make([]*pricing.PriceChange, 0)
+	for i := len(repo.changes) - 1; i >= 0; i-- {
+		if c := repo.changes[i]; c.ItemType == itemType && c.ItemID == itemID {
+			changes = append(changes, &c)
+		}
+	}
+	return changes, nil
+}
+
+func (repo *InMemoryPriceRepository) CreateSchedule(ctx context.Context, s *pricing.ScheduledChange) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	stored := *s
+	repo.schedules[s.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryPriceRepository) GetSchedule(ctx context.Context, id uuid.UUID) (*pricing.ScheduledChange, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if s, exists := repo.schedules[id]; exists {
+		found := *s
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryPriceRepository) ListSchedules(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.ScheduledChange, error) {
+	return repo.filterSchedules(func(s *pricing.ScheduledChange) bool {
+		return s.ItemType == itemType && s.ItemID == itemID
+	}), nil
+}
+
+func (repo *InMemoryPriceRepository) ListDueSchedules(ctx context.Context, now time.Time) ([]*pricing.ScheduledChange, error) {
+	return repo.filterSchedules(func(s *pricing.ScheduledChange) bool {
+		return s.Status == pricing.ScheduleStatusPending && !s.EffectiveAt.After(now)
+	}), nil
+}
+
+func (repo *InMemoryPriceRepository) ResolveSchedule(ctx context.Context, id uuid.UUID, status pricing.ScheduleStatus, at time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	s, exists := repo.schedules[id]
+	if !exists || s.Status != pricing.ScheduleStatusPending {
+		return false, nil
+	}
+	s.Status = status
+	switch status {
+	case pricing.ScheduleStatusApplied:
+		s.AppliedAt = &at
+	case pricing.ScheduleStatusCanceled:
+		s.CanceledAt = &at
+	}
+	return true, nil
+}
+
+func (repo *InMemoryPriceRepository) filterSchedules(match func(*pricing.ScheduledChange) bool) []*pricing.ScheduledChange {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	schedules := make([]*pricing.ScheduledChange, 0)
+	for _, s := range repo.schedules {
+		if match(s) {
+			found := *s
+			schedules = append(schedules, &found)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].EffectiveAt.Equal(schedules[j].EffectiveAt) {
+			return schedules[i].EffectiveAt.Before(schedules[j].EffectiveAt)
+		}
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+	return schedules
+}
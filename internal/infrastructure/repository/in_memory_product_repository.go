@@ -5,30 +5,38 @@ import (
 	"errors"
 	"sync"
 
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 
 	"github.com/google/uuid"
 )
 
+// InMemoryProductRepository grava o histórico de preços e a auditoria em
+// prices e auditRepo, que podem ser nulos.
 type InMemoryProductRepository struct {
 	mu       sync.RWMutex
 	products map[uuid.UUID]*product.Product
+	history  inMemoryHistory
 }
 
-func NewInMemoryProductRepository() *InMemoryProductRepository {
+func NewInMemoryProductRepository(prices *InMemoryPriceRepository, auditRepo *InMemoryAuditRepository) *InMemoryProductRepository {
 	return &InMemoryProductRepository{
 		products: make(map[uuid.UUID]*product.Product),
+		history:  inMemoryHistory{prices: prices, audit: auditRepo},
 	}
 }
 
-func (repo *InMemoryProductRepository) Create(ctx context.Context, p *product.Product) error {
+func (repo *InMemoryProductRepository) Create(ctx context.Context, p *product.Product, change *pricing.PriceChange, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if p.ID == uuid.Nil {
 		p.ID = uuid.New()
 	}
 	repo.products[p.ID] = p
+	repo.history.append(change, entry)
 	return nil
 }
 
@@ -42,23 +50,28 @@ func (repo *InMemoryProductRepository) GetByID(ctx context.Context, id uuid.UUID
 	return nil, nil
 }
 
-func (repo *InMemoryProductRepository) Update(ctx context.Context, p *product.Product) error {
+func (repo *InMemoryProductRepository) Update(ctx context.Context, p *product.Product, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
-	if _, exists := repo.products[p.ID]; exists {
-		repo.products[p.ID] = p
-		return nil
+	current, exists := repo.products[p.ID]
+	if !exists || current.Price != oldPrice {
+		return product.ErrProductConflict
 	}
-	return errors.New("product not found")
+	repo.products[p.ID] = p
+	repo.history.append(change, entry)
+	return nil
 }
 
-func (repo *InMemoryProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (repo *InMemoryProductRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if _, exists := repo.products[id]; exists {
 		delete(repo.products, id)
+		repo.history.append(nil, entry)
 		return nil
 	}
 	return errors.New("product not found")
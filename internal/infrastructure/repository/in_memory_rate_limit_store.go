@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/ratelimit"
+)
+
+// sweepInterval espaça a limpeza das chaves expiradas, feita durante os
+// incrementos.
+const sweepInterval = time.Minute
+
+type InMemoryRateLimitStore struct {
+	mu        sync.Mutex
+	counters  map[string]ratelimit.Counter
+	lastSweep time.Time
+}
+
+func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		counters: make(map[string]ratelimit.Counter),
+	}
+}
+
+func (store *InMemoryRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (ratelimit.Counter, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if now.Sub(store.lastSweep) >= sweepInterval {
+		for k, c := range store.counters {
+			if !now.Before(c.ExpiresAt) {
+				delete(store.counters, k)
+			}
+		}
+		store.lastSweep = now
+	}
+
+	c, exists := store.counters[key]
+	if !exists || !now.Before(c.ExpiresAt) {
+		c = ratelimit.Counter{ExpiresAt: now.Add(window)}
+	}
+	c.Count++
+	c.LastHit = now
+	store.counters[key] = c
+	return c, nil
+}
+
+func (store *InMemoryRateLimitStore) Get(ctx context.Context, key string, now time.Time) (ratelimit.Counter, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c, exists := store.counters[key]
+	if !exists || !now.Before(c.ExpiresAt) {
+		return ratelimit.Counter{}, nil
+	}
+	return c, nil
+}
+
+func (store *InMemoryRateLimitStore) Decrement(ctx context.Context, key string, now time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c, exists := store.counters[key]
+	if !exists || !now.Before(c.ExpiresAt) || c.Count == 0 {
+		return nil
+	}
+	c.Count--
+	store.counters[key] = c
+	return nil
+}
+
+func (store *InMemoryRateLimitStore) Reset(ctx context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.counters, key)
+	return nil
+}
@@ -3,32 +3,57 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/sale"
 
 	"github.com/google/uuid"
 )
 
+// InMemorySaleRepository grava a auditoria em auditRepo, que pode ser nulo.
 type InMemorySaleRepository struct {
-	mu    sync.RWMutex
-	sales map[uuid.UUID]*sale.Sale
+	mu           sync.RWMutex
+	sales        map[uuid.UUID]*sale.Sale
+	orderNumbers map[string]int
+	history      inMemoryHistory
 }
 
-func NewInMemorySaleRepository() *InMemorySaleRepository {
+func NewInMemorySaleRepository(auditRepo *InMemoryAuditRepository) *InMemorySaleRepository {
 	return &InMemorySaleRepository{
-		sales: make(map[uuid.UUID]*sale.Sale),
+		sales:        make(map[uuid.UUID]*sale.Sale),
+		orderNumbers: make(map[string]int),
+		history:      inMemoryHistory{audit: auditRepo},
 	}
 }
 
-func (repo *InMemorySaleRepository) Create(ctx context.Context, s *sale.Sale) error {
+func (repo *InMemorySaleRepository) Create(ctx context.Context, s *sale.Sale, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if s.ID == uuid.Nil {
 		s.ID = uuid.New()
 	}
+	businessDate := s.BusinessDate.Format("2006-01-02")
+	repo.orderNumbers[businessDate]++
+	s.OrderNumber = repo.orderNumbers[businessDate]
+	for i := range s.Items {
+		s.Items[i].SaleID = s.ID
+		s.Items[i].ItemID = i + 1
+	}
+	if entry != nil {
+		changes, err := audit.Diff(nil, s)
+		if err != nil {
+			return err
+		}
+		entry.Changes = changes
+	}
 	repo.sales[s.ID] = s
+	repo.history.append(nil, entry)
 	return nil
 }
 
@@ -37,17 +62,94 @@ func (repo *InMemorySaleRepository) GetByID(ctx context.Context, id uuid.UUID) (
 	defer repo.mu.RUnlock()
 
 	if s, exists := repo.sales[id]; exists {
-		return s, nil
+		return copySale(s), nil
 	}
 	return nil, nil
 }
 
-func (repo *InMemorySaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (repo *InMemorySaleRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status sale.Status, entry *audit.Entry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	defer repo.history.lock()()
+
+	s, exists := repo.sales[id]
+	if !exists {
+		return sale.ErrSaleNotFound
+	}
+	s.Status = status
+	repo.history.append(nil, entry)
+	return nil
+}
+
+func (repo *InMemorySaleRepository) UpdateItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string, entry *audit.Entry) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
+	defer repo.history.lock()()
+
+	s, exists := repo.sales[saleID]
+	if !exists {
+		return sale.ErrSaleNotFound
+	}
+	for i := range s.Items {
+		if s.Items[i].ItemID == itemID {
+			s.Items[i].Notes = notes
+			repo.history.append(nil, entry)
+			return nil
+		}
+	}
+	return sale.ErrSaleItemNotFound
+}
+
+func (repo *InMemorySaleRepository) UpdateItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status sale.ItemStatus, entry *audit.Entry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	defer repo.history.lock()()
+
+	s, exists := repo.sales[saleID]
+	if !exists {
+		return sale.ErrSaleNotFound
+	}
+	for i := range s.Items {
+		if s.Items[i].ItemID == itemID {
+			s.Items[i].Status = status
+			repo.history.append(nil, entry)
+			return nil
+		}
+	}
+	return sale.ErrSaleItemNotFound
+}
+
+func (repo *InMemorySaleRepository) UpdateStationItemsStatus(ctx context.Context, saleID, stationID uuid.UUID, status sale.ItemStatus, entry *audit.Entry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	defer repo.history.lock()()
+
+	s, exists := repo.sales[saleID]
+	if !exists {
+		return sale.ErrSaleNotFound
+	}
+	found := false
+	for i := range s.Items {
+		if s.Items[i].StationID != nil && *s.Items[i].StationID == stationID {
+			s.Items[i].Status = status
+			found = true
+		}
+	}
+	if !found {
+		return sale.ErrStationTicketNotFound
+	}
+	repo.history.append(nil, entry)
+	return nil
+}
+
+func (repo *InMemorySaleRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	defer repo.history.lock()()
 
 	if _, exists := repo.sales[id]; exists {
 		delete(repo.sales, id)
+		repo.history.append(nil, entry)
 		return nil
 	}
 	return errors.New("sale not found")
@@ -63,3 +165,127 @@ func (repo *InMemorySaleRepository) List(ctx context.Context) ([]*sale.Sale, err
 	}
 	return sales, nil
 }
+
+func (repo *InMemorySaleRepository) ListByPeriod(ctx context.Context, start, end time.Time) ([]*sale.Sale, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sales := make([]*sale.Sale, 0)
+	for _, s := range repo.sales {
+		if !s.Date.Before(start) && s.Date.Before(end) {
+			sales = append(sales, s)
+		}
+	}
+	return sales, nil
+}
+
+func (repo *InMemorySaleRepository) SumItemsByPeriod(ctx context.Context, start, end time.Time) ([]sale.ItemSales, error) {
+	sales, err := repo.ListByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []sale.ItemSales
+	index := make(map[pricing.ItemType]map[uuid.UUID]int)
+	add := func(itemType pricing.ItemType, id uuid.UUID, name string, quantity int, unitPrice float64, unitCost *float64) {
+		if index[itemType] == nil {
+			index[itemType] = make(map[uuid.UUID]int)
+		}
+		i, ok := index[itemType][id]
+		if !ok {
+			i = len(totals)
+			index[itemType][id] = i
+			totals = append(totals, sale.ItemSales{ItemType: itemType, ItemID: id, Name: name})
+		}
+		t := &totals[i]
+		t.Quantity += quantity
+		t.Revenue += unitPrice * float64(quantity)
+		if unitCost != nil {
+			t.Cost += *unitCost * float64(quantity)
+		} else {
+			t.UncostedQuantity += quantity
+		}
+	}
+	for _, s := range sales {
+		if s.Status == sale.StatusCanceled {
+			continue
+		}
+		for _, item := range s.Items {
+			add(pricing.ItemProduct, item.ProductID, item.ProductName, item.Quantity, item.UnitPrice, item.UnitCost)
+			for _, a := range item.Additions {
+				add(pricing.ItemAddition, a.ID, a.Name, item.Quantity, a.Price, a.UnitCost)
+			}
+		}
+	}
+	return totals, nil
+}
+
+func (repo *InMemorySaleRepository) SumByWeekdayHour(ctx context.Context, start, end time.Time, timezone string) ([]sale.HeatmapCell, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := repo.ListByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var cells []sale.HeatmapCell
+	index := make(map[[2]int]int)
+	for _, s := range sales {
+		if s.Status == sale.StatusCanceled {
+			continue
+		}
+		key := [2]int{int(s.BusinessDate.Weekday()), s.Date.In(location).Hour()}
+		i, ok := index[key]
+		if !ok {
+			i = len(cells)
+			index[key] = i
+			cells = append(cells, sale.HeatmapCell{Weekday: time.Weekday(key[0]), Hour: key[1]})
+		}
+		cells[i].Orders++
+		cells[i].Revenue += s.TotalAmount
+	}
+	return cells, nil
+}
+
+func (repo *InMemorySaleRepository) ListByStatus(ctx context.Context, statuses ...sale.Status) ([]*sale.Sale, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sales := make([]*sale.Sale, 0)
+	for _, s := range repo.sales {
+		for _, status := range statuses {
+			if s.Status == status {
+				sales = append(sales, s)
+				break
+			}
+		}
+	}
+	sort.Slice(sales, func(i, j int) bool {
+		return sales[i].Date.Before(sales[j].Date)
+	})
+	return sales, nil
+}
+
+func (repo *InMemorySaleRepository) ListByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*sale.Sale, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sales := make([]*sale.Sale, 0)
+	for _, s := range repo.sales {
+		if s.OrderNumber == orderNumber && s.BusinessDate.Format("2006-01-02") == businessDate.Format("2006-01-02") {
+			sales = append(sales, s)
+		}
+	}
+	return sales, nil
+}
+
+// copySale devolve uma cópia com os itens e pagamentos próprios, para que
+// alterações posteriores no repositório não mudem a venda já lida.
+func copySale(s *sale.Sale) *sale.Sale {
+	found := *s
+	found.Items = append([]sale.SaleItem(nil), s.Items...)
+	found.Payments = append([]sale.Payment(nil), s.Payments...)
+	return &found
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/session"
+
+	"github.com/google/uuid"
+)
+
+type InMemorySessionRepository struct {
+	mu      sync.RWMutex
+	tokens  map[uuid.UUID]*session.RefreshToken
+	revoked map[uuid.UUID]time.Time
+}
+
+func NewInMemorySessionRepository() *InMemorySessionRepository {
+	return &InMemorySessionRepository{
+		tokens:  make(map[uuid.UUID]*session.RefreshToken),
+		revoked: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (repo *InMemorySessionRepository) Create(ctx context.Context, t *session.RefreshToken) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	stored := *t
+	repo.tokens[t.ID] = &stored
+	return nil
+}
+
+func (repo *InMemorySessionRepository) GetByHash(ctx context.Context, tokenHash string) (*session.RefreshToken, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, t := range repo.tokens {
+		if t.TokenHash == tokenHash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemorySessionRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	t, exists := repo.tokens[id]
+	if !exists || t.UsedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &at
+	return true, nil
+}
+
+func (repo *InMemorySessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, t := range repo.tokens {
+		if t.FamilyID != familyID {
+			continue
+		}
+		if t.AccessTokenExpiresAt.After(at) {
+			repo.revoked[t.AccessTokenID] = t.AccessTokenExpiresAt
+		}
+		if t.RevokedAt == nil {
+			revokedAt := at
+			t.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (repo *InMemorySessionRepository) RevokeAccessToken(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.revoked[tokenID] = expiresAt
+	return nil
+}
+
+func (repo *InMemorySessionRepository) IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	_, revoked := repo.revoked[tokenID]
+	return revoked, nil
+}
+
+func (repo *InMemorySessionRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for id, expiresAt := range repo.revoked {
+		if expiresAt.Before(before) {
+			delete(repo.revoked, id)
+		}
+	}
+	for id, t := range repo.tokens {
+		if t.ExpiresAt.Before(before) {
+			delete(repo.tokens, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"andressa-lanches/internal/domain/station"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryStationRepository struct {
+	mu       sync.RWMutex
+	stations map[uuid.UUID]*station.Station
+}
+
+func NewInMemoryStationRepository() *InMemoryStationRepository {
+	return &InMemoryStationRepository{
+		stations: make(map[uuid.UUID]*station.Station),
+	}
+}
+
+func (repo *InMemoryStationRepository) Create(ctx context.Context, s *station.Station) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	repo.stations[s.ID] = s
+	return nil
+}
+
+func (repo *InMemoryStationRepository) GetByID(ctx context.Context, id uuid.UUID) (*station.Station, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if s, exists := repo.stations[id]; exists {
+		return s, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryStationRepository) Update(ctx context.Context, s *station.Station) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.stations[s.ID]; exists {
+		repo.stations[s.ID] = s
+		return nil
+	}
+	return errors.New("station not found")
+}
+
+func (repo *InMemoryStationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.stations[id]; exists {
+		delete(repo.stations, id)
+		return nil
+	}
+	return errors.New("station not found")
+}
+
+func (repo *InMemoryStationRepository) List(ctx context.Context) ([]*station.Station, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	stations := make([]*station.Station, 0, len(repo.stations))
+	for _, s := range repo.stations {
+		stations = append(stations, s)
+	}
+	return stations, nil
+}
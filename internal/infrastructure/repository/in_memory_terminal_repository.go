@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"andressa-lanches/internal/domain/terminal"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryTerminalRepository struct {
+	mu        sync.RWMutex
+	terminals map[uuid.UUID]*terminal.Terminal
+}
+
+func NewInMemoryTerminalRepository() *InMemoryTerminalRepository {
+	return &InMemoryTerminalRepository{
+		terminals: make(map[uuid.UUID]*terminal.Terminal),
+	}
+}
+
+func (repo *InMemoryTerminalRepository) Create(ctx context.Context, t *terminal.Terminal) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	stored := *t
+	repo.terminals[t.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryTerminalRepository) GetByID(ctx context.Context, id uuid.UUID) (*terminal.Terminal, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if t, exists := repo.terminals[id]; exists {
+		found := *t
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryTerminalRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*terminal.Terminal, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, t := range repo.terminals {
+		if t.TokenHash == tokenHash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryTerminalRepository) Update(ctx context.Context, t *terminal.Terminal) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	existing, exists := repo.terminals[t.ID]
+	if !exists {
+		return errors.New("terminal not found")
+	}
+	stored := *t
+	stored.LastSeenAt = existing.LastSeenAt
+	repo.terminals[t.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryTerminalRepository) UpdateLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if t, exists := repo.terminals[id]; exists {
+		t.LastSeenAt = &at
+	}
+	return nil
+}
+
+func (repo *InMemoryTerminalRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.terminals[id]; exists {
+		delete(repo.terminals, id)
+		return nil
+	}
+	return errors.New("terminal not found")
+}
+
+func (repo *InMemoryTerminalRepository) List(ctx context.Context) ([]*terminal.Terminal, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	terminals := make([]*terminal.Terminal, 0, len(repo.terminals))
+	for _, t := range repo.terminals {
+		found := *t
+		terminals = append(terminals, &found)
+	}
+	sort.Slice(terminals, func(i, j int) bool { return terminals[i].Name < terminals[j].Name })
+	return terminals, nil
+}
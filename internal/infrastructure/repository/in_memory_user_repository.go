@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"andressa-lanches/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+type InMemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]*user.User
+}
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users: make(map[uuid.UUID]*user.User),
+	}
+}
+
+func (repo *InMemoryUserRepository) Create(ctx context.Context, u *user.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.users {
+		if existing.Username == u.Username {
+			return user.ErrUsernameTaken
+		}
+	}
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	stored := *u
+	repo.users[u.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if u, exists := repo.users[id]; exists {
+		found := *u
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, u := range repo.users {
+		if u.Username == username {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repo *InMemoryUserRepository) Update(ctx context.Context, u *user.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.users[u.ID]; !exists {
+		return errors.New("user not found")
+	}
+	stored := *u
+	repo.users[u.ID] = &stored
+	return nil
+}
+
+func (repo *InMemoryUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.users[id]; exists {
+		delete(repo.users, id)
+		return nil
+	}
+	return errors.New("user not found")
+}
+
+func (repo *InMemoryUserRepository) List(ctx context.Context) ([]*user.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(repo.users))
+	for _, u := range repo.users {
+		found := *u
+		users = append(users, &found)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+func (repo *InMemoryUserRepository) CountActiveByRole(ctx context.Context, role user.Role) (int, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	count := 0
+	for _, u := range repo.users {
+		if u.Role == role && u.Active {
+			count++
+		}
+	}
+	return count, nil
+}
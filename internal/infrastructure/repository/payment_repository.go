@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/payment"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const paymentChargeColumns = `id, sale_id, provider, provider_charge_id, method, amount, status, pix_payload, checkout_url,
+               created_at, updated_at`
+
+type PaymentRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewPaymentRepository(pool *pgxpool.Pool) *PaymentRepository {
+	return &PaymentRepository{Pool: pool}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, c *payment.Charge) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// A trava na venda enfileira as cobranças criadas ao mesmo tempo.
+	var total float64
+	if err = tx.QueryRow(ctx, `SELECT total_amount FROM sales WHERE id = $1 FOR UPDATE`, c.SaleID).Scan(&total); err != nil {
+		return err
+	}
+	var committed float64
+	balanceQuery := `
+        SELECT COALESCE((SELECT SUM(amount) FROM sale_payments WHERE sale_id = $1), 0)
+             + COALESCE((SELECT SUM(amount) FROM payment_charges WHERE sale_id = $1 AND status = $2), 0)
+    `
+	if err = tx.QueryRow(ctx, balanceQuery, c.SaleID, payment.StatusPending).Scan(&committed); err != nil {
+		return err
+	}
+	if c.Amount > total-committed+0.005 {
+		err = payment.ErrChargeExceedsBalance
+		return err
+	}
+
+	query := `
+        INSERT INTO payment_charges (sale_id, provider, provider_charge_id, method, amount, status, pix_payload,
+                                     checkout_url, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        RETURNING id
+    `
+	err = tx.QueryRow(ctx, query,
+		c.SaleID, c.Provider, c.ProviderChargeID, c.Method, c.Amount, c.Status, c.PixPayload,
+		c.CheckoutURL, c.CreatedAt, c.UpdatedAt,
+	).Scan(&c.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*payment.Charge, error) {
+	query := `
+        SELECT ` + paymentChargeColumns + `
+        FROM payment_charges
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *PaymentRepository) GetByProviderChargeID(ctx context.Context, provider, providerChargeID string) (*payment.Charge, error) {
+	query := `
+        SELECT ` + paymentChargeColumns + `
+        FROM payment_charges
+        WHERE provider = $1 AND provider_charge_id = $2
+    `
+	return r.get(ctx, query, provider, providerChargeID)
+}
+
+func (r *PaymentRepository) ListBySale(ctx context.Context, saleID uuid.UUID) ([]*payment.Charge, error) {
+	query := `
+        SELECT ` + paymentChargeColumns + `
+        FROM payment_charges
+        WHERE sale_id = $1
+        ORDER BY created_at
+    `
+	rows, err := r.Pool.Query(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var charges []*payment.Charge
+	for rows.Next() {
+		var c payment.Charge
+		if err := scanPaymentCharge(rows, &c); err != nil {
+			return nil, err
+		}
+		charges = append(charges, &c)
+	}
+	return charges, rows.Err()
+}
+
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, c *payment.Charge, to payment.Status, entry *audit.Entry) (changed bool, err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !changed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	query := `
+        UPDATE payment_charges
+        SET status = $1, updated_at = $2
+        WHERE id = $3 AND status = $4
+    `
+	result, err := tx.Exec(ctx, query, to, time.Now(), c.ID, c.Status)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	switch to {
+	case payment.StatusConfirmed:
+		_, err = tx.Exec(ctx, insertSalePaymentQuery, c.SaleID, c.Method, c.Amount, c.Reference())
+	case payment.StatusRefunded:
+		_, err = tx.Exec(ctx, `DELETE FROM sale_payments WHERE sale_id = $1 AND reference = $2`, c.SaleID, c.Reference())
+	}
+	if err != nil {
+		return false, err
+	}
+	if entry != nil {
+		if err = insertAuditEntry(ctx, tx, entry); err != nil {
+			return false, err
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *PaymentRepository) RegisterWebhookEvent(ctx context.Context, provider, eventID string) (bool, error) {
+	query := `
+        INSERT INTO payment_webhook_events (provider, event_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `
+	result, err := r.Pool.Exec(ctx, query, provider, eventID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *PaymentRepository) ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error {
+	query := `
+        DELETE FROM payment_webhook_events
+        WHERE provider = $1 AND event_id = $2
+    `
+	_, err := r.Pool.Exec(ctx, query, provider, eventID)
+	return err
+}
+
+func (r *PaymentRepository) get(ctx context.Context, query string, args ...any) (*payment.Charge, error) {
+	var c payment.Charge
+	err := scanPaymentCharge(r.Pool.QueryRow(ctx, query, args...), &c)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanPaymentCharge(row pgx.Row, c *payment.Charge) error {
+	return row.Scan(
+		&c.ID, &c.SaleID, &c.Provider, &c.ProviderChargeID, &c.Method, &c.Amount, &c.Status, &c.PixPayload, &c.CheckoutURL,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/pricing"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	priceChangeColumns     = `id, item_type, item_id, old_price, new_price, changed_by, actor, scheduled_change_id, changed_at`
+	scheduledChangeColumns = `id, item_type, item_id, price, effective_at, status, created_by, created_at, applied_at, canceled_at`
+)
+
+type PriceRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewPriceRepository(pool *pgxpool.Pool) *PriceRepository {
+	return &PriceRepository{Pool: pool}
+}
+
+func (r *PriceRepository) AppendChange(ctx context.Context, c *pricing.PriceChange) error {
+	query := `
+        INSERT INTO price_history (item_type, item_id, old_price, new_price, changed_by, actor, scheduled_change_id, changed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query, c.ItemType, c.ItemID, c.OldPrice, c.NewPrice, c.ChangedBy, c.Actor, c.ScheduledChangeID, c.ChangedAt).Scan(&c.ID)
+}
+
+func (r *PriceRepository) ListChanges(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.PriceChange, error) {
+	query := `
+        SELECT ` + priceChangeColumns + `
+        FROM price_history
+        WHERE item_type = $1 AND item_id = $2
+        ORDER BY changed_at DESC
+    `
+	rows, err := r.Pool.Query(ctx, query, itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*pricing.PriceChange
+	for rows.Next() {
+		var c pricing.PriceChange
+		if err := rows.Scan(&c.ID, &c.ItemType, &c.ItemID, &c.OldPrice, &c.NewPrice, &c.ChangedBy, &c.Actor, &c.ScheduledChangeID, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &c)
+	}
+	return changes, rows.Err()
+}
+
+func (r *PriceRepository) CreateSchedule(ctx context.Context, s *pricing.ScheduledChange) error {
+	query := `
+        INSERT INTO scheduled_price_changes (item_type, item_id, price, effective_at, status, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query, s.ItemType, s.ItemID, s.Price, s.EffectiveAt, s.Status, s.CreatedBy, s.CreatedAt).Scan(&s.ID)
+}
+
+func (r *PriceRepository) GetSchedule(ctx context.Context, id uuid.UUID) (*pricing.ScheduledChange, error) {
+	query := `
+        SELECT ` + scheduledChangeColumns + `
+        FROM scheduled_price_changes
+        WHERE id = $1
+    `
+	var s pricing.ScheduledChange
+	if err := scanScheduledChange(r.Pool.QueryRow(ctx, query, id), &s); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PriceRepository) ListSchedules(ctx context.Context, itemType pricing.ItemType, itemID uuid.UUID) ([]*pricing.ScheduledChange, error) {
+	query := `
+        SELECT ` + scheduledChangeColumns + `
+        FROM scheduled_price_changes
+        WHERE item_type = $1 AND item_id = $2
+        ORDER BY effective_at
+    `
+	return r.listSchedules(ctx, query, itemType, itemID)
+}
+
+func (r *PriceRepository) ListDueSchedules(ctx context.Context, now time.Time) ([]*pricing.ScheduledChange, error) {
+	query := `
+        SELECT ` + scheduledChangeColumns + `
+        FROM scheduled_price_changes
+        WHERE status = $1 AND effective_at <= $2
+        ORDER BY effective_at, created_at
+    `
+	return r.listSchedules(ctx, query, pricing.ScheduleStatusPending, now)
+}
+
+func (r *PriceRepository) ResolveSchedule(ctx context.Context, id uuid.UUID, status pricing.ScheduleStatus, at time.Time) (bool, error) {
+	var appliedAt, canceledAt *time.Time
+	switch status {
+	case pricing.ScheduleStatusApplied:
+		appliedAt = &at
+	case pricing.ScheduleStatusCanceled:
+		canceledAt = &at
+	}
+
+	query := `
+        UPDATE scheduled_price_changes
+        SET status = $1, applied_at = $2, canceled_at = $3
+        WHERE id = $4 AND status = $5
+    `
+	tag, err := r.Pool.Exec(ctx, query, status, appliedAt, canceledAt, id, pricing.ScheduleStatusPending)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PriceRepository) listSchedules(ctx context.Context, query string, args ...any) ([]*pricing.ScheduledChange, error) {
+	rows, err := r.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*pricing.ScheduledChange
+	for rows.Next() {
+		var s pricing.ScheduledChange
+		if err := scanScheduledChange(rows, &s); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &s)
+	}
+	return schedules, rows.Err()
+}
+
+func scanScheduledChange(row pgx.Row, s *pricing.ScheduledChange) error {
+	return row.Scan(&s.ID, &s.ItemType, &s.ItemID, &s.Price, &s.EffectiveAt, &s.Status, &s.CreatedBy, &s.CreatedAt, &s.AppliedAt, &s.CanceledAt)
+}
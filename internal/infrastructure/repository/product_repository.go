@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 	"context"
 
@@ -17,26 +19,41 @@ func NewProductRepository(pool *pgxpool.Pool) *ProductRepository {
 	return &ProductRepository{Pool: pool}
 }
 
-func (r *ProductRepository) Create(ctx context.Context, p *product.Product) error {
+func (r *ProductRepository) Create(ctx context.Context, p *product.Product, change *pricing.PriceChange, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
-        INSERT INTO products (name, price, description, category_id)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id
+        INSERT INTO products (id, name, price, unit_cost, description, category_id, external_code, ` + fiscalDataColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12, $13, $14, $15, $16)
     `
-	err := r.Pool.QueryRow(ctx, query, p.Name, p.Price, p.Description, p.CategoryID).Scan(&p.ID)
-	return err
+	args := append([]any{p.ID, p.Name, p.Price, p.UnitCost, p.Description, p.CategoryID, p.ExternalCode}, fiscalDataValues(&p.FiscalData)...)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+	if err = insertHistory(ctx, tx, change, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*product.Product, error) {
 	query := `
-		SELECT id, name, price, description, category_id
+		SELECT ` + productColumns + `
 		FROM products
 		WHERE id = $1
 	`
 	row := r.Pool.QueryRow(ctx, query, id)
 
 	var p product.Product
-	err := row.Scan(&p.ID, &p.Name, &p.Price, &p.Description, &p.CategoryID)
+	err := row.Scan(productDest(&p)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -46,28 +63,64 @@ func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*product
 	return &p, nil
 }
 
-func (r *ProductRepository) Update(ctx context.Context, product *product.Product) error {
+func (r *ProductRepository) Update(ctx context.Context, p *product.Product, oldPrice float64, change *pricing.PriceChange, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
 		UPDATE products
-		SET name = $1, price = $2, description = $3, category_id = $4
-		WHERE id = $5
+		SET name = $1, price = $2, unit_cost = $3, description = $4, category_id = $5, external_code = NULLIF($6, ''), ncm = $7,
+		    cest = $8, cfop = $9, cst = $10, origin = $11, unit = $12, icms_rate = $13, pis_rate = $14, cofins_rate = $15
+		WHERE id = $16 AND price = $17
 	`
-	_, err := r.Pool.Exec(ctx, query, product.Name, product.Price, product.Description, product.CategoryID, product.ID)
-	return err
+	args := append([]any{p.Name, p.Price, p.UnitCost, p.Description, p.CategoryID, p.ExternalCode}, fiscalDataValues(&p.FiscalData)...)
+	result, err := tx.Exec(ctx, query, append(args, p.ID, oldPrice)...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return product.ErrProductConflict
+	}
+	if err = insertHistory(ctx, tx, change, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
 	query := `
         DELETE FROM products
         WHERE id = $1
     `
-	_, err := r.Pool.Exec(ctx, query, id)
-	return err
+	if _, err = tx.Exec(ctx, query, id); err != nil {
+		return err
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *ProductRepository) List(ctx context.Context) ([]*product.Product, error) {
 	query := `
-		SELECT id, name, price, description, category_id
+		SELECT ` + productColumns + `
 		FROM products
 	`
 	rows, err := r.Pool.Query(ctx, query)
@@ -79,7 +132,7 @@ func (r *ProductRepository) List(ctx context.Context) ([]*product.Product, error
 	var products []*product.Product
 	for rows.Next() {
 		var p product.Product
-		err = rows.Scan(&p.ID, &p.Name, &p.Price, &p.Description, &p.CategoryID)
+		err = rows.Scan(productDest(&p)...)
 		if err != nil {
 			return nil, err
 		}
@@ -87,3 +140,9 @@ func (r *ProductRepository) List(ctx context.Context) ([]*product.Product, error
 	}
 	return products, nil
 }
+
+const productColumns = `id, name, price, unit_cost, description, category_id, COALESCE(external_code, ''), ` + fiscalDataColumns
+
+func productDest(p *product.Product) []any {
+	return append([]any{&p.ID, &p.Name, &p.Price, &p.UnitCost, &p.Description, &p.CategoryID, &p.ExternalCode}, fiscalDataDest(&p.FiscalData)...)
+}
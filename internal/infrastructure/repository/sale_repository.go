@@ -2,15 +2,42 @@ package repository
 
 import (
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/sale"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const saleColumns = `id, order_number, date, business_date, order_type, status, employee, operator_id, terminal_id, total_amount, discount,
+               additional_charges, service_charge, service_charge_waived, service_charge_waiver_reason`
+
+const saleItemsQuery = `
+        SELECT si.sale_id, si.item_id, si.product_id, COALESCE(p.name, ''), COALESCE(p.category_id, '00000000-0000-0000-0000-000000000000'),
+               si.station_id, si.status, si.quantity, si.unit_price, si.total_price, si.unit_cost, si.total_cost, si.notes
+        FROM sale_items si
+        LEFT JOIN products p ON p.id = si.product_id
+        WHERE si.sale_id = $1
+        ORDER BY si.item_id
+    `
+
+const salePaymentsQuery = `
+        SELECT method, amount, reference
+        FROM sale_payments
+        WHERE sale_id = $1
+        ORDER BY payment_id
+    `
+
+const insertSalePaymentQuery = `
+        INSERT INTO sale_payments (sale_id, method, amount, reference)
+        VALUES ($1, $2, $3, $4)
+    `
+
 type SaleRepository struct {
 	Pool *pgxpool.Pool
 }
@@ -19,7 +46,7 @@ func NewSaleRepository(pool *pgxpool.Pool) *SaleRepository {
 	return &SaleRepository{Pool: pool}
 }
 
-func (r *SaleRepository) Create(ctx context.Context, s *sale.Sale) error {
+func (r *SaleRepository) Create(ctx context.Context, s *sale.Sale, entry *audit.Entry) (err error) {
 	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -35,30 +62,51 @@ func (r *SaleRepository) Create(ctx context.Context, s *sale.Sale) error {
 		}
 	}()
 
+	// O upsert trava a linha do dia até o fim da transação, serializando a
+	// numeração entre criações concorrentes.
+	orderNumberQuery := `
+        INSERT INTO order_number_sequences (business_date, last_number)
+        VALUES ($1, 1)
+        ON CONFLICT (business_date)
+        DO UPDATE SET last_number = order_number_sequences.last_number + 1
+        RETURNING last_number
+    `
+	err = tx.QueryRow(ctx, orderNumberQuery, s.BusinessDate).Scan(&s.OrderNumber)
+	if err != nil {
+		return err
+	}
+
 	saleQuery := `
-        INSERT INTO sales (date, total_amount, discount, additional_charges)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id
+        INSERT INTO sales (id, order_number, date, business_date, order_type, status, employee, operator_id, terminal_id, total_amount,
+                           discount, additional_charges, service_charge, service_charge_waived, service_charge_waiver_reason)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
     `
-	err = tx.QueryRow(ctx, saleQuery, s.Date, s.TotalAmount, s.Discount, s.AdditionalCharges).Scan(&s.ID)
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	_, err = tx.Exec(ctx, saleQuery,
+		s.ID, s.OrderNumber, s.Date, s.BusinessDate, s.OrderType, s.Status, s.Employee, s.OperatorID, s.TerminalID, s.TotalAmount, s.Discount,
+		s.AdditionalCharges, s.ServiceCharge, s.ServiceChargeWaived, s.ServiceChargeWaiverReason,
+	)
 	if err != nil {
 		return err
 	}
 
 	saleItemQuery := `
-        INSERT INTO sale_items (sale_id, product_id, quantity, unit_price, total_price)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO sale_items (sale_id, product_id, station_id, status, quantity, unit_price, total_price, unit_cost, total_cost, notes)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
         RETURNING item_id
     `
 
 	saleItemAdditionQuery := `
-        INSERT INTO sale_item_additions (sale_id, item_id, addition_id)
-        VALUES ($1, $2, $3)
+        INSERT INTO sale_item_additions (sale_id, item_id, addition_id, unit_price, unit_cost)
+        VALUES ($1, $2, $3, $4, $5)
     `
 
 	for i := range s.Items {
 		item := &s.Items[i]
-		err = tx.QueryRow(ctx, saleItemQuery, s.ID, item.ProductID, item.Quantity, item.UnitPrice, item.TotalPrice).Scan(&item.ItemID)
+		err = tx.QueryRow(ctx, saleItemQuery, s.ID, item.ProductID, item.StationID, item.Status, item.Quantity, item.UnitPrice, item.TotalPrice,
+			item.UnitCost, item.TotalCost, item.Notes).Scan(&item.ItemID)
 		if err != nil {
 			return err
 		}
@@ -66,7 +114,7 @@ func (r *SaleRepository) Create(ctx context.Context, s *sale.Sale) error {
 		if len(item.Additions) > 0 {
 			batch := &pgx.Batch{}
 			for _, addition := range item.Additions {
-				batch.Queue(saleItemAdditionQuery, s.ID, item.ItemID, addition.ID)
+				batch.Queue(saleItemAdditionQuery, s.ID, item.ItemID, addition.ID, addition.Price, addition.UnitCost)
 			}
 			results := tx.SendBatch(ctx, batch)
 			for range item.Additions {
@@ -83,20 +131,35 @@ func (r *SaleRepository) Create(ctx context.Context, s *sale.Sale) error {
 		}
 	}
 
-	err = tx.Commit(ctx)
-	return err
+	for _, payment := range s.Payments {
+		_, err = tx.Exec(ctx, insertSalePaymentQuery, s.ID, payment.Method, payment.Amount, payment.Reference)
+		if err != nil {
+			return err
+		}
+	}
+
+	if entry != nil {
+		entry.Changes, err = audit.Diff(nil, s)
+		if err != nil {
+			return err
+		}
+		if err = insertAuditEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *SaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*sale.Sale, error) {
 	saleQuery := `
-        SELECT id, date, total_amount, discount, additional_charges
+        SELECT ` + saleColumns + `
         FROM sales
         WHERE id = $1
     `
 	row := r.Pool.QueryRow(ctx, saleQuery, id)
 
 	var s sale.Sale
-	err := row.Scan(&s.ID, &s.Date, &s.TotalAmount, &s.Discount, &s.AdditionalCharges)
+	err := scanSale(row, &s)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -104,11 +167,6 @@ func (r *SaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*sale.Sale,
 		return nil, err
 	}
 
-	saleItemsQuery := `
-        SELECT sale_id, item_id, product_id, quantity, unit_price, total_price
-        FROM sale_items
-        WHERE sale_id = $1
-    `
 	rows, err := r.Pool.Query(ctx, saleItemsQuery, s.ID)
 	if err != nil {
 		return nil, err
@@ -118,13 +176,13 @@ func (r *SaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*sale.Sale,
 	var items []sale.SaleItem
 	for rows.Next() {
 		var item sale.SaleItem
-		err := rows.Scan(&item.SaleID, &item.ItemID, &item.ProductID, &item.Quantity, &item.UnitPrice, &item.TotalPrice)
+		err := scanSaleItem(rows, &item)
 		if err != nil {
 			return nil, err
 		}
 
 		additionsQuery := `
-            SELECT a.id, a.name, a.price
+            SELECT a.id, a.name, COALESCE(sia.unit_price, a.price), sia.unit_cost
             FROM sale_item_additions sia
             INNER JOIN additions a ON sia.addition_id = a.id
             WHERE sia.sale_id = $1 AND sia.item_id = $2
@@ -137,7 +195,7 @@ func (r *SaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*sale.Sale,
 		var additions []addition.Addition
 		for additionRows.Next() {
 			var add addition.Addition
-			err := additionRows.Scan(&add.ID, &add.Name, &add.Price)
+			err := additionRows.Scan(&add.ID, &add.Name, &add.Price, &add.UnitCost)
 			if err != nil {
 				additionRows.Close()
 				return nil, err
@@ -151,16 +209,125 @@ func (r *SaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*sale.Sale,
 	}
 
 	s.Items = items
+
+	s.Payments, err = r.listPayments(ctx, s.ID)
+	if err != nil {
+		return nil, err
+	}
 	return &s, nil
 }
 
 func (r *SaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 	salesQuery := `
-        SELECT id, date, total_amount, discount, additional_charges
+        SELECT ` + saleColumns + `
         FROM sales
         ORDER BY date DESC
     `
-	salesRows, err := r.Pool.Query(ctx, salesQuery)
+	return r.list(ctx, salesQuery)
+}
+
+func (r *SaleRepository) ListByPeriod(ctx context.Context, start, end time.Time) ([]*sale.Sale, error) {
+	salesQuery := `
+        SELECT ` + saleColumns + `
+        FROM sales
+        WHERE date >= $1 AND date < $2
+        ORDER BY date DESC
+    `
+	return r.list(ctx, salesQuery, start, end)
+}
+
+// SumItemsByPeriod agrega no banco os itens e os acréscimos vendidos, com o
+// preço e o custo gravados em cada venda.
+func (r *SaleRepository) SumItemsByPeriod(ctx context.Context, start, end time.Time) ([]sale.ItemSales, error) {
+	query := `
+        SELECT $4, si.product_id, COALESCE(MAX(p.name), ''), SUM(si.quantity), SUM(si.unit_price * si.quantity),
+               SUM(COALESCE(si.unit_cost, 0) * si.quantity), SUM(CASE WHEN si.unit_cost IS NULL THEN si.quantity ELSE 0 END)
+        FROM sale_items si
+        INNER JOIN sales s ON s.id = si.sale_id
+        LEFT JOIN products p ON p.id = si.product_id
+        WHERE s.date >= $1 AND s.date < $2 AND s.status <> $3
+        GROUP BY si.product_id
+        UNION ALL
+        SELECT $5, a.id, MAX(a.name), SUM(si.quantity), SUM(COALESCE(sia.unit_price, a.price) * si.quantity),
+               SUM(COALESCE(sia.unit_cost, 0) * si.quantity), SUM(CASE WHEN sia.unit_cost IS NULL THEN si.quantity ELSE 0 END)
+        FROM sale_item_additions sia
+        INNER JOIN sale_items si ON si.sale_id = sia.sale_id AND si.item_id = sia.item_id
+        INNER JOIN sales s ON s.id = sia.sale_id
+        INNER JOIN additions a ON a.id = sia.addition_id
+        WHERE s.date >= $1 AND s.date < $2 AND s.status <> $3
+        GROUP BY a.id
+    `
+	rows, err := r.Pool.Query(ctx, query, start, end, sale.StatusCanceled, pricing.ItemProduct, pricing.ItemAddition)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []sale.ItemSales
+	for rows.Next() {
+		var t sale.ItemSales
+		if err := rows.Scan(&t.ItemType, &t.ItemID, &t.Name, &t.Quantity, &t.Revenue, &t.Cost, &t.UncostedQuantity); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// SumByWeekdayHour usa o dia da semana do business_date, já gravado no dia
+// de operação, e a hora de date convertida para o fuso da loja.
+func (r *SaleRepository) SumByWeekdayHour(ctx context.Context, start, end time.Time, timezone string) ([]sale.HeatmapCell, error) {
+	query := `
+        SELECT EXTRACT(DOW FROM business_date)::int AS weekday, EXTRACT(HOUR FROM date AT TIME ZONE $3)::int AS hour,
+               COUNT(*), SUM(total_amount)
+        FROM sales
+        WHERE date >= $1 AND date < $2 AND status <> $4
+        GROUP BY weekday, hour
+    `
+	rows, err := r.Pool.Query(ctx, query, start, end, timezone, sale.StatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cells []sale.HeatmapCell
+	for rows.Next() {
+		var c sale.HeatmapCell
+		var weekday int
+		if err := rows.Scan(&weekday, &c.Hour, &c.Orders, &c.Revenue); err != nil {
+			return nil, err
+		}
+		c.Weekday = time.Weekday(weekday)
+		cells = append(cells, c)
+	}
+	return cells, rows.Err()
+}
+
+func (r *SaleRepository) ListByStatus(ctx context.Context, statuses ...sale.Status) ([]*sale.Sale, error) {
+	salesQuery := `
+        SELECT ` + saleColumns + `
+        FROM sales
+        WHERE status = ANY($1)
+        ORDER BY date
+    `
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return r.list(ctx, salesQuery, values)
+}
+
+func (r *SaleRepository) ListByOrderNumber(ctx context.Context, businessDate time.Time, orderNumber int) ([]*sale.Sale, error) {
+	salesQuery := `
+        SELECT ` + saleColumns + `
+        FROM sales
+        WHERE business_date = $1 AND order_number = $2
+    `
+	return r.list(ctx, salesQuery, businessDate, orderNumber)
+}
+
+func (r *SaleRepository) list(ctx context.Context, salesQuery string, args ...any) ([]*sale.Sale, error) {
+	salesRows, err := r.Pool.Query(ctx, salesQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -169,16 +336,11 @@ func (r *SaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 	var salesList []*sale.Sale
 	for salesRows.Next() {
 		var s sale.Sale
-		err := salesRows.Scan(&s.ID, &s.Date, &s.TotalAmount, &s.Discount, &s.AdditionalCharges)
+		err := scanSale(salesRows, &s)
 		if err != nil {
 			return nil, err
 		}
 
-		saleItemsQuery := `
-            SELECT sale_id, item_id, product_id, quantity, unit_price, total_price
-            FROM sale_items
-            WHERE sale_id = $1
-        `
 		itemsRows, err := r.Pool.Query(ctx, saleItemsQuery, s.ID)
 		if err != nil {
 			return nil, err
@@ -187,14 +349,14 @@ func (r *SaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 		var items []sale.SaleItem
 		for itemsRows.Next() {
 			var item sale.SaleItem
-			err := itemsRows.Scan(&item.SaleID, &item.ItemID, &item.ProductID, &item.Quantity, &item.UnitPrice, &item.TotalPrice)
+			err := scanSaleItem(itemsRows, &item)
 			if err != nil {
 				itemsRows.Close()
 				return nil, err
 			}
 
 			additionsQuery := `
-                SELECT a.id, a.name, a.price
+                SELECT a.id, a.name, COALESCE(sia.unit_price, a.price), sia.unit_cost
                 FROM sale_item_additions sia
                 INNER JOIN additions a ON sia.addition_id = a.id
                 WHERE sia.sale_id = $1 AND sia.item_id = $2
@@ -208,7 +370,7 @@ func (r *SaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 			var additions []addition.Addition
 			for additionRows.Next() {
 				var add addition.Addition
-				err := additionRows.Scan(&add.ID, &add.Name, &add.Price)
+				err := additionRows.Scan(&add.ID, &add.Name, &add.Price, &add.UnitCost)
 				if err != nil {
 					additionRows.Close()
 					itemsRows.Close()
@@ -224,13 +386,80 @@ func (r *SaleRepository) List(ctx context.Context) ([]*sale.Sale, error) {
 		itemsRows.Close()
 
 		s.Items = items
+
+		s.Payments, err = r.listPayments(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
 		salesList = append(salesList, &s)
 	}
 
 	return salesList, nil
 }
 
-func (r *SaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *SaleRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status sale.Status, entry *audit.Entry) error {
+	query := `
+        UPDATE sales
+        SET status = $1
+        WHERE id = $2
+    `
+	return r.update(ctx, entry, sale.ErrSaleNotFound, query, status, id)
+}
+
+func (r *SaleRepository) UpdateItemNotes(ctx context.Context, saleID uuid.UUID, itemID int, notes string, entry *audit.Entry) error {
+	query := `
+        UPDATE sale_items
+        SET notes = $1
+        WHERE sale_id = $2 AND item_id = $3
+    `
+	return r.update(ctx, entry, sale.ErrSaleItemNotFound, query, notes, saleID, itemID)
+}
+
+func (r *SaleRepository) UpdateItemStatus(ctx context.Context, saleID uuid.UUID, itemID int, status sale.ItemStatus, entry *audit.Entry) error {
+	query := `
+        UPDATE sale_items
+        SET status = $1
+        WHERE sale_id = $2 AND item_id = $3
+    `
+	return r.update(ctx, entry, sale.ErrSaleItemNotFound, query, status, saleID, itemID)
+}
+
+func (r *SaleRepository) UpdateStationItemsStatus(ctx context.Context, saleID, stationID uuid.UUID, status sale.ItemStatus, entry *audit.Entry) error {
+	query := `
+        UPDATE sale_items
+        SET status = $1
+        WHERE sale_id = $2 AND station_id = $3
+    `
+	return r.update(ctx, entry, sale.ErrStationTicketNotFound, query, status, saleID, stationID)
+}
+
+// update executa a alteração e grava a auditoria na mesma transação,
+// devolvendo notFound quando a alteração não encontra nenhuma linha.
+func (r *SaleRepository) update(ctx context.Context, entry *audit.Entry, notFound error, query string, args ...any) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return notFound
+	}
+	if err = insertHistory(ctx, tx, nil, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *SaleRepository) Delete(ctx context.Context, id uuid.UUID, entry *audit.Entry) (err error) {
 	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -246,6 +475,22 @@ func (r *SaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		}
 	}()
 
+	// Cobranças e documentos fiscais registram o que aconteceu fora do
+	// sistema e não são apagados com a venda.
+	var hasDocuments bool
+	documentsQuery := `
+        SELECT EXISTS (SELECT 1 FROM payment_charges WHERE sale_id = $1)
+            OR EXISTS (SELECT 1 FROM fiscal_documents WHERE sale_id = $1)
+    `
+	err = tx.QueryRow(ctx, documentsQuery, id).Scan(&hasDocuments)
+	if err != nil {
+		return err
+	}
+	if hasDocuments {
+		err = sale.ErrSaleHasDocuments
+		return err
+	}
+
 	deleteAdditionsQuery := `
         DELETE FROM sale_item_additions
         WHERE sale_id = $1
@@ -255,6 +500,15 @@ func (r *SaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	deletePaymentsQuery := `
+        DELETE FROM sale_payments
+        WHERE sale_id = $1
+    `
+	_, err = tx.Exec(ctx, deletePaymentsQuery, id)
+	if err != nil {
+		return err
+	}
+
 	deleteItemsQuery := `
         DELETE FROM sale_items
         WHERE sale_id = $1
@@ -273,9 +527,42 @@ func (r *SaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 	if result.RowsAffected() == 0 {
-		return errors.New("venda não encontrada")
+		err = errors.New("venda não encontrada")
+		return err
 	}
 
-	err = tx.Commit(ctx)
+	err = insertHistory(ctx, tx, nil, entry)
 	return err
 }
+
+func (r *SaleRepository) listPayments(ctx context.Context, saleID uuid.UUID) ([]sale.Payment, error) {
+	rows, err := r.Pool.Query(ctx, salePaymentsQuery, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []sale.Payment
+	for rows.Next() {
+		var payment sale.Payment
+		if err := rows.Scan(&payment.Method, &payment.Amount, &payment.Reference); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func scanSale(row pgx.Row, s *sale.Sale) error {
+	return row.Scan(
+		&s.ID, &s.OrderNumber, &s.Date, &s.BusinessDate, &s.OrderType, &s.Status, &s.Employee, &s.OperatorID, &s.TerminalID, &s.TotalAmount,
+		&s.Discount, &s.AdditionalCharges, &s.ServiceCharge, &s.ServiceChargeWaived, &s.ServiceChargeWaiverReason,
+	)
+}
+
+func scanSaleItem(row pgx.Row, item *sale.SaleItem) error {
+	return row.Scan(
+		&item.SaleID, &item.ItemID, &item.ProductID, &item.ProductName, &item.CategoryID,
+		&item.StationID, &item.Status, &item.Quantity, &item.UnitPrice, &item.TotalPrice, &item.UnitCost, &item.TotalCost, &item.Notes,
+	)
+}
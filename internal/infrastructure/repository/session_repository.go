@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/session"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SessionRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewSessionRepository(pool *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{Pool: pool}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, t *session.RefreshToken) error {
+	query := `
+        INSERT INTO refresh_tokens (family_id, user_id, token_hash, access_token_id, access_token_expires_at,
+                                    expires_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query,
+		t.FamilyID, t.UserID, t.TokenHash, t.AccessTokenID, t.AccessTokenExpiresAt, t.ExpiresAt, t.CreatedAt,
+	).Scan(&t.ID)
+}
+
+func (r *SessionRepository) GetByHash(ctx context.Context, tokenHash string) (*session.RefreshToken, error) {
+	query := `
+        SELECT id, family_id, user_id, token_hash, access_token_id, access_token_expires_at, expires_at,
+               created_at, used_at, revoked_at
+        FROM refresh_tokens
+        WHERE token_hash = $1
+    `
+	var t session.RefreshToken
+	err := r.Pool.QueryRow(ctx, query, tokenHash).Scan(
+		&t.ID, &t.FamilyID, &t.UserID, &t.TokenHash, &t.AccessTokenID, &t.AccessTokenExpiresAt, &t.ExpiresAt,
+		&t.CreatedAt, &t.UsedAt, &t.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *SessionRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	query := `
+        UPDATE refresh_tokens
+        SET used_at = $1
+        WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL
+    `
+	result, err := r.Pool.Exec(ctx, query, at, id)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) (err error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	revokeAccessQuery := `
+        INSERT INTO revoked_access_tokens (token_id, expires_at)
+        SELECT access_token_id, access_token_expires_at
+        FROM refresh_tokens
+        WHERE family_id = $1 AND access_token_expires_at > $2
+        ON CONFLICT DO NOTHING
+    `
+	if _, err = tx.Exec(ctx, revokeAccessQuery, familyID, at); err != nil {
+		return err
+	}
+
+	revokeRefreshQuery := `
+        UPDATE refresh_tokens
+        SET revoked_at = $1
+        WHERE family_id = $2 AND revoked_at IS NULL
+    `
+	_, err = tx.Exec(ctx, revokeRefreshQuery, at, familyID)
+	return err
+}
+
+func (r *SessionRepository) RevokeAccessToken(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error {
+	query := `
+        INSERT INTO revoked_access_tokens (token_id, expires_at)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `
+	_, err := r.Pool.Exec(ctx, query, tokenID, expiresAt)
+	return err
+}
+
+func (r *SessionRepository) IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error) {
+	query := `
+        SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE token_id = $1)
+    `
+	var revoked bool
+	err := r.Pool.QueryRow(ctx, query, tokenID).Scan(&revoked)
+	return revoked, err
+}
+
+func (r *SessionRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	query := `
+        DELETE FROM revoked_access_tokens
+        WHERE expires_at < $1
+    `
+	if _, err := r.Pool.Exec(ctx, query, before); err != nil {
+		return err
+	}
+
+	query = `
+        DELETE FROM refresh_tokens
+        WHERE expires_at < $1
+    `
+	_, err := r.Pool.Exec(ctx, query, before)
+	return err
+}
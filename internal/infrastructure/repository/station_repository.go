@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/station"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StationRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewStationRepository(pool *pgxpool.Pool) *StationRepository {
+	return &StationRepository{Pool: pool}
+}
+
+func (r *StationRepository) Create(ctx context.Context, s *station.Station) error {
+	query := `
+        INSERT INTO stations (name)
+        VALUES ($1)
+        RETURNING id
+    `
+	err := r.Pool.QueryRow(ctx, query, s.Name).Scan(&s.ID)
+	return err
+}
+
+func (r *StationRepository) GetByID(ctx context.Context, id uuid.UUID) (*station.Station, error) {
+	query := `
+        SELECT id, name
+        FROM stations
+        WHERE id = $1
+    `
+	row := r.Pool.QueryRow(ctx, query, id)
+
+	var s station.Station
+	err := row.Scan(&s.ID, &s.Name)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *StationRepository) Update(ctx context.Context, s *station.Station) error {
+	query := `
+        UPDATE stations
+        SET name = $1
+        WHERE id = $2
+    `
+	_, err := r.Pool.Exec(ctx, query, s.Name, s.ID)
+	return err
+}
+
+func (r *StationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+        DELETE FROM stations
+        WHERE id = $1
+    `
+	_, err := r.Pool.Exec(ctx, query, id)
+	return err
+}
+
+func (r *StationRepository) List(ctx context.Context) ([]*station.Station, error) {
+	query := `
+        SELECT id, name
+        FROM stations
+        ORDER BY name
+    `
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []*station.Station
+	for rows.Next() {
+		var s station.Station
+		err := rows.Scan(&s.ID, &s.Name)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, &s)
+	}
+	return stations, nil
+}
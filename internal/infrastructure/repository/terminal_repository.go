@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/terminal"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const terminalColumns = `id, name, active, token_hash, last_seen_at, created_at`
+
+type TerminalRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewTerminalRepository(pool *pgxpool.Pool) *TerminalRepository {
+	return &TerminalRepository{Pool: pool}
+}
+
+func (r *TerminalRepository) Create(ctx context.Context, t *terminal.Terminal) error {
+	query := `
+        INSERT INTO terminals (name, active, token_hash, created_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+	return r.Pool.QueryRow(ctx, query, t.Name, t.Active, t.TokenHash, t.CreatedAt).Scan(&t.ID)
+}
+
+func (r *TerminalRepository) GetByID(ctx context.Context, id uuid.UUID) (*terminal.Terminal, error) {
+	query := `
+        SELECT ` + terminalColumns + `
+        FROM terminals
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *TerminalRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*terminal.Terminal, error) {
+	query := `
+        SELECT ` + terminalColumns + `
+        FROM terminals
+        WHERE token_hash = $1
+    `
+	return r.get(ctx, query, tokenHash)
+}
+
+func (r *TerminalRepository) Update(ctx context.Context, t *terminal.Terminal) error {
+	query := `
+        UPDATE terminals
+        SET name = $1, active = $2, token_hash = $3
+        WHERE id = $4
+    `
+	_, err := r.Pool.Exec(ctx, query, t.Name, t.Active, t.TokenHash, t.ID)
+	return err
+}
+
+func (r *TerminalRepository) UpdateLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+        UPDATE terminals
+        SET last_seen_at = $1
+        WHERE id = $2
+    `
+	_, err := r.Pool.Exec(ctx, query, at, id)
+	return err
+}
+
+func (r *TerminalRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+        DELETE FROM terminals
+        WHERE id = $1
+    `
+	_, err := r.Pool.Exec(ctx, query, id)
+	return err
+}
+
+func (r *TerminalRepository) List(ctx context.Context) ([]*terminal.Terminal, error) {
+	query := `
+        SELECT ` + terminalColumns + `
+        FROM terminals
+        ORDER BY name
+    `
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terminals []*terminal.Terminal
+	for rows.Next() {
+		var t terminal.Terminal
+		if err := scanTerminal(rows, &t); err != nil {
+			return nil, err
+		}
+		terminals = append(terminals, &t)
+	}
+	return terminals, rows.Err()
+}
+
+func (r *TerminalRepository) get(ctx context.Context, query string, args ...any) (*terminal.Terminal, error) {
+	var t terminal.Terminal
+	err := scanTerminal(r.Pool.QueryRow(ctx, query, args...), &t)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanTerminal(row pgx.Row, t *terminal.Terminal) error {
+	return row.Scan(&t.ID, &t.Name, &t.Active, &t.TokenHash, &t.LastSeenAt, &t.CreatedAt)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"andressa-lanches/internal/domain/user"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const userColumns = `id, username, name, role, active, password_hash, pin_hash, created_at, updated_at`
+
+type UserRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
+	return &UserRepository{Pool: pool}
+}
+
+// Create devolve user.ErrUsernameTaken quando o nome já está cadastrado,
+// inclusive quando dois cadastros com o mesmo nome chegam ao mesmo tempo.
+func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
+	query := `
+        INSERT INTO users (username, name, role, active, password_hash, pin_hash, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (username) DO NOTHING
+        RETURNING id
+    `
+	err := r.Pool.QueryRow(ctx, query,
+		u.Username, u.Name, u.Role, u.Active, u.PasswordHash, u.PINHash, u.CreatedAt, u.UpdatedAt,
+	).Scan(&u.ID)
+	if err == pgx.ErrNoRows {
+		return user.ErrUsernameTaken
+	}
+	return err
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users
+        WHERE username = $1
+    `
+	return r.get(ctx, query, username)
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	query := `
+        UPDATE users
+        SET username = $1, name = $2, role = $3, active = $4, password_hash = $5, pin_hash = $6, updated_at = $7
+        WHERE id = $8
+    `
+	_, err := r.Pool.Exec(ctx, query, u.Username, u.Name, u.Role, u.Active, u.PasswordHash, u.PINHash, u.UpdatedAt, u.ID)
+	return err
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+        DELETE FROM users
+        WHERE id = $1
+    `
+	_, err := r.Pool.Exec(ctx, query, id)
+	return err
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]*user.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users
+        ORDER BY username
+    `
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		var u user.User
+		if err := scanUser(rows, &u); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) CountActiveByRole(ctx context.Context, role user.Role) (int, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM users
+        WHERE role = $1 AND active
+    `
+	var count int
+	err := r.Pool.QueryRow(ctx, query, role).Scan(&count)
+	return count, err
+}
+
+func (r *UserRepository) get(ctx context.Context, query string, args ...any) (*user.User, error) {
+	var u user.User
+	err := scanUser(r.Pool.QueryRow(ctx, query, args...), &u)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func scanUser(row pgx.Row, u *user.User) error {
+	return row.Scan(&u.ID, &u.Username, &u.Name, &u.Role, &u.Active, &u.PasswordHash, &u.PINHash, &u.CreatedAt, &u.UpdatedAt)
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job executa uma tarefa em segundo plano a cada intervalo até ser
+// encerrado. As falhas são registradas no log e a tarefa roda de novo no
+// próximo intervalo.
+type Job struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func Every(interval time.Duration, name string, task func(ctx context.Context) error) *Job {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &Job{cancel: cancel}
+
+	j.wg.Add(1)
+	go j.run(ctx, interval, name, task)
+	return j
+}
+
+// Close encerra o job, esperando a execução em andamento terminar.
+func (j *Job) Close() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+func (j *Job) run(ctx context.Context, interval time.Duration, name string, task func(ctx context.Context) error) {
+	defer j.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := task(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Falha ao executar %s: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
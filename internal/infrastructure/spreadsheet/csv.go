@@ -0,0 +1,60 @@
+package spreadsheet
+
+import (
+	"andressa-lanches/internal/domain/export"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type csvWriter struct {
+	writer         *csv.Writer
+	decimal        export.Decimal
+	dateLayout     string
+	dateTimeLayout string
+	record         []string
+}
+
+func newCSVWriter(w io.Writer, opts export.Options) *csvWriter {
+	writer := csv.NewWriter(w)
+	if opts.Decimal == export.DecimalComma {
+		writer.Comma = ';'
+	}
+	dateLayout, dateTimeLayout := dateLayouts(opts.DateFormat)
+	return &csvWriter{writer: writer, decimal: opts.Decimal, dateLayout: dateLayout, dateTimeLayout: dateTimeLayout}
+}
+
+func (w *csvWriter) WriteRow(cells []export.Cell) error {
+	w.record = w.record[:0]
+	for _, cell := range cells {
+		w.record = append(w.record, w.format(cell))
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) format(cell export.Cell) string {
+	if cell.Empty {
+		return ""
+	}
+	switch cell.Kind {
+	case export.KindInteger:
+		return strconv.FormatInt(int64(cell.Number), 10)
+	case export.KindMoney:
+		value := strconv.FormatFloat(cell.Number, 'f', 2, 64)
+		if w.decimal == export.DecimalComma {
+			value = strings.Replace(value, ".", ",", 1)
+		}
+		return value
+	case export.KindDateTime:
+		return cell.Time.Format(w.dateTimeLayout)
+	case export.KindDate:
+		return cell.Time.Format(w.dateLayout)
+	}
+	return cell.Text
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
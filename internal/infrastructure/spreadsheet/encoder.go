@@ -0,0 +1,31 @@
+package spreadsheet
+
+import (
+	"andressa-lanches/internal/domain/export"
+	"io"
+)
+
+// Encoder grava planilhas em CSV ou XLSX à medida que as linhas chegam, sem
+// montar o arquivo inteiro em memória.
+type Encoder struct{}
+
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+func (e *Encoder) NewWriter(w io.Writer, sheet string, opts export.Options) (export.Writer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Format == export.FormatXLSX {
+		return newXLSXWriter(w, sheet, opts)
+	}
+	return newCSVWriter(w, opts), nil
+}
+
+func dateLayouts(format export.DateFormat) (string, string) {
+	if format == export.DateBR {
+		return "02/01/2006", "02/01/2006 15:04:05"
+	}
+	return "2006-01-02", "2006-01-02T15:04:05-07:00"
+}
@@ -0,0 +1,193 @@
+package spreadsheet
+
+import (
+	"andressa-lanches/internal/domain/export"
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Índices dos estilos de célula definidos em stylesXML.
+const (
+	styleMoney    = 1
+	styleDate     = 2
+	styleDateTime = 3
+)
+
+// O Excel conta as datas em dias desde 30/12/1899.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="%s"/><numFmt numFmtId="165" formatCode="%s"/></numFmts>
+<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+</styleSheet>`
+
+const sheetStartXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetEndXML = `</sheetData></worksheet>`
+
+// xlsxWriter grava as partes fixas da pasta de trabalho de uma vez e depois
+// escreve a planilha linha a linha, com textos embutidos nas células em vez
+// da tabela de textos compartilhados, que exigiria conhecer todas as linhas.
+// Os valores em dinheiro e as datas são gravados como números formatados,
+// então o separador decimal segue a configuração de quem abre o arquivo.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, sheet string, opts export.Options) (*xlsxWriter, error) {
+	dateCode, dateTimeCode := "yyyy-mm-dd", "yyyy-mm-dd hh:mm:ss"
+	if opts.DateFormat == export.DateBR {
+		dateCode, dateTimeCode = "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"
+	}
+
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/workbook.xml", sprintfXML(workbookXML, sheet)},
+		{"xl/styles.xml", sprintfXML(stylesXML, dateCode, dateTimeCode)},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheetWriter := bufio.NewWriter(entry)
+	if _, err := sheetWriter.WriteString(sheetStartXML); err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{archive: archive, sheet: sheetWriter}, nil
+}
+
+func (w *xlsxWriter) WriteRow(cells []export.Cell) error {
+	w.row++
+	row := strconv.Itoa(w.row)
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		if cell.Empty {
+			continue
+		}
+		ref := columnName(i) + row
+		switch cell.Kind {
+		case export.KindText:
+			w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w.sheet, []byte(cell.Text)); err != nil {
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		case export.KindInteger:
+			w.writeNumber(ref, 0, cell.Number)
+		case export.KindMoney:
+			w.writeNumber(ref, styleMoney, cell.Number)
+		case export.KindDate:
+			w.writeNumber(ref, styleDate, excelSerial(cell.Time))
+		case export.KindDateTime:
+			w.writeNumber(ref, styleDateTime, excelSerial(cell.Time))
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) writeNumber(ref string, style int, value float64) {
+	w.sheet.WriteString(`<c r="` + ref + `"`)
+	if style != 0 {
+		w.sheet.WriteString(` s="` + strconv.Itoa(style) + `"`)
+	}
+	w.sheet.WriteString(`><v>` + strconv.FormatFloat(value, 'f', -1, 64) + `</v></c>`)
+}
+
+func (w *xlsxWriter) Close() error {
+	if _, err := w.sheet.WriteString(sheetEndXML); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// excelSerial converte o horário local de t, ignorando o fuso, para o número
+// de dias usado pelo Excel.
+func excelSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return float64(wall.Sub(excelEpoch)/time.Second) / 86400
+}
+
+// columnName devolve o nome da coluna de índice i: A, B, ..., Z, AA, AB...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// sprintfXML preenche format com os valores já escapados para XML.
+func sprintfXML(format string, values ...string) string {
+	escaped := make([]any, len(values))
+	for i, value := range values {
+		var b strings.Builder
+		_ = xml.EscapeText(&b, []byte(value))
+		escaped[i] = b.String()
+	}
+	return fmt.Sprintf(format, escaped...)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,13 +12,16 @@ import (
 )
 
 func RegisterAdditionRoutes(router *gin.RouterGroup, service services.AdditionService) {
+	read := middlewares.RequirePermission(user.PermissionCatalogRead)
+	write := middlewares.RequirePermission(user.PermissionCatalogWrite)
+
 	additions := router.Group("/additions")
 	{
-		additions.POST("/", CreateAdditionHandler(service))
-		additions.GET("/:id", GetAdditionByIDHandler(service))
-		additions.PUT("/:id", UpdateAdditionHandler(service))
-		additions.DELETE("/:id", DeleteAdditionHandler(service))
-		additions.GET("/", ListAdditionsHandler(service))
+		additions.POST("/", write, CreateAdditionHandler(service))
+		additions.GET("/:id", read, GetAdditionByIDHandler(service))
+		additions.PUT("/:id", write, UpdateAdditionHandler(service))
+		additions.DELETE("/:id", write, DeleteAdditionHandler(service))
+		additions.GET("/", read, ListAdditionsHandler(service))
 	}
 }
 
@@ -42,7 +47,7 @@ func CreateAdditionHandler(service services.AdditionService) gin.HandlerFunc {
 		err := service.CreateAddition(c.Request.Context(), &add)
 		if err != nil {
 			switch err {
-			case addition.ErrAdditionNameRequired, addition.ErrAdditionPriceRequired:
+			case addition.ErrAdditionNameRequired, addition.ErrAdditionPriceRequired, addition.ErrAdditionCostNegative:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -94,6 +99,7 @@ func GetAdditionByIDHandler(service services.AdditionService) gin.HandlerFunc {
 // @Success 200 {object} map[string]addition.Addition
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /additions/{id} [put]
@@ -116,6 +122,10 @@ func UpdateAdditionHandler(service services.AdditionService) gin.HandlerFunc {
 
 		err = service.UpdateAddition(c.Request.Context(), &add)
 		if err != nil {
+			if err == addition.ErrAdditionConflict {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
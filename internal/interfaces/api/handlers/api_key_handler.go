@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyInput é o corpo de criação de chaves de API.
+type APIKeyInput struct {
+	Name   string            `json:"name" binding:"required"`
+	Scopes []user.Permission `json:"scopes" binding:"required"`
+}
+
+// RegisterAPIKeyRoutes registra o cadastro das chaves de API usadas pelas
+// integrações.
+func RegisterAPIKeyRoutes(router *gin.RouterGroup, service services.APIKeyService) {
+	keys := router.Group("/api-keys", middlewares.RequirePermission(user.PermissionAPIKeys))
+	{
+		keys.POST("/", CreateAPIKeyHandler(service))
+		keys.GET("/:id", GetAPIKeyByIDHandler(service))
+		keys.POST("/:id/rotate", RotateAPIKeyHandler(service))
+		keys.DELETE("/:id", RevokeAPIKeyHandler(service))
+		keys.GET("/", ListAPIKeysHandler(service))
+	}
+}
+
+// @Summary Create an API Key
+// @Description Cria uma chave de API com os escopos informados e devolve a chave completa, exibida apenas uma vez
+// @Tags API Keys
+// @Accept  json
+// @Produce  json
+// @Param api_key body APIKeyInput true "Chave a ser criada"
+// @Success 201 {object} apikey.Issued
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /api-keys [post]
+func CreateAPIKeyHandler(service services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input APIKeyInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		k := &apikey.APIKey{Name: input.Name, Scopes: input.Scopes}
+		if userID, ok := middlewares.CurrentUserID(c); ok {
+			k.CreatedBy = &userID
+		}
+
+		issued, err := service.CreateAPIKey(c.Request.Context(), k)
+		if err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, issued)
+	}
+}
+
+// @Summary Get API Key by ID
+// @Description Recupera uma chave de API pelo ID, sem o segredo
+// @Tags API Keys
+// @Produce  json
+// @Param id path string true "ID da Chave"
+// @Success 200 {object} map[string]apikey.APIKey
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /api-keys/{id} [get]
+func GetAPIKeyByIDHandler(service services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apikey.ErrAPIKeyIdInvalid.Error()})
+			return
+		}
+
+		k, err := service.GetAPIKeyByID(c.Request.Context(), id)
+		if err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"api_key": k})
+	}
+}
+
+// @Summary Rotate an API Key
+// @Description Gera uma nova chave com os mesmos escopos; a anterior deixa de valer imediatamente
+// @Tags API Keys
+// @Produce  json
+// @Param id path string true "ID da Chave"
+// @Success 200 {object} apikey.Issued
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security BearerAuth
+// @Router /api-keys/{id}/rotate [post]
+func RotateAPIKeyHandler(service services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apikey.ErrAPIKeyIdInvalid.Error()})
+			return
+		}
+
+		issued, err := service.RotateAPIKey(c.Request.Context(), id)
+		if err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, issued)
+	}
+}
+
+// @Summary Revoke an API Key
+// @Description Revoga a chave de API; o registro continua listado com a data da revogação
+// @Tags API Keys
+// @Param id path string true "ID da Chave"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func RevokeAPIKeyHandler(service services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apikey.ErrAPIKeyIdInvalid.Error()})
+			return
+		}
+
+		if err := service.RevokeAPIKey(c.Request.Context(), id); err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary List API Keys
+// @Description Lista as chaves de API, incluindo as revogadas, com o último uso de cada uma
+// @Tags API Keys
+// @Produce  json
+// @Success 200 {object} map[string][]apikey.APIKey
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /api-keys [get]
+func ListAPIKeysHandler(service services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys, err := service.ListAPIKeys(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+	}
+}
+
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch err {
+	case apikey.ErrAPIKeyNameRequired, apikey.ErrAPIKeyIdInvalid, apikey.ErrScopesRequired, apikey.ErrScopeInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case apikey.ErrAPIKeyNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case apikey.ErrAPIKeyRevoked:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
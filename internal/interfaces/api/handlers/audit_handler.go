@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RegisterAuditRoutes registra a consulta da trilha de auditoria.
+func RegisterAuditRoutes(router *gin.RouterGroup, service services.AuditService) {
+	router.GET("/audit", middlewares.RequirePermission(user.PermissionAuditRead), ListAuditEntriesHandler(service))
+}
+
+// @Summary List Audit Entries
+// @Description Lista a trilha de auditoria, mais recentes primeiro: quem criou, alterou ou removeu produtos, categorias, acréscimos e vendas, com os campos alterados
+// @Tags Audit
+// @Produce  json
+// @Param action query string false "Ação (create, update, delete, auth.login_failed, auth.pin_login_failed)"
+// @Param entity_type query string false "Tipo do registro (product, category, addition, sale, payment_charge)"
+// @Param entity_id query string false "ID do registro"
+// @Param actor_id query string false "ID do usuário autor"
+// @Param request_id query string false "ID da requisição"
+// @Param start query string false "Data inicial (AAAA-MM-DD)"
+// @Param end query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param limit query int false "Quantidade máxima de registros (padrão 100, máximo 500)"
+// @Success 200 {object} map[string][]audit.Entry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /audit [get]
+func ListAuditEntriesHandler(service services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseAuditFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries, err := service.ListEntries(c.Request.Context(), filter)
+		if err != nil {
+			if err == audit.ErrFilterInvalid {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"entries": entries})
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (audit.Filter, error) {
+	filter := audit.Filter{
+		Action:     audit.Action(c.Query("action")),
+		EntityType: audit.EntityType(c.Query("entity_type")),
+		RequestID:  c.Query("request_id"),
+	}
+
+	var err error
+	if filter.EntityID, err = optionalUUIDQuery(c, "entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.ActorID, err = optionalUUIDQuery(c, "actor_id"); err != nil {
+		return filter, err
+	}
+
+	// As datas seguem o calendário da loja; o serviço aplica o fuso dela.
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			return filter, errInvalidPeriod
+		}
+		filter.Start = start
+	}
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			return filter, errInvalidPeriod
+		}
+		filter.End = end.AddDate(0, 0, 1)
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, audit.ErrFilterInvalid
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func optionalUUIDQuery(c *gin.Context, name string) (*uuid.UUID, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, audit.ErrFilterInvalid
+	}
+	return &id, nil
+}
@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
-	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type LoginInput struct {
@@ -15,18 +23,45 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type PINLoginInput struct {
+	Username string `json:"username" binding:"required"`
+	PIN      string `json:"pin" binding:"required"`
+}
+
+// DeviceTokenHeader identifica o terminal nas rotas de login com PIN.
+const DeviceTokenHeader = "X-Device-Token"
+
+// RegisterAuthRoutes registra o login e a renovação, públicos, o login com
+// PIN, que exige o token do terminal, e o logout, que exige o access token da
+// sessão a encerrar.
+func RegisterAuthRoutes(router *gin.RouterGroup, service services.AuthService) {
+	auth := router.Group("/auth")
+	{
+		auth.POST("/login", LoginHandler(service))
+		auth.POST("/refresh", RefreshTokenHandler(service))
+		auth.POST("/pin", PINLoginHandler(service))
+		auth.GET("/operators", ListOperatorsHandler(service))
+		auth.POST("/logout", middlewares.AuthMiddleware(service), LogoutHandler(service))
+	}
+}
+
 // @Summary Login
-// @Description Autentica um usuário e retorna um token JWT
+// @Description Autentica um usuário e retorna um access token de curta duração e um refresh token
 // @Tags Authentication
 // @Accept  json
 // @Produce  json
 // @Param credentials body LoginInput true "Credenciais do Usuário"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} session.TokenPair
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /auth/login [post]
-func LoginHandler() gin.HandlerFunc {
+func LoginHandler(service services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var input LoginInput
 		if err := c.ShouldBindJSON(&input); err != nil {
@@ -34,22 +69,163 @@ func LoginHandler() gin.HandlerFunc {
 			return
 		}
 
-		if input.Username != config.AuthUser || input.Password != config.AuthPassword {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
+		tokens, err := service.Login(c.Request.Context(), input.Username, input.Password, c.ClientIP())
+		if err != nil {
+			if respondLoginLocked(c, err) {
+				return
+			}
+			if err == user.ErrInvalidCredentials {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
 			return
 		}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-			"username": input.Username,
-			"exp":      time.Now().Add(time.Hour * 72).Unix(),
-		})
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// @Summary Refresh Token
+// @Description Troca o refresh token por um novo par de tokens; cada refresh token vale uma única vez e a reutilização encerra a sessão
+// @Tags Authentication
+// @Accept  json
+// @Produce  json
+// @Param refresh body RefreshInput true "Refresh token"
+// @Success 200 {object} session.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/refresh [post]
+func RefreshTokenHandler(service services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input RefreshInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
-		tokenString, err := token.SignedString([]byte(config.JWTSecret))
+		tokens, err := service.Refresh(c.Request.Context(), input.RefreshToken)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
+			switch err {
+			case session.ErrRefreshTokenInvalid, session.ErrRefreshTokenReused:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"token": tokenString})
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// @Summary PIN Login
+// @Description Abre uma sessão curta, sem refresh token, para o operador que digitou o PIN em um terminal cadastrado
+// @Tags Authentication
+// @Accept  json
+// @Produce  json
+// @Param X-Device-Token header string true "Token do terminal"
+// @Param credentials body PINLoginInput true "Usuário e PIN do operador"
+// @Success 200 {object} session.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/pin [post]
+func PINLoginHandler(service services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input PINLoginInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tokens, err := service.OperatorLogin(c.Request.Context(), c.GetHeader(DeviceTokenHeader), input.Username, input.PIN, c.ClientIP())
+		if err != nil {
+			respondTerminalAuthError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// @Summary List Operators
+// @Description Lista os funcionários ativos com PIN, para a tela de troca de operador do terminal
+// @Tags Authentication
+// @Produce  json
+// @Param X-Device-Token header string true "Token do terminal"
+// @Success 200 {object} map[string][]user.User
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/operators [get]
+func ListOperatorsHandler(service services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operators, err := service.ListOperators(c.Request.Context(), c.GetHeader(DeviceTokenHeader))
+		if err != nil {
+			respondTerminalAuthError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"operators": operators})
+	}
+}
+
+// @Summary Logout
+// @Description Encerra a sessão do token: revoga os refresh tokens e os access tokens emitidos nela
+// @Tags Authentication
+// @Produce  json
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func LogoutHandler(service services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middlewares.CurrentClaims(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": session.ErrAccessTokenInvalid.Error()})
+			return
+		}
+
+		if err := service.Logout(c.Request.Context(), claims); err != nil {
+			if err == apikey.ErrLogoutUnsupported {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func respondTerminalAuthError(c *gin.Context, err error) {
+	if respondLoginLocked(c, err) {
+		return
+	}
+	switch err {
+	case terminal.ErrDeviceTokenInvalid:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case user.ErrInvalidCredentials:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// respondLoginLocked responde 429 com Retry-After quando o login está
+// bloqueado por excesso de falhas.
+func respondLoginLocked(c *gin.Context, err error) bool {
+	var locked *ratelimit.LockedError
+	if !errors.As(err, &locked) {
+		return false
 	}
+	retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	return true
 }
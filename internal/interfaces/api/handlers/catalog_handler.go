@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/catalog"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCatalogImportSize limita o arquivo da importação, lido inteiro antes
+// de qualquer gravação.
+const maxCatalogImportSize = 5 << 20
+
+var errDryRunInvalid = errors.New("dry_run inválido: use true ou false")
+
+// RegisterCatalogRoutes registra a exportação e a importação do cardápio
+// inteiro.
+func RegisterCatalogRoutes(router *gin.RouterGroup, service services.CatalogService) {
+	catalogs := router.Group("/catalog")
+	{
+		catalogs.GET("/export", middlewares.RequirePermission(user.PermissionCatalogRead), ExportCatalogHandler(service))
+		catalogs.POST("/import", middlewares.RequirePermission(user.PermissionCatalogWrite), ImportCatalogHandler(service))
+	}
+}
+
+// @Summary Export Catalog
+// @Description Categorias, produtos e acréscimos, ligados pelo nome e pelo código externo, no formato aceito pela importação
+// @Tags Catalog
+// @Produce  application/json
+// @Produce  text/csv
+// @Param format query string false "Formato: json ou csv" default(json)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /catalog/export [get]
+func ExportCatalogHandler(service services.CatalogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := catalog.Format(c.DefaultQuery("format", string(catalog.FormatJSON)))
+		if !format.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": catalog.ErrFormatInvalid.Error()})
+			return
+		}
+
+		// O cardápio é pequeno; montá-lo em memória permite responder com
+		// o erro certo se a leitura falhar no meio.
+		var body bytes.Buffer
+		if err := service.ExportCatalog(c.Request.Context(), &body, format); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+		c.Data(http.StatusOK, format.ContentType(), body.Bytes())
+	}
+}
+
+// @Summary Import Catalog
+// @Description Cria ou atualiza categorias, produtos e acréscimos, procurando cada linha pelo código externo ou pelo nome. Qualquer erro de linha impede a gravação de todo o arquivo; com dry_run o relatório é devolvido sem gravar nada.
+// @Tags Catalog
+// @Accept  application/json
+// @Accept  text/csv
+// @Produce  json
+// @Param format query string false "Formato: json ou csv; sem ele, vem do Content-Type"
+// @Param dry_run query bool false "Só confere o arquivo" default(false)
+// @Success 200 {object} catalog.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 422 {object} catalog.ImportResult
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /catalog/import [post]
+func ImportCatalogHandler(service services.CatalogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := catalog.Format(c.Query("format"))
+		if format == "" {
+			format = catalog.FormatJSON
+			if strings.Contains(c.ContentType(), "csv") {
+				format = catalog.FormatCSV
+			}
+		}
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errDryRunInvalid.Error()})
+			return
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, maxCatalogImportSize)
+		result, err := service.ImportCatalog(c.Request.Context(), body, format, dryRun)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("o arquivo passa do limite de %d bytes", tooLarge.Limit)})
+			case errors.Is(err, catalog.ErrFormatInvalid), errors.Is(err, catalog.ErrFileInvalid):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, catalog.ErrImportConflict):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		if len(result.Errors) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, result)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,13 +12,16 @@ import (
 )
 
 func RegisterCategoryRoutes(router *gin.RouterGroup, service services.CategoryService) {
+	read := middlewares.RequirePermission(user.PermissionCatalogRead)
+	write := middlewares.RequirePermission(user.PermissionCatalogWrite)
+
 	categories := router.Group("/categories")
 	{
-		categories.POST("/", CreateCategoryHandler(service))
-		categories.GET("/:id", GetCategoryByIDHandler(service))
-		categories.PUT("/:id", UpdateCategoryHandler(service))
-		categories.DELETE("/:id", DeleteCategoryHandler(service))
-		categories.GET("/", ListCategoriesHandler(service))
+		categories.POST("/", write, CreateCategoryHandler(service))
+		categories.GET("/:id", read, GetCategoryByIDHandler(service))
+		categories.PUT("/:id", write, UpdateCategoryHandler(service))
+		categories.DELETE("/:id", write, DeleteCategoryHandler(service))
+		categories.GET("/", read, ListCategoriesHandler(service))
 	}
 }
 
@@ -41,8 +46,12 @@ func CreateCategoryHandler(service services.CategoryService) gin.HandlerFunc {
 
 		err := service.CreateCategory(c.Request.Context(), &cte)
 		if err != nil {
+			if isTaxationError(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			switch err {
-			case category.ErrCategoryNameRequired:
+			case category.ErrCategoryNameRequired, category.ErrStationNotFound:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -116,7 +125,16 @@ func UpdateCategoryHandler(service services.CategoryService) gin.HandlerFunc {
 
 		err = service.UpdateCategory(c.Request.Context(), &cte)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			if isTaxationError(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			switch err {
+			case category.ErrCategoryNameRequired, category.ErrStationNotFound:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			}
 			return
 		}
 
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/export"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExportRoutes(router *gin.RouterGroup, exportService services.ExportService, saleService services.SaleService) {
+	exports := router.Group("/exports", middlewares.RequirePermission(user.PermissionReports))
+	{
+		exports.GET("/sales", ExportSalesHandler(exportService, saleService))
+	}
+}
+
+// @Summary Export Sales
+// @Description Planilha das vendas, dos itens ou dos acréscimos do período, gerada à medida que as linhas são lidas
+// @Tags Exports
+// @Produce  text/csv
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param start query string false "Dia de operação inicial (AAAA-MM-DD), padrão o dia em andamento"
+// @Param end query string false "Dia de operação final inclusivo (AAAA-MM-DD), padrão igual ao inicial"
+// @Param format query string false "Formato: csv ou xlsx" default(csv)
+// @Param dataset query string false "Linhas: sales, items ou additions" default(sales)
+// @Param decimal query string false "Separador decimal do CSV: point ou comma (colunas separadas por ponto e vírgula)" default(point)
+// @Param date_format query string false "Formato das datas: iso ou br" default(iso)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /exports/sales [get]
+func ExportSalesHandler(exportService services.ExportService, saleService services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parsePeriod(c, saleService.CurrentBusinessDate())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opts := export.Options{
+			Format:     export.Format(c.DefaultQuery("format", string(export.FormatCSV))),
+			Dataset:    export.Dataset(c.DefaultQuery("dataset", string(export.DatasetSales))),
+			Decimal:    export.Decimal(c.DefaultQuery("decimal", string(export.DecimalPoint))),
+			DateFormat: export.DateFormat(c.DefaultQuery("date_format", string(export.DateISO))),
+		}
+		if err := opts.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		name := fmt.Sprintf("%s-%s-%s.%s", opts.Dataset, start.Format(reportDateLayout),
+			end.AddDate(0, 0, -1).Format(reportDateLayout), opts.Format)
+		c.Header("Content-Type", opts.Format.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+
+		err = exportService.ExportSales(c.Request.Context(), c.Writer, start, end, opts)
+		if err == nil {
+			return
+		}
+		// Depois que a planilha começou a ser enviada não há como trocar o
+		// status: a resposta termina incompleta e o erro vai para o log com o
+		// ID da requisição, o mesmo devolvido no cabeçalho X-Request-ID.
+		if c.Writer.Written() {
+			log.Printf("Falha ao exportar as vendas na requisição %s: %v", c.GetString(middlewares.ContextRequestID), err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/fiscal"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterFiscalRoutes(router *gin.RouterGroup, service services.FiscalService) {
+	sales := router.Group("/sales")
+	{
+		sales.POST("/:id/nfce", middlewares.RequirePermission(user.PermissionSalesWrite), IssueSaleNFCeHandler(service))
+		sales.GET("/:id/nfce", middlewares.RequirePermission(user.PermissionSalesRead), GetSaleNFCeHandler(service))
+	}
+	router.GET("/reports/products-missing-fiscal-data", middlewares.RequirePermission(user.PermissionReports), ProductsMissingFiscalDataHandler(service))
+}
+
+// @Summary Issue Sale NFC-e
+// @Description Gera, assina e transmite a NFC-e de uma venda paga; notas rejeitadas são reenviadas com o mesmo número
+// @Tags Fiscal
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Success 201 {object} map[string]fiscal.Document
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/nfce [post]
+func IssueSaleNFCeHandler(service services.FiscalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		doc, err := service.IssueSaleDocument(c.Request.Context(), id)
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"nfce": doc})
+	}
+}
+
+// @Summary Get Sale NFC-e
+// @Description Recupera a NFC-e de uma venda com o status na SEFAZ ou o XML assinado
+// @Tags Fiscal
+// @Produce  json
+// @Produce  xml
+// @Param id path string true "ID da Venda"
+// @Param format query string false "Formato: json ou xml" default(json)
+// @Success 200 {object} map[string]fiscal.Document
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/nfce [get]
+func GetSaleNFCeHandler(service services.FiscalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "xml" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato inválido: use json ou xml"})
+			return
+		}
+
+		doc, err := service.GetSaleDocument(c.Request.Context(), id)
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		if format == "xml" {
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-nfce.xml"`, doc.AccessKey))
+			c.Data(http.StatusOK, "application/xml; charset=utf-8", doc.XML)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"nfce": doc})
+	}
+}
+
+// @Summary Products Missing Fiscal Data
+// @Description Lista os produtos sem NCM, CFOP ou CST, já considerando os padrões da categoria
+// @Tags Fiscal
+// @Produce  json
+// @Success 200 {object} map[string][]fiscal.MissingFiscalData
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/products-missing-fiscal-data [get]
+func ProductsMissingFiscalDataHandler(service services.FiscalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		products, err := service.ListProductsMissingFiscalData(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"products": products})
+	}
+}
+
+func respondFiscalError(c *gin.Context, err error) {
+	switch err {
+	case fiscal.ErrProductFiscalData, fiscal.ErrTaxSituationUnsupported, fiscal.ErrSaleNotPaid, sale.ErrSaleStatusInvalid,
+		taxation.ErrICMSRequired:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sale.ErrSaleNotFound, fiscal.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case fiscal.ErrDocumentAuthorized, fiscal.ErrIssueInProgress:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case fiscal.ErrTransmissionFailed:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case fiscal.ErrNotConfigured:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
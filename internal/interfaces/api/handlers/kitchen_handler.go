@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/domain/kitchen"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const kitchenHeartbeatInterval = 15 * time.Second
+
+func RegisterKitchenRoutes(router *gin.RouterGroup, feed kitchen.Feed) {
+	kitchenRoutes := router.Group("/kitchen")
+	{
+		kitchenRoutes.GET("/feed", middlewares.RequirePermission(user.PermissionKitchen), KitchenFeedHandler(feed))
+	}
+}
+
+// @Summary Kitchen Feed
+// @Description Transmite via Server-Sent Events as vendas criadas, observações e mudanças de status para as telas da cozinha. Envie o cabeçalho Last-Event-ID (ou o parâmetro last_event_id) para receber os eventos perdidos após uma reconexão. Quando o ID não é reconhecido, como após um reinício do servidor, chega primeiro um evento resync: a tela descarta o que exibe e remonta as vendas com os eventos seguintes.
+// @Tags Kitchen
+// @Produce  text/event-stream
+// @Param category query []string false "IDs das categorias exibidas na tela" collectionFormat(multi)
+// @Param station query []string false "IDs das estações exibidas na tela" collectionFormat(multi)
+// @Param last_event_id query int false "Último evento recebido"
+// @Success 200 {string} string "Fluxo de eventos"
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/feed [get]
+func KitchenFeedHandler(feed kitchen.Feed) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var categoryIDs []uuid.UUID
+		for _, value := range c.QueryArray("category") {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID da categoria inválido"})
+				return
+			}
+			categoryIDs = append(categoryIDs, id)
+		}
+
+		var stationIDs []uuid.UUID
+		for _, value := range c.QueryArray("station") {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+				return
+			}
+			stationIDs = append(stationIDs, id)
+		}
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+		var lastID uint64
+		if lastEventID != "" {
+			parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID inválido"})
+				return
+			}
+			lastID = parsed
+		}
+
+		missed, events, cancel := feed.Subscribe(lastID)
+		defer cancel()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		for _, event := range missed {
+			if err := writeKitchenEvent(c, event, categoryIDs, stationIDs); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(kitchenHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeKitchenEvent(c, event, categoryIDs, stationIDs); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+func writeKitchenEvent(c *gin.Context, event kitchen.Event, categoryIDs, stationIDs []uuid.UUID) error {
+	filtered, ok := event.Filter(categoryIDs, stationIDs)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(filtered)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", filtered.ID, filtered.Type, data)
+	return err
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookSignatureHeader carrega a assinatura HMAC das notificações do gateway.
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+const maxWebhookBodySize = 1 << 20
+
+type CreateChargeInput struct {
+	Method sale.PaymentMethod `json:"method"`
+}
+
+type SimulateChargeInput struct {
+	Status payment.Status `json:"status" binding:"required"`
+}
+
+// FakeSettler é implementado pelo gateway simulado, que permite liquidar uma
+// cobrança em execuções locais.
+type FakeSettler interface {
+	Settle(ctx context.Context, id string, status payment.Status) ([]byte, string, error)
+}
+
+func RegisterPaymentRoutes(router *gin.RouterGroup, service services.PaymentService) {
+	sales := router.Group("/sales")
+	{
+		sales.POST("/:id/charges", middlewares.RequirePermission(user.PermissionSalesWrite), CreateChargeHandler(service))
+		sales.GET("/:id/charges", middlewares.RequirePermission(user.PermissionSalesRead), ListSaleChargesHandler(service))
+	}
+
+	charges := router.Group("/payments/charges")
+	{
+		charges.GET("/:id", middlewares.RequirePermission(user.PermissionSalesRead), GetChargeHandler(service))
+		charges.POST("/:id/refund", middlewares.RequirePermission(user.PermissionPaymentsRefund), RefundChargeHandler(service))
+	}
+}
+
+// RegisterPaymentWebhookRoutes registra o webhook do gateway, que fica fora
+// da autenticação JWT e é protegido pela assinatura.
+func RegisterPaymentWebhookRoutes(router *gin.RouterGroup, service services.PaymentService) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("/payments", PaymentWebhookHandler(service))
+	}
+}
+
+// RegisterFakeGatewayRoutes expõe a liquidação de cobranças do gateway
+// simulado; usado apenas quando PAYMENT_GATEWAY=fake.
+func RegisterFakeGatewayRoutes(router *gin.RouterGroup, settler FakeSettler, service services.PaymentService) {
+	charges := router.Group("/payments/charges")
+	{
+		charges.POST("/:id/simulate", middlewares.RequirePermission(user.PermissionSalesWrite), SimulateChargeHandler(settler, service))
+	}
+}
+
+// @Summary Create Payment Charge
+// @Description Cria no gateway uma cobrança PIX ou de cartão com o valor pendente da venda, descontadas as cobranças ainda pendentes
+// @Tags Payments
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Param charge body CreateChargeInput false "Forma de pagamento (padrão pix)"
+// @Success 201 {object} payment.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/charges [post]
+func CreateChargeHandler(service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		var input CreateChargeInput
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		charge, err := service.CreateCharge(c.Request.Context(), id, input.Method)
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, charge)
+	}
+}
+
+// @Summary List Sale Charges
+// @Description Lista as cobranças criadas para uma venda
+// @Tags Payments
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Success 200 {object} map[string][]payment.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/charges [get]
+func ListSaleChargesHandler(service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		charges, err := service.ListSaleCharges(c.Request.Context(), id)
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"charges": charges})
+	}
+}
+
+// @Summary Get Payment Charge
+// @Description Recupera uma cobrança, atualizando o status junto ao gateway enquanto estiver pendente
+// @Tags Payments
+// @Produce  json
+// @Param id path string true "ID da Cobrança"
+// @Success 200 {object} map[string]payment.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /payments/charges/{id} [get]
+func GetChargeHandler(service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da cobrança inválido"})
+			return
+		}
+
+		charge, err := service.GetCharge(c.Request.Context(), id)
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"charge": charge})
+	}
+}
+
+// @Summary Refund Payment Charge
+// @Description Estorna uma cobrança confirmada e remove o pagamento da venda
+// @Tags Payments
+// @Produce  json
+// @Param id path string true "ID da Cobrança"
+// @Success 200 {object} map[string]payment.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /payments/charges/{id}/refund [post]
+func RefundChargeHandler(service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da cobrança inválido"})
+			return
+		}
+
+		charge, err := service.RefundCharge(c.Request.Context(), id)
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"charge": charge})
+	}
+}
+
+// @Summary Payment Webhook
+// @Description Recebe as notificações do gateway de pagamento; a assinatura HMAC é obrigatória
+// @Tags Payments
+// @Accept  json
+// @Produce  json
+// @Param X-Webhook-Signature header string true "Assinatura HMAC-SHA256 do corpo"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/payments [post]
+func PaymentWebhookHandler(service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": payment.ErrWebhookPayloadInvalid.Error()})
+			return
+		}
+
+		err = service.HandleWebhook(c.Request.Context(), payload, c.GetHeader(WebhookSignatureHeader))
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Simulate Payment
+// @Description Liquida uma cobrança no gateway simulado, que envia a notificação assinada como um provedor real
+// @Tags Payments
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Cobrança"
+// @Param status body SimulateChargeInput true "Novo status (confirmed ou failed)"
+// @Success 200 {object} map[string]payment.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /payments/charges/{id}/simulate [post]
+func SimulateChargeHandler(settler FakeSettler, service services.PaymentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da cobrança inválido"})
+			return
+		}
+
+		var input SimulateChargeInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Status != payment.StatusConfirmed && input.Status != payment.StatusFailed {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status inválido: use confirmed ou failed"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		charge, err := service.GetCharge(ctx, id)
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		payload, signature, err := settler.Settle(ctx, charge.ProviderChargeID, input.Status)
+		if err == nil {
+			err = service.HandleWebhook(ctx, payload, signature)
+		}
+		if err == nil {
+			charge, err = service.GetCharge(ctx, id)
+		}
+		if err != nil {
+			respondPaymentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"charge": charge})
+	}
+}
+
+func respondPaymentError(c *gin.Context, err error) {
+	switch err {
+	case sale.ErrPaymentMethodInvalid, sale.ErrSaleStatusInvalid, payment.ErrChargeMethodInvalid,
+		payment.ErrNothingToCharge, payment.ErrChargeNotRefundable, payment.ErrWebhookPayloadInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case payment.ErrWebhookSignatureInvalid:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case sale.ErrSaleNotFound, payment.ErrChargeNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case payment.ErrChargeExceedsBalance:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
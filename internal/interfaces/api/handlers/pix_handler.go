@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultQRCodeSize = 300
+	maxQRCodeSize     = 1200
+)
+
+func RegisterPixRoutes(router *gin.RouterGroup, service services.PixService) {
+	sales := router.Group("/sales")
+	{
+		sales.GET("/:id/pix", middlewares.RequirePermission(user.PermissionSalesRead), GetSalePixHandler(service))
+	}
+}
+
+// @Summary Get Sale PIX Charge
+// @Description Gera o PIX copia e cola (BR Code) com o valor total da venda ou a imagem PNG do código QR
+// @Tags Sales
+// @Produce  json
+// @Produce  png
+// @Param id path string true "ID da Venda"
+// @Param format query string false "Formato: json ou png" default(json)
+// @Param size query int false "Tamanho aproximado da imagem em pixels" default(300)
+// @Success 200 {object} map[string]pix.Charge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/pix [get]
+func GetSalePixHandler(service services.PixService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		switch c.DefaultQuery("format", "json") {
+		case "json":
+			charge, err := service.GetSaleCharge(c.Request.Context(), id)
+			if err != nil {
+				respondPixError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"pix": charge})
+		case "png":
+			size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultQRCodeSize)))
+			if err != nil || size <= 0 || size > maxQRCodeSize {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "tamanho da imagem inválido"})
+				return
+			}
+
+			image, err := service.GetSaleQRCode(c.Request.Context(), id, size)
+			if err != nil {
+				respondPixError(c, err)
+				return
+			}
+			c.Data(http.StatusOK, "image/png", image)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato inválido: use json ou png"})
+		}
+	}
+}
+
+func respondPixError(c *gin.Context, err error) {
+	switch err {
+	case pix.ErrAmountInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sale.ErrSaleNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case pix.ErrNotConfigured:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errPriceItemIdInvalid = errors.New("ID do item inválido")
+	errPriceRequired      = errors.New("o preço é obrigatório")
+	errEffectiveAtInvalid = errors.New("data de vigência inválida: use o formato RFC 3339")
+)
+
+// ScheduledPriceInput é a troca de preço a agendar para o produto ou
+// acréscimo do caminho.
+type ScheduledPriceInput struct {
+	Price       *float64 `json:"price"`
+	EffectiveAt string   `json:"effective_at"`
+}
+
+// RegisterPricingRoutes registra o reajuste em lote e o histórico e os
+// agendamentos de preço dos produtos e acréscimos.
+func RegisterPricingRoutes(router *gin.RouterGroup, service services.PricingService) {
+	read := middlewares.RequirePermission(user.PermissionCatalogRead)
+	write := middlewares.RequirePermission(user.PermissionCatalogWrite)
+
+	router.POST("/pricing/adjustments", write, AdjustPricesHandler(service))
+
+	for path, itemType := range map[string]pricing.ItemType{
+		"/products":  pricing.ItemProduct,
+		"/additions": pricing.ItemAddition,
+	} {
+		items := router.Group(path)
+		{
+			items.GET("/:id/price-history", read, ListPriceHistoryHandler(service, itemType))
+			items.GET("/:id/scheduled-prices", read, ListScheduledPricesHandler(service, itemType))
+			items.POST("/:id/scheduled-prices", write, SchedulePriceHandler(service, itemType))
+			items.DELETE("/:id/scheduled-prices/:schedule_id", write, CancelScheduledPriceHandler(service, itemType))
+		}
+	}
+}
+
+// @Summary List Price History
+// @Description Lista as trocas de preço do produto ou acréscimo, mais recentes primeiro, com o autor e o agendamento aplicado
+// @Tags Pricing
+// @Produce  json
+// @Param id path string true "ID do produto ou acréscimo"
+// @Success 200 {object} map[string][]pricing.PriceChange
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /products/{id}/price-history [get]
+// @Router /additions/{id}/price-history [get]
+func ListPriceHistoryHandler(service services.PricingService, itemType pricing.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		itemID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPriceItemIdInvalid.Error()})
+			return
+		}
+
+		history, err := service.ListPriceHistory(c.Request.Context(), itemType, itemID)
+		if err != nil {
+			respondPricingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"history": history})
+	}
+}
+
+// @Summary List Scheduled Prices
+// @Description Lista as trocas de preço agendadas para o produto ou acréscimo, por data de vigência
+// @Tags Pricing
+// @Produce  json
+// @Param id path string true "ID do produto ou acréscimo"
+// @Success 200 {object} map[string][]pricing.ScheduledChange
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /products/{id}/scheduled-prices [get]
+// @Router /additions/{id}/scheduled-prices [get]
+func ListScheduledPricesHandler(service services.PricingService, itemType pricing.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		itemID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPriceItemIdInvalid.Error()})
+			return
+		}
+
+		schedules, err := service.ListScheduledPriceChanges(c.Request.Context(), itemType, itemID)
+		if err != nil {
+			respondPricingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"scheduled_prices": schedules})
+	}
+}
+
+// @Summary Schedule a Price Change
+// @Description Agenda um novo preço para o produto ou acréscimo, aplicado automaticamente a partir da data de vigência
+// @Tags Pricing
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID do produto ou acréscimo"
+// @Param input body ScheduledPriceInput true "Novo preço e data de vigência (RFC 3339)"
+// @Success 201 {object} map[string]pricing.ScheduledChange
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /products/{id}/scheduled-prices [post]
+// @Router /additions/{id}/scheduled-prices [post]
+func SchedulePriceHandler(service services.PricingService, itemType pricing.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		itemID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPriceItemIdInvalid.Error()})
+			return
+		}
+
+		var input ScheduledPriceInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Price == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPriceRequired.Error()})
+			return
+		}
+		effectiveAt, err := time.Parse(time.RFC3339, input.EffectiveAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errEffectiveAtInvalid.Error()})
+			return
+		}
+
+		schedule := &pricing.ScheduledChange{
+			ItemType:    itemType,
+			ItemID:      itemID,
+			Price:       *input.Price,
+			EffectiveAt: effectiveAt,
+		}
+		if err := service.SchedulePriceChange(c.Request.Context(), schedule); err != nil {
+			respondPricingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"scheduled_price": schedule})
+	}
+}
+
+// @Summary Cancel a Scheduled Price
+// @Description Cancela uma troca de preço ainda pendente
+// @Tags Pricing
+// @Param id path string true "ID do produto ou acréscimo"
+// @Param schedule_id path string true "ID do agendamento"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /products/{id}/scheduled-prices/{schedule_id} [delete]
+// @Router /additions/{id}/scheduled-prices/{schedule_id} [delete]
+func CancelScheduledPriceHandler(service services.PricingService, itemType pricing.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		itemID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPriceItemIdInvalid.Error()})
+			return
+		}
+		scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": pricing.ErrScheduleIdInvalid.Error()})
+			return
+		}
+
+		if err := service.CancelScheduledPriceChange(c.Request.Context(), itemType, itemID, scheduleID); err != nil {
+			respondPricingError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Bulk Price Adjustment
+// @Description Reajusta em lote, por percentual ou valor fixo, os produtos das categorias e os produtos e acréscimos informados, com arredondamento opcional (ending 0.90 ou multiple 0.50). Com dry_run devolve só a prévia; sem ele grava tudo numa única transação, com histórico e auditoria
+// @Tags Pricing
+// @Accept  json
+// @Produce  json
+// @Param adjustment body pricing.Adjustment true "Reajuste"
+// @Success 200 {object} map[string]pricing.AdjustmentResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /pricing/adjustments [post]
+func AdjustPricesHandler(service services.PricingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var adjustment pricing.Adjustment
+		if err := c.ShouldBindJSON(&adjustment); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := service.AdjustPrices(c.Request.Context(), &adjustment)
+		if err != nil {
+			if errors.Is(err, pricing.ErrAdjustedPriceInvalid) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			respondPricingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"adjustment": result})
+	}
+}
+
+func respondPricingError(c *gin.Context, err error) {
+	switch err {
+	case pricing.ErrItemTypeInvalid, pricing.ErrPriceNegative, pricing.ErrEffectiveAtPast, pricing.ErrScheduleIdInvalid,
+		product.ErrProductPricePositive, addition.ErrAdditionPriceRequired, addition.ErrAdditionIdInvalid,
+		pricing.ErrAdjustmentModeInvalid, pricing.ErrAdjustmentValueRequired, pricing.ErrAdjustmentValueInvalid,
+		pricing.ErrAdjustmentScopeRequired, pricing.ErrRoundingInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case product.ErrProductNotFound, addition.ErrAdditionNotFound, pricing.ErrScheduleNotFound, pricing.ErrScheduleItemMismatched:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case pricing.ErrScheduleNotPending, pricing.ErrAdjustmentConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
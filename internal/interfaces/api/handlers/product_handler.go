@@ -3,6 +3,10 @@ package handlers
 import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/taxation"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,13 +14,16 @@ import (
 )
 
 func RegisterProductRoutes(router *gin.RouterGroup, service services.ProductService) {
+	read := middlewares.RequirePermission(user.PermissionCatalogRead)
+	write := middlewares.RequirePermission(user.PermissionCatalogWrite)
+
 	products := router.Group("/products")
 	{
-		products.POST("/", CreateProductHandler(service))
-		products.GET("/:id", GetProductByIDHandler(service))
-		products.PUT("/:id", UpdateProductHandler(service))
-		products.DELETE("/:id", DeleteProductHandler(service))
-		products.GET("/", ListProductsHandler(service))
+		products.POST("/", write, CreateProductHandler(service))
+		products.GET("/:id", read, GetProductByIDHandler(service))
+		products.PUT("/:id", write, UpdateProductHandler(service))
+		products.DELETE("/:id", write, DeleteProductHandler(service))
+		products.GET("/", read, ListProductsHandler(service))
 	}
 }
 
@@ -41,8 +48,12 @@ func CreateProductHandler(service services.ProductService) gin.HandlerFunc {
 
 		err := service.CreateProduct(c.Request.Context(), &p)
 		if err != nil {
+			if isTaxationError(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			switch err {
-			case product.ErrProductNameRequired, product.ErrProductPricePositive, product.ErrProductCategoryID:
+			case product.ErrProductNameRequired, product.ErrProductPricePositive, product.ErrProductCategoryID, product.ErrProductCostNegative:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -94,6 +105,7 @@ func GetProductByIDHandler(service services.ProductService) gin.HandlerFunc {
 // @Success 200 {object} map[string]product.Product
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /products/{id} [put]
@@ -116,11 +128,17 @@ func UpdateProductHandler(service services.ProductService) gin.HandlerFunc {
 
 		err = service.UpdateProduct(c.Request.Context(), &p)
 		if err != nil {
+			if isTaxationError(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			switch err {
-			case product.ErrProductNameRequired, product.ErrProductPricePositive, product.ErrProductCategoryID:
+			case product.ErrProductNameRequired, product.ErrProductPricePositive, product.ErrProductCategoryID, product.ErrProductCostNegative:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			case product.ErrProductNotFound:
 				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case product.ErrProductConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			}
@@ -181,3 +199,25 @@ func ListProductsHandler(service services.ProductService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"products": products})
 	}
 }
+
+// taxationErrors são os erros de validação dos dados fiscais, comuns a
+// produtos e categorias.
+var taxationErrors = []error{
+	taxation.ErrNCMInvalid,
+	taxation.ErrCESTInvalid,
+	taxation.ErrCFOPInvalid,
+	taxation.ErrCSTInvalid,
+	taxation.ErrOriginInvalid,
+	taxation.ErrUnitInvalid,
+	taxation.ErrTaxRateInvalid,
+	taxation.ErrICMSRequired,
+}
+
+func isTaxationError(err error) bool {
+	for _, target := range taxationErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
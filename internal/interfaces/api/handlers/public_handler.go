@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPublicRoutes registra as rotas abertas, sem autenticação, usadas
+// pelo site da loja.
+func RegisterPublicRoutes(router *gin.RouterGroup, menuService services.MenuService) {
+	public := router.Group("/public")
+	{
+		public.GET("/menu", PublicMenuHandler(menuService))
+	}
+}
+
+// @Summary Public Menu
+// @Description Cardápio para os clientes: categorias com produtos, em ordem alfabética, e os acréscimos, sem custos nem dados fiscais. Responde 304 quando o If-None-Match ou o If-Modified-Since ainda valem.
+// @Tags Public
+// @Produce  json
+// @Param If-None-Match header string false "ETag da última resposta"
+// @Param If-Modified-Since header string false "Last-Modified da última resposta"
+// @Success 200 {object} menu.Menu
+// @Success 304
+// @Failure 500 {object} map[string]string
+// @Router /public/menu [get]
+func PublicMenuHandler(menuService services.MenuService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshot, err := menuService.PublicMenu(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// O navegador e os proxies podem guardar a resposta, mas confirmam o
+		// ETag a cada uso, para que uma alteração apareça na hora.
+		c.Header("Cache-Control", "public, no-cache")
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Header("ETag", snapshot.ETag)
+		http.ServeContent(c.Writer, c.Request, "", snapshot.LastModified, bytes.NewReader(snapshot.Body))
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/station"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterReceiptRoutes(router *gin.RouterGroup, service services.ReceiptService) {
+	sales := router.Group("/sales")
+	{
+		sales.GET("/:id/receipt", middlewares.RequirePermission(user.PermissionSalesRead), GetReceiptHandler(service))
+		sales.POST("/:id/print", middlewares.RequirePermission(user.PermissionSalesWrite), PrintReceiptHandler(service))
+	}
+}
+
+// @Summary Get Sale Receipt
+// @Description Gera o cupom do cliente ou a comanda da cozinha de uma venda
+// @Tags Receipts
+// @Produce  octet-stream
+// @Produce  plain
+// @Produce  application/pdf
+// @Param id path string true "ID da Venda"
+// @Param format query string false "Formato: escpos, text ou pdf" default(text)
+// @Param kind query string false "Layout: customer ou kitchen" default(customer)
+// @Param station query string false "ID da estação (apenas para a comanda da cozinha)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/receipt [get]
+func GetReceiptHandler(service services.ReceiptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		opts, err := receiptOptions(c, receipt.FormatText)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		data, err := service.RenderReceipt(c.Request.Context(), id, opts)
+		if err != nil {
+			respondReceiptError(c, err)
+			return
+		}
+
+		sendDocument(c, opts.Format, "comprovante-"+id.String(), data)
+	}
+}
+
+// @Summary Print Sale Receipt
+// @Description Envia o cupom ou a comanda da cozinha para a fila de impressão
+// @Tags Receipts
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Param kind query string false "Layout: customer ou kitchen" default(customer)
+// @Param station query string false "ID da estação (apenas para a comanda da cozinha)"
+// @Success 202 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/print [post]
+func PrintReceiptHandler(service services.ReceiptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		opts, err := receiptOptions(c, receipt.FormatESCPOS)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = service.PrintReceipt(c.Request.Context(), id, opts)
+		if err != nil {
+			respondReceiptError(c, err)
+			return
+		}
+
+		c.Status(http.StatusAccepted)
+	}
+}
+
+func receiptOptions(c *gin.Context, defaultFormat receipt.Format) (receipt.Options, error) {
+	opts := receipt.Options{
+		Kind:   receipt.Kind(c.DefaultQuery("kind", string(receipt.KindCustomer))),
+		Format: receipt.Format(c.DefaultQuery("format", string(defaultFormat))),
+	}
+	if value := c.Query("station"); value != "" {
+		stationID, err := uuid.Parse(value)
+		if err != nil {
+			return opts, station.ErrStationIdInvalid
+		}
+		opts.StationID = &stationID
+	}
+	return opts, opts.Validate()
+}
+
+// sendDocument responde com o documento renderizado; PDFs recebem um nome de
+// arquivo para que o navegador ou o aplicativo de mensagens os salve corretamente.
+func sendDocument(c *gin.Context, format receipt.Format, name string, data []byte) {
+	if format == receipt.FormatPDF {
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, name))
+	}
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+func respondReceiptError(c *gin.Context, err error) {
+	switch err {
+	case receipt.ErrKindInvalid, receipt.ErrFormatInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case sale.ErrSaleNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case receipt.ErrPrinterNotConfigured, receipt.ErrPrintQueueFull:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const reportDateLayout = "2006-01-02"
+
+var (
+	errInvalidPeriod       = errors.New("período inválido: use start e end no formato AAAA-MM-DD")
+	errInvalidDate         = errors.New("data inválida: use o formato AAAA-MM-DD")
+	errInvalidExportFormat = errors.New("formato inválido: use json ou csv")
+)
+
+func RegisterReportRoutes(router *gin.RouterGroup, saleService services.SaleService, receiptService services.ReceiptService) {
+	reports := router.Group("/reports", middlewares.RequirePermission(user.PermissionReports))
+	{
+		reports.GET("/tips", TipsReportHandler(saleService))
+		reports.GET("/daily-closing", DailyClosingReportHandler(saleService, receiptService))
+		reports.GET("/margin", MarginReportHandler(saleService))
+		reports.GET("/abc", ABCReportHandler(saleService))
+		reports.GET("/heatmap", HeatmapReportHandler(saleService))
+	}
+}
+
+// @Summary Tips Report
+// @Description Distribui as taxas de serviço recebidas no período por funcionário
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start query string false "Dia de operação inicial (AAAA-MM-DD), padrão o dia em andamento"
+// @Param end query string false "Dia de operação final inclusivo (AAAA-MM-DD), padrão igual ao inicial"
+// @Success 200 {object} map[string][]sale.TipsSummary
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/tips [get]
+func TipsReportHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parsePeriod(c, service.CurrentBusinessDate())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := service.TipsReport(c.Request.Context(), start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tips": report})
+	}
+}
+
+// @Summary Daily Closing Report
+// @Description Fechamento do caixa de um dia: totais, tipos de pedido, formas de pagamento e produtos vendidos
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Produce  application/pdf
+// @Produce  plain
+// @Param date query string false "Dia de operação (AAAA-MM-DD), padrão o dia em andamento"
+// @Param format query string false "Formato: json, pdf, text ou escpos" default(json)
+// @Success 200 {object} map[string]sale.DailyClosing
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/daily-closing [get]
+func DailyClosingReportHandler(saleService services.SaleService, receiptService services.ReceiptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		date := saleService.CurrentBusinessDate()
+		if value := c.Query("date"); value != "" {
+			parsed, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidDate.Error()})
+				return
+			}
+			date = parsed
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && !receipt.Format(format).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": receipt.ErrFormatInvalid.Error()})
+			return
+		}
+
+		report, err := saleService.DailyClosingReport(c.Request.Context(), date)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, gin.H{"closing": report})
+			return
+		}
+
+		data, err := receiptService.RenderDailyClosing(c.Request.Context(), report, receipt.Format(format))
+		if err != nil {
+			respondReceiptError(c, err)
+			return
+		}
+		sendDocument(c, receipt.Format(format), "fechamento-"+report.BusinessDate.Format(reportDateLayout), data)
+	}
+}
+
+// @Summary Gross Margin Report
+// @Description Margem bruta do período por venda, produto e categoria, pelo custo registrado nos itens no momento da venda
+// @Tags Reports
+// @Produce  json
+// @Param start query string false "Dia de operação inicial (AAAA-MM-DD), padrão o dia em andamento"
+// @Param end query string false "Dia de operação final inclusivo (AAAA-MM-DD), padrão igual ao inicial"
+// @Success 200 {object} map[string]sale.MarginReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/margin [get]
+func MarginReportHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parsePeriod(c, service.CurrentBusinessDate())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := service.MarginReport(c.Request.Context(), start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"margin": report})
+	}
+}
+
+// @Summary ABC Curve Report
+// @Description Ranking dos produtos e acréscimos do período por quantidade, receita ou margem, classificados na curva ABC e comparados com o período anterior equivalente
+// @Tags Reports
+// @Produce  json
+// @Param start query string false "Dia de operação inicial (AAAA-MM-DD), padrão o dia em andamento"
+// @Param end query string false "Dia de operação final inclusivo (AAAA-MM-DD), padrão igual ao inicial"
+// @Param rank_by query string false "Critério: quantity, revenue ou margin" default(revenue)
+// @Success 200 {object} map[string]sale.ABCReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/abc [get]
+func ABCReportHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parsePeriod(c, service.CurrentBusinessDate())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		metric := sale.ABCMetric(c.DefaultQuery("rank_by", string(sale.ABCByRevenue)))
+		report, err := service.ABCReport(c.Request.Context(), start, end, metric)
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, gin.H{"abc": report})
+		case sale.ErrABCMetricInvalid:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+	}
+}
+
+// @Summary Sales Heatmap
+// @Description Pedidos e faturamento por dia da semana e hora no fuso da loja; em CSV, a matriz da métrica escolhida
+// @Tags Reports
+// @Produce  json
+// @Produce  text/csv
+// @Param start query string false "Dia de operação inicial (AAAA-MM-DD), padrão o dia em andamento"
+// @Param end query string false "Dia de operação final inclusivo (AAAA-MM-DD), padrão igual ao inicial"
+// @Param format query string false "Formato: json ou csv" default(json)
+// @Param metric query string false "Métrica do CSV: orders ou revenue" default(orders)
+// @Success 200 {object} map[string]sale.Heatmap
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/heatmap [get]
+func HeatmapReportHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parsePeriod(c, service.CurrentBusinessDate())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidExportFormat.Error()})
+			return
+		}
+		metric := sale.HeatmapMetric(c.DefaultQuery("metric", string(sale.HeatmapOrders)))
+		if !metric.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": sale.ErrHeatmapMetricInvalid.Error()})
+			return
+		}
+
+		heatmap, err := service.HeatmapReport(c.Request.Context(), start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, gin.H{"heatmap": heatmap})
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := writeHeatmapCSV(&buf, heatmap, metric); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		name := fmt.Sprintf("mapa-de-calor-%s-%s", heatmap.Start.Format(reportDateLayout), heatmap.End.Format(reportDateLayout))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	}
+}
+
+// writeHeatmapCSV escreve a matriz com um dia da semana por linha e uma hora
+// por coluna.
+func writeHeatmapCSV(w io.Writer, heatmap *sale.Heatmap, metric sale.HeatmapMetric) error {
+	writer := csv.NewWriter(w)
+	header := []string{"dia"}
+	for hour := 0; hour < 24; hour++ {
+		header = append(header, fmt.Sprintf("%02dh", hour))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		row := []string{sale.WeekdayName(day)}
+		for hour := 0; hour < 24; hour++ {
+			cell := heatmap.Cell(day, hour)
+			if metric == sale.HeatmapRevenue {
+				row = append(row, strconv.FormatFloat(cell.Revenue, 'f', 2, 64))
+			} else {
+				row = append(row, strconv.Itoa(cell.Orders))
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// parsePeriod lê os parâmetros start e end (datas inclusivas, padrão today)
+// e devolve o período semiaberto [start, end) de datas esperado pelos
+// relatórios.
+func parsePeriod(c *gin.Context, today time.Time) (time.Time, time.Time, error) {
+	start := today
+	if value := c.Query("start"); value != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidPeriod
+		}
+		start = parsed
+	}
+
+	end := start
+	if value := c.Query("end"); value != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidPeriod
+		}
+		end = parsed
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errInvalidPeriod
+	}
+
+	return start, end.AddDate(0, 0, 1), nil
+}
@@ -3,22 +3,44 @@ package handlers
 import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 func RegisterSaleRoutes(router *gin.RouterGroup, service services.SaleService) {
+	read := middlewares.RequirePermission(user.PermissionSalesRead)
+	write := middlewares.RequirePermission(user.PermissionSalesWrite)
+
 	sales := router.Group("/sales")
 	{
-		sales.POST("/", CreateSaleHandler(service))
-		sales.GET("/:id", GetSaleByIDHandler(service))
-		sales.GET("/", ListSalesHandler(service))
-		sales.DELETE("/:id", DeleteSaleHandler(service))
+		sales.POST("/", write, CreateSaleHandler(service))
+		sales.GET("/:id", read, GetSaleByIDHandler(service))
+		sales.GET("/", read, ListSalesHandler(service))
+		sales.DELETE("/:id", middlewares.RequirePermission(user.PermissionSalesDelete), DeleteSaleHandler(service))
+		sales.PATCH("/:id/status", middlewares.RequirePermission(user.PermissionSalesStatus), UpdateSaleStatusHandler(service))
+		sales.PATCH("/:id/items/:item_id/notes", write, UpdateSaleItemNotesHandler(service))
+		sales.PATCH("/:id/items/:item_id/status", middlewares.RequirePermission(user.PermissionKitchen), UpdateSaleItemStatusHandler(service))
 	}
 }
 
+type UpdateSaleStatusInput struct {
+	Status sale.Status `json:"status" binding:"required"`
+}
+
+type UpdateSaleItemNotesInput struct {
+	Notes string `json:"notes"`
+}
+
+type UpdateSaleItemStatusInput struct {
+	Status sale.ItemStatus `json:"status" binding:"required"`
+}
+
 // @Summary Create a Sale
 // @Description Cria uma nova venda
 // @Tags Sales
@@ -38,9 +60,21 @@ func CreateSaleHandler(service services.SaleService) gin.HandlerFunc {
 			return
 		}
 
+		s.OperatorID, s.TerminalID = nil, nil
+		if claims, ok := middlewares.CurrentClaims(c); ok && !claims.IsAPIKey() {
+			s.OperatorID = &claims.UserID
+			s.TerminalID = claims.TerminalID
+		}
+
 		err := service.CreateSale(c.Request.Context(), &s)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			switch err {
+			case sale.ErrOrderTypeInvalid, sale.ErrServiceChargeWaiverReason,
+				sale.ErrPaymentMethodInvalid, sale.ErrPaymentAmountPositive:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
 			return
 		}
 
@@ -87,6 +121,7 @@ func GetSaleByIDHandler(service services.SaleService) gin.HandlerFunc {
 // @Success 204 {object} nil
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Security BearerAuth
 // @Router /sales/{id} [delete]
 func DeleteSaleHandler(service services.SaleService) gin.HandlerFunc {
@@ -100,7 +135,12 @@ func DeleteSaleHandler(service services.SaleService) gin.HandlerFunc {
 
 		err = service.DeleteSale(c.Request.Context(), id)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			switch err {
+			case sale.ErrSaleHasDocuments:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			}
 			return
 		}
 
@@ -109,16 +149,24 @@ func DeleteSaleHandler(service services.SaleService) gin.HandlerFunc {
 }
 
 // @Summary List Sales
-// @Description Recupera uma lista de todas as vendas
+// @Description Recupera uma lista de todas as vendas ou busca pelo número do pedido no dia
 // @Tags Sales
 // @Accept  json
 // @Produce  json
+// @Param order_number query int false "Número do pedido"
+// @Param date query string false "Dia de operação do pedido (AAAA-MM-DD), padrão o dia em andamento"
 // @Success 200 {object} map[string][]sale.Sale
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /sales [get]
 func ListSalesHandler(service services.SaleService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if value := c.Query("order_number"); value != "" {
+			findSalesByOrderNumber(c, service, value)
+			return
+		}
+
 		sales, err := service.ListSales(c.Request.Context())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -128,3 +176,177 @@ func ListSalesHandler(service services.SaleService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"sales": sales})
 	}
 }
+
+// @Summary Update Sale Status
+// @Description Atualiza o status de preparo de uma venda e notifica a cozinha
+// @Tags Sales
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Param status body UpdateSaleStatusInput true "Novo status"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/status [patch]
+func UpdateSaleStatusHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		var input UpdateSaleStatusInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = service.UpdateSaleStatus(c.Request.Context(), id, input.Status)
+		if err != nil {
+			switch err {
+			case sale.ErrSaleStatusInvalid:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case sale.ErrSaleNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Update Sale Item Notes
+// @Description Atualiza as observações de um item da venda e notifica a cozinha
+// @Tags Sales
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Param item_id path int true "ID do Item"
+// @Param notes body UpdateSaleItemNotesInput true "Observações do item"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/items/{item_id}/notes [patch]
+func UpdateSaleItemNotesHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		itemID, err := strconv.Atoi(c.Param("item_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID do item inválido"})
+			return
+		}
+
+		var input UpdateSaleItemNotesInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = service.UpdateSaleItemNotes(c.Request.Context(), id, itemID, input.Notes)
+		if err != nil {
+			switch err {
+			case sale.ErrSaleNotFound, sale.ErrSaleItemNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Update Sale Item Status
+// @Description Marca um item da venda como pronto ou despachado na sua estação
+// @Tags Sales
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Venda"
+// @Param item_id path int true "ID do Item"
+// @Param status body UpdateSaleItemStatusInput true "Novo status do item"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sales/{id}/items/{item_id}/status [patch]
+func UpdateSaleItemStatusHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		itemID, err := strconv.Atoi(c.Param("item_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID do item inválido"})
+			return
+		}
+
+		var input UpdateSaleItemStatusInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = service.UpdateSaleItemStatus(c.Request.Context(), id, itemID, input.Status)
+		if err != nil {
+			switch err {
+			case sale.ErrItemStatusInvalid:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case sale.ErrSaleNotFound, sale.ErrSaleItemNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func findSalesByOrderNumber(c *gin.Context, service services.SaleService, value string) {
+	orderNumber, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": sale.ErrOrderNumberInvalid.Error()})
+		return
+	}
+
+	businessDate := service.CurrentBusinessDate()
+	if date := c.Query("date"); date != "" {
+		businessDate, err = time.ParseInLocation(reportDateLayout, date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "data inválida: use o formato AAAA-MM-DD"})
+			return
+		}
+	}
+
+	sales, err := service.FindSalesByOrderNumber(c.Request.Context(), businessDate, orderNumber)
+	if err != nil {
+		switch err {
+		case sale.ErrOrderNumberInvalid:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sales": sales})
+}
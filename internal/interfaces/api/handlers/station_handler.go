@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/station"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func RegisterStationRoutes(router *gin.RouterGroup, service services.StationService, saleService services.SaleService) {
+	read := middlewares.RequirePermission(user.PermissionCatalogRead)
+	write := middlewares.RequirePermission(user.PermissionCatalogWrite)
+	kitchen := middlewares.RequirePermission(user.PermissionKitchen)
+
+	stations := router.Group("/stations")
+	{
+		stations.POST("/", write, CreateStationHandler(service))
+		stations.GET("/:id", read, GetStationByIDHandler(service))
+		stations.PUT("/:id", write, UpdateStationHandler(service))
+		stations.DELETE("/:id", write, DeleteStationHandler(service))
+		stations.GET("/", read, ListStationsHandler(service))
+		stations.GET("/:id/tickets", kitchen, ListStationTicketsHandler(saleService))
+		stations.POST("/:id/tickets/:sale_id/bump", kitchen, BumpStationTicketHandler(saleService))
+	}
+}
+
+// @Summary Create a Station
+// @Description Cria uma nova estação de preparo
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param station body station.Station true "Estação a ser criada"
+// @Success 201 {object} map[string]station.Station
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations [post]
+func CreateStationHandler(service services.StationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var st station.Station
+		if err := c.ShouldBindJSON(&st); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := service.CreateStation(c.Request.Context(), &st)
+		if err != nil {
+			switch err {
+			case station.ErrStationNameRequired:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, st)
+	}
+}
+
+// @Summary Get Station by ID
+// @Description Recupera uma única estação pelo seu ID
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Estação"
+// @Success 200 {object} map[string]station.Station
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations/{id} [get]
+func GetStationByIDHandler(service services.StationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := uuid.Parse(idParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+			return
+		}
+
+		st, err := service.GetStationByID(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"station": st})
+	}
+}
+
+// @Summary Update a Station
+// @Description Atualiza uma estação existente pelo ID
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Estação"
+// @Param station body station.Station true "Estação a ser atualizada"
+// @Success 200 {object} map[string]station.Station
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations/{id} [put]
+func UpdateStationHandler(service services.StationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := uuid.Parse(idParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+			return
+		}
+
+		var st station.Station
+		if err := c.ShouldBindJSON(&st); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		st.ID = id
+
+		err = service.UpdateStation(c.Request.Context(), &st)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, st)
+	}
+}
+
+// @Summary Delete a Station
+// @Description Deleta uma estação pelo ID
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Estação"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations/{id} [delete]
+func DeleteStationHandler(service services.StationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := uuid.Parse(idParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+			return
+		}
+
+		err = service.DeleteStation(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary List Stations
+// @Description Recupera uma lista de todas as estações
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string][]station.Station
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations [get]
+func ListStationsHandler(service services.StationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stations, err := service.ListStations(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"stations": stations})
+	}
+}
+
+// @Summary List Station Tickets
+// @Description Lista as comandas abertas de uma estação de preparo
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Estação"
+// @Success 200 {object} map[string][]sale.StationTicket
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations/{id}/tickets [get]
+func ListStationTicketsHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+			return
+		}
+
+		tickets, err := service.ListStationTickets(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tickets": tickets})
+	}
+}
+
+// @Summary Bump Station Ticket
+// @Description Despacha todos os itens da comanda de uma venda na estação
+// @Tags Stations
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID da Estação"
+// @Param sale_id path string true "ID da Venda"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /stations/{id}/tickets/{sale_id}/bump [post]
+func BumpStationTicketHandler(service services.SaleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stationID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da estação inválido"})
+			return
+		}
+
+		saleID, err := uuid.Parse(c.Param("sale_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da venda inválido"})
+			return
+		}
+
+		err = service.BumpStationTicket(c.Request.Context(), saleID, stationID)
+		if err != nil {
+			switch err {
+			case sale.ErrSaleNotFound, sale.ErrStationTicketNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TerminalInput é o corpo de cadastro e atualização de terminais.
+type TerminalInput struct {
+	Name   string `json:"name" binding:"required"`
+	Active *bool  `json:"active"`
+}
+
+func (in TerminalInput) terminal(id uuid.UUID) *terminal.Terminal {
+	active := true
+	if in.Active != nil {
+		active = *in.Active
+	}
+	return &terminal.Terminal{ID: id, Name: in.Name, Active: active}
+}
+
+// RegisterTerminalRoutes registra o cadastro dos terminais compartilhados do
+// balcão.
+func RegisterTerminalRoutes(router *gin.RouterGroup, service services.TerminalService) {
+	terminals := router.Group("/terminals", middlewares.RequirePermission(user.PermissionTerminals))
+	{
+		terminals.POST("/", CreateTerminalHandler(service))
+		terminals.GET("/:id", GetTerminalByIDHandler(service))
+		terminals.PUT("/:id", UpdateTerminalHandler(service))
+		terminals.POST("/:id/token", RotateDeviceTokenHandler(service))
+		terminals.DELETE("/:id", DeleteTerminalHandler(service))
+		terminals.GET("/", ListTerminalsHandler(service))
+	}
+}
+
+// @Summary Create a Terminal
+// @Description Cadastra um terminal e devolve o token do dispositivo, exibido apenas uma vez
+// @Tags Terminals
+// @Accept  json
+// @Produce  json
+// @Param terminal body TerminalInput true "Terminal a ser cadastrado"
+// @Success 201 {object} terminal.Registration
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals [post]
+func CreateTerminalHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input TerminalInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		registration, err := service.CreateTerminal(c.Request.Context(), input.terminal(uuid.Nil))
+		if err != nil {
+			respondTerminalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, registration)
+	}
+}
+
+// @Summary Get Terminal by ID
+// @Description Recupera um terminal pelo ID
+// @Tags Terminals
+// @Produce  json
+// @Param id path string true "ID do Terminal"
+// @Success 200 {object} map[string]terminal.Terminal
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals/{id} [get]
+func GetTerminalByIDHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": terminal.ErrTerminalIdInvalid.Error()})
+			return
+		}
+
+		t, err := service.GetTerminalByID(c.Request.Context(), id)
+		if err != nil {
+			respondTerminalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"terminal": t})
+	}
+}
+
+// @Summary Update a Terminal
+// @Description Atualiza o nome ou a situação do terminal; desativá-lo encerra as sessões de operador abertas nele
+// @Tags Terminals
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID do Terminal"
+// @Param terminal body TerminalInput true "Terminal a ser atualizado"
+// @Success 200 {object} terminal.Terminal
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals/{id} [put]
+func UpdateTerminalHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": terminal.ErrTerminalIdInvalid.Error()})
+			return
+		}
+
+		var input TerminalInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		t := input.terminal(id)
+		if err := service.UpdateTerminal(c.Request.Context(), t); err != nil {
+			respondTerminalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, t)
+	}
+}
+
+// @Summary Rotate Device Token
+// @Description Gera um novo token para o terminal; o anterior deixa de valer para o login com PIN e as sessões de operador abertas com ele são encerradas
+// @Tags Terminals
+// @Produce  json
+// @Param id path string true "ID do Terminal"
+// @Success 200 {object} terminal.Registration
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals/{id}/token [post]
+func RotateDeviceTokenHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": terminal.ErrTerminalIdInvalid.Error()})
+			return
+		}
+
+		registration, err := service.RotateDeviceToken(c.Request.Context(), id)
+		if err != nil {
+			respondTerminalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, registration)
+	}
+}
+
+// @Summary Delete a Terminal
+// @Description Remove um terminal; as vendas lançadas nele ficam sem terminal
+// @Tags Terminals
+// @Produce  json
+// @Param id path string true "ID do Terminal"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals/{id} [delete]
+func DeleteTerminalHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": terminal.ErrTerminalIdInvalid.Error()})
+			return
+		}
+
+		if err := service.DeleteTerminal(c.Request.Context(), id); err != nil {
+			respondTerminalError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary List Terminals
+// @Description Lista os terminais cadastrados
+// @Tags Terminals
+// @Produce  json
+// @Success 200 {object} map[string][]terminal.Terminal
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /terminals [get]
+func ListTerminalsHandler(service services.TerminalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		terminals, err := service.ListTerminals(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"terminals": terminals})
+	}
+}
+
+func respondTerminalError(c *gin.Context, err error) {
+	switch err {
+	case terminal.ErrTerminalNameRequired, terminal.ErrTerminalIdInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case terminal.ErrTerminalNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserInput é o corpo de criação e atualização de usuários. Na atualização a
+// senha é opcional e mantém a atual quando omitida.
+type UserInput struct {
+	Username string    `json:"username" binding:"required"`
+	Name     string    `json:"name"`
+	Role     user.Role `json:"role" binding:"required"`
+	Password string    `json:"password"`
+	Active   *bool     `json:"active"`
+}
+
+// PINInput define o PIN do funcionário nos terminais; vazio remove o PIN.
+type PINInput struct {
+	PIN string `json:"pin"`
+}
+
+func (in UserInput) user(id uuid.UUID) *user.User {
+	active := true
+	if in.Active != nil {
+		active = *in.Active
+	}
+	return &user.User{ID: id, Username: in.Username, Name: in.Name, Role: in.Role, Active: active}
+}
+
+// RegisterUserRoutes registra o cadastro de funcionários, restrito aos
+// proprietários.
+func RegisterUserRoutes(router *gin.RouterGroup, service services.UserService) {
+	users := router.Group("/users", middlewares.RequirePermission(user.PermissionUsersManage))
+	{
+		users.POST("/", CreateUserHandler(service))
+		users.GET("/:id", GetUserByIDHandler(service))
+		users.PUT("/:id", UpdateUserHandler(service))
+		users.PUT("/:id/pin", SetUserPINHandler(service))
+		users.DELETE("/:id", DeleteUserHandler(service))
+		users.GET("/", ListUsersHandler(service))
+	}
+}
+
+// @Summary Create a User
+// @Description Cadastra um funcionário com papel e senha
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param user body UserInput true "Usuário a ser criado"
+// @Success 201 {object} user.User
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users [post]
+func CreateUserHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input UserInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u := input.user(uuid.Nil)
+		if err := service.CreateUser(c.Request.Context(), u, input.Password); err != nil {
+			respondUserError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, u)
+	}
+}
+
+// @Summary Get User by ID
+// @Description Recupera um funcionário pelo ID
+// @Tags Users
+// @Produce  json
+// @Param id path string true "ID do Usuário"
+// @Success 200 {object} map[string]user.User
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [get]
+func GetUserByIDHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": user.ErrUserIdInvalid.Error()})
+			return
+		}
+
+		u, err := service.GetUserByID(c.Request.Context(), id)
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user": u})
+	}
+}
+
+// @Summary Update a User
+// @Description Atualiza dados, papel, situação ou senha de um funcionário
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID do Usuário"
+// @Param user body UserInput true "Usuário a ser atualizado"
+// @Success 200 {object} user.User
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [put]
+func UpdateUserHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": user.ErrUserIdInvalid.Error()})
+			return
+		}
+
+		var input UserInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u := input.user(id)
+		if err := service.UpdateUser(c.Request.Context(), u, input.Password); err != nil {
+			respondUserError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, u)
+	}
+}
+
+// @Summary Set User PIN
+// @Description Define o PIN de 4 a 6 dígitos usado para entrar nos terminais; PIN vazio remove o acesso por PIN
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path string true "ID do Usuário"
+// @Param pin body PINInput true "PIN do funcionário"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id}/pin [put]
+func SetUserPINHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": user.ErrUserIdInvalid.Error()})
+			return
+		}
+
+		var input PINInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := service.SetUserPIN(c.Request.Context(), id, input.PIN); err != nil {
+			respondUserError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Delete a User
+// @Description Remove um funcionário; o último proprietário ativo não pode ser removido
+// @Tags Users
+// @Produce  json
+// @Param id path string true "ID do Usuário"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/{id} [delete]
+func DeleteUserHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": user.ErrUserIdInvalid.Error()})
+			return
+		}
+
+		if err := service.DeleteUser(c.Request.Context(), id); err != nil {
+			respondUserError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary List Users
+// @Description Lista os funcionários cadastrados
+// @Tags Users
+// @Produce  json
+// @Success 200 {object} map[string][]user.User
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /users [get]
+func ListUsersHandler(service services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := service.ListUsers(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"users": users})
+	}
+}
+
+func respondUserError(c *gin.Context, err error) {
+	switch err {
+	case user.ErrUsernameRequired, user.ErrUsernameInvalid, user.ErrRoleInvalid, user.ErrPasswordTooShort,
+		user.ErrPasswordTooLong, user.ErrPINInvalid, user.ErrUserIdInvalid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case user.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case user.ErrUsernameTaken, user.ErrLastOwner:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,17 +1,48 @@
 package middlewares
 
 import (
-	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/user"
 
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// Chaves do contexto preenchidas a partir das claims do token.
+const (
+	ContextUserID   = "user_id"
+	ContextUsername = "username"
+	ContextRole     = "role"
+	ContextClaims   = "claims"
+	ContextAPIKeyID = "api_key_id"
+)
+
+// APIKeyHeader carrega a chave de API das integrações, aceita no lugar do
+// cabeçalho Authorization.
+const APIKeyHeader = "X-API-Key"
+
+// TokenVerifier valida a assinatura, a validade e a revogação do access token,
+// e as chaves de API das integrações.
+type TokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, token string) (*session.Claims, error)
+	VerifyAPIKey(ctx context.Context, key string) (*session.Claims, error)
+}
+
+// AuthMiddleware aceita o JWT de um funcionário no cabeçalho Authorization ou
+// a chave de uma integração no cabeçalho X-API-Key.
+func AuthMiddleware(verifier TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			authenticateAPIKey(c, verifier, key)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
@@ -26,18 +57,99 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(config.JWTSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := verifier.VerifyAccessToken(c.Request.Context(), tokenString)
+		switch err {
+		case nil:
+		case session.ErrAccessTokenRevoked:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			return
+		case session.ErrAccessTokenInvalid:
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			return
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(ContextClaims, claims)
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextUsername, claims.Username)
+		c.Set(ContextRole, claims.Role)
+		setAuditActor(c, audit.Actor{UserID: &claims.UserID, Name: claims.Username, IP: c.ClientIP()})
+
+		c.Next()
+	}
+}
+
+func authenticateAPIKey(c *gin.Context, verifier TokenVerifier, key string) {
+	claims, err := verifier.VerifyAPIKey(c.Request.Context(), key)
+	switch err {
+	case nil:
+	case apikey.ErrAPIKeyRevoked:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key has been revoked"})
+		return
+	case apikey.ErrAPIKeyInvalid:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set(ContextClaims, claims)
+	c.Set(ContextUsername, claims.Username)
+	c.Set(ContextAPIKeyID, *claims.APIKeyID)
+	setAuditActor(c, audit.Actor{APIKeyID: claims.APIKeyID, Name: claims.Username, IP: c.ClientIP()})
+
+	c.Next()
+}
+
+// setAuditActor leva o autor da requisição para o contexto usado pelos
+// serviços, que registram a auditoria.
+func setAuditActor(c *gin.Context, actor audit.Actor) {
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+}
+
+// RequirePermission barra a rota quando o papel do token, ou os escopos da
+// chave de API, não concedem a permissão. Deve ser usado depois do
+// AuthMiddleware.
+func RequirePermission(permission user.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := CurrentClaims(c); !ok || !claims.Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "permissão insuficiente para esta operação"})
+			return
 		}
 
 		c.Next()
 	}
 }
+
+// CurrentUserID devolve o usuário autenticado na requisição.
+func CurrentUserID(c *gin.Context) (uuid.UUID, bool) {
+	id, ok := c.Get(ContextUserID)
+	if !ok {
+		return uuid.Nil, false
+	}
+	userID, ok := id.(uuid.UUID)
+	return userID, ok
+}
+
+// CurrentAPIKeyID devolve a chave de API que autenticou a requisição.
+func CurrentAPIKeyID(c *gin.Context) (uuid.UUID, bool) {
+	id, ok := c.Get(ContextAPIKeyID)
+	if !ok {
+		return uuid.Nil, false
+	}
+	keyID, ok := id.(uuid.UUID)
+	return keyID, ok
+}
+
+// CurrentClaims devolve as claims do access token da requisição.
+func CurrentClaims(c *gin.Context) (*session.Claims, bool) {
+	value, ok := c.Get(ContextClaims)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*session.Claims)
+	return claims, ok
+}
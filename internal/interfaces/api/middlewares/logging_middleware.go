@@ -23,12 +23,13 @@ func LoggingMiddleware() gin.HandlerFunc {
 		path := c.Request.URL.Path
 
 		logger.WithFields(logrus.Fields{
-			"status":    status,
-			"method":    method,
-			"path":      path,
-			"duration":  duration,
-			"client_ip": clientIP,
-			"timestamp": startTime.Format(time.RFC3339),
+			"status":     status,
+			"method":     method,
+			"path":       path,
+			"duration":   duration,
+			"client_ip":  clientIP,
+			"request_id": c.GetString(ContextRequestID),
+			"timestamp":  startTime.Format(time.RFC3339),
 		}).Info("Request processed")
 	}
 }
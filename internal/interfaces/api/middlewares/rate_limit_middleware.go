@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"andressa-lanches/internal/domain/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit limita as requisições por janela fixa, contando por usuário ou
+// chave de API autenticados ou, sem eles, por IP. Deve ser usado depois do
+// AuthMiddleware para contar por usuário.
+func RateLimit(store ratelimit.Store, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		key := "rate:ip:" + c.ClientIP()
+		if userID, ok := CurrentUserID(c); ok {
+			key = "rate:user:" + userID.String()
+		} else if keyID, ok := CurrentAPIKeyID(c); ok {
+			key = "rate:apikey:" + keyID.String()
+		}
+
+		now := time.Now()
+		counter, err := store.Increment(c.Request.Context(), key, limit.Window, now)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(max(limit.Requests-counter.Count, 0)))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(counter.ExpiresAt.Unix(), 10))
+
+		if counter.Count > limit.Requests {
+			retryAfter := int(math.Ceil(counter.ExpiresAt.Sub(now).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": ratelimit.ErrLimitExceeded.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"andressa-lanches/internal/domain/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader identifica a requisição nos logs e na auditoria. Um valor
+// enviado pelo cliente (ou por um proxy) é mantido; sem ele, um novo é
+// gerado.
+const RequestIDHeader = "X-Request-ID"
+
+const ContextRequestID = "request_id"
+
+// maxRequestIDLength limita o valor aceito do cliente ao tamanho da coluna.
+const maxRequestIDLength = 64
+
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(ContextRequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(audit.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// validRequestID aceita apenas caracteres visíveis ASCII, para que o valor
+// possa ir para os logs sem escapes.
+func validRequestID(value string) bool {
+	if value == "" || len(value) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] <= ' ' || value[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/domain/kitchen"
+	"andressa-lanches/internal/domain/ratelimit"
 	"andressa-lanches/internal/interfaces/api/docs"
 	"andressa-lanches/internal/interfaces/api/handlers"
 	"andressa-lanches/internal/interfaces/api/middlewares"
@@ -10,15 +12,50 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// NewEngine cria o roteador sem middlewares. Só os proxies de trustedProxies
+// podem informar o IP do cliente em X-Forwarded-For e X-Real-IP; sem eles,
+// vale o endereço da conexão, que identifica o cliente no bloqueio de login
+// e no limite de requisições.
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
 func SetupRouter(
 	productService services.ProductService,
 	categoryService services.CategoryService,
 	additionService services.AdditionService,
 	saleService services.SaleService,
-) *gin.Engine {
-	router := gin.New()
+	stationService services.StationService,
+	receiptService services.ReceiptService,
+	pixService services.PixService,
+	paymentService services.PaymentService,
+	fakeGateway handlers.FakeSettler,
+	fiscalService services.FiscalService,
+	kitchenFeed kitchen.Feed,
+	userService services.UserService,
+	authService services.AuthService,
+	terminalService services.TerminalService,
+	apiKeyService services.APIKeyService,
+	auditService services.AuditService,
+	pricingService services.PricingService,
+	catalogService services.CatalogService,
+	menuService services.MenuService,
+	exportService services.ExportService,
+	rateLimitStore ratelimit.Store,
+	rateLimit ratelimit.Limit,
+	trustedProxies []string,
+) (*gin.Engine, error) {
+	router, err := NewEngine(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
 
 	router.Use(gin.Recovery())
+	router.Use(middlewares.RequestID())
 	router.Use(middlewares.LoggingMiddleware())
 
 	p := ginprom.New(
@@ -28,14 +65,18 @@ func SetupRouter(
 	)
 	router.Use(p.Instrument())
 
-	auth := router.Group("/auth")
-	{
-		auth.POST("/login", handlers.LoginHandler())
-	}
+	// Login, login com PIN nos terminais, renovação e logout
+	handlers.RegisterAuthRoutes(&router.RouterGroup, authService)
+
+	// Notificações do gateway de pagamento, autenticadas pela assinatura
+	handlers.RegisterPaymentWebhookRoutes(&router.RouterGroup, paymentService)
 
-	// Rotas com JWT
+	// Cardápio público, sem autenticação
+	handlers.RegisterPublicRoutes(&router.RouterGroup, menuService)
+
+	// Rotas com JWT ou chave de API
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(authService), middlewares.RateLimit(rateLimitStore, rateLimit))
 	{
 		// Produtos
 		handlers.RegisterProductRoutes(protected, productService)
@@ -46,11 +87,44 @@ func SetupRouter(
 		// Acréscimos
 		handlers.RegisterAdditionRoutes(protected, additionService)
 
+		// Histórico e agendamento de preços
+		handlers.RegisterPricingRoutes(protected, pricingService)
+
+		// Importação e exportação do cardápio
+		handlers.RegisterCatalogRoutes(protected, catalogService)
+
 		// Vendas
 		handlers.RegisterSaleRoutes(protected, saleService)
+		handlers.RegisterReceiptRoutes(protected, receiptService)
+		handlers.RegisterPixRoutes(protected, pixService)
+
+		// Pagamentos
+		handlers.RegisterPaymentRoutes(protected, paymentService)
+		if fakeGateway != nil {
+			handlers.RegisterFakeGatewayRoutes(protected, fakeGateway, paymentService)
+		}
+
+		// Fiscal
+		handlers.RegisterFiscalRoutes(protected, fiscalService)
+
+		// Cozinha
+		handlers.RegisterStationRoutes(protected, stationService, saleService)
+		handlers.RegisterKitchenRoutes(protected, kitchenFeed)
+
+		// Relatórios
+		handlers.RegisterReportRoutes(protected, saleService, receiptService)
+		handlers.RegisterExportRoutes(protected, exportService, saleService)
+
+		// Usuários
+		handlers.RegisterUserRoutes(protected, userService)
+		handlers.RegisterTerminalRoutes(protected, terminalService)
+		handlers.RegisterAPIKeyRoutes(protected, apiKeyService)
+
+		// Auditoria
+		handlers.RegisterAuditRoutes(protected, auditService)
 	}
 
 	docs.InitializeSwagger(router)
 
-	return router
+	return router, nil
 }
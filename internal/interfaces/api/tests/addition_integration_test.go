@@ -27,14 +27,15 @@ func setupAdditionTestRouter() *gin.Engine {
 	config.AuthUser = "test_user"
 	config.AuthPassword = "test_password"
 
-	additionRepo := repository.NewInMemoryAdditionRepository()
-	additionService := services.NewAdditionService(additionRepo)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	additionService := services.NewAdditionService(additionRepo, nil)
 
 	router := gin.Default()
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(authService))
 	handlers.RegisterAdditionRoutes(protected, additionService)
 
 	return router
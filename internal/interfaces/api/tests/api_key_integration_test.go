@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAPIKeyTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	userRepo := newTestUserRepository()
+	apiKeyRepo := repository.NewInMemoryAPIKeyRepository()
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	authService := newTestAuthServiceWithRepos(userRepo, repository.NewInMemoryTerminalRepository(), apiKeyRepo)
+	saleService := services.NewSaleService(repository.NewInMemorySaleRepository(nil), productRepo, repository.NewInMemoryAdditionRepository(nil, nil),
+		repository.NewInMemoryCategoryRepository(nil), nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+
+	router := gin.Default()
+	handlers.RegisterAuthRoutes(&router.RouterGroup, authService)
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterProductRoutes(protected, services.NewProductService(productRepo, nil))
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterUserRoutes(protected, services.NewUserService(userRepo))
+	handlers.RegisterAPIKeyRoutes(protected, services.NewAPIKeyService(apiKeyRepo))
+
+	return router
+}
+
+func withAPIKey(t *testing.T, router *gin.Engine, key, method, path string, body any) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middlewares.APIKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeys_ScopedAccess(t *testing.T) {
+	router := setupAPIKeyTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/api-keys/", handlers.APIKeyInput{Name: "ponte", Scopes: []user.Permission{user.PermissionUsersManage}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var issued apikey.Issued
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/api-keys/", handlers.APIKeyInput{
+		Name: "ponte-delivery", Scopes: []user.Permission{user.PermissionCatalogWrite, user.PermissionSalesWrite},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+	assert.NotContains(t, w.Body.String(), "key_hash")
+	require.NotNil(t, issued.APIKey.CreatedBy)
+	prefix, ok := apikey.ParsePrefix(issued.Key)
+	require.True(t, ok)
+	assert.Equal(t, issued.APIKey.Prefix, prefix)
+
+	var burger product.Product
+	w = withAPIKey(t, router, issued.Key, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	// A venda da integração não tem operador.
+	var created sale.Sale
+	w = withAPIKey(t, router, issued.Key, http.MethodPost, "/sales/", sale.Sale{Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Nil(t, created.OperatorID)
+
+	// Fora dos escopos da chave.
+	assert.Equal(t, http.StatusForbidden, withAPIKey(t, router, issued.Key, http.MethodGet, "/sales/", nil).Code)
+	assert.Equal(t, http.StatusForbidden, withAPIKey(t, router, issued.Key, http.MethodGet, "/users/", nil).Code)
+	assert.Equal(t, http.StatusForbidden, withAPIKey(t, router, issued.Key, http.MethodGet, "/api-keys/", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, withAPIKey(t, router, issued.Key, http.MethodPost, "/auth/logout", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, withAPIKey(t, router, "al_"+prefix+"_errado", http.MethodGet, "/products/", nil).Code)
+
+	w = getAuthorized(t, router, ownerToken, "/api-keys/"+issued.APIKey.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var found map[string]apikey.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	assert.NotNil(t, found["api_key"].LastUsedAt)
+}
+
+func TestAPIKeys_RotateAndRevoke(t *testing.T) {
+	router := setupAPIKeyTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	var issued apikey.Issued
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/api-keys/", handlers.APIKeyInput{
+		Name: "relatorios", Scopes: []user.Permission{user.PermissionSalesRead},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+	assert.Equal(t, http.StatusOK, withAPIKey(t, router, issued.Key, http.MethodGet, "/sales/", nil).Code)
+
+	var rotated apikey.Issued
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/api-keys/"+issued.APIKey.ID.String()+"/rotate", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.Equal(t, issued.APIKey.ID, rotated.APIKey.ID)
+	assert.NotEqual(t, issued.Key, rotated.Key)
+
+	assert.Equal(t, http.StatusUnauthorized, withAPIKey(t, router, issued.Key, http.MethodGet, "/sales/", nil).Code)
+	assert.Equal(t, http.StatusOK, withAPIKey(t, router, rotated.Key, http.MethodGet, "/sales/", nil).Code)
+
+	w = postJSON(t, router, ownerToken, http.MethodDelete, "/api-keys/"+issued.APIKey.ID.String(), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = withAPIKey(t, router, rotated.Key, http.MethodGet, "/sales/", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "revoked")
+
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/api-keys/"+issued.APIKey.ID.String()+"/rotate", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// A chave revogada continua listada.
+	w = getAuthorized(t, router, ownerToken, "/api-keys/")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list map[string][]apikey.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list["api_keys"], 1)
+	assert.NotNil(t, list["api_keys"][0].RevokedAt)
+}
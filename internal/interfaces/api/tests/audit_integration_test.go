@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/audit"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuditTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	userRepo := newTestUserRepository()
+	auditRepo := repository.NewInMemoryAuditRepository()
+	productRepo := repository.NewInMemoryProductRepository(nil, auditRepo)
+	authService := newTestAuthService(userRepo)
+	auditService := services.NewAuditService(auditRepo, sale.Calendar{})
+	saleService := services.NewSaleService(repository.NewInMemorySaleRepository(auditRepo), productRepo, repository.NewInMemoryAdditionRepository(nil, nil),
+		repository.NewInMemoryCategoryRepository(nil), nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+
+	router := gin.Default()
+	router.Use(middlewares.RequestID())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterProductRoutes(protected, services.NewProductService(productRepo, nil))
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterUserRoutes(protected, services.NewUserService(userRepo))
+	handlers.RegisterAuditRoutes(protected, auditService)
+
+	return router
+}
+
+func listAuditEntries(t *testing.T, router *gin.Engine, token, query string) []audit.Entry {
+	w := getAuthorized(t, router, token, "/audit?"+query)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string][]audit.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response["entries"]
+}
+
+func TestAudit_RecordsCatalogAndSaleChanges(t *testing.T) {
+	router := setupAuditTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	var burger product.Product
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	burger.Price = 21
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/products/"+burger.ID.String(), burger)
+	require.Equal(t, http.StatusOK, w.Code)
+	updateRequestID := w.Header().Get(middlewares.RequestIDHeader)
+	require.NotEmpty(t, updateRequestID)
+
+	var created sale.Sale
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/sales/", sale.Sale{Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = postJSON(t, router, ownerToken, http.MethodPatch, "/sales/"+created.ID.String()+"/status", handlers.UpdateSaleStatusInput{Status: sale.StatusCanceled})
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = postJSON(t, router, ownerToken, http.MethodDelete, "/sales/"+created.ID.String(), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	entries := listAuditEntries(t, router, ownerToken, "entity_type=product")
+	require.Len(t, entries, 2)
+	assert.Equal(t, audit.ActionUpdate, entries[0].Action)
+	assert.Equal(t, "test_user", entries[0].Actor)
+	assert.NotNil(t, entries[0].ActorID)
+	assert.Equal(t, updateRequestID, entries[0].RequestID)
+	assert.JSONEq(t, `{"price":{"before":18.5,"after":21}}`, string(entries[0].Changes))
+	assert.Equal(t, audit.ActionCreate, entries[1].Action)
+
+	entries = listAuditEntries(t, router, ownerToken, "request_id="+updateRequestID)
+	require.Len(t, entries, 1)
+	assert.Equal(t, burger.ID, *entries[0].EntityID)
+
+	// A venda tem a criação, a mudança de status e a remoção, nessa ordem.
+	entries = listAuditEntries(t, router, ownerToken, "entity_id="+created.ID.String())
+	require.Len(t, entries, 3)
+	assert.Equal(t, audit.ActionDelete, entries[0].Action)
+	assert.Equal(t, audit.ActionUpdate, entries[1].Action)
+	var changes map[string]audit.FieldChange
+	require.NoError(t, json.Unmarshal(entries[1].Changes, &changes))
+	assert.Equal(t, string(sale.StatusReceived), changes["status"].Before)
+	assert.Equal(t, string(sale.StatusCanceled), changes["status"].After)
+	assert.Equal(t, audit.ActionCreate, entries[2].Action)
+	// A criação é registrada depois da numeração, na mesma gravação.
+	require.NoError(t, json.Unmarshal(entries[2].Changes, &changes))
+	assert.Equal(t, float64(created.OrderNumber), changes["order_number"].After)
+
+	entries = listAuditEntries(t, router, ownerToken, "entity_type=sale&action=delete&limit=1")
+	require.Len(t, entries, 1)
+
+	assert.Equal(t, http.StatusBadRequest, getAuthorized(t, router, ownerToken, "/audit?entity_id=abc").Code)
+	assert.Equal(t, http.StatusBadRequest, getAuthorized(t, router, ownerToken, "/audit?limit=1000").Code)
+	assert.Equal(t, http.StatusBadRequest, getAuthorized(t, router, ownerToken, "/audit?start=10/05/2024").Code)
+}
+
+func TestAudit_RequiresPermission(t *testing.T) {
+	router := setupAuditTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/users/", handlers.UserInput{Username: "gerente", Role: user.RoleManager, Password: "segredo123"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	managerToken := loginAs(t, router, "gerente", "segredo123")
+	assert.Equal(t, http.StatusForbidden, getAuthorized(t, router, managerToken, "/audit").Code)
+}
@@ -1,19 +1,70 @@
 package tests
 
 import (
+	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/apikey"
+	"andressa-lanches/internal/domain/ratelimit"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api"
 	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
 
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// newTestUserRepository cadastra o proprietário com as credenciais de teste
+// da configuração. O hash usa o custo mínimo do bcrypt para não deixar cada
+// login dos testes lento.
+func newTestUserRepository() *repository.InMemoryUserRepository {
+	hash, err := bcrypt.GenerateFromPassword([]byte(config.AuthPassword), bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := repository.NewInMemoryUserRepository()
+	owner := &user.User{Username: config.AuthUser, Name: config.AuthUser, Role: user.RoleOwner, Active: true, PasswordHash: string(hash)}
+	if err := repo.Create(context.Background(), owner); err != nil {
+		panic(err)
+	}
+	return repo
+}
+
+func newTestAuthService(userRepo user.Repository) services.AuthService {
+	return newTestTerminalAuthService(userRepo, repository.NewInMemoryTerminalRepository())
+}
+
+func newTestTerminalAuthService(userRepo user.Repository, terminalRepo terminal.Repository) services.AuthService {
+	return newTestAuthServiceWithRepos(userRepo, terminalRepo, repository.NewInMemoryAPIKeyRepository())
+}
+
+func newTestAuthServiceWithRepos(userRepo user.Repository, terminalRepo terminal.Repository, apiKeyRepo apikey.Repository) services.AuthService {
+	guard := services.NewLoginGuard(repository.NewInMemoryRateLimitStore(), repository.NewInMemoryAuditRepository(), services.LoginPolicy{
+		PerUser: ratelimit.LockoutPolicy{MaxAttempts: 5, BaseLockout: time.Minute, MaxLockout: time.Hour},
+		PerIP:   ratelimit.LockoutPolicy{MaxAttempts: 20, BaseLockout: time.Minute, MaxLockout: time.Hour},
+	})
+	return services.NewAuthService(userRepo, repository.NewInMemorySessionRepository(), terminalRepo, apiKeyRepo, guard, services.TokenPolicy{
+		Secret:             config.JWTSecret,
+		AccessTokenTTL:     15 * time.Minute,
+		RefreshTokenTTL:    24 * time.Hour,
+		OperatorSessionTTL: 30 * time.Minute,
+	})
+}
+
 func TestLoginHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -23,7 +74,8 @@ func TestLoginHandler_Success(t *testing.T) {
 
 	// Configurar o roteador
 	router := gin.Default()
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	// Dados de login válidos
 	loginData := map[string]string{
@@ -52,8 +104,11 @@ func TestLoginHandler_Success(t *testing.T) {
 func TestLoginHandler_InvalidCredentials(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
 	router := gin.Default()
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	// Dados de login inválidos
 	loginData := map[string]string{
@@ -70,3 +125,86 @@ func TestLoginHandler_InvalidCredentials(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestLoginHandler_LocksOutAfterFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	login := func(password string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(map[string]string{"username": "test_user", "password": password})
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusUnauthorized, login("wrong_password").Code)
+	}
+
+	// Bloqueado, nem a senha certa entra.
+	w := login("test_password")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService), middlewares.RateLimit(repository.NewInMemoryRateLimitStore(), ratelimit.Limit{Requests: 2, Window: time.Minute}))
+	protected.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	token := getValidToken(t, router)
+	for i := 0; i < 2; i++ {
+		w := getAuthorized(t, router, token, "/ping")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, strconv.Itoa(1-i), w.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	w := getAuthorized(t, router, token, "/ping")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestLoginHandler_IgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+	router, err := api.NewEngine(nil)
+	require.NoError(t, err)
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	login := func(username string, attempt int) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(map[string]string{"username": username, "password": "wrong_password"})
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.7:40000"
+		// Cada tentativa finge vir de outro IP.
+		req.Header.Set("X-Forwarded-For", "198.51.100."+strconv.Itoa(attempt))
+		req.Header.Set("X-Real-IP", "198.51.100."+strconv.Itoa(attempt))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Usuários diferentes, para que só o limite por IP seja atingido.
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, http.StatusUnauthorized, login("user"+strconv.Itoa(i), i).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, login("outro", 20).Code)
+}
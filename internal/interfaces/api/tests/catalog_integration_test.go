@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/catalog"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/station"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type catalogTestRepos struct {
+	categories *repository.InMemoryCategoryRepository
+	products   *repository.InMemoryProductRepository
+	additions  *repository.InMemoryAdditionRepository
+	prices     *repository.InMemoryPriceRepository
+	stations   *repository.InMemoryStationRepository
+}
+
+func setupCatalogTestRouter() (*gin.Engine, catalogTestRepos) {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	repos := catalogTestRepos{
+		categories: repository.NewInMemoryCategoryRepository(nil),
+		products:   repository.NewInMemoryProductRepository(nil, nil),
+		additions:  repository.NewInMemoryAdditionRepository(nil, nil),
+		prices:     repository.NewInMemoryPriceRepository(),
+		stations:   repository.NewInMemoryStationRepository(),
+	}
+	catalogRepo := repository.NewInMemoryCatalogRepository(repos.categories, repos.products, repos.additions, repos.prices, nil)
+	catalogService := services.NewCatalogService(catalogRepo, repos.categories, repos.products, repos.additions, repos.stations, nil)
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterCategoryRoutes(protected, services.NewCategoryService(repos.categories, repos.stations, nil))
+	handlers.RegisterProductRoutes(protected, services.NewProductService(repos.products, nil))
+	handlers.RegisterCatalogRoutes(protected, catalogService)
+	return router, repos
+}
+
+func postCatalog(t *testing.T, router *gin.Engine, token, path, contentType, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCatalogImport_DryRunThenApply(t *testing.T) {
+	router, repos := setupCatalogTestRouter()
+	token := getValidToken(t, router)
+	ctx := context.Background()
+
+	require.NoError(t, repos.stations.Create(ctx, &station.Station{Name: "Chapa"}))
+	var drinks category.Category
+	w := postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Bebidas"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drinks))
+	var soda product.Product
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Refrigerante", Price: 6, CategoryID: drinks.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &soda))
+
+	// Separado por ponto e vírgula e com vírgula decimal, como o Excel grava.
+	file := "tipo;codigo_externo;nome;preco;custo_unitario;categoria;codigo_categoria;estacao\n" +
+		"categoria;CAT-1;Lanches;;;;;chapa\n" +
+		"produto;P-1;X-Burger;18,50;7,25;;CAT-1;\n" +
+		"produto;;refrigerante;6,50;;Bebidas;;\n" +
+		"acrescimo;A-1;Bacon;4;;;;\n"
+
+	w = postCatalog(t, router, token, "/catalog/import?dry_run=true", "text/csv", file)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result catalog.ImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.DryRun)
+	assert.False(t, result.Applied)
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Empty(t, result.Errors)
+	require.Len(t, result.Items, 4)
+	assert.Equal(t, catalog.ImportItem{Type: catalog.EntryCategory, Row: 2, Name: "Lanches", Action: catalog.ActionCreate}, result.Items[0])
+	assert.Equal(t, catalog.ActionUpdate, result.Items[2].Action)
+	assert.Equal(t, soda.ID, *result.Items[2].ID)
+	products, _ := repos.products.List(ctx)
+	assert.Len(t, products, 1)
+
+	w = postCatalog(t, router, token, "/catalog/import", "text/csv", file)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result = catalog.ImportResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Applied)
+	require.NotNil(t, result.Items[0].ID)
+
+	snacks, err := repos.categories.GetByID(ctx, *result.Items[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, snacks.StationID)
+	burger, err := repos.products.GetByID(ctx, *result.Items[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "P-1", burger.ExternalCode)
+	assert.Equal(t, snacks.ID, burger.CategoryID)
+	assert.Equal(t, 18.5, burger.Price)
+	assert.Equal(t, 7.25, *burger.UnitCost)
+	updatedSoda, _ := repos.products.GetByID(ctx, soda.ID)
+	assert.Equal(t, "refrigerante", updatedSoda.Name)
+	assert.Equal(t, 6.5, updatedSoda.Price)
+	history, _ := repos.prices.ListChanges(ctx, "product", soda.ID)
+	require.Len(t, history, 1)
+	assert.Equal(t, 6.0, *history[0].OldPrice)
+
+	// A exportação reimportada não muda nada.
+	w = getAuthorized(t, router, token, "/catalog/export")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="catalog.json"`)
+	exported := w.Body.String()
+	assert.Contains(t, exported, `"category_code": "CAT-1"`)
+	assert.Contains(t, exported, `"station": "Chapa"`)
+	w = postCatalog(t, router, token, "/catalog/import", "application/json", exported)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result = catalog.ImportResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 5, result.Unchanged)
+	assert.False(t, result.Applied)
+
+	w = getAuthorized(t, router, token, "/catalog/export?format=csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	assert.Equal(t, []string{"categoria", "", "Bebidas"}, records[1][:3])
+	assert.Equal(t, []string{"produto", "P-1", "X-Burger", "", "18.50", "7.25", "Lanches", "CAT-1"}, records[4][:8])
+	w = postCatalog(t, router, token, "/catalog/import", "text/csv", w.Body.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result = catalog.ImportResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 5, result.Unchanged)
+}
+
+func TestCatalogImport_RowErrorsBlockTheWholeFile(t *testing.T) {
+	router, repos := setupCatalogTestRouter()
+	token := getValidToken(t, router)
+	ctx := context.Background()
+
+	body := `{
+		"categories": [{"name": "Lanches"}, {"name": "Porções", "station": "Fritadeira"}],
+		"products": [
+			{"external_code": "P-1", "name": "X-Burger", "price": 18.5, "category": "Lanches"},
+			{"name": "X-Salada", "price": 0, "category": "Lanches"},
+			{"name": "Batata", "price": 15, "category": "Porções"},
+			{"external_code": "P-1", "name": "X-Burger duplo", "price": 25, "category": "Lanches"}
+		],
+		"additions": [{"name": "Bacon", "price": 4}]
+	}`
+	w := postCatalog(t, router, token, "/catalog/import", "application/json", body)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var result catalog.ImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.False(t, result.Applied)
+	assert.Equal(t, []catalog.RowError{
+		{Type: catalog.EntryCategory, Row: 2, Name: "Porções", Error: `estação "Fritadeira" não encontrada`},
+		{Type: catalog.EntryProduct, Row: 2, Name: "X-Salada", Error: product.ErrProductPricePositive.Error()},
+		{Type: catalog.EntryProduct, Row: 3, Name: "Batata", Error: `categoria "Porções" não encontrada`},
+		{Type: catalog.EntryProduct, Row: 4, Name: "X-Burger duplo", Error: "o item já aparece na linha 1"},
+	}, result.Errors)
+
+	categories, _ := repos.categories.List(ctx)
+	assert.Empty(t, categories)
+	additions, _ := repos.additions.List(ctx)
+	assert.Empty(t, additions)
+
+	w = postCatalog(t, router, token, "/catalog/import", "text/csv", "tipo,nome\nbebida,Suco\nproduto,Suco,abc\n")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	result = catalog.ImportResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, 2, result.Errors[0].Row)
+	assert.Equal(t, 3, result.Errors[1].Row)
+
+	w = postCatalog(t, router, token, "/catalog/import?format=csv", "text/csv", "tipo,nome,sabor\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = postCatalog(t, router, token, "/catalog/import", "application/json", `{"products": [{"nome": "X"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = postCatalog(t, router, token, "/catalog/import?format=xml", "application/xml", "<catalog/>")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCatalogImport_ConflictsWithConcurrentChange(t *testing.T) {
+	_, repos := setupCatalogTestRouter()
+	ctx := context.Background()
+	catalogRepo := repository.NewInMemoryCatalogRepository(repos.categories, repos.products, repos.additions, repos.prices, nil)
+
+	soda := &product.Product{Name: "Refrigerante", Price: 6}
+	require.NoError(t, repos.products.Create(ctx, soda, nil, nil))
+	read := *soda
+
+	// Outra requisição muda o preço depois que a importação leu o cadastro.
+	changed := *soda
+	changed.Price = 7
+	require.NoError(t, repos.products.Update(ctx, &changed, soda.Price, nil, nil))
+
+	imported := read
+	imported.Name = "Refrigerante lata"
+	err := catalogRepo.Apply(ctx, &catalog.Plan{
+		UpdatedProducts:  []*product.Product{&imported},
+		PreviousProducts: map[uuid.UUID]*product.Product{read.ID: &read},
+	})
+	assert.Equal(t, catalog.ErrImportConflict, err)
+	current, _ := repos.products.GetByID(ctx, soda.ID)
+	assert.Equal(t, "Refrigerante", current.Name)
+	assert.Equal(t, 7.0, current.Price)
+}
+
+func TestCatalogImport_RejectsOversizedFile(t *testing.T) {
+	router, _ := setupCatalogTestRouter()
+	token := getValidToken(t, router)
+
+	file := "tipo,nome,preco\n" + strings.Repeat("acrescimo,Bacon,4\n", 6<<20/18)
+	w := postCatalog(t, router, token, "/catalog/import", "text/csv", file)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+}
@@ -27,14 +27,15 @@ func setupCategoryTestRouter() *gin.Engine {
 	config.AuthUser = "test_user"
 	config.AuthPassword = "test_password"
 
-	categoryRepo := repository.NewInMemoryCategoryRepository()
-	categoryService := services.NewCategoryService(categoryRepo)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+	categoryService := services.NewCategoryService(categoryRepo, repository.NewInMemoryStationRepository(), nil)
 
 	router := gin.Default()
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(authService))
 	handlers.RegisterCategoryRoutes(protected, categoryService)
 
 	return router
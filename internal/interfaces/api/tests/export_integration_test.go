@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/export"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/infrastructure/spreadsheet"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupExportTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+
+	calendar := sale.Calendar{Location: time.UTC}
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, nil, sale.ServiceChargePolicy{}, calendar, nil)
+	exportService := services.NewExportService(repository.NewInMemoryExportRepository(saleRepo), spreadsheet.NewEncoder(), calendar)
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterProductRoutes(protected, services.NewProductService(productRepo, nil))
+	handlers.RegisterAdditionRoutes(protected, services.NewAdditionService(additionRepo, nil))
+	handlers.RegisterExportRoutes(protected, exportService, saleService)
+	return router
+}
+
+func TestExportSales_CSVAndXLSX(t *testing.T) {
+	router := setupExportTestRouter()
+	token := getValidToken(t, router)
+
+	cost := func(value float64) *float64 { return &value }
+	var burger product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 20.5, UnitCost: cost(8), CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+	var bacon addition.Addition
+	w = postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon & ovo", Price: 4})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bacon))
+
+	date := time.Date(2024, 9, 1, 19, 30, 0, 0, time.UTC)
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Date:     date,
+		Employee: "Maria",
+		Items: []sale.SaleItem{
+			{ProductID: burger.ID, Quantity: 2, Notes: "sem cebola", Additions: []addition.Addition{{ID: bacon.ID}}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	// Fora do período exportado.
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Date:  date.AddDate(0, 0, 2),
+		Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = getAuthorized(t, router, token, "/exports/sales?start=2024-09-01&end=2024-09-01")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="sales-2024-09-01-2024-09-01.csv"`)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "venda_id", records[0][0])
+	assert.Equal(t, "2024-09-01", records[1][2])
+	assert.Equal(t, "2024-09-01T19:30:00+00:00", records[1][3])
+	assert.Equal(t, "Maria", records[1][6])
+	assert.Equal(t, "49.00", records[1][10])
+
+	w = getAuthorized(t, router, token, "/exports/sales?start=2024-09-01&dataset=items&decimal=comma&date_format=br")
+	require.Equal(t, http.StatusOK, w.Code)
+	reader := csv.NewReader(w.Body)
+	reader.Comma = ';'
+	records, err = reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "01/09/2024", records[1][2])
+	assert.Equal(t, "01/09/2024 19:30:00", records[1][3])
+	assert.Equal(t, "X-Salada", records[1][7])
+	assert.Equal(t, "20,50", records[1][11])
+	assert.Equal(t, "49,00", records[1][12])
+	// O acréscimo não tem custo, então o custo do item fica vazio.
+	assert.Equal(t, "8,00", records[1][13])
+	assert.Equal(t, "", records[1][14])
+	assert.Equal(t, "sem cebola", records[1][15])
+
+	w = getAuthorized(t, router, token, "/exports/sales?start=2024-09-01&dataset=additions&format=xlsx")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	parts := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(content)
+		assert.NoError(t, wellFormedXML(content), f.Name)
+	}
+	require.Contains(t, parts, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Acréscimos"`)
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<t xml:space="preserve">Bacon &amp; ovo</t>`)
+	// 01/09/2024 19:30 é o dia 45536 do Excel mais 19,5 horas.
+	assert.Contains(t, sheet, `<c r="C2" s="2"><v>45536</v></c>`)
+	assert.Contains(t, sheet, `<c r="D2" s="3"><v>45536.8125</v></c>`)
+	assert.Contains(t, sheet, `<c r="L2" s="1"><v>8</v></c>`)
+	assert.NotContains(t, sheet, `r="M2"`)
+
+	w = getAuthorized(t, router, token, "/exports/sales?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = getAuthorized(t, router, token, "/exports/sales?dataset=payments")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = getAuthorized(t, router, token, "/exports/sales?decimal=space")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// failingExportService envia o cabeçalho e a primeira linha e falha em
+// seguida, como uma consulta interrompida no meio da exportação.
+type failingExportService struct{}
+
+func (failingExportService) ExportSales(ctx context.Context, w io.Writer, start, end time.Time, opts export.Options) error {
+	if _, err := io.WriteString(w, "order_number,date\n1,2024-09-01\n"); err != nil {
+		return err
+	}
+	return errors.New("conexão com o banco perdida")
+}
+
+func TestExportSales_LogsFailureAfterFirstRow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	saleService := services.NewSaleService(repository.NewInMemorySaleRepository(nil), repository.NewInMemoryProductRepository(nil, nil),
+		repository.NewInMemoryAdditionRepository(nil, nil), repository.NewInMemoryCategoryRepository(nil), nil, sale.ServiceChargePolicy{}, sale.Calendar{Location: time.UTC}, nil)
+	router := gin.New()
+	router.Use(middlewares.RequestID())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterExportRoutes(protected, failingExportService{}, saleService)
+	token := getValidToken(t, router)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	w := getAuthorized(t, router, token, "/exports/sales?start=2024-09-01")
+
+	// O status já foi enviado com a primeira linha; o erro fica no log com o
+	// ID da requisição devolvido ao cliente.
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "order_number,date\n1,2024-09-01\n", w.Body.String())
+	requestID := w.Header().Get(middlewares.RequestIDHeader)
+	require.NotEmpty(t, requestID)
+	assert.Contains(t, logs.String(), requestID)
+	assert.Contains(t, logs.String(), "conexão com o banco perdida")
+}
+
+func wellFormedXML(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
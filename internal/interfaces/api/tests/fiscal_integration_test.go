@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/fiscal"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/taxation"
+	"andressa-lanches/internal/infrastructure/nfce"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFiscalTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	issuer := fiscal.Issuer{
+		CNPJ:              "11222333000181",
+		StateRegistration: "123456789012",
+		Name:              "Andressa Lanches LTDA",
+		TaxRegime:         fiscal.TaxRegimeSimples,
+		Address:           fiscal.Address{Street: "Rua Exemplo", Number: "123", District: "Centro", CityCode: "3550308", City: "Sao Paulo", State: "SP", ZipCode: "01001000"},
+		CSCID:             "1",
+		CSC:               "123456",
+		QRCodeURL:         "https://nfce.example.com/qrcode",
+		ConsultURL:        "https://nfce.example.com/consulta",
+	}
+	certPEM, keyPEM, err := nfce.GenerateTestCertificate(issuer.Name, issuer.CNPJ)
+	require.NoError(t, err)
+	signer, err := nfce.ParseSigner(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+	fiscalRepo := repository.NewInMemoryFiscalRepository()
+
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+	productService := services.NewProductService(productRepo, nil)
+	categoryService := services.NewCategoryService(categoryRepo, repository.NewInMemoryStationRepository(), nil)
+	fiscalService := services.NewFiscalService(fiscalRepo, saleRepo, productRepo, categoryRepo, issuer, fiscal.EnvironmentHomologation, 1,
+		nfce.NewEncoder(signer), nfce.NewFakeTransmitter())
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterCategoryRoutes(protected, categoryService)
+	handlers.RegisterFiscalRoutes(protected, fiscalService)
+
+	return router
+}
+
+func createFiscalTestSale(t *testing.T, router *gin.Engine, token string, p product.Product) sale.Sale {
+	var created product.Product
+	if p.CategoryID == uuid.Nil {
+		p.CategoryID = uuid.New()
+	}
+	w := postJSON(t, router, token, http.MethodPost, "/products/", p)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	var createdSale sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Items:    []sale.SaleItem{{ProductID: created.ID, Quantity: 2}},
+		Payments: []sale.Payment{{Method: sale.PaymentMethodCash, Amount: 50}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdSale))
+	return createdSale
+}
+
+func TestIssueSaleNFCe(t *testing.T) {
+	router := setupFiscalTestRouter(t)
+	token := getValidToken(t, router)
+	createdSale := createFiscalTestSale(t, router, token, product.Product{Name: "X-Salada", Price: 18.5, FiscalData: taxation.FiscalData{NCM: "21069090", CFOP: "5102", CST: "102"}})
+	path := "/sales/" + createdSale.ID.String() + "/nfce"
+
+	w := postJSON(t, router, token, http.MethodPost, path, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]fiscal.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	doc := response["nfce"]
+	assert.Equal(t, fiscal.StatusAuthorized, doc.Status)
+	assert.Equal(t, 1, doc.Number)
+	assert.Len(t, doc.AccessKey, 44)
+	assert.NotEmpty(t, doc.Protocol)
+
+	w = postJSON(t, router, token, http.MethodPost, path, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = getAuthorized(t, router, token, path)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = getAuthorized(t, router, token, path+"?format=xml")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<xProd>"+fiscal.HomologationDescription+"</xProd>")
+	assert.Contains(t, w.Body.String(), "<vNF>37.00</vNF>")
+	assert.Contains(t, w.Body.String(), "<vTroco>13.00</vTroco>")
+	assert.NoError(t, nfce.Verify(w.Body.Bytes()))
+}
+
+func TestIssueSaleNFCe_Errors(t *testing.T) {
+	router := setupFiscalTestRouter(t)
+	token := getValidToken(t, router)
+	createdSale := createFiscalTestSale(t, router, token, product.Product{Name: "X-Salada", Price: 18.5})
+
+	w := postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/nfce", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/nfce")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, "/sales/"+uuid.New().String()+"/nfce", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/nfce?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIssueSaleNFCe_InheritsCategoryFiscalData(t *testing.T) {
+	router := setupFiscalTestRouter(t)
+	token := getValidToken(t, router)
+
+	var lanches category.Category
+	w := postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{
+		Name:       "Lanches",
+		FiscalData: taxation.FiscalData{NCM: "21069090", CFOP: "5102", CST: "102"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lanches))
+
+	w = getAuthorized(t, router, token, "/reports/products-missing-fiscal-data")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"products":[]}`, w.Body.String())
+
+	createdSale := createFiscalTestSale(t, router, token, product.Product{
+		Name:       "X-Salada",
+		Price:      18.5,
+		CategoryID: lanches.ID,
+		FiscalData: taxation.FiscalData{CEST: "1704900"},
+	})
+	createFiscalTestSale(t, router, token, product.Product{Name: "Suco", Price: 6, FiscalData: taxation.FiscalData{CFOP: "5102"}})
+
+	w = getAuthorized(t, router, token, "/reports/products-missing-fiscal-data")
+	require.Equal(t, http.StatusOK, w.Code)
+	var report map[string][]fiscal.MissingFiscalData
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report["products"], 1)
+	assert.Equal(t, "Suco", report["products"][0].Name)
+	assert.Equal(t, []string{"ncm", "cst"}, report["products"][0].Fields)
+
+	path := "/sales/" + createdSale.ID.String() + "/nfce"
+	w = postJSON(t, router, token, http.MethodPost, path, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = getAuthorized(t, router, token, path+"?format=xml")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<NCM>21069090</NCM><CEST>1704900</CEST><CFOP>5102</CFOP>")
+}
+
+func TestFiscalDataValidation(t *testing.T) {
+	router := setupFiscalTestRouter(t)
+	token := getValidToken(t, router)
+
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{
+		Name:       "X-Salada",
+		Price:      18.5,
+		CategoryID: uuid.New(),
+		FiscalData: taxation.FiscalData{NCM: "2106.90"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), taxation.ErrNCMInvalid.Error())
+
+	var lanches category.Category
+	w = postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Lanches"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lanches))
+
+	lanches.CFOP = "5999"
+	w = postJSON(t, router, token, http.MethodPut, "/categories/"+lanches.ID.String(), lanches)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), taxation.ErrCFOPInvalid.Error())
+}
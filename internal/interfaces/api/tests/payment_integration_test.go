@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/payment"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/infrastructure/payments"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentTestStore reúne os repositórios em memória, que podem ser
+// compartilhados por roteadores com gateways diferentes.
+type paymentTestStore struct {
+	saleRepo     *repository.InMemorySaleRepository
+	productRepo  *repository.InMemoryProductRepository
+	additionRepo *repository.InMemoryAdditionRepository
+	categoryRepo *repository.InMemoryCategoryRepository
+	paymentRepo  *repository.InMemoryPaymentRepository
+}
+
+func newPaymentTestStore() paymentTestStore {
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	return paymentTestStore{
+		saleRepo:     saleRepo,
+		productRepo:  repository.NewInMemoryProductRepository(nil, nil),
+		additionRepo: repository.NewInMemoryAdditionRepository(nil, nil),
+		categoryRepo: repository.NewInMemoryCategoryRepository(nil),
+		paymentRepo:  repository.NewInMemoryPaymentRepository(saleRepo, nil),
+	}
+}
+
+func newPaymentTestGateway() *payments.FakeGateway {
+	return payments.NewFakeGateway("webhook_secret", pix.Merchant{Key: "+5511999999999", Name: "Andressa Lanches", City: "Sao Paulo"})
+}
+
+func setupPaymentTestRouter() (*gin.Engine, *payments.FakeGateway) {
+	gateway := newPaymentTestGateway()
+	return setupPaymentTestRouterWith(newPaymentTestStore(), gateway), gateway
+}
+
+func setupPaymentTestRouterWith(store paymentTestStore, gateway *payments.FakeGateway) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	saleService := services.NewSaleService(store.saleRepo, store.productRepo, store.additionRepo, store.categoryRepo, nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+	productService := services.NewProductService(store.productRepo, nil)
+	paymentService := services.NewPaymentService(store.paymentRepo, store.saleRepo, gateway)
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+	handlers.RegisterPaymentWebhookRoutes(&router.RouterGroup, paymentService)
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterPaymentRoutes(protected, paymentService)
+	handlers.RegisterFakeGatewayRoutes(protected, gateway, paymentService)
+
+	return router
+}
+
+func postWebhook(router *gin.Engine, payload []byte, signature string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks/payments", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.WebhookSignatureHeader, signature)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func getPaymentTestSale(t *testing.T, router *gin.Engine, token string, s sale.Sale) sale.Sale {
+	w := getAuthorized(t, router, token, "/sales/"+s.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]sale.Sale
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response["sale"]
+}
+
+func TestPaymentCharge_WebhookConfirmsAndRefunds(t *testing.T) {
+	router, gateway := setupPaymentTestRouter()
+	token := getValidToken(t, router)
+	createdSale := createPixTestSale(t, router, token)
+
+	w := postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var charge payment.Charge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &charge))
+	assert.Equal(t, payment.StatusPending, charge.Status)
+	assert.Equal(t, sale.PaymentMethodPix, charge.Method)
+	assert.Equal(t, createdSale.TotalAmount, charge.Amount)
+	assert.NotEmpty(t, charge.PixPayload)
+
+	// Com a cobrança pendente não há mais o que cobrar
+	w = postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	payload, signature, err := gateway.Settle(context.Background(), charge.ProviderChargeID, payment.StatusConfirmed)
+	require.NoError(t, err)
+
+	w = postWebhook(router, payload, "assinatura-invalida")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postWebhook(router, payload, signature)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Reentrega da mesma notificação não duplica o pagamento
+	w = postWebhook(router, payload, signature)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	paid := getPaymentTestSale(t, router, token, createdSale)
+	require.Len(t, paid.Payments, 1)
+	assert.Equal(t, sale.PaymentMethodPix, paid.Payments[0].Method)
+	assert.Equal(t, createdSale.TotalAmount, paid.Payments[0].Amount)
+	assert.Equal(t, payments.FakeGatewayName+":"+charge.ProviderChargeID, paid.Payments[0].Reference)
+
+	w = postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, "/payments/charges/"+charge.ID.String()+"/refund", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var refunded map[string]payment.Charge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refunded))
+	assert.Equal(t, payment.StatusRefunded, refunded["charge"].Status)
+	assert.Empty(t, getPaymentTestSale(t, router, token, createdSale).Payments)
+}
+
+func TestPaymentCharge_WebhooksFromAnotherGatewayInstance(t *testing.T) {
+	// Duas instâncias do gateway gravando no mesmo banco, como depois de um
+	// reinício: a notificação da segunda não é uma reentrega da primeira.
+	store := newPaymentTestStore()
+	for i := 0; i < 2; i++ {
+		gateway := newPaymentTestGateway()
+		router := setupPaymentTestRouterWith(store, gateway)
+		token := getValidToken(t, router)
+		createdSale := createPixTestSale(t, router, token)
+
+		w := postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var charge payment.Charge
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &charge))
+
+		payload, signature, err := gateway.Settle(context.Background(), charge.ProviderChargeID, payment.StatusConfirmed)
+		require.NoError(t, err)
+		w = postWebhook(router, payload, signature)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		paid := getPaymentTestSale(t, router, token, createdSale)
+		require.Len(t, paid.Payments, 1, "venda da instância %d", i+1)
+		assert.Equal(t, createdSale.TotalAmount, paid.Payments[0].Amount)
+	}
+}
+
+func TestPaymentCharge_Simulate(t *testing.T) {
+	router, _ := setupPaymentTestRouter()
+	token := getValidToken(t, router)
+	createdSale := createPixTestSale(t, router, token)
+
+	w := postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{Method: sale.PaymentMethodCreditCard})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var charge payment.Charge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &charge))
+	assert.NotEmpty(t, charge.CheckoutURL)
+
+	w = postJSON(t, router, token, http.MethodPost, "/payments/charges/"+charge.ID.String()+"/simulate", handlers.SimulateChargeInput{Status: payment.StatusFailed})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]payment.Charge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, payment.StatusFailed, response["charge"].Status)
+	assert.Empty(t, getPaymentTestSale(t, router, token, createdSale).Payments)
+
+	w = postJSON(t, router, token, http.MethodPost, "/payments/charges/"+charge.ID.String()+"/refund", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/charges", handlers.CreateChargeInput{Method: sale.PaymentMethodCash})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postWebhook(router, []byte(`{"id":`), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
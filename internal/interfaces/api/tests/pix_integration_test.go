@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/pix"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/infrastructure/qrcode"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPixTestRouter(merchant pix.Merchant) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+	productService := services.NewProductService(productRepo, nil)
+	pixService := services.NewPixService(saleRepo, merchant, qrcode.PNGEncoder{})
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterPixRoutes(protected, pixService)
+
+	return router
+}
+
+func createPixTestSale(t *testing.T, router *gin.Engine, token string) sale.Sale {
+	var burger product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	var createdSale sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 2}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdSale))
+	return createdSale
+}
+
+func TestGetSalePix(t *testing.T) {
+	router := setupPixTestRouter(pix.Merchant{Key: "+5511999999999", Name: "Andressa Lanches", City: "São Paulo"})
+	token := getValidToken(t, router)
+	createdSale := createPixTestSale(t, router, token)
+
+	w := getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/pix")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]pix.Charge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	charge := response["pix"]
+	assert.Equal(t, createdSale.ID, charge.SaleID)
+	assert.Equal(t, 37.0, charge.Amount)
+	assert.Equal(t, pix.TxIDForOrder(createdSale.BusinessDate, createdSale.OrderNumber), charge.TxID)
+	assert.Contains(t, charge.Payload, "540537.00")
+	assert.Contains(t, charge.Payload, "6009Sao Paulo")
+
+	// O CRC no fim do payload confere com o restante do texto
+	body := charge.Payload[:len(charge.Payload)-4]
+	assert.Equal(t, fmt.Sprintf("%04X", pix.CRC16([]byte(body))), charge.Payload[len(body):])
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/pix?format=png&size=250")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	_, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(t, err)
+}
+
+func TestGetSalePix_Errors(t *testing.T) {
+	router := setupPixTestRouter(pix.Merchant{Key: "+5511999999999", Name: "Andressa Lanches", City: "Sao Paulo"})
+	token := getValidToken(t, router)
+	createdSale := createPixTestSale(t, router, token)
+
+	w := getAuthorized(t, router, token, "/sales/"+uuid.New().String()+"/pix")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/pix?format=gif")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/pix?format=png&size=0")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	unconfigured := setupPixTestRouter(pix.Merchant{})
+	token = getValidToken(t, unconfigured)
+	w = getAuthorized(t, unconfigured, token, "/sales/"+createdSale.ID.String()+"/pix")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/pricing"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPricingTestRouter() (*gin.Engine, services.PricingService) {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	userRepo := newTestUserRepository()
+	authService := newTestAuthService(userRepo)
+	priceRepo := repository.NewInMemoryPriceRepository()
+	auditRepo := repository.NewInMemoryAuditRepository()
+	productRepo := repository.NewInMemoryProductRepository(priceRepo, auditRepo)
+	additionRepo := repository.NewInMemoryAdditionRepository(priceRepo, auditRepo)
+	auditService := services.NewAuditService(auditRepo, sale.Calendar{})
+	productService := services.NewProductService(productRepo, nil)
+	additionService := services.NewAdditionService(additionRepo, nil)
+	pricingService := services.NewPricingService(priceRepo, repository.NewInMemoryAdjustmentRepository(productRepo, additionRepo, priceRepo, auditRepo),
+		productService, additionService, nil)
+
+	router := gin.Default()
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterAdditionRoutes(protected, additionService)
+	handlers.RegisterPricingRoutes(protected, pricingService)
+	handlers.RegisterAuditRoutes(protected, auditService)
+
+	return router, pricingService
+}
+
+func TestPricing_HistoryAndScheduledChange(t *testing.T) {
+	router, pricingService := setupPricingTestRouter()
+	token := getValidToken(t, router)
+
+	var burger product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	burger.Price = 20
+	w = postJSON(t, router, token, http.MethodPut, "/products/"+burger.ID.String(), burger)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	price := 22.0
+	w = postJSON(t, router, token, http.MethodPost, "/products/"+burger.ID.String()+"/scheduled-prices",
+		handlers.ScheduledPriceInput{Price: &price, EffectiveAt: time.Now().Add(50 * time.Millisecond).Format(time.RFC3339Nano)})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var scheduled map[string]pricing.ScheduledChange
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scheduled))
+	assert.Equal(t, pricing.ScheduleStatusPending, scheduled["scheduled_price"].Status)
+
+	// Ainda não venceu: nada muda.
+	require.NoError(t, pricingService.ApplyDuePriceChanges(context.Background()))
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"price":20`)
+
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, pricingService.ApplyDuePriceChanges(context.Background()))
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"price":22`)
+
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String()+"/price-history")
+	require.Equal(t, http.StatusOK, w.Code)
+	var history map[string][]pricing.PriceChange
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history["history"], 3)
+	assert.Equal(t, 22.0, history["history"][0].NewPrice)
+	assert.Equal(t, pricing.SchedulerActor, history["history"][0].Actor)
+	assert.Equal(t, scheduled["scheduled_price"].ID, *history["history"][0].ScheduledChangeID)
+	assert.Equal(t, "test_user", history["history"][1].Actor)
+	assert.Nil(t, history["history"][2].OldPrice)
+
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String()+"/scheduled-prices")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"applied"`)
+
+	// Um agendamento aplicado não pode mais ser cancelado.
+	w = postJSON(t, router, token, http.MethodDelete, "/products/"+burger.ID.String()+"/scheduled-prices/"+scheduled["scheduled_price"].ID.String(), nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPricing_CancelScheduledAdditionPrice(t *testing.T) {
+	router, pricingService := setupPricingTestRouter()
+	token := getValidToken(t, router)
+
+	var bacon addition.Addition
+	w := postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon", Price: 4})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bacon))
+
+	price := 5.0
+	path := "/additions/" + bacon.ID.String() + "/scheduled-prices"
+	w = postJSON(t, router, token, http.MethodPost, path, handlers.ScheduledPriceInput{Price: &price, EffectiveAt: "2000-01-01T00:00:00Z"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = postJSON(t, router, token, http.MethodPost, "/additions/"+uuid.NewString()+"/scheduled-prices",
+		handlers.ScheduledPriceInput{Price: &price, EffectiveAt: time.Now().Add(time.Hour).Format(time.RFC3339)})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, path, handlers.ScheduledPriceInput{Price: &price, EffectiveAt: time.Now().Add(50 * time.Millisecond).Format(time.RFC3339Nano)})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var scheduled map[string]pricing.ScheduledChange
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scheduled))
+
+	w = postJSON(t, router, token, http.MethodDelete, path+"/"+scheduled["scheduled_price"].ID.String(), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, pricingService.ApplyDuePriceChanges(context.Background()))
+	w = getAuthorized(t, router, token, "/additions/"+bacon.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"price":4`)
+}
+
+func TestPricing_BulkAdjustment(t *testing.T) {
+	router, _ := setupPricingTestRouter()
+	token := getValidToken(t, router)
+
+	drinks, snacks := uuid.New(), uuid.New()
+	create := func(p product.Product) product.Product {
+		w := postJSON(t, router, token, http.MethodPost, "/products/", p)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		return p
+	}
+	soda := create(product.Product{Name: "Refrigerante", Price: 6, CategoryID: drinks})
+	juice := create(product.Product{Name: "Suco", Price: 8.5, CategoryID: drinks})
+	burger := create(product.Product{Name: "X-Salada", Price: 18.5, CategoryID: snacks})
+	var bacon addition.Addition
+	w := postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon", Price: 4})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bacon))
+
+	adjustment := pricing.Adjustment{
+		Mode:        pricing.AdjustmentPercentage,
+		Value:       10,
+		CategoryIDs: []uuid.UUID{drinks},
+		AdditionIDs: []uuid.UUID{bacon.ID},
+		Rounding:    pricing.Rounding{Mode: pricing.RoundingEnding, Value: 0.9},
+		DryRun:      true,
+	}
+	w = postJSON(t, router, token, http.MethodPost, "/pricing/adjustments", adjustment)
+	require.Equal(t, http.StatusOK, w.Code)
+	var preview map[string]pricing.AdjustmentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.True(t, preview["adjustment"].DryRun)
+	assert.Equal(t, []pricing.AdjustmentItem{
+		{ItemType: pricing.ItemProduct, ItemID: soda.ID, Name: "Refrigerante", OldPrice: 6, NewPrice: 6.9},
+		{ItemType: pricing.ItemProduct, ItemID: juice.ID, Name: "Suco", OldPrice: 8.5, NewPrice: 9.9},
+		{ItemType: pricing.ItemAddition, ItemID: bacon.ID, Name: "Bacon", OldPrice: 4, NewPrice: 4.9},
+	}, preview["adjustment"].Items)
+
+	// A prévia não grava nada.
+	w = getAuthorized(t, router, token, "/products/"+soda.ID.String())
+	assert.Contains(t, w.Body.String(), `"price":6,`)
+
+	adjustment.DryRun = false
+	w = postJSON(t, router, token, http.MethodPost, "/pricing/adjustments", adjustment)
+	require.Equal(t, http.StatusOK, w.Code)
+	var applied map[string]pricing.AdjustmentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &applied))
+	assert.False(t, applied["adjustment"].DryRun)
+	assert.Equal(t, preview["adjustment"].Items, applied["adjustment"].Items)
+
+	w = getAuthorized(t, router, token, "/products/"+juice.ID.String())
+	assert.Contains(t, w.Body.String(), `"price":9.9`)
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String())
+	assert.Contains(t, w.Body.String(), `"price":18.5`)
+	w = getAuthorized(t, router, token, "/additions/"+bacon.ID.String()+"/price-history")
+	require.Equal(t, http.StatusOK, w.Code)
+	var history map[string][]pricing.PriceChange
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history["history"], 2)
+	assert.Equal(t, 4.9, history["history"][0].NewPrice)
+	assert.Equal(t, "test_user", history["history"][0].Actor)
+
+	entries := listAuditEntries(t, router, token, "action=update&entity_id="+soda.ID.String())
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, `{"price":{"before":6,"after":6.9}}`, string(entries[0].Changes))
+
+	// Um item que ficaria com preço inválido cancela o lote inteiro.
+	w = postJSON(t, router, token, http.MethodPost, "/pricing/adjustments", pricing.Adjustment{
+		Mode:       pricing.AdjustmentFixed,
+		Value:      -7,
+		ProductIDs: []uuid.UUID{burger.ID, soda.ID},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Refrigerante")
+	w = getAuthorized(t, router, token, "/products/"+burger.ID.String())
+	assert.Contains(t, w.Body.String(), `"price":18.5`)
+
+	w = postJSON(t, router, token, http.MethodPost, "/pricing/adjustments", pricing.Adjustment{
+		Mode:       pricing.AdjustmentFixed,
+		Value:      1,
+		ProductIDs: []uuid.UUID{uuid.New()},
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -30,15 +30,16 @@ func setupProductTestRouter() *gin.Engine {
 	config.AuthUser = "test_user"
 	config.AuthPassword = "test_password"
 
-	productRepo := repository.NewInMemoryProductRepository()
-	productService := services.NewProductService(productRepo)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	productService := services.NewProductService(productRepo, nil)
 
 	router := gin.Default()
 
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(authService))
 	handlers.RegisterProductRoutes(protected, productService)
 
 	return router
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/menu"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/taxation"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPublicMenuTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	menuService := services.NewMenuService(categoryRepo, productRepo, additionRepo)
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+	handlers.RegisterPublicRoutes(&router.RouterGroup, menuService)
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterCategoryRoutes(protected, services.NewCategoryService(categoryRepo, repository.NewInMemoryStationRepository(), menuService))
+	handlers.RegisterProductRoutes(protected, services.NewProductService(productRepo, menuService))
+	handlers.RegisterAdditionRoutes(protected, services.NewAdditionService(additionRepo, menuService))
+	return router
+}
+
+func getPublicMenu(router *gin.Engine, header, value string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/public/menu", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPublicMenu_CachedUntilCatalogChanges(t *testing.T) {
+	router := setupPublicMenuTestRouter()
+	token := getValidToken(t, router)
+
+	var snacks category.Category
+	w := postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Lanches"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snacks))
+	w = postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Sobremesas"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	cost := 8.0
+	var burger product.Product
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{
+		Name: "X-Salada", Price: 20, UnitCost: &cost, CategoryID: snacks.ID, ExternalCode: "P-1",
+		FiscalData: taxation.FiscalData{NCM: "21069090"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+	w = postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon", Price: 4, UnitCost: &cost})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// Sem token.
+	w = getPublicMenu(router, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+	assert.NotContains(t, w.Body.String(), "unit_cost")
+	assert.NotContains(t, w.Body.String(), "ncm")
+	assert.NotContains(t, w.Body.String(), "P-1")
+
+	var m menu.Menu
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	require.Len(t, m.Categories, 1, "a categoria sem produtos fica de fora")
+	assert.Equal(t, "Lanches", m.Categories[0].Name)
+	assert.Equal(t, []menu.Product{{ID: burger.ID, Name: "X-Salada", Price: 20}}, m.Categories[0].Products)
+	require.Len(t, m.Additions, 1)
+	assert.Equal(t, "Bacon", m.Additions[0].Name)
+
+	w = getPublicMenu(router, "If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	w = getPublicMenu(router, "If-Modified-Since", lastModified)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	burger.Price = 22
+	w = postJSON(t, router, token, http.MethodPut, "/products/"+burger.ID.String(), burger)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = getPublicMenu(router, "If-None-Match", etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	m = menu.Menu{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assert.Equal(t, 22.0, m.Categories[0].Products[0].Price)
+	w = getPublicMenu(router, "If-Modified-Since", lastModified)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/receipt"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/infrastructure/printing"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupReceiptTestRouter(printer receipt.Printer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, nil, sale.ServiceChargePolicy{}, sale.Calendar{}, nil)
+	productService := services.NewProductService(productRepo, nil)
+	renderer := printing.NewRenderer(printing.Layout{
+		Width:  printing.Width58mm,
+		Header: []string{"Andressa Lanches"},
+		Footer: []string{"Obrigado pela preferência!"},
+	})
+	receiptService := services.NewReceiptService(saleRepo, renderer, printer)
+
+	router := gin.Default()
+	authService := newTestAuthService(newTestUserRepository())
+	router.POST("/auth/login", handlers.LoginHandler(authService))
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterReceiptRoutes(protected, receiptService)
+	handlers.RegisterReportRoutes(protected, saleService, receiptService)
+
+	return router
+}
+
+func createReceiptTestSale(t *testing.T, router *gin.Engine, token string) sale.Sale {
+	var burger product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Coração", Price: 1250, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	var createdSale sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Employee: "Andressa",
+		Items: []sale.SaleItem{
+			{ProductID: burger.ID, Quantity: 1, Notes: "sem cebola"},
+		},
+		Payments: []sale.Payment{{Method: sale.PaymentMethodCash, Amount: 1300}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdSale))
+	return createdSale
+}
+
+func getAuthorized(t *testing.T, router *gin.Engine, token, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetReceipt_TextAndESCPOS(t *testing.T) {
+	router := setupReceiptTestRouter(nil)
+	token := getValidToken(t, router)
+	createdSale := createReceiptTestSale(t, router, token)
+
+	w := getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, w.Body.String(), "Andressa Lanches")
+	assert.Contains(t, w.Body.String(), "X-Coração")
+	assert.Contains(t, w.Body.String(), "1.250,00")
+	assert.Contains(t, w.Body.String(), "Obs: sem cebola")
+	assert.Contains(t, w.Body.String(), "Dinheiro")
+	assert.Contains(t, w.Body.String(), "Troco")
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt?kind=kitchen&format=escpos")
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.Bytes()
+	assert.True(t, bytes.HasPrefix(body, []byte{0x1b, 0x40}), "o documento deve começar com ESC @")
+	assert.Contains(t, string(body), "PEDIDO")
+	// "ç" e "ã" na página de código 850
+	assert.Contains(t, string(body), "X-Cora\x87\xc6o")
+	assert.NotContains(t, string(body), "1.250,00")
+}
+
+// assertValidPDF confere a estrutura básica do arquivo: cabeçalho, marcador
+// de fim e a posição da tabela de referências cruzadas.
+func assertValidPDF(t *testing.T, body []byte) {
+	require.True(t, bytes.HasPrefix(body, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(body, []byte("%%EOF\n")))
+
+	trailer := body[bytes.LastIndex(body, []byte("startxref\n"))+len("startxref\n"):]
+	offset, err := strconv.Atoi(string(trailer[:bytes.IndexByte(trailer, '\n')]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(body[offset:], []byte("xref\n")))
+}
+
+func TestGetReceipt_PDF(t *testing.T) {
+	router := setupReceiptTestRouter(nil)
+	token := getValidToken(t, router)
+	createdSale := createReceiptTestSale(t, router, token)
+
+	w := getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt?format=pdf")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="comprovante-`+createdSale.ID.String()+`.pdf"`, w.Header().Get("Content-Disposition"))
+
+	body := w.Body.Bytes()
+	assertValidPDF(t, body)
+	assert.Contains(t, string(body), "/BaseFont /Courier")
+	// "ç" e "ã" em WinAnsi
+	assert.Contains(t, string(body), "X-Cora\xe7\xe3o")
+	assert.Contains(t, string(body), "Troco")
+}
+
+func TestDailyClosingReport(t *testing.T) {
+	router := setupReceiptTestRouter(nil)
+	token := getValidToken(t, router)
+	createReceiptTestSale(t, router, token)
+	createReceiptTestSale(t, router, token)
+
+	today := time.Now().Format("2006-01-02")
+
+	w := getAuthorized(t, router, token, "/reports/daily-closing?date="+today)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]sale.DailyClosing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	closing := response["closing"]
+	assert.Equal(t, 2, closing.SalesCount)
+	assert.Equal(t, 2500.0, closing.TotalAmount)
+	assert.Equal(t, []sale.PaymentSummary{{Method: sale.PaymentMethodCash, Amount: 2500}}, closing.Payments)
+	assert.Len(t, closing.Products, 2)
+
+	w = getAuthorized(t, router, token, "/reports/daily-closing?date="+today+"&format=pdf")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="fechamento-`+today+`.pdf"`, w.Header().Get("Content-Disposition"))
+	assertValidPDF(t, w.Body.Bytes())
+	assert.Contains(t, w.Body.String(), "FECHAMENTO DO CAIXA")
+	assert.Contains(t, w.Body.String(), "/MediaBox [0 0 595 842]")
+
+	w = getAuthorized(t, router, token, "/reports/daily-closing?date="+today+"&format=text")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "2.500,00")
+
+	w = getAuthorized(t, router, token, "/reports/daily-closing?date=01-09-2024")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/reports/daily-closing?format=docx")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetReceipt_InvalidRequests(t *testing.T) {
+	router := setupReceiptTestRouter(nil)
+	token := getValidToken(t, router)
+	createdSale := createReceiptTestSale(t, router, token)
+
+	w := getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt?kind=invoice")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt?format=html")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/"+createdSale.ID.String()+"/receipt?kind=kitchen&station=abc")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getAuthorized(t, router, token, "/sales/00000000-0000-0000-0000-000000000001/receipt")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/print", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestPrintReceipt_NetworkPrinter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	queue := printing.NewPrintQueue(&printing.NetworkPrinter{Address: listener.Addr().String()}, 5)
+	defer queue.Close()
+
+	router := setupReceiptTestRouter(queue)
+	token := getValidToken(t, router)
+	createdSale := createReceiptTestSale(t, router, token)
+
+	w := postJSON(t, router, token, http.MethodPost, "/sales/"+createdSale.ID.String()+"/print?kind=kitchen", nil)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	select {
+	case data := <-received:
+		assert.True(t, bytes.HasPrefix(data, []byte{0x1b, 0x40}))
+		assert.Contains(t, string(data), "PEDIDO")
+		assert.Contains(t, string(data), "OBS: sem cebola")
+	case <-time.After(5 * time.Second):
+		t.Fatal("a impressora não recebeu o documento")
+	}
+}
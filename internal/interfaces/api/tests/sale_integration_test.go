@@ -4,17 +4,28 @@ import (
 	"andressa-lanches/internal/application/services"
 	"andressa-lanches/internal/config"
 	"andressa-lanches/internal/domain/addition"
+	"andressa-lanches/internal/domain/category"
+	"andressa-lanches/internal/domain/pricing"
 	"andressa-lanches/internal/domain/product"
 	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/station"
+	"andressa-lanches/internal/infrastructure/events"
+	"andressa-lanches/internal/infrastructure/printing"
 	"andressa-lanches/internal/infrastructure/repository"
 	"andressa-lanches/internal/interfaces/api/handlers"
 	"andressa-lanches/internal/interfaces/api/middlewares"
 
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -30,25 +41,41 @@ func setupSaleTestRouter() *gin.Engine {
 	config.AuthPassword = "test_password"
 
 	// Repositórios em memória
-	saleRepo := repository.NewInMemorySaleRepository()
-	productRepo := repository.NewInMemoryProductRepository()
-	additionRepo := repository.NewInMemoryAdditionRepository()
+	saleRepo := repository.NewInMemorySaleRepository(nil)
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	additionRepo := repository.NewInMemoryAdditionRepository(nil, nil)
+	categoryRepo := repository.NewInMemoryCategoryRepository(nil)
+	stationRepo := repository.NewInMemoryStationRepository()
+	userRepo := newTestUserRepository()
+
+	kitchenBroker := events.NewKitchenBroker(100)
 
 	// Serviços
-	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo)
-	productService := services.NewProductService(productRepo)
-	additionService := services.NewAdditionService(additionRepo)
+	saleService := services.NewSaleService(saleRepo, productRepo, additionRepo, categoryRepo, userRepo, sale.ServiceChargePolicy{
+		Percentage: 10,
+		OrderTypes: []sale.OrderType{sale.OrderTypeDineIn},
+	}, sale.Calendar{}, kitchenBroker)
+	productService := services.NewProductService(productRepo, nil)
+	additionService := services.NewAdditionService(additionRepo, nil)
+	categoryService := services.NewCategoryService(categoryRepo, stationRepo, nil)
+	stationService := services.NewStationService(stationRepo)
+	receiptService := services.NewReceiptService(saleRepo, printing.NewRenderer(printing.Layout{}), nil)
 
 	router := gin.Default()
-	router.POST("/auth/login", handlers.LoginHandler())
+	authService := newTestAuthService(userRepo)
+	router.POST("/auth/login", handlers.LoginHandler(authService))
 
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
+	protected.Use(middlewares.AuthMiddleware(authService))
 
 	// Registrar rotas necessárias
 	handlers.RegisterSaleRoutes(protected, saleService)
 	handlers.RegisterProductRoutes(protected, productService)
 	handlers.RegisterAdditionRoutes(protected, additionService)
+	handlers.RegisterReportRoutes(protected, saleService, receiptService)
+	handlers.RegisterKitchenRoutes(protected, kitchenBroker)
+	handlers.RegisterCategoryRoutes(protected, categoryService)
+	handlers.RegisterStationRoutes(protected, stationService, saleService)
 
 	return router
 }
@@ -630,3 +657,597 @@ func TestCreateSale_InvalidAdditionID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response["error"], "ID do acréscimo inválido")
 }
+
+func TestCreateSale_DineInServiceChargeAndTipsReport(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	// Criar um produto
+	newProduct := &product.Product{
+		Name:       "Test Product",
+		Price:      30.0,
+		CategoryID: uuid.New(),
+	}
+	payload, _ := json.Marshal(newProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var createdProduct product.Product
+	err := json.Unmarshal(w.Body.Bytes(), &createdProduct)
+	require.NoError(t, err)
+
+	// Criar uma venda no salão com taxa de serviço
+	newSale := &sale.Sale{
+		OrderType: sale.OrderTypeDineIn,
+		Employee:  "Maria",
+		Items: []sale.SaleItem{
+			{ProductID: createdProduct.ID, Quantity: 2},
+		},
+	}
+	payload, _ = json.Marshal(newSale)
+
+	req, _ = http.NewRequest(http.MethodPost, "/sales/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var createdSale sale.Sale
+	err = json.Unmarshal(w.Body.Bytes(), &createdSale)
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, createdSale.ServiceCharge)
+	assert.Equal(t, 66.0, createdSale.TotalAmount)
+
+	// Venda no salão com taxa dispensada sem motivo
+	newSale.ServiceChargeWaived = true
+	payload, _ = json.Marshal(newSale)
+
+	req, _ = http.NewRequest(http.MethodPost, "/sales/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Relatório de gorjetas do dia
+	req, _ = http.NewRequest(http.MethodGet, "/reports/tips", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var reportResponse map[string][]sale.TipsSummary
+	err = json.Unmarshal(w.Body.Bytes(), &reportResponse)
+	require.NoError(t, err)
+	require.Len(t, reportResponse["tips"], 1)
+	// A venda é do operador logado, com o nome do cadastro, e não do nome
+	// digitado.
+	assert.Equal(t, config.AuthUser, reportResponse["tips"][0].Employee)
+	assert.NotNil(t, reportResponse["tips"][0].OperatorID)
+	assert.Equal(t, 6.0, reportResponse["tips"][0].ServiceCharge)
+}
+
+func TestCreateSale_CostSnapshotAndMarginReport(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	var snacks category.Category
+	w := postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Lanches"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snacks))
+
+	cost := func(value float64) *float64 { return &value }
+	var burger, juice product.Product
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 20, UnitCost: cost(8), CategoryID: snacks.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Suco", Price: 8, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &juice))
+	var bacon addition.Addition
+	w = postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon", Price: 4, UnitCost: cost(1.5)})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bacon))
+
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Errado", Price: 8, UnitCost: cost(-1), CategoryID: uuid.New()})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var created sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Discount: 4,
+		Items: []sale.SaleItem{
+			{ProductID: burger.ID, Quantity: 2, Additions: []addition.Addition{{ID: bacon.ID}}},
+			{ProductID: juice.ID, Quantity: 1},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Items[0].TotalCost)
+	assert.Equal(t, 8.0, *created.Items[0].UnitCost)
+	assert.Equal(t, 19.0, *created.Items[0].TotalCost)
+	assert.Nil(t, created.Items[1].TotalCost)
+
+	// O custo registrado na venda não muda com o cadastro.
+	burger.UnitCost = cost(10)
+	w = postJSON(t, router, token, http.MethodPut, "/products/"+burger.ID.String(), burger)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = getAuthorized(t, router, token, "/reports/margin")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]sale.MarginReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	report := response["margin"]
+	assert.Equal(t, 56.0, report.Revenue)
+	assert.Equal(t, 4.0, report.Discount)
+	assert.Equal(t, 19.0, report.Cost)
+	assert.Equal(t, 33.0, report.GrossMargin)
+	assert.Equal(t, 1, report.UncostedQuantity)
+
+	require.Len(t, report.Sales, 1)
+	assert.Equal(t, 33.0, report.Sales[0].GrossMargin)
+	require.Len(t, report.Products, 2)
+	assert.Equal(t, "X-Salada", report.Products[0].ProductName)
+	assert.Equal(t, 29.0, report.Products[0].GrossMargin)
+	assert.Equal(t, 60.42, report.Products[0].MarginPercentage)
+	require.Len(t, report.Categories, 2)
+	assert.Equal(t, "Lanches", report.Categories[0].CategoryName)
+	assert.Equal(t, 48.0, report.Categories[0].Revenue)
+}
+
+func TestABCReport_RanksAndComparesWithPreviousPeriod(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	cost := func(value float64) *float64 { return &value }
+	var burger, juice, water product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 20, UnitCost: cost(8), CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Suco", Price: 8, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &juice))
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Água", Price: 3, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &water))
+	var bacon addition.Addition
+	w = postJSON(t, router, token, http.MethodPost, "/additions/", addition.Addition{Name: "Bacon", Price: 4, UnitCost: cost(1.5)})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bacon))
+
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Date:  time.Now().AddDate(0, 0, -1),
+		Items: []sale.SaleItem{{ProductID: juice.ID, Quantity: 3}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Items: []sale.SaleItem{
+			{ProductID: burger.ID, Quantity: 2, Additions: []addition.Addition{{ID: bacon.ID}}},
+			{ProductID: juice.ID, Quantity: 1},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = getAuthorized(t, router, token, "/reports/abc")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]sale.ABCReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	report := response["abc"]
+	assert.Equal(t, sale.ABCByRevenue, report.RankBy)
+	require.Len(t, report.Items, 4)
+
+	assert.Equal(t, "X-Salada", report.Items[0].Name)
+	assert.Equal(t, 40.0, report.Items[0].Revenue)
+	assert.Equal(t, 24.0, report.Items[0].GrossMargin)
+	assert.Equal(t, 71.43, report.Items[0].Share)
+	assert.Equal(t, sale.ABCClassA, report.Items[0].Class)
+	assert.Nil(t, report.Items[0].Previous)
+
+	assert.Equal(t, pricing.ItemAddition, report.Items[1].ItemType)
+	assert.Equal(t, 8.0, report.Items[1].Revenue)
+	assert.Equal(t, sale.ABCClassA, report.Items[1].Class)
+
+	assert.Equal(t, "Suco", report.Items[2].Name)
+	assert.Equal(t, sale.ABCClassB, report.Items[2].Class)
+	assert.Equal(t, 1, report.Items[2].UncostedQuantity)
+	require.NotNil(t, report.Items[2].Previous)
+	assert.Equal(t, 1, report.Items[2].Previous.Rank)
+	assert.Equal(t, 3, report.Items[2].Previous.Quantity)
+	assert.Equal(t, sale.ABCClassA, report.Items[2].Previous.Class)
+
+	assert.Equal(t, water.ID, report.Items[3].ItemID)
+	assert.Equal(t, 0, report.Items[3].Quantity)
+	assert.Equal(t, sale.ABCClassC, report.Items[3].Class)
+
+	require.Len(t, report.Classes, 3)
+	assert.Equal(t, 2, report.Classes[0].Items)
+	assert.Equal(t, 85.72, report.Classes[0].Share)
+
+	w = getAuthorized(t, router, token, "/reports/abc?rank_by=margin")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	report = response["abc"]
+	assert.Equal(t, "X-Salada", report.Items[0].Name)
+	assert.Equal(t, "Suco", report.Items[1].Name)
+	assert.Equal(t, 5.0, report.Items[2].GrossMargin)
+
+	w = getAuthorized(t, router, token, "/reports/abc?rank_by=profit")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHeatmapReport_JSONAndCSV(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	var burger product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 20, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 19, 30, 0, 0, time.Local)
+	for i := 0; i < 2; i++ {
+		w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+			Date:  date,
+			Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}},
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	w = getAuthorized(t, router, token, "/reports/heatmap")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]sale.Heatmap
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	heatmap := response["heatmap"]
+	assert.Equal(t, 2, heatmap.Orders)
+	assert.Equal(t, 40.0, heatmap.Revenue)
+	assert.Equal(t, 2, heatmap.Cell(date.Weekday(), 19).Orders)
+
+	w = getAuthorized(t, router, token, "/reports/heatmap?format=csv&metric=revenue")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 8)
+	assert.True(t, strings.HasPrefix(lines[0], "dia,00h,01h,"))
+	row := strings.Split(lines[int(date.Weekday())+1], ",")
+	assert.Equal(t, sale.WeekdayName(date.Weekday()), row[0])
+	assert.Equal(t, "40.00", row[20])
+
+	w = getAuthorized(t, router, token, "/reports/heatmap?format=xls")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = getAuthorized(t, router, token, "/reports/heatmap?metric=tickets")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// readKitchenFeed abre o feed da cozinha e o encerra após um curto intervalo,
+// devolvendo os eventos recebidos até então.
+func readKitchenFeed(t *testing.T, router *gin.Engine, token, query, lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/kitchen/feed"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestKitchenFeed_StatusAndNotes(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	// Criar um produto
+	categoryID := uuid.New()
+	newProduct := &product.Product{
+		Name:       "X-Salada",
+		Price:      18.0,
+		CategoryID: categoryID,
+	}
+	payload, _ := json.Marshal(newProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var createdProduct product.Product
+	err := json.Unmarshal(w.Body.Bytes(), &createdProduct)
+	require.NoError(t, err)
+
+	// Criar uma venda com observação
+	newSale := &sale.Sale{
+		Items: []sale.SaleItem{
+			{ProductID: createdProduct.ID, Quantity: 1, Notes: "sem cebola"},
+		},
+	}
+	payload, _ = json.Marshal(newSale)
+
+	req, _ = http.NewRequest(http.MethodPost, "/sales/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var createdSale sale.Sale
+	err = json.Unmarshal(w.Body.Bytes(), &createdSale)
+	require.NoError(t, err)
+	require.Len(t, createdSale.Items, 1)
+
+	// Atualizar a observação do item e o status da venda
+	payload, _ = json.Marshal(handlers.UpdateSaleItemNotesInput{Notes: "sem cebola, bem passado"})
+	req, _ = http.NewRequest(http.MethodPatch, "/sales/"+createdSale.ID.String()+"/items/1/notes", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	payload, _ = json.Marshal(handlers.UpdateSaleStatusInput{Status: sale.StatusPreparing})
+	req, _ = http.NewRequest(http.MethodPatch, "/sales/"+createdSale.ID.String()+"/status", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// Status inválido
+	payload, _ = json.Marshal(handlers.UpdateSaleStatusInput{Status: "burnt"})
+	req, _ = http.NewRequest(http.MethodPatch, "/sales/"+createdSale.ID.String()+"/status", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Uma tela reconectando recebe todos os eventos perdidos
+	w = readKitchenFeed(t, router, token, "?category="+categoryID.String(), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	ids := kitchenEventIDs(t, body)
+	require.Equal(t, []string{"sale.created", "sale.item_notes_changed", "sale.status_changed"}, kitchenEventTypes(body))
+	assert.Equal(t, ids[0]+1, ids[1])
+	assert.Equal(t, ids[1]+1, ids[2])
+	assert.Contains(t, body, "bem passado")
+
+	// Com Last-Event-ID apenas os eventos posteriores são reenviados
+	w = readKitchenFeed(t, router, token, "", strconv.FormatUint(ids[1], 10))
+	assert.Equal(t, []string{"sale.status_changed"}, kitchenEventTypes(w.Body.String()))
+
+	// Um ID de uma execução anterior do servidor pede que a tela se
+	// remonte com todo o histórico
+	w = readKitchenFeed(t, router, token, "", "2")
+	assert.Equal(t, []string{"resync", "sale.created", "sale.item_notes_changed", "sale.status_changed"}, kitchenEventTypes(w.Body.String()))
+	w = readKitchenFeed(t, router, token, "", strconv.FormatUint(ids[2]+10, 10))
+	assert.Equal(t, "resync", kitchenEventTypes(w.Body.String())[0])
+
+	// Telas de outras categorias não recebem a venda
+	w = readKitchenFeed(t, router, token, "?category="+uuid.New().String(), "")
+	assert.NotContains(t, w.Body.String(), "event:")
+}
+
+var kitchenEventPattern = regexp.MustCompile(`id: (\d+)\nevent: (\S+)\n`)
+
+// kitchenEventIDs devolve os IDs dos eventos do feed, na ordem recebida.
+func kitchenEventIDs(t *testing.T, body string) []uint64 {
+	var ids []uint64
+	for _, match := range kitchenEventPattern.FindAllStringSubmatch(body, -1) {
+		id, err := strconv.ParseUint(match[1], 10, 64)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// kitchenEventTypes devolve os tipos dos eventos do feed, na ordem recebida.
+func kitchenEventTypes(body string) []string {
+	var types []string
+	for _, match := range kitchenEventPattern.FindAllStringSubmatch(body, -1) {
+		types = append(types, match[2])
+	}
+	return types
+}
+
+// postJSON envia uma requisição autenticada com corpo JSON.
+func postJSON(t *testing.T, router *gin.Engine, token, method, path string, body any) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestStationTickets_SaleReadyWhenAllStationsDone(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	// Estações e categorias
+	var grill, counter station.Station
+	w := postJSON(t, router, token, http.MethodPost, "/stations/", station.Station{Name: "Chapa"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &grill))
+
+	w = postJSON(t, router, token, http.MethodPost, "/stations/", station.Station{Name: "Balcão"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &counter))
+
+	var burgers, drinks category.Category
+	w = postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Lanches", StationID: &grill.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burgers))
+
+	w = postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Bebidas", StationID: &counter.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drinks))
+
+	// Produtos
+	var burger, soda product.Product
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "X-Bacon", Price: 22, CategoryID: burgers.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	w = postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Refrigerante", Price: 6, CategoryID: drinks.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &soda))
+
+	// Venda com itens para as duas estações
+	var createdSale sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Items: []sale.SaleItem{
+			{ProductID: burger.ID, Quantity: 1},
+			{ProductID: soda.ID, Quantity: 2},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdSale))
+	require.Len(t, createdSale.Items, 2)
+	assert.Equal(t, grill.ID, *createdSale.Items[0].StationID)
+	assert.Equal(t, counter.ID, *createdSale.Items[1].StationID)
+
+	// A chapa enxerga apenas o lanche
+	req, _ := http.NewRequest(http.MethodGet, "/stations/"+grill.ID.String()+"/tickets", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var ticketsResponse map[string][]sale.StationTicket
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ticketsResponse))
+	require.Len(t, ticketsResponse["tickets"], 1)
+	require.Len(t, ticketsResponse["tickets"][0].Items, 1)
+	assert.Equal(t, "X-Bacon", ticketsResponse["tickets"][0].Items[0].ProductName)
+
+	// Chapa despacha sua comanda: a venda ainda não está pronta
+	w = postJSON(t, router, token, http.MethodPost, "/stations/"+grill.ID.String()+"/tickets/"+createdSale.ID.String()+"/bump", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	getSale := func() sale.Sale {
+		req, _ := http.NewRequest(http.MethodGet, "/sales/"+createdSale.ID.String(), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]sale.Sale
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["sale"]
+	}
+	assert.Equal(t, sale.StatusPreparing, getSale().Status)
+
+	// Balcão marca a bebida como pronta: todas as comandas concluídas
+	w = postJSON(t, router, token, http.MethodPatch, "/sales/"+createdSale.ID.String()+"/items/2/status",
+		handlers.UpdateSaleItemStatusInput{Status: sale.ItemStatusReady})
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, sale.StatusReady, getSale().Status)
+
+	// A bebida é reaberta: a venda volta ao preparo
+	w = postJSON(t, router, token, http.MethodPatch, "/sales/"+createdSale.ID.String()+"/items/2/status",
+		handlers.UpdateSaleItemStatusInput{Status: sale.ItemStatusPending})
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, sale.StatusPreparing, getSale().Status)
+
+	// Comanda inexistente para a estação
+	w = postJSON(t, router, token, http.MethodPost, "/stations/"+uuid.New().String()+"/tickets/"+createdSale.ID.String()+"/bump", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Categoria com estação desconhecida
+	unknown := uuid.New()
+	w = postJSON(t, router, token, http.MethodPost, "/categories/", category.Category{Name: "Porções", StationID: &unknown})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = postJSON(t, router, token, http.MethodPut, "/categories/"+burgers.ID.String(), category.Category{Name: "Lanches", StationID: &unknown})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateSale_DailyOrderNumbers(t *testing.T) {
+	router := setupSaleTestRouter()
+	token := getValidToken(t, router)
+
+	var createdProduct product.Product
+	w := postJSON(t, router, token, http.MethodPost, "/products/", product.Product{Name: "Pastel", Price: 8, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdProduct))
+
+	// Vendas simultâneas recebem números distintos e sequenciais
+	const totalSales = 20
+	numbers := make(chan int, totalSales)
+	var wg sync.WaitGroup
+	for i := 0; i < totalSales; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+				Items: []sale.SaleItem{{ProductID: createdProduct.ID, Quantity: 1}},
+			})
+			assert.Equal(t, http.StatusCreated, w.Code)
+
+			var createdSale sale.Sale
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdSale))
+			numbers <- createdSale.OrderNumber
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	seen := make(map[int]bool)
+	for number := range numbers {
+		assert.False(t, seen[number], "número de pedido repetido: %d", number)
+		seen[number] = true
+	}
+	for number := 1; number <= totalSales; number++ {
+		assert.True(t, seen[number], "número de pedido ausente: %d", number)
+	}
+
+	// Um novo dia de operação reinicia a numeração
+	var yesterdaySale sale.Sale
+	w = postJSON(t, router, token, http.MethodPost, "/sales/", sale.Sale{
+		Date:  time.Now().AddDate(0, 0, -1),
+		Items: []sale.SaleItem{{ProductID: createdProduct.ID, Quantity: 1}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &yesterdaySale))
+	assert.Equal(t, 1, yesterdaySale.OrderNumber)
+
+	// Busca pelo número do pedido
+	req, _ := http.NewRequest(http.MethodGet, "/sales/?order_number=1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var listResponse map[string][]sale.Sale
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResponse))
+	require.Len(t, listResponse["sales"], 1)
+	assert.NotEqual(t, yesterdaySale.ID, listResponse["sales"][0].ID)
+
+	yesterday := yesterdaySale.BusinessDate.Format("2006-01-02")
+	req, _ = http.NewRequest(http.MethodGet, "/sales/?order_number=1&date="+yesterday, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResponse))
+	require.Len(t, listResponse["sales"], 1)
+	assert.Equal(t, yesterdaySale.ID, listResponse["sales"][0].ID)
+
+	req, _ = http.NewRequest(http.MethodGet, "/sales/?order_number=abc", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/sale"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/terminal"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/infrastructure/events"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTerminalTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	userRepo := newTestUserRepository()
+	terminalRepo := repository.NewInMemoryTerminalRepository()
+	productRepo := repository.NewInMemoryProductRepository(nil, nil)
+	authService := newTestTerminalAuthService(userRepo, terminalRepo)
+	saleService := services.NewSaleService(repository.NewInMemorySaleRepository(nil), productRepo, repository.NewInMemoryAdditionRepository(nil, nil),
+		repository.NewInMemoryCategoryRepository(nil), nil, sale.ServiceChargePolicy{}, sale.Calendar{}, events.NewKitchenBroker(10))
+
+	router := gin.Default()
+	handlers.RegisterAuthRoutes(&router.RouterGroup, authService)
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterProductRoutes(protected, services.NewProductService(productRepo, nil))
+	handlers.RegisterSaleRoutes(protected, saleService)
+	handlers.RegisterUserRoutes(protected, services.NewUserService(userRepo))
+	handlers.RegisterTerminalRoutes(protected, services.NewTerminalService(terminalRepo))
+
+	return router
+}
+
+func pinLogin(router *gin.Engine, deviceToken, username, pin string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(handlers.PINLoginInput{Username: username, PIN: pin})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/pin", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.DeviceTokenHeader, deviceToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTerminals_OperatorPINLogin(t *testing.T) {
+	router := setupTerminalTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	var registration terminal.Registration
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/terminals/", handlers.TerminalInput{Name: "Balcão"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+	require.NotEmpty(t, registration.DeviceToken)
+	assert.NotContains(t, w.Body.String(), "token_hash")
+
+	var cashier user.User
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/users/", handlers.UserInput{
+		Username: "caixa01", Role: user.RoleCashier, Password: "segredo123",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cashier))
+
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/users/"+cashier.ID.String()+"/pin", handlers.PINInput{PIN: "12a4"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/users/"+cashier.ID.String()+"/pin", handlers.PINInput{PIN: "2468"})
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// O PIN só vale com o token de um terminal cadastrado.
+	assert.Equal(t, http.StatusUnauthorized, pinLogin(router, "", "caixa01", "2468").Code)
+	assert.Equal(t, http.StatusUnauthorized, pinLogin(router, "desconhecido", "caixa01", "2468").Code)
+	assert.Equal(t, http.StatusUnauthorized, pinLogin(router, registration.DeviceToken, "caixa01", "1357").Code)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/operators", nil)
+	req.Header.Set(handlers.DeviceTokenHeader, registration.DeviceToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var operators map[string][]user.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &operators))
+	require.Len(t, operators["operators"], 1)
+	assert.Equal(t, "caixa01", operators["operators"][0].Username)
+
+	w = pinLogin(router, registration.DeviceToken, "caixa01", "2468")
+	require.Equal(t, http.StatusOK, w.Code)
+	var tokens session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Empty(t, tokens.RefreshToken)
+
+	// A venda registra o operador e o terminal da sessão, não os do corpo.
+	var burger product.Product
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/products/", product.Product{Name: "X-Salada", Price: 18.5, CategoryID: uuid.New()})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &burger))
+
+	other := uuid.New()
+	var created sale.Sale
+	w = postJSON(t, router, tokens.AccessToken, http.MethodPost, "/sales/", sale.Sale{
+		OperatorID: &other,
+		Items:      []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.OperatorID)
+	require.NotNil(t, created.TerminalID)
+	assert.Equal(t, cashier.ID, *created.OperatorID)
+	assert.Equal(t, registration.Terminal.ID, *created.TerminalID)
+
+	// Vendas lançadas fora de um terminal não têm terminal.
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/sales/", sale.Sale{
+		Items: []sale.SaleItem{{ProductID: burger.ID, Quantity: 1}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var counterSale sale.Sale
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &counterSale))
+	assert.Nil(t, counterSale.TerminalID)
+	assert.NotNil(t, counterSale.OperatorID)
+
+	// Desativar o terminal encerra a sessão e bloqueia novos logins.
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/terminals/"+registration.Terminal.ID.String(), handlers.TerminalInput{Name: "Balcão", Active: new(bool)})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = getAuthorized(t, router, tokens.AccessToken, "/sales/")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, pinLogin(router, registration.DeviceToken, "caixa01", "2468").Code)
+}
+
+func TestTerminals_RotateTokenAndLogout(t *testing.T) {
+	router := setupTerminalTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	var registration terminal.Registration
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/terminals/", handlers.TerminalInput{Name: "Tablet"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+
+	w = getAuthorized(t, router, ownerToken, "/users/")
+	require.Equal(t, http.StatusOK, w.Code)
+	var users map[string][]user.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/users/"+users["users"][0].ID.String()+"/pin", handlers.PINInput{PIN: "9999"})
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = pinLogin(router, registration.DeviceToken, "test_user", "9999")
+	require.Equal(t, http.StatusOK, w.Code)
+	var previous session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &previous))
+	require.Equal(t, http.StatusOK, getAuthorized(t, router, previous.AccessToken, "/sales/").Code)
+
+	var rotated terminal.Registration
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/terminals/"+registration.Terminal.ID.String()+"/token", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.NotEqual(t, registration.DeviceToken, rotated.DeviceToken)
+	assert.Equal(t, http.StatusUnauthorized, getAuthorized(t, router, previous.AccessToken, "/sales/").Code, "a troca do token encerra as sessões abertas com o anterior")
+
+	assert.Equal(t, http.StatusUnauthorized, pinLogin(router, registration.DeviceToken, "test_user", "9999").Code)
+	w = pinLogin(router, rotated.DeviceToken, "test_user", "9999")
+	require.Equal(t, http.StatusOK, w.Code)
+	var tokens session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+	w = postJSON(t, router, tokens.AccessToken, http.MethodPost, "/auth/logout", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = getAuthorized(t, router, tokens.AccessToken, "/sales/")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Caixas não gerenciam terminais.
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/users/", handlers.UserInput{
+		Username: "caixa02", Role: user.RoleCashier, Password: "segredo123",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = getAuthorized(t, router, loginAs(t, router, "caixa02", "segredo123"), "/terminals/")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
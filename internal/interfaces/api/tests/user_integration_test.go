@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"andressa-lanches/internal/application/services"
+	"andressa-lanches/internal/config"
+	"andressa-lanches/internal/domain/product"
+	"andressa-lanches/internal/domain/session"
+	"andressa-lanches/internal/domain/user"
+	"andressa-lanches/internal/infrastructure/repository"
+	"andressa-lanches/internal/interfaces/api/handlers"
+	"andressa-lanches/internal/interfaces/api/middlewares"
+
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupUserTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config.JWTSecret = "test_secret"
+	config.AuthUser = "test_user"
+	config.AuthPassword = "test_password"
+
+	userRepo := newTestUserRepository()
+	userService := services.NewUserService(userRepo)
+	authService := newTestAuthService(userRepo)
+	productService := services.NewProductService(repository.NewInMemoryProductRepository(nil, nil), nil)
+
+	router := gin.Default()
+	handlers.RegisterAuthRoutes(&router.RouterGroup, authService)
+
+	protected := router.Group("/")
+	protected.Use(middlewares.AuthMiddleware(authService))
+	handlers.RegisterProductRoutes(protected, productService)
+	handlers.RegisterUserRoutes(protected, userService)
+
+	return router
+}
+
+func loginAs(t *testing.T, router *gin.Engine, username, password string) string {
+	w := postJSON(t, router, "", http.MethodPost, "/auth/login", handlers.LoginInput{Username: username, Password: password})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response["token"]
+}
+
+func TestUsers_RoleBasedAccess(t *testing.T) {
+	router := setupUserTestRouter()
+	ownerToken := getValidToken(t, router)
+
+	var cashier user.User
+	w := postJSON(t, router, ownerToken, http.MethodPost, "/users/", handlers.UserInput{
+		Username: "Caixa01", Name: "Maria", Role: user.RoleCashier, Password: "segredo123",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cashier))
+	assert.Equal(t, "caixa01", cashier.Username)
+	assert.True(t, cashier.Active)
+	assert.NotContains(t, w.Body.String(), "password")
+
+	w = postJSON(t, router, ownerToken, http.MethodPost, "/users/", handlers.UserInput{
+		Username: "caixa01", Role: user.RoleKitchen, Password: "segredo123",
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	cashierToken := loginAs(t, router, "caixa01", "segredo123")
+
+	w = getAuthorized(t, router, cashierToken, "/products/")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON(t, router, cashierToken, http.MethodPost, "/products/", product.Product{Name: "X-Tudo", Price: 20, CategoryID: uuid.New()})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = getAuthorized(t, router, cashierToken, "/users/")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Usuário desativado não consegue entrar.
+	w = postJSON(t, router, ownerToken, http.MethodPut, "/users/"+cashier.ID.String(), handlers.UserInput{
+		Username: "caixa01", Role: user.RoleCashier, Active: new(bool),
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = postJSON(t, router, "", http.MethodPost, "/auth/login", handlers.LoginInput{Username: "caixa01", Password: "segredo123"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUsers_KeepsLastOwner(t *testing.T) {
+	router := setupUserTestRouter()
+	token := getValidToken(t, router)
+
+	w := getAuthorized(t, router, token, "/users/")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string][]user.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response["users"], 1)
+	owner := response["users"][0]
+	assert.Equal(t, user.RoleOwner, owner.Role)
+
+	w = postJSON(t, router, token, http.MethodDelete, "/users/"+owner.ID.String(), nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPut, "/users/"+owner.ID.String(), handlers.UserInput{
+		Username: owner.Username, Role: user.RoleManager,
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = postJSON(t, router, token, http.MethodPost, "/users/", handlers.UserInput{
+		Username: "gerente", Role: "admin", Password: "segredo123",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuth_RefreshRotationAndLogout(t *testing.T) {
+	router := setupUserTestRouter()
+
+	w := postJSON(t, router, "", http.MethodPost, "/auth/login", handlers.LoginInput{Username: "test_user", Password: "test_password"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var first session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	require.NotEmpty(t, first.RefreshToken)
+
+	w = postJSON(t, router, "", http.MethodPost, "/auth/refresh", handlers.RefreshInput{RefreshToken: first.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code)
+	var second session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	w = getAuthorized(t, router, second.AccessToken, "/products/")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Reaproveitar um refresh token já usado derruba a sessão inteira.
+	w = postJSON(t, router, "", http.MethodPost, "/auth/refresh", handlers.RefreshInput{RefreshToken: first.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = getAuthorized(t, router, second.AccessToken, "/products/")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postJSON(t, router, "", http.MethodPost, "/auth/refresh", handlers.RefreshInput{RefreshToken: second.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postJSON(t, router, "", http.MethodPost, "/auth/login", handlers.LoginInput{Username: "test_user", Password: "test_password"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var third session.TokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &third))
+
+	w = postJSON(t, router, third.AccessToken, http.MethodPost, "/auth/logout", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = getAuthorized(t, router, third.AccessToken, "/products/")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postJSON(t, router, "", http.MethodPost, "/auth/refresh", handlers.RefreshInput{RefreshToken: third.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}